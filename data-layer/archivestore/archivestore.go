@@ -0,0 +1,29 @@
+package archivestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Storage keeps archive files by key. Keys are slash separated paths.
+type Storage interface {
+	// Name identifies the backend in the archive manifest
+	Name() string
+	// Create starts writing the file at key; the file only appears once the writer is closed
+	Create(ctx context.Context, key string) (io.WriteCloser, error)
+	// Open reads the file at key
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file at key, if any
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend by name
+func New(backend, localDir string) (Storage, error) {
+	switch backend {
+	case "", "local":
+		return NewLocalStorage(localDir), nil
+	default:
+		return nil, fmt.Errorf("unknown archive storage backend %q", backend)
+	}
+}
@@ -16,6 +16,14 @@ type Config struct {
 	User        UserConfig
 	JWT         JWTConfig
 	Cloudinary  CloudinaryConfig
+	MQTT        MQTTConfig
+	Notifier    NotifierConfig
+	Maintenance MaintenanceConfig
+	Alerting    AlertingConfig
+	Outbox      OutboxConfig
+	Rollup      RollupConfig
+	Retention   RetentionConfig
+	Archive     ArchiveConfig
 }
 
 // ServerConfig holds server configuration
@@ -63,6 +71,81 @@ type CloudinaryConfig struct {
 	APISecret string
 }
 
+// MQTTConfig holds MQTT ingestion bridge configuration
+type MQTTConfig struct {
+	Enabled      bool
+	BrokerURL    string
+	ClientID     string
+	Username     string
+	Password     string
+	TopicPattern string // e.g. tenant/{tenant}/sensor/{mac}
+	QoS          int
+	KeepAlive    int  // seconds
+	CleanSession bool // Discards unacknowledged messages on reconnect when set
+}
+
+// NotifierConfig holds alert notification delivery configuration
+type NotifierConfig struct {
+	PollInterval   int // seconds between delivery queue polls
+	MaxAttempts    int
+	RetryBaseDelay int // seconds, doubled after every failed attempt
+	RetryMaxDelay  int // seconds
+	HTTPTimeout    int // seconds, for webhook and Slack deliveries
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SMTPFrom       string
+
+	EscalationInterval int // seconds between escalation policy evaluations
+}
+
+// AlertingConfig holds asynchronous alert evaluation configuration
+type AlertingConfig struct {
+	Workers           int // workers evaluating readings; readings of one sensor always use the same worker
+	QueueSize         int // readings waiting for evaluation across all workers
+	EvaluationTimeout int // seconds a single reading evaluation may take
+	DrainTimeout      int // seconds to keep evaluating queued readings on shutdown
+}
+
+// OutboxConfig holds reading outbox dispatch configuration
+type OutboxConfig struct {
+	PollInterval   int // seconds between polls when no readings are stored
+	BatchSize      int // events claimed per poll
+	Lease          int // seconds a claimed event stays reserved before it is applied again
+	MaxAttempts    int
+	RetryBaseDelay int // seconds
+	RetryMaxDelay  int // seconds
+	RetentionHours int // hours completed events are kept; 0 keeps them
+}
+
+// RollupConfig holds reading rollup refresh configuration
+type RollupConfig struct {
+	PollInterval int // seconds between rollup refreshes
+	SafetyLag    int // seconds readings must be stored for before they are rolled up
+	ChunkHours   int // hours of stored readings rolled up per refresh transaction
+}
+
+// RetentionConfig holds reading partitioning and retention configuration
+type RetentionConfig struct {
+	PollInterval    int // seconds between partition maintenance and retention runs
+	PartitionsAhead int // monthly reading partitions created ahead of the current month
+	DeleteBatchSize int // expired readings deleted or archived per statement
+	MessageTTLHours int // hours client message IDs are kept to drop redelivered messages; 0 keeps them
+}
+
+// ArchiveConfig holds reading archive export configuration
+type ArchiveConfig struct {
+	PollInterval int    // seconds between archive exports
+	Storage      string // storage backend for archive files: local
+	LocalDir     string // directory of the local storage backend
+}
+
+// MaintenanceConfig holds maintenance window scheduling configuration
+type MaintenanceConfig struct {
+	PollInterval int // seconds between asset status checks for maintenance windows
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -100,6 +183,65 @@ func Load() *Config {
 			APIKey:    getEnvOrFail("CLOUDINARY_API_KEY"),
 			APISecret: getEnvOrFail("CLOUDINARY_API_SECRET"),
 		},
+		MQTT: MQTTConfig{
+			Enabled:      getEnvAsBoolOrDefault("MQTT_ENABLED", false),
+			BrokerURL:    getEnvOrDefault("MQTT_BROKER_URL", "tcp://localhost:1883"),
+			ClientID:     getEnvOrDefault("MQTT_CLIENT_ID", "asset-management-ingestion"),
+			Username:     getEnvOrDefault("MQTT_USERNAME", ""),
+			Password:     getEnvOrDefault("MQTT_PASSWORD", ""),
+			TopicPattern: getEnvOrDefault("MQTT_TOPIC_PATTERN", "tenant/{tenant}/sensor/{mac}"),
+			QoS:          getEnvAsIntOrDefault("MQTT_QOS", 1),
+			KeepAlive:    getEnvAsIntOrDefault("MQTT_KEEP_ALIVE", 60),
+			CleanSession: getEnvAsBoolOrDefault("MQTT_CLEAN_SESSION", false),
+		},
+		Notifier: NotifierConfig{
+			PollInterval:   getEnvAsIntOrDefault("NOTIFIER_POLL_INTERVAL", 5),
+			MaxAttempts:    getEnvAsIntOrDefault("NOTIFIER_MAX_ATTEMPTS", 6),
+			RetryBaseDelay: getEnvAsIntOrDefault("NOTIFIER_RETRY_BASE_DELAY", 30),
+			RetryMaxDelay:  getEnvAsIntOrDefault("NOTIFIER_RETRY_MAX_DELAY", 3600),
+			HTTPTimeout:    getEnvAsIntOrDefault("NOTIFIER_HTTP_TIMEOUT", 10),
+			SMTPHost:       getEnvOrDefault("SMTP_HOST", ""),
+			SMTPPort:       getEnvAsIntOrDefault("SMTP_PORT", 587),
+			SMTPUsername:   getEnvOrDefault("SMTP_USERNAME", ""),
+			SMTPPassword:   getEnvOrDefault("SMTP_PASSWORD", ""),
+			SMTPFrom:       getEnvOrDefault("SMTP_FROM", ""),
+
+			EscalationInterval: getEnvAsIntOrDefault("ESCALATION_POLL_INTERVAL", 30),
+		},
+		Maintenance: MaintenanceConfig{
+			PollInterval: getEnvAsIntOrDefault("MAINTENANCE_POLL_INTERVAL", 60),
+		},
+		Alerting: AlertingConfig{
+			Workers:           getEnvAsIntOrDefault("ALERT_EVALUATION_WORKERS", 8),
+			QueueSize:         getEnvAsIntOrDefault("ALERT_EVALUATION_QUEUE_SIZE", 10000),
+			EvaluationTimeout: getEnvAsIntOrDefault("ALERT_EVALUATION_TIMEOUT", 30),
+			DrainTimeout:      getEnvAsIntOrDefault("ALERT_EVALUATION_DRAIN_TIMEOUT", 30),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:   getEnvAsIntOrDefault("OUTBOX_POLL_INTERVAL", 5),
+			BatchSize:      getEnvAsIntOrDefault("OUTBOX_BATCH_SIZE", 500),
+			Lease:          getEnvAsIntOrDefault("OUTBOX_LEASE", 300),
+			MaxAttempts:    getEnvAsIntOrDefault("OUTBOX_MAX_ATTEMPTS", 10),
+			RetryBaseDelay: getEnvAsIntOrDefault("OUTBOX_RETRY_BASE_DELAY", 5),
+			RetryMaxDelay:  getEnvAsIntOrDefault("OUTBOX_RETRY_MAX_DELAY", 600),
+			RetentionHours: getEnvAsIntOrDefault("OUTBOX_RETENTION_HOURS", 24),
+		},
+		Rollup: RollupConfig{
+			PollInterval: getEnvAsIntOrDefault("ROLLUP_POLL_INTERVAL", 60),
+			SafetyLag:    getEnvAsIntOrDefault("ROLLUP_SAFETY_LAG", 60),
+			ChunkHours:   getEnvAsIntOrDefault("ROLLUP_CHUNK_HOURS", 24),
+		},
+		Retention: RetentionConfig{
+			PollInterval:    getEnvAsIntOrDefault("RETENTION_POLL_INTERVAL", 3600),
+			PartitionsAhead: getEnvAsIntOrDefault("RETENTION_PARTITIONS_AHEAD", 3),
+			DeleteBatchSize: getEnvAsIntOrDefault("RETENTION_DELETE_BATCH_SIZE", 10000),
+			MessageTTLHours: getEnvAsIntOrDefault("RETENTION_MESSAGE_TTL_HOURS", 168),
+		},
+		Archive: ArchiveConfig{
+			PollInterval: getEnvAsIntOrDefault("ARCHIVE_POLL_INTERVAL", 3600),
+			Storage:      getEnvOrDefault("ARCHIVE_STORAGE", "local"),
+			LocalDir:     getEnvOrDefault("ARCHIVE_LOCAL_DIR", "./archives"),
+		},
 	}
 }
 
@@ -131,3 +273,38 @@ func getEnvAsBoolOrFail(key string) bool {
 	}
 	return value
 }
+
+// getEnvOrDefault gets an environment variable or returns the default if not set
+func getEnvOrDefault(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsIntOrDefault gets an environment variable as an integer or returns the default if not set
+func getEnvAsIntOrDefault(key string, defaultValue int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be an integer: %v", key, err)
+	}
+	return value
+}
+
+// getEnvAsBoolOrDefault gets an environment variable as a boolean or returns the default if not set
+func getEnvAsBoolOrDefault(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be a boolean: %v", key, err)
+	}
+	return value
+}
@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Calendar intervals of a reading aggregation, which are date_trunc fields
+const (
+	AggregateIntervalHour  = "hour"
+	AggregateIntervalDay   = "day"
+	AggregateIntervalWeek  = "week"
+	AggregateIntervalMonth = "month"
+)
+
+// MinAggregateStep is the shortest fixed interval of a reading aggregation
+const MinAggregateStep = time.Minute
+
+var aggregateStepPattern = regexp.MustCompile(`^(\d+)([mhd])$`)
+
+var aggregateStepUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+}
+
+// AggregateInterval is the bucket size of a reading aggregation: a calendar interval
+// (hour, day, week or month) or a fixed length such as 5m, 15m or 6h. Buckets follow the
+// wall clock of the aggregation's time zone, so day buckets start at local midnight and
+// fixed lengths are counted from local midnight of 1970-01-01.
+type AggregateInterval struct {
+	Name     string        // Interval as requested
+	Calendar string        // date_trunc field of a calendar interval
+	Step     time.Duration // Length of a fixed interval
+}
+
+// ParseAggregateInterval parses a calendar interval name or a fixed length made of a
+// count and a unit: m (minutes), h (hours) or d (days)
+func ParseAggregateInterval(value string) (AggregateInterval, error) {
+	switch value {
+	case AggregateIntervalHour, AggregateIntervalDay, AggregateIntervalWeek, AggregateIntervalMonth:
+		return AggregateInterval{Name: value, Calendar: value}, nil
+	}
+
+	match := aggregateStepPattern.FindStringSubmatch(value)
+	if match == nil {
+		return AggregateInterval{}, fmt.Errorf("invalid interval %q, must be hour, day, week, month or a length such as 5m, 15m, 6h or 1d", value)
+	}
+	count, err := strconv.ParseInt(match[1], 10, 64)
+	unit := aggregateStepUnits[match[2]]
+	if err != nil || count <= 0 || count > int64(366*24*time.Hour/unit) {
+		return AggregateInterval{}, fmt.Errorf("invalid interval %q, length must be between 1m and 366d", value)
+	}
+
+	step := time.Duration(count) * unit
+	if step < MinAggregateStep {
+		return AggregateInterval{}, fmt.Errorf("interval must be at least %s", MinAggregateStep)
+	}
+	return AggregateInterval{Name: value, Step: step}, nil
+}
+
+// IsCalendar reports whether the interval is a calendar interval rather than a fixed length
+func (i AggregateInterval) IsCalendar() bool {
+	return i.Calendar != ""
+}
+
+// Buckets returns the starts of the buckets from the one containing from up to to, in the
+// time zone loc. ok is false when there are more than limit buckets.
+func (i AggregateInterval) Buckets(from, to time.Time, loc *time.Location, limit int) ([]time.Time, bool) {
+	var buckets []time.Time
+	for wall := i.truncateWallClock(wallClock(from.In(loc))); ; wall = i.nextWallClock(wall) {
+		bucket := fromWallClock(wall, loc)
+		if bucket.After(to) {
+			return buckets, true
+		}
+		// A bucket starting in a skipped hour of a daylight saving change can map to the
+		// same instant as the next one
+		if len(buckets) > 0 && !bucket.After(buckets[len(buckets)-1]) {
+			continue
+		}
+		if len(buckets) == limit {
+			return buckets, false
+		}
+		buckets = append(buckets, bucket)
+	}
+}
+
+// truncateWallClock returns the start of the bucket containing the wall clock time wall,
+// the way the aggregation query buckets readings
+func (i AggregateInterval) truncateWallClock(wall time.Time) time.Time {
+	year, month, day := wall.Date()
+	switch i.Calendar {
+	case AggregateIntervalHour:
+		return wall.Truncate(time.Hour)
+	case AggregateIntervalDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	case AggregateIntervalWeek:
+		// Weeks start on Monday
+		offset := (int(wall.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC)
+	case AggregateIntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	seconds := int64(i.Step / time.Second)
+	unix := wall.Unix()
+	start := unix - unix%seconds
+	if unix%seconds < 0 {
+		start -= seconds
+	}
+	return time.Unix(start, 0).UTC()
+}
+
+// nextWallClock returns the start of the bucket after the one starting at the wall clock
+// time wall
+func (i AggregateInterval) nextWallClock(wall time.Time) time.Time {
+	switch i.Calendar {
+	case AggregateIntervalHour:
+		return wall.Add(time.Hour)
+	case AggregateIntervalDay:
+		return wall.AddDate(0, 0, 1)
+	case AggregateIntervalWeek:
+		return wall.AddDate(0, 0, 7)
+	case AggregateIntervalMonth:
+		return wall.AddDate(0, 1, 0)
+	}
+	return wall.Add(i.Step)
+}
+
+// wallClock returns the wall clock time of t as a UTC time
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock returns the time in loc showing the wall clock time wall
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+}
+
+// GapFill is how the empty buckets of a reading aggregation are reported
+type GapFill string
+
+const (
+	// GapFillNone leaves empty buckets out
+	GapFillNone GapFill = "none"
+	// GapFillNull reports empty buckets without values
+	GapFillNull GapFill = "null"
+	// GapFillPrevious carries the last values of each measurement forward
+	GapFillPrevious GapFill = "previous"
+	// GapFillLinear interpolates the values of each measurement between its neighbouring buckets
+	GapFillLinear GapFill = "linear"
+)
+
+// IsValid checks if the gap fill strategy is valid
+func (f GapFill) IsValid() bool {
+	switch f {
+	case GapFillNone, GapFillNull, GapFillPrevious, GapFillLinear:
+		return true
+	}
+	return false
+}
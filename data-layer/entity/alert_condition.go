@@ -0,0 +1,210 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertTypeCondition is the alert type of alerts raised by composite alert conditions
+const AlertTypeCondition = "condition"
+
+// DefaultConditionReadingAgeSeconds is how old the other readings of a condition may be
+// when it is evaluated, unless configured otherwise
+const DefaultConditionReadingAgeSeconds = 300
+
+// MaxConditionReferences bounds the number of distinct fields a condition may use
+const MaxConditionReferences = 20
+
+var conditionAliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AlertCondition raises a single alert when a boolean expression over several measurement
+// fields of one asset holds, e.g. "temperature > 30 AND humidity < 20". Fields are
+// referenced by name on the default asset sensor, or as alias.field for the sensors
+// listed in Sensors. The condition is evaluated whenever one of its fields gets a reading,
+// using the latest reading of each other field.
+type AlertCondition struct {
+	ID                   uuid.UUID            `json:"id"`
+	TenantID             uuid.UUID            `json:"tenant_id"`
+	AssetID              uuid.UUID            `json:"asset_id"`
+	AssetSensorID        *uuid.UUID           `json:"asset_sensor_id,omitempty"` // Sensor of unqualified field names
+	Sensors              map[string]uuid.UUID `json:"sensors"`                   // Aliases for alias.field references
+	Name                 string               `json:"name"`
+	Description          *string              `json:"description,omitempty"`
+	Expression           string               `json:"expression"`
+	Severity             ThresholdSeverity    `json:"severity"`
+	AlertRules           ThresholdAlertRules  `json:"alert_rules"`
+	MaxReadingAgeSeconds int                  `json:"max_reading_age_seconds"` // Older readings of the other fields count as missing
+	IsActive             bool                 `json:"is_active"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            *time.Time           `json:"updated_at,omitempty"`
+}
+
+// AlertConditionState tracks breach/clear progress of an alert condition
+type AlertConditionState struct {
+	ConditionID uuid.UUID `json:"condition_id"`
+	AlertProgress
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewAlertCondition creates a new alert condition with default values
+func NewAlertCondition() *AlertCondition {
+	return &AlertCondition{
+		ID:                   uuid.New(),
+		Sensors:              map[string]uuid.UUID{},
+		Severity:             ThresholdSeverityWarning,
+		MaxReadingAgeSeconds: DefaultConditionReadingAgeSeconds,
+		IsActive:             true,
+		CreatedAt:            time.Now(),
+	}
+}
+
+// ParseExpression parses the condition's expression
+func (c *AlertCondition) ParseExpression() (*ConditionExpression, error) {
+	return ParseConditionExpression(c.Expression)
+}
+
+// ResolveReference returns the asset sensor a field reference points to
+func (c *AlertCondition) ResolveReference(ref ConditionReference) (uuid.UUID, bool) {
+	if ref.Sensor == "" {
+		if c.AssetSensorID == nil {
+			return uuid.Nil, false
+		}
+		return *c.AssetSensorID, true
+	}
+	id, ok := c.Sensors[ref.Sensor]
+	return id, ok
+}
+
+// SensorIDs returns the distinct asset sensors the condition may reference
+func (c *AlertCondition) SensorIDs() []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	if c.AssetSensorID != nil {
+		seen[*c.AssetSensorID] = true
+		ids = append(ids, *c.AssetSensorID)
+	}
+
+	aliases := make([]string, 0, len(c.Sensors))
+	for alias := range c.Sensors {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if id := c.Sensors[alias]; !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// MaxReadingAge returns how old the other readings may be when the condition is evaluated
+func (c *AlertCondition) MaxReadingAge() time.Duration {
+	return time.Duration(c.MaxReadingAgeSeconds) * time.Second
+}
+
+// Validate checks the condition and parses its expression. It does not check that the
+// referenced fields exist; that needs the sensors' measurement field definitions.
+func (c *AlertCondition) Validate() (*ConditionExpression, error) {
+	if strings.TrimSpace(c.Name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if c.Severity != ThresholdSeverityWarning && c.Severity != ThresholdSeverityCritical {
+		return nil, fmt.Errorf("invalid severity: %s", c.Severity)
+	}
+	if err := c.AlertRules.Validate(); err != nil {
+		return nil, err
+	}
+	if c.AlertRules.Hysteresis != 0 {
+		return nil, fmt.Errorf("hysteresis is not supported by alert conditions")
+	}
+	if c.MaxReadingAgeSeconds <= 0 {
+		return nil, fmt.Errorf("max_reading_age_seconds must be positive")
+	}
+	for alias := range c.Sensors {
+		if !conditionAliasPattern.MatchString(alias) {
+			return nil, fmt.Errorf("invalid sensor alias %q: use letters, digits and underscores", alias)
+		}
+	}
+
+	expression, err := c.ParseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	refs := expression.References()
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("expression must reference at least one field")
+	}
+	if len(refs) > MaxConditionReferences {
+		return nil, fmt.Errorf("expression must not reference more than %d fields", MaxConditionReferences)
+	}
+	for _, ref := range refs {
+		if _, ok := c.ResolveReference(ref); !ok {
+			if ref.Sensor == "" {
+				return nil, fmt.Errorf("field %s has no sensor: set asset_sensor_id or use alias.%s", ref, ref.Field)
+			}
+			return nil, fmt.Errorf("unknown sensor alias %q in %s", ref.Sensor, ref)
+		}
+	}
+
+	return expression, nil
+}
+
+// Evaluate applies the outcome of the expression to the condition state and reports
+// whether an alert should be opened, updated or closed. The state is modified in place.
+func (c *AlertCondition) Evaluate(state *AlertConditionState, matched bool, at time.Time) ThresholdTransition {
+	return c.AlertRules.Advance(&state.AlertProgress, matched, !matched, at)
+}
+
+// GenerateAlertMessage describes the condition and the field values that met it
+func (c *AlertCondition) GenerateAlertMessage(refs []ConditionReference, values map[ConditionReference]interface{}) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		switch value := values[ref].(type) {
+		case float64:
+			parts = append(parts, fmt.Sprintf("%s=%.2f", ref, value))
+		case string:
+			parts = append(parts, fmt.Sprintf("%s=%q", ref, value))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", ref, value))
+		}
+	}
+	return fmt.Sprintf("Condition %s met: %s (%s)", c.Name, c.Expression, strings.Join(parts, ", "))
+}
+
+// CreateAlertFromCondition creates an alert when a condition is met. The alert belongs to
+// the asset sensor whose reading triggered it; triggerValue is that reading's numeric value.
+func CreateAlertFromCondition(
+	tenantID, assetID, assetSensorID uuid.UUID,
+	condition *AlertCondition,
+	triggerValue float64,
+	message string,
+) *AssetAlert {
+	conditionID := condition.ID
+
+	alert := NewAssetAlert()
+	alert.TenantID = tenantID
+	alert.AssetID = assetID
+	alert.AssetSensorID = assetSensorID
+	alert.ConditionID = &conditionID
+	alert.MeasurementFieldName = condition.Name
+	alert.Severity = condition.Severity
+	alert.TriggerValue = triggerValue
+	alert.LastTriggerValue = triggerValue
+	alert.PeakTriggerValue = triggerValue
+	alert.AlertType = AlertTypeCondition
+	alert.AlertMessage = message
+
+	alert.Status = ThresholdStatusWarning
+	if condition.Severity == ThresholdSeverityCritical {
+		alert.Status = ThresholdStatusCritical
+	}
+
+	return alert
+}
@@ -6,37 +6,90 @@ import (
 	"github.com/google/uuid"
 )
 
+// AlertState is the workflow state of an alert
+type AlertState string
+
+const (
+	AlertStateOpen         AlertState = "open"
+	AlertStateAcknowledged AlertState = "acknowledged"
+	AlertStateResolved     AlertState = "resolved"
+)
+
+// AlertResolutionCode records why an alert was resolved
+type AlertResolutionCode string
+
+const (
+	AlertResolutionFixed            AlertResolutionCode = "fixed"
+	AlertResolutionFalsePositive    AlertResolutionCode = "false_positive"
+	AlertResolutionExpectedBehavior AlertResolutionCode = "expected_behavior"
+	AlertResolutionDuplicate        AlertResolutionCode = "duplicate"
+	AlertResolutionOther            AlertResolutionCode = "other"
+	AlertResolutionAutoCleared      AlertResolutionCode = "auto_cleared" // Value returned to normal; set by threshold evaluation only
+)
+
+// IsManual reports whether the code may be chosen by a user resolving an alert
+func (c AlertResolutionCode) IsManual() bool {
+	switch c {
+	case AlertResolutionFixed, AlertResolutionFalsePositive, AlertResolutionExpectedBehavior,
+		AlertResolutionDuplicate, AlertResolutionOther:
+		return true
+	}
+	return false
+}
+
+// AlertResolution describes how an alert is being resolved
+type AlertResolution struct {
+	Code       AlertResolutionCode
+	Note       *string
+	ResolvedBy *uuid.UUID
+}
+
 // AssetAlert represents a notification generated when a sensor reading exceeds thresholds
 type AssetAlert struct {
-	ID                   uuid.UUID         `json:"id"`
-	TenantID             uuid.UUID         `json:"tenant_id"`
-	AssetID              uuid.UUID         `json:"asset_id"`
-	AssetSensorID        uuid.UUID         `json:"asset_sensor_id"`
-	ThresholdID          uuid.UUID         `json:"threshold_id"`
-	MeasurementFieldName string            `json:"measurement_field_name"` // Field yang trigger alert
-	AlertTime            time.Time         `json:"alert_time"`
-	ResolvedTime         *time.Time        `json:"resolved_time,omitempty"`
-	Severity             ThresholdSeverity `json:"severity"`
-	Status               ThresholdStatus   `json:"status"`              // Current status of the alert
-	TriggerValue         float64           `json:"trigger_value"`       // Nilai yang memicu alert
-	ThresholdMinValue    *float64          `json:"threshold_min_value"` // Min threshold saat alert
-	ThresholdMaxValue    *float64          `json:"threshold_max_value"` // Max threshold saat alert
-	AlertMessage         string            `json:"alert_message"`       // Pesan alert
-	AlertType            string            `json:"alert_type"`          // "min_breach", "max_breach"
-	IsResolved           bool              `json:"is_resolved"`
-	CreatedAt            time.Time         `json:"created_at"`
-	UpdatedAt            *time.Time        `json:"updated_at,omitempty"`
+	ID                   uuid.UUID            `json:"id"`
+	TenantID             uuid.UUID            `json:"tenant_id"`
+	AssetID              uuid.UUID            `json:"asset_id"`
+	AssetSensorID        uuid.UUID            `json:"asset_sensor_id"`
+	ThresholdID          uuid.UUID            `json:"threshold_id"`           // Nil for alerts raised by an alert condition
+	ConditionID          *uuid.UUID           `json:"condition_id,omitempty"` // Set for alerts raised by an alert condition
+	MeasurementFieldName string               `json:"measurement_field_name"` // Field yang trigger alert
+	AlertTime            time.Time            `json:"alert_time"`
+	ResolvedTime         *time.Time           `json:"resolved_time,omitempty"`
+	Severity             ThresholdSeverity    `json:"severity"`
+	Status               ThresholdStatus      `json:"status"`              // Current status of the alert
+	TriggerValue         float64              `json:"trigger_value"`       // Nilai yang memicu alert
+	ThresholdMinValue    *float64             `json:"threshold_min_value"` // Min threshold saat alert
+	ThresholdMaxValue    *float64             `json:"threshold_max_value"` // Max threshold saat alert
+	AlertMessage         string               `json:"alert_message"`       // Pesan alert
+	AlertType            string               `json:"alert_type"`          // "min_breach", "max_breach", "value_match", "condition"
+	IsResolved           bool                 `json:"is_resolved"`
+	LastTriggerValue     float64              `json:"last_trigger_value"` // Most recent breaching value
+	PeakTriggerValue     float64              `json:"peak_trigger_value"` // Most extreme breaching value
+	OccurrenceCount      int                  `json:"occurrence_count"`   // Breaching readings folded into this alert
+	LastTriggeredAt      *time.Time           `json:"last_triggered_at,omitempty"`
+	AcknowledgedAt       *time.Time           `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy       *uuid.UUID           `json:"acknowledged_by,omitempty"` // User ID
+	AssignedTo           *uuid.UUID           `json:"assigned_to,omitempty"`     // User ID
+	AssignedAt           *time.Time           `json:"assigned_at,omitempty"`
+	ResolvedBy           *uuid.UUID           `json:"resolved_by,omitempty"` // User ID, empty when resolved automatically
+	ResolutionCode       *AlertResolutionCode `json:"resolution_code,omitempty"`
+	ResolutionNote       *string              `json:"resolution_note,omitempty"`
+	MaintenanceWindowID  *uuid.UUID           `json:"maintenance_window_id,omitempty"` // Set when raised during a tagging maintenance window
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            *time.Time           `json:"updated_at,omitempty"`
 }
 
 // NewAssetAlert creates a new asset alert
 func NewAssetAlert() *AssetAlert {
 	now := time.Now()
 	return &AssetAlert{
-		ID:         uuid.New(),
-		AlertTime:  now,
-		IsResolved: false,
-		Status:     ThresholdStatusWarning, // Default to warning
-		CreatedAt:  now,
+		ID:              uuid.New(),
+		AlertTime:       now,
+		IsResolved:      false,
+		Status:          ThresholdStatusWarning, // Default to warning
+		OccurrenceCount: 1,
+		LastTriggeredAt: &now,
+		CreatedAt:       now,
 	}
 }
 
@@ -54,6 +107,8 @@ func CreateAlertFromThresholdBreach(
 	alert.MeasurementFieldName = threshold.MeasurementFieldName
 	alert.Severity = threshold.Severity
 	alert.TriggerValue = triggerValue
+	alert.LastTriggerValue = triggerValue
+	alert.PeakTriggerValue = triggerValue
 	alert.ThresholdMinValue = threshold.MinValue
 	alert.ThresholdMaxValue = threshold.MaxValue
 
@@ -77,6 +132,36 @@ func CreateAlertFromThresholdBreach(
 	return alert
 }
 
+// CreateAlertFromValueMatch creates an alert when a boolean or text reading breaches a
+// value match rule
+func CreateAlertFromValueMatch(
+	tenantID, assetID, assetSensorID uuid.UUID,
+	threshold *SensorThreshold,
+	value interface{},
+) *AssetAlert {
+	triggerValue := MatchTriggerValue(value)
+
+	alert := NewAssetAlert()
+	alert.TenantID = tenantID
+	alert.AssetID = assetID
+	alert.AssetSensorID = assetSensorID
+	alert.ThresholdID = threshold.ID
+	alert.MeasurementFieldName = threshold.MeasurementFieldName
+	alert.Severity = threshold.Severity
+	alert.TriggerValue = triggerValue
+	alert.LastTriggerValue = triggerValue
+	alert.PeakTriggerValue = triggerValue
+	alert.AlertType = AlertTypeValueMatch
+	alert.AlertMessage = threshold.GenerateMatchAlertMessage(value)
+
+	alert.Status = ThresholdStatusWarning
+	if threshold.Severity == ThresholdSeverityCritical {
+		alert.Status = ThresholdStatusCritical
+	}
+
+	return alert
+}
+
 // Resolve marks the alert as resolved
 func (a *AssetAlert) Resolve() {
 	now := time.Now()
@@ -97,10 +182,31 @@ func (a *AssetAlert) UpdateStatus(newValue float64, threshold *SensorThreshold)
 		a.Resolve()
 	} else {
 		a.Status = ThresholdStatus(status)
-		a.TriggerValue = newValue
-		now := time.Now()
-		a.UpdatedAt = &now
+		a.RecordOccurrence(newValue, time.Now())
+	}
+}
+
+// RecordOccurrence folds another breaching reading into the alert
+func (a *AssetAlert) RecordOccurrence(value float64, at time.Time) {
+	a.LastTriggerValue = value
+	if (a.AlertType == "min_breach" && value < a.PeakTriggerValue) ||
+		(a.AlertType != "min_breach" && value > a.PeakTriggerValue) {
+		a.PeakTriggerValue = value
+	}
+	a.OccurrenceCount++
+	a.LastTriggeredAt = &at
+	a.UpdatedAt = &at
+}
+
+// State returns the workflow state of the alert
+func (a *AssetAlert) State() AlertState {
+	if a.IsResolved {
+		return AlertStateResolved
 	}
+	if a.AcknowledgedAt != nil {
+		return AlertStateAcknowledged
+	}
+	return AlertStateOpen
 }
 
 // IsActive returns true if the alert is still active (not resolved)
@@ -119,18 +225,21 @@ func (a *AssetAlert) GetDuration() time.Duration {
 // GetAlertInfo returns comprehensive alert information
 func (a *AssetAlert) GetAlertInfo() map[string]interface{} {
 	info := map[string]interface{}{
-		"id":                a.ID,
-		"asset_id":          a.AssetID,
-		"asset_sensor_id":   a.AssetSensorID,
-		"threshold_id":      a.ThresholdID,
-		"measurement_field": a.MeasurementFieldName,
-		"alert_time":        a.AlertTime,
-		"severity":          a.Severity,
-		"trigger_value":     a.TriggerValue,
-		"alert_type":        a.AlertType,
-		"alert_message":     a.AlertMessage,
-		"is_resolved":       a.IsResolved,
-		"duration_seconds":  a.GetDuration().Seconds(),
+		"id":                 a.ID,
+		"asset_id":           a.AssetID,
+		"asset_sensor_id":    a.AssetSensorID,
+		"threshold_id":       a.ThresholdID,
+		"measurement_field":  a.MeasurementFieldName,
+		"alert_time":         a.AlertTime,
+		"severity":           a.Severity,
+		"trigger_value":      a.TriggerValue,
+		"last_trigger_value": a.LastTriggerValue,
+		"peak_trigger_value": a.PeakTriggerValue,
+		"occurrence_count":   a.OccurrenceCount,
+		"alert_type":         a.AlertType,
+		"alert_message":      a.AlertMessage,
+		"is_resolved":        a.IsResolved,
+		"duration_seconds":   a.GetDuration().Seconds(),
 	}
 
 	if a.ThresholdMinValue != nil {
@@ -142,6 +251,9 @@ func (a *AssetAlert) GetAlertInfo() map[string]interface{} {
 	if a.ResolvedTime != nil {
 		info["resolved_time"] = *a.ResolvedTime
 	}
+	if a.LastTriggeredAt != nil {
+		info["last_triggered_at"] = *a.LastTriggeredAt
+	}
 
 	return info
 }
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssetAlertEventType identifies an entry in an alert's timeline
+type AssetAlertEventType string
+
+const (
+	AssetAlertEventOpened       AssetAlertEventType = "opened"
+	AssetAlertEventAcknowledged AssetAlertEventType = "acknowledged"
+	AssetAlertEventAssigned     AssetAlertEventType = "assigned"
+	AssetAlertEventUnassigned   AssetAlertEventType = "unassigned"
+	AssetAlertEventComment      AssetAlertEventType = "comment"
+	AssetAlertEventResolved     AssetAlertEventType = "resolved"
+	AssetAlertEventEscalated    AssetAlertEventType = "escalated"
+)
+
+// AssetAlertEvent is a timeline entry recording who did what to an alert
+type AssetAlertEvent struct {
+	ID        uuid.UUID           `json:"id"`
+	AlertID   uuid.UUID           `json:"alert_id"`
+	TenantID  uuid.UUID           `json:"tenant_id"`
+	Type      AssetAlertEventType `json:"type"`
+	UserID    *uuid.UUID          `json:"user_id,omitempty"` // Empty for system events
+	Comment   *string             `json:"comment,omitempty"`
+	Details   map[string]string   `json:"details,omitempty"` // e.g. assigned_to, resolution_code
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// NewAssetAlertEvent creates a timeline entry for an alert
+func NewAssetAlertEvent(alert *AssetAlert, eventType AssetAlertEventType, userID *uuid.UUID) *AssetAlertEvent {
+	return &AssetAlertEvent{
+		ID:        uuid.New(),
+		AlertID:   alert.ID,
+		TenantID:  alert.TenantID,
+		Type:      eventType,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+}
@@ -57,22 +57,33 @@ func (s *AssetSensor) GetConfiguration() (map[string]interface{}, error) {
 }
 
 // UpdateLastReadingValues updates the sensor's last reading values with multiple measurements
-func (s *AssetSensor) UpdateLastReadingValues(readings map[string]interface{}) error {
+// taken at readingTime. The last reading only moves forward: values older than the current
+// last reading are ignored and updated reports false.
+func (s *AssetSensor) UpdateLastReadingValues(readings map[string]interface{}, readingTime time.Time) (updated bool, err error) {
+	if s.IsLateReading(readingTime) {
+		return false, nil
+	}
+
 	readingsJSON, err := json.Marshal(readings)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	s.LastReadingValues = readingsJSON
-	now := time.Now()
-	s.LastReadingTime = &now
+	s.LastReadingTime = &readingTime
 
 	// If a primary reading value is provided, update the simple LastReadingValue field too
 	if primaryValue, ok := readings["primary"].(float64); ok {
 		s.LastReadingValue = &primaryValue
 	}
 
-	return nil
+	return true, nil
+}
+
+// IsLateReading reports whether a reading taken at readingTime is older than the
+// sensor's last reading, i.e. arrived late or out of order
+func (s *AssetSensor) IsLateReading(readingTime time.Time) bool {
+	return s.LastReadingTime != nil && readingTime.Before(*s.LastReadingTime)
 }
 
 // GetLastReadingValues parses the last reading values into a map
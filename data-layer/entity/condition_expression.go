@@ -0,0 +1,594 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ConditionReference names a measurement field used in a condition expression. Sensor
+// is the alias of an asset sensor, or empty for the condition's default sensor.
+type ConditionReference struct {
+	Sensor string
+	Field  string
+}
+
+// String returns the reference as written in an expression
+func (r ConditionReference) String() string {
+	if r.Sensor == "" {
+		return r.Field
+	}
+	return r.Sensor + "." + r.Field
+}
+
+// ConditionOperator compares two operands of a condition expression
+type ConditionOperator string
+
+const (
+	ConditionOpEqual          ConditionOperator = "=="
+	ConditionOpNotEqual       ConditionOperator = "!="
+	ConditionOpLess           ConditionOperator = "<"
+	ConditionOpLessOrEqual    ConditionOperator = "<="
+	ConditionOpGreater        ConditionOperator = ">"
+	ConditionOpGreaterOrEqual ConditionOperator = ">="
+)
+
+// MaxConditionExpressionLength bounds the size of a condition expression
+const MaxConditionExpressionLength = 1000
+
+// ConditionExpression is a parsed boolean condition over measurement fields, e.g.
+// "temperature > 30 AND humidity < 20" or "probe.ph < 6.5 OR NOT door_closed".
+//
+// Comparisons use ==, !=, <, <=, > and >= (= and <> are accepted as well) between field
+// references and number, 'string' or true/false literals; numbers may be ordered, strings
+// and booleans only tested for equality. A boolean field on its own is true when the field
+// is. Comparisons combine with AND, OR and NOT (or &&, || and !) and parentheses.
+type ConditionExpression struct {
+	source string
+	root   conditionNode
+}
+
+// ParseConditionExpression parses a condition expression
+func ParseConditionExpression(source string) (*ConditionExpression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("expression is required")
+	}
+	if len(source) > MaxConditionExpressionLength {
+		return nil, fmt.Errorf("expression must not be longer than %d characters", MaxConditionExpressionLength)
+	}
+
+	tokens, err := tokenizeCondition(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &conditionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != conditionTokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos+1)
+	}
+
+	return &ConditionExpression{source: source, root: root}, nil
+}
+
+// String returns the expression as it was written
+func (e *ConditionExpression) String() string {
+	return e.source
+}
+
+// References returns the distinct field references in order of appearance
+func (e *ConditionExpression) References() []ConditionReference {
+	var refs []ConditionReference
+	e.root.collect(&refs)
+
+	seen := make(map[ConditionReference]bool, len(refs))
+	unique := refs[:0]
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+	return unique
+}
+
+// Check verifies that every reference has a known type and that every comparison is
+// valid for the types involved
+func (e *ConditionExpression) Check(types map[ConditionReference]MeasurementDataType) error {
+	return e.root.check(types)
+}
+
+// Evaluate evaluates the expression against field values, which must be float64, string
+// or bool. Every referenced field must have a value.
+func (e *ConditionExpression) Evaluate(values map[ConditionReference]interface{}) (bool, error) {
+	return e.root.eval(values)
+}
+
+// conditionNode is a node of a parsed condition expression
+type conditionNode interface {
+	eval(values map[ConditionReference]interface{}) (bool, error)
+	collect(refs *[]ConditionReference)
+	check(types map[ConditionReference]MeasurementDataType) error
+}
+
+// conditionLogical combines two nodes with AND or OR
+type conditionLogical struct {
+	and         bool
+	left, right conditionNode
+}
+
+func (n *conditionLogical) eval(values map[ConditionReference]interface{}) (bool, error) {
+	left, err := n.left.eval(values)
+	if err != nil {
+		return false, err
+	}
+	if left != n.and {
+		// false AND x, true OR x
+		return left, nil
+	}
+	return n.right.eval(values)
+}
+
+func (n *conditionLogical) collect(refs *[]ConditionReference) {
+	n.left.collect(refs)
+	n.right.collect(refs)
+}
+
+func (n *conditionLogical) check(types map[ConditionReference]MeasurementDataType) error {
+	if err := n.left.check(types); err != nil {
+		return err
+	}
+	return n.right.check(types)
+}
+
+// conditionNot negates a node
+type conditionNot struct {
+	operand conditionNode
+}
+
+func (n *conditionNot) eval(values map[ConditionReference]interface{}) (bool, error) {
+	result, err := n.operand.eval(values)
+	if err != nil {
+		return false, err
+	}
+	return !result, nil
+}
+
+func (n *conditionNot) collect(refs *[]ConditionReference) {
+	n.operand.collect(refs)
+}
+
+func (n *conditionNot) check(types map[ConditionReference]MeasurementDataType) error {
+	return n.operand.check(types)
+}
+
+// conditionOperand is a field reference or a literal value
+type conditionOperand struct {
+	ref   *ConditionReference
+	value interface{}
+}
+
+func (o conditionOperand) String() string {
+	if o.ref != nil {
+		return o.ref.String()
+	}
+	if s, ok := o.value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(o.value)
+}
+
+func (o conditionOperand) resolve(values map[ConditionReference]interface{}) (interface{}, error) {
+	if o.ref == nil {
+		return o.value, nil
+	}
+	value, ok := values[*o.ref]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("no value for %s", o.ref)
+	}
+	return value, nil
+}
+
+func (o conditionOperand) dataType(types map[ConditionReference]MeasurementDataType) (MeasurementDataType, error) {
+	if o.ref != nil {
+		dataType, ok := types[*o.ref]
+		if !ok {
+			return "", fmt.Errorf("unknown field %s", o.ref)
+		}
+		return dataType, nil
+	}
+	switch o.value.(type) {
+	case float64:
+		return MeasurementDataTypeNumber, nil
+	case bool:
+		return MeasurementDataTypeBoolean, nil
+	default:
+		return MeasurementDataTypeString, nil
+	}
+}
+
+// conditionComparison compares two operands
+type conditionComparison struct {
+	left, right conditionOperand
+	op          ConditionOperator
+}
+
+func (n *conditionComparison) eval(values map[ConditionReference]interface{}) (bool, error) {
+	left, err := n.left.resolve(values)
+	if err != nil {
+		return false, err
+	}
+	right, err := n.right.resolve(values)
+	if err != nil {
+		return false, err
+	}
+	return compareConditionValues(left, right, n.op)
+}
+
+func (n *conditionComparison) collect(refs *[]ConditionReference) {
+	for _, operand := range []conditionOperand{n.left, n.right} {
+		if operand.ref != nil {
+			*refs = append(*refs, *operand.ref)
+		}
+	}
+}
+
+func (n *conditionComparison) check(types map[ConditionReference]MeasurementDataType) error {
+	left, err := n.left.dataType(types)
+	if err != nil {
+		return err
+	}
+	right, err := n.right.dataType(types)
+	if err != nil {
+		return err
+	}
+
+	for _, dataType := range []MeasurementDataType{left, right} {
+		switch dataType {
+		case MeasurementDataTypeNumber, MeasurementDataTypeString, MeasurementDataTypeBoolean:
+		default:
+			return fmt.Errorf("%s values cannot be compared in %s %s %s", dataType, n.left, n.op, n.right)
+		}
+	}
+	if left != right {
+		return fmt.Errorf("cannot compare %s with %s in %s %s %s", left, right, n.left, n.op, n.right)
+	}
+	if left != MeasurementDataTypeNumber && n.op != ConditionOpEqual && n.op != ConditionOpNotEqual {
+		return fmt.Errorf("%s values only support == and != in %s %s %s", left, n.left, n.op, n.right)
+	}
+	return nil
+}
+
+// compareConditionValues compares two float64, string or bool values
+func compareConditionValues(left, right interface{}, op ConditionOperator) (bool, error) {
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			break
+		}
+		switch op {
+		case ConditionOpEqual:
+			return l == r, nil
+		case ConditionOpNotEqual:
+			return l != r, nil
+		case ConditionOpLess:
+			return l < r, nil
+		case ConditionOpLessOrEqual:
+			return l <= r, nil
+		case ConditionOpGreater:
+			return l > r, nil
+		case ConditionOpGreaterOrEqual:
+			return l >= r, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			switch op {
+			case ConditionOpEqual:
+				return l == r, nil
+			case ConditionOpNotEqual:
+				return l != r, nil
+			}
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			switch op {
+			case ConditionOpEqual:
+				return l == r, nil
+			case ConditionOpNotEqual:
+				return l != r, nil
+			}
+		}
+	}
+	return false, fmt.Errorf("cannot compare %v %s %v", left, op, right)
+}
+
+// conditionTokenKind is the kind of a lexical token
+type conditionTokenKind int
+
+const (
+	conditionTokenEOF conditionTokenKind = iota
+	conditionTokenIdent
+	conditionTokenNumber
+	conditionTokenString
+	conditionTokenOperator
+	conditionTokenAnd
+	conditionTokenOr
+	conditionTokenNot
+	conditionTokenTrue
+	conditionTokenFalse
+	conditionTokenLParen
+	conditionTokenRParen
+)
+
+// conditionToken is a lexical token of a condition expression
+type conditionToken struct {
+	kind  conditionTokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+func (t conditionToken) String() string {
+	if t.kind == conditionTokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// tokenizeCondition splits an expression into tokens
+func tokenizeCondition(source string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+
+		case c == '(' || c == ')':
+			kind := conditionTokenLParen
+			if c == ')' {
+				kind = conditionTokenRParen
+			}
+			tokens = append(tokens, conditionToken{kind: kind, text: string(c), pos: start})
+			i++
+			continue
+
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != c {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, conditionToken{kind: conditionTokenString, text: string(runes[start:i]), value: b.String(), pos: start})
+			continue
+
+		case unicode.IsDigit(c) || ((c == '-' || c == '.') && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, start+1)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenNumber, text: text, value: value, pos: start})
+			continue
+
+		case unicode.IsLetter(c) || c == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			tok := conditionToken{kind: conditionTokenIdent, text: text, pos: start}
+			switch strings.ToLower(text) {
+			case "and":
+				tok.kind = conditionTokenAnd
+			case "or":
+				tok.kind = conditionTokenOr
+			case "not":
+				tok.kind = conditionTokenNot
+			case "true":
+				tok.kind, tok.value = conditionTokenTrue, true
+			case "false":
+				tok.kind, tok.value = conditionTokenFalse, false
+			}
+			tokens = append(tokens, tok)
+			continue
+		}
+
+		// Operators, longest first
+		two := ""
+		if i+1 < len(runes) {
+			two = string(runes[i : i+2])
+		}
+		switch two {
+		case "==", "!=", "<=", ">=":
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: two, value: ConditionOperator(two), pos: start})
+			i += 2
+			continue
+		case "<>":
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: two, value: ConditionOpNotEqual, pos: start})
+			i += 2
+			continue
+		case "&&":
+			tokens = append(tokens, conditionToken{kind: conditionTokenAnd, text: two, pos: start})
+			i += 2
+			continue
+		case "||":
+			tokens = append(tokens, conditionToken{kind: conditionTokenOr, text: two, pos: start})
+			i += 2
+			continue
+		}
+
+		switch c {
+		case '<', '>':
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: string(c), value: ConditionOperator(string(c)), pos: start})
+		case '=':
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: "=", value: ConditionOpEqual, pos: start})
+		case '!':
+			tokens = append(tokens, conditionToken{kind: conditionTokenNot, text: "!", pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, start+1)
+		}
+		i++
+	}
+
+	tokens = append(tokens, conditionToken{kind: conditionTokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// conditionParser is a recursive descent parser over condition tokens
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	depth  int
+}
+
+// maxConditionDepth bounds nesting of parentheses and NOT
+const maxConditionDepth = 32
+
+func (p *conditionParser) peek() conditionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() conditionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != conditionTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses: and { OR and }
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == conditionTokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &conditionLogical{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary { AND unary }
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == conditionTokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &conditionLogical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: NOT unary | ( or ) | comparison
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxConditionDepth {
+		return nil, fmt.Errorf("expression is nested too deeply")
+	}
+
+	switch tok := p.peek(); tok.kind {
+	case conditionTokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &conditionNot{operand: operand}, nil
+
+	case conditionTokenLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != conditionTokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d, found %s", closing.pos+1, closing)
+		}
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses: operand [ operator operand ]. A lone operand is compared
+// with true, so it must be boolean.
+func (p *conditionParser) parseComparison() (conditionNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != conditionTokenOperator {
+		return &conditionComparison{left: left, op: ConditionOpEqual, right: conditionOperand{value: true}}, nil
+	}
+
+	op := p.next().value.(ConditionOperator)
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionComparison{left: left, op: op, right: right}, nil
+}
+
+// parseOperand parses a field reference or a literal
+func (p *conditionParser) parseOperand() (conditionOperand, error) {
+	tok := p.next()
+	switch tok.kind {
+	case conditionTokenNumber, conditionTokenString, conditionTokenTrue, conditionTokenFalse:
+		return conditionOperand{value: tok.value}, nil
+	case conditionTokenIdent:
+		ref, err := parseConditionReference(tok)
+		if err != nil {
+			return conditionOperand{}, err
+		}
+		return conditionOperand{ref: &ref}, nil
+	}
+	return conditionOperand{}, fmt.Errorf("expected a field or value at position %d, found %s", tok.pos+1, tok)
+}
+
+// parseConditionReference splits "field" or "sensor.field"
+func parseConditionReference(tok conditionToken) (ConditionReference, error) {
+	parts := strings.Split(tok.text, ".")
+	switch {
+	case len(parts) == 1:
+		return ConditionReference{Field: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return ConditionReference{Sensor: parts[0], Field: parts[1]}, nil
+	}
+	return ConditionReference{}, fmt.Errorf("invalid field reference %q at position %d", tok.text, tok.pos+1)
+}
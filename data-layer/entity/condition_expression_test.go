@@ -0,0 +1,227 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+// conditionValues builds evaluation values keyed by the written form of each reference
+func conditionValues(values map[string]interface{}) map[ConditionReference]interface{} {
+	refs := make(map[ConditionReference]interface{}, len(values))
+	for name, value := range values {
+		ref := ConditionReference{Field: name}
+		if sensor, field, ok := strings.Cut(name, "."); ok {
+			ref = ConditionReference{Sensor: sensor, Field: field}
+		}
+		refs[ref] = value
+	}
+	return refs
+}
+
+func TestConditionExpressionEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		values map[string]interface{}
+		want   bool
+	}{
+		{"number comparison", "temperature > 30", map[string]interface{}{"temperature": 31.0}, true},
+		{"number at bound", "temperature >= 30", map[string]interface{}{"temperature": 30.0}, true},
+		{"negative and exponent literals", "temperature < -1.5e1", map[string]interface{}{"temperature": -20.0}, true},
+		{"literal on the left", "30 < temperature", map[string]interface{}{"temperature": 25.0}, false},
+		{"single equals", "mode = 'auto'", map[string]interface{}{"mode": "auto"}, true},
+		{"angle not equal", "mode <> 'auto'", map[string]interface{}{"mode": "auto"}, false},
+		{"escaped quote", `label == 'it\'s'`, map[string]interface{}{"label": "it's"}, true},
+		{"lone boolean field", "door_open", map[string]interface{}{"door_open": true}, true},
+		{"boolean literal", "door_open == false", map[string]interface{}{"door_open": true}, false},
+		{"sensor alias", "probe.ph < 6.5", map[string]interface{}{"probe.ph": 6.0}, true},
+		{"keywords are case insensitive", "a and not b", map[string]interface{}{"a": true, "b": false}, true},
+		{"symbolic operators", "a && !b || c", map[string]interface{}{"a": false, "b": false, "c": true}, true},
+
+		// AND binds tighter than OR: a OR (b AND c), not (a OR b) AND c
+		{"and before or", "a OR b AND c", map[string]interface{}{"a": true, "b": false, "c": false}, true},
+		{"and before or on the right", "a AND b OR c", map[string]interface{}{"a": false, "b": true, "c": true}, true},
+		// NOT binds tighter than AND: (NOT a) AND b, not NOT (a AND b)
+		{"not before and", "NOT a AND b", map[string]interface{}{"a": false, "b": false}, false},
+		{"double not", "NOT NOT a", map[string]interface{}{"a": true}, true},
+
+		{"parentheses override precedence", "(a OR b) AND c", map[string]interface{}{"a": true, "b": false, "c": false}, false},
+		{"not of a group", "NOT (a AND b)", map[string]interface{}{"a": true, "b": false}, true},
+		{"nested parentheses", "((temperature > 30) AND (humidity < 20 OR fan))",
+			map[string]interface{}{"temperature": 35.0, "humidity": 50.0, "fan": true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := ParseConditionExpression(tt.source)
+			if err != nil {
+				t.Fatalf("ParseConditionExpression(%q) returned error: %v", tt.source, err)
+			}
+			got, err := expression.Evaluate(conditionValues(tt.values))
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%q = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionExpressionRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"empty", ""},
+		{"blank", "   "},
+		{"too long", "a > " + strings.Repeat("1", MaxConditionExpressionLength)},
+		{"missing right operand", "temperature >"},
+		{"missing left operand", "> 30"},
+		{"dangling and", "a AND"},
+		{"leading or", "OR a"},
+		{"dangling not", "NOT"},
+		{"unclosed parenthesis", "(a AND b"},
+		{"unopened parenthesis", "a AND b)"},
+		{"empty parentheses", "()"},
+		{"two operators", "a > < 3"},
+		{"chained comparison", "1 < a < 3"},
+		{"two operands", "a b"},
+		{"unterminated string", "mode == 'auto"},
+		{"trailing backslash in string", `mode == 'auto\`},
+		{"invalid number", "a > 1.2.3"},
+		{"incomplete exponent", "a > 1e"},
+		{"unexpected character", "a > 3 # comment"},
+		{"empty sensor alias", ".ph < 7"},
+		{"empty field", "probe. < 7"},
+		{"too many dots", "site.probe.ph < 7"},
+		{"nested too deeply", strings.Repeat("(", maxConditionDepth+1) + "a" + strings.Repeat(")", maxConditionDepth+1)},
+		{"not nested too deeply", strings.Repeat("NOT ", maxConditionDepth+1) + "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if expression, err := ParseConditionExpression(tt.source); err == nil {
+				t.Errorf("ParseConditionExpression(%q) = %v, want an error", tt.source, expression.root)
+			}
+		})
+	}
+}
+
+func TestConditionExpressionPrefixesNeverPanic(t *testing.T) {
+	// Every prefix of a valid expression stops somewhere in the middle of a token or rule
+	source := `NOT (probe.temperature >= -1.5e1 AND mode <> 'a\'b') || (door_open && humidity < 20)`
+	for i := 0; i <= len(source); i++ {
+		expression, err := ParseConditionExpression(source[:i])
+		if err != nil {
+			continue
+		}
+		// Prefixes that parse must evaluate, or fail, without panicking too
+		expression.Evaluate(nil)
+	}
+}
+
+func TestConditionExpressionReferences(t *testing.T) {
+	expression, err := ParseConditionExpression("a > 1 AND probe.b < 2 OR a < 0 OR NOT c")
+	if err != nil {
+		t.Fatalf("ParseConditionExpression returned error: %v", err)
+	}
+
+	want := []ConditionReference{{Field: "a"}, {Sensor: "probe", Field: "b"}, {Field: "c"}}
+	got := expression.References()
+	if len(got) != len(want) {
+		t.Fatalf("References() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("References()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestConditionExpressionCheck(t *testing.T) {
+	types := map[ConditionReference]MeasurementDataType{
+		{Field: "temperature"}:             MeasurementDataTypeNumber,
+		{Field: "mode"}:                    MeasurementDataTypeString,
+		{Field: "door_open"}:               MeasurementDataTypeBoolean,
+		{Field: "readings"}:                MeasurementDataTypeArray,
+		{Sensor: "probe", Field: "ph"}:     MeasurementDataTypeNumber,
+		{Sensor: "probe", Field: "status"}: MeasurementDataTypeString,
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"valid", "temperature > 30 AND mode == 'auto' AND door_open AND probe.ph < 7", ""},
+		{"unknown field", "pressure > 30", "unknown field pressure"},
+		{"unknown field in a skipped branch", "door_open OR pressure > 30", "unknown field pressure"},
+		{"unknown sensor alias", "tank.ph < 7", "unknown field tank.ph"},
+		{"field known only on another sensor", "ph < 7", "unknown field ph"},
+		{"number against string", "temperature == 'hot'", "cannot compare number with string"},
+		{"lone number field", "temperature", "cannot compare number with boolean"},
+		{"ordered string comparison", "mode < 'b'", "only support == and !="},
+		{"ordered boolean comparison", "door_open > false", "only support == and !="},
+		{"array field", "readings == 'x'", "array values cannot be compared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := ParseConditionExpression(tt.source)
+			if err != nil {
+				t.Fatalf("ParseConditionExpression(%q) returned error: %v", tt.source, err)
+			}
+			err = expression.Check(types)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check(%q) returned error: %v", tt.source, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check(%q) = %v, want an error containing %q", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionExpressionEvaluateMissingValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		values  map[string]interface{}
+		want    bool
+		wantErr bool
+	}{
+		{"missing field", "temperature > 30", nil, false, true},
+		{"nil value", "temperature > 30", map[string]interface{}{"temperature": nil}, false, true},
+		{"missing field under not", "NOT door_open", nil, false, true},
+		{"missing right field", "temperature > limit", map[string]interface{}{"temperature": 31.0}, false, true},
+		{"missing field on the evaluated side of and", "temperature > 30 AND humidity < 20",
+			map[string]interface{}{"temperature": 31.0}, false, true},
+		// AND and OR stop at the left operand when it decides the result
+		{"missing field after a false and", "temperature > 30 AND humidity < 20",
+			map[string]interface{}{"temperature": 25.0}, false, false},
+		{"missing field after a true or", "temperature > 30 OR humidity < 20",
+			map[string]interface{}{"temperature": 31.0}, true, false},
+		{"value of another type", "temperature > 30", map[string]interface{}{"temperature": "hot"}, false, true},
+		{"value of an unsupported type", "temperature > 30", map[string]interface{}{"temperature": 31}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := ParseConditionExpression(tt.source)
+			if err != nil {
+				t.Fatalf("ParseConditionExpression(%q) returned error: %v", tt.source, err)
+			}
+			got, err := expression.Evaluate(conditionValues(tt.values))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate(%q) error = %v, want error %v", tt.source, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
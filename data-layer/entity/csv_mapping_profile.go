@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Timestamp formats of a CSV mapping profile besides Go time layouts
+const (
+	CSVTimestampRFC3339    = TimestampRFC3339 // The default
+	CSVTimestampUnix       = TimestampUnix
+	CSVTimestampUnixMillis = TimestampUnixMillis
+)
+
+// CSVColumnMapping maps a CSV column to a measurement field
+type CSVColumnMapping struct {
+	Column string `json:"column"`         // Header of the column
+	Field  string `json:"field"`          // Measurement field of the profile's sensor type
+	Unit   string `json:"unit,omitempty"` // Unit of the column's values, defaults to the field's unit
+}
+
+// CSVMappingProfile describes how the CSV exports of a sensor type map to readings. Columns
+// are found by their header, so exports may add, drop or reorder other columns.
+type CSVMappingProfile struct {
+	ID                uuid.UUID          `json:"id"`
+	SensorTypeID      uuid.UUID          `json:"sensor_type_id"`
+	Name              string             `json:"name"`
+	Description       *string            `json:"description,omitempty"`
+	Delimiter         string             `json:"delimiter"`
+	TimestampColumn   string             `json:"timestamp_column"`
+	TimestampFormat   string             `json:"timestamp_format"` // rfc3339, unix, unix_ms, auto or a Go time layout such as 2006-01-02 15:04:05
+	Timezone          string             `json:"timezone"`         // IANA time zone of timestamps without an offset
+	AssetSensorColumn *string            `json:"asset_sensor_column,omitempty"`
+	Columns           []CSVColumnMapping `json:"columns"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         *time.Time         `json:"updated_at,omitempty"`
+}
+
+// NewCSVMappingProfile creates a new profile for comma separated RFC3339 exports in UTC
+func NewCSVMappingProfile() *CSVMappingProfile {
+	return &CSVMappingProfile{
+		ID:              uuid.New(),
+		Delimiter:       ",",
+		TimestampFormat: CSVTimestampRFC3339,
+		Timezone:        "UTC",
+		CreatedAt:       time.Now(),
+	}
+}
+
+// Validate checks that the profile is complete and consistent. It doesn't check the
+// measurement fields, which belong to the sensor type.
+func (p *CSVMappingProfile) Validate() error {
+	if utf8.RuneCountInString(p.Delimiter) != 1 || p.Delimiter == "\"" || p.Delimiter == "\r" || p.Delimiter == "\n" {
+		return fmt.Errorf("delimiter must be a single character other than a quote or line break")
+	}
+	if strings.TrimSpace(p.TimestampColumn) == "" {
+		return fmt.Errorf("timestamp_column is required")
+	}
+	if err := ValidateTimestampFormat(p.TimestampFormat); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", p.Timezone)
+	}
+	if len(p.Columns) == 0 {
+		return fmt.Errorf("at least one column must be mapped to a measurement field")
+	}
+
+	columns := map[string]bool{p.TimestampColumn: true}
+	if p.AssetSensorColumn != nil {
+		if *p.AssetSensorColumn == "" || columns[*p.AssetSensorColumn] {
+			return fmt.Errorf("asset_sensor_column must be a separate column")
+		}
+		columns[*p.AssetSensorColumn] = true
+	}
+
+	fields := make(map[string]bool)
+	for i, mapping := range p.Columns {
+		if strings.TrimSpace(mapping.Column) == "" || strings.TrimSpace(mapping.Field) == "" {
+			return fmt.Errorf("column mapping %d needs a column and a field", i+1)
+		}
+		if columns[mapping.Column] {
+			return fmt.Errorf("column %s is mapped more than once", mapping.Column)
+		}
+		if fields[mapping.Field] {
+			return fmt.Errorf("field %s is mapped from more than one column", mapping.Field)
+		}
+		columns[mapping.Column] = true
+		fields[mapping.Field] = true
+	}
+
+	return nil
+}
+
+// ParseTimestamp parses a timestamp cell. Timestamps without an offset are in the
+// profile's time zone.
+func (p *CSVMappingProfile) ParseTimestamp(value string) (time.Time, error) {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timezone %q", p.Timezone)
+	}
+	return ParseTimestamp(strings.TrimSpace(value), p.TimestampFormat, location)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceAPIKey is a credential used by field devices and gateways to push
+// sensor readings. Only a hash of the key is stored; the plaintext key is
+// returned once when the key is issued or rotated.
+type DeviceAPIKey struct {
+	ID            uuid.UUID  `json:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	AssetSensorID *uuid.UUID `json:"asset_sensor_id,omitempty"` // Nil for gateway keys (any sensor in the tenant)
+	Name          string     `json:"name"`
+	KeyPrefix     string     `json:"key_prefix"` // Public part of the key, used for lookup
+	KeyHash       string     `json:"-"`          // SHA-256 of the full key
+	IsActive      bool       `json:"is_active"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	RotatedAt     *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewDeviceAPIKey creates a new device API key with default values
+func NewDeviceAPIKey() *DeviceAPIKey {
+	return &DeviceAPIKey{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsGatewayKey reports whether the key may write readings for any sensor in its tenant
+func (k *DeviceAPIKey) IsGatewayKey() bool {
+	return k.AssetSensorID == nil
+}
+
+// IsUsable reports whether the key is active, not revoked and not expired
+func (k *DeviceAPIKey) IsUsable(now time.Time) bool {
+	if !k.IsActive || k.RevokedAt != nil {
+		return false
+	}
+	if k.ExpiresAt != nil && now.After(*k.ExpiresAt) {
+		return false
+	}
+	return true
+}
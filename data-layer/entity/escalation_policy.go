@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationStep is one level of an escalation policy. It fires once an alert has
+// stayed open and unacknowledged for DelayMinutes since it was raised.
+type EscalationStep struct {
+	DelayMinutes int         `json:"delay_minutes"`
+	ChannelIDs   []uuid.UUID `json:"channel_ids,omitempty"` // Notification channels to alert
+	UserIDs      []uuid.UUID `json:"user_ids,omitempty"`    // Users to escalate to; the first one is assigned if nobody is
+}
+
+// Delay returns how long after the alert was raised the step fires
+func (s EscalationStep) Delay() time.Duration {
+	return time.Duration(s.DelayMinutes) * time.Minute
+}
+
+// EscalationPolicy escalates open, unacknowledged alerts of a tenant step by step
+type EscalationPolicy struct {
+	ID          uuid.UUID           `json:"id"`
+	TenantID    uuid.UUID           `json:"tenant_id"`
+	Name        string              `json:"name"`
+	Description *string             `json:"description,omitempty"`
+	Severities  []ThresholdSeverity `json:"severities"` // Alert severities the policy applies to
+	Steps       []EscalationStep    `json:"steps"`      // Ordered by increasing delay
+	IsActive    bool                `json:"is_active"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   *time.Time          `json:"updated_at,omitempty"`
+}
+
+// NewEscalationPolicy creates a new escalation policy for critical alerts
+func NewEscalationPolicy() *EscalationPolicy {
+	return &EscalationPolicy{
+		ID:         uuid.New(),
+		Severities: []ThresholdSeverity{ThresholdSeverityCritical},
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// AppliesTo reports whether the policy escalates the given alert
+func (p *EscalationPolicy) AppliesTo(alert *AssetAlert) bool {
+	if !p.IsActive || alert.TenantID != p.TenantID {
+		return false
+	}
+	for _, severity := range p.Severities {
+		if severity == alert.Severity {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetAlertEscalation records an escalation step that fired for an alert
+type AssetAlertEscalation struct {
+	ID          uuid.UUID   `json:"id"`
+	AlertID     uuid.UUID   `json:"alert_id"`
+	TenantID    uuid.UUID   `json:"tenant_id"`
+	PolicyID    *uuid.UUID  `json:"policy_id,omitempty"` // Empty once the policy is deleted
+	PolicyName  string      `json:"policy_name"`
+	StepIndex   int         `json:"step_index"` // Zero-based
+	ChannelIDs  []uuid.UUID `json:"channel_ids"`
+	UserIDs     []uuid.UUID `json:"user_ids"`
+	EscalatedAt time.Time   `json:"escalated_at"`
+}
+
+// NewAssetAlertEscalation creates the escalation record of a policy step for an alert
+func NewAssetAlertEscalation(alert *AssetAlert, policy *EscalationPolicy, stepIndex int) *AssetAlertEscalation {
+	policyID := policy.ID
+	step := policy.Steps[stepIndex]
+	return &AssetAlertEscalation{
+		ID:          uuid.New(),
+		AlertID:     alert.ID,
+		TenantID:    alert.TenantID,
+		PolicyID:    &policyID,
+		PolicyName:  policy.Name,
+		StepIndex:   stepIndex,
+		ChannelIDs:  append([]uuid.UUID{}, step.ChannelIDs...),
+		UserIDs:     append([]uuid.UUID{}, step.UserIDs...),
+		EscalatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"fmt"
+)
+
+// IngestionPolicy decides what happens to readings that don't match the measurement
+// fields of their sensor type
+type IngestionPolicy string
+
+const (
+	// IngestionPolicyStrict rejects a message with any violation and quarantines it whole
+	IngestionPolicyStrict IngestionPolicy = "strict"
+	// IngestionPolicyWarn stores the valid measurements of a message and quarantines the others
+	IngestionPolicyWarn IngestionPolicy = "warn"
+	// IngestionPolicyPermissive stores every measurement as sent and only reports violations
+	IngestionPolicyPermissive IngestionPolicy = "permissive"
+)
+
+// DefaultIngestionPolicy is the policy of sensor types that don't set one
+const DefaultIngestionPolicy = IngestionPolicyWarn
+
+// IsValid reports whether the policy is known
+func (p IngestionPolicy) IsValid() bool {
+	switch p {
+	case IngestionPolicyStrict, IngestionPolicyWarn, IngestionPolicyPermissive:
+		return true
+	}
+	return false
+}
+
+// SchemaViolationCode identifies the kind of a schema violation
+type SchemaViolationCode string
+
+const (
+	ViolationUnknownField SchemaViolationCode = "unknown_field" // The sensor type has no such field
+	ViolationMissingField SchemaViolationCode = "missing_field" // A required field wasn't sent
+	ViolationInvalidType  SchemaViolationCode = "invalid_type"  // The value doesn't match the field's data type
+	ViolationInvalidUnit  SchemaViolationCode = "invalid_unit"  // The unit can't be converted to the field's unit
+	ViolationOutOfRange   SchemaViolationCode = "out_of_range"  // The value is outside the field's min/max
+)
+
+// SchemaViolation describes how a measurement doesn't match its field
+type SchemaViolation struct {
+	Field   string              `json:"field"`
+	Code    SchemaViolationCode `json:"code"`
+	Message string              `json:"message"`
+}
+
+// Error implements the error interface
+func (v *SchemaViolation) Error() string {
+	return v.Message
+}
+
+func newSchemaViolation(field string, code SchemaViolationCode, format string, args ...interface{}) *SchemaViolation {
+	return &SchemaViolation{Field: field, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// UnknownFieldViolation reports a measurement the sensor type has no field for
+func UnknownFieldViolation(field string) *SchemaViolation {
+	return newSchemaViolation(field, ViolationUnknownField, "unknown measurement field %s", field)
+}
+
+// MissingFieldViolation reports a required field that wasn't sent
+func MissingFieldViolation(field string) *SchemaViolation {
+	return newSchemaViolation(field, ViolationMissingField, "required field %s is missing", field)
+}
+
+// SetFieldValue stores a value in the reading column of the field's data type, converting
+// numbers from the reading's unit to the field's unit and checking the field's range. On
+// a unit or range violation the value is still set, as sent or converted respectively.
+func (r *IoTSensorReadingFlexible) SetFieldValue(field SensorMeasurementField, value interface{}) *SchemaViolation {
+	r.NumericValue, r.TextValue, r.BooleanValue = nil, nil, nil
+
+	switch field.DataType {
+	case MeasurementDataTypeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return newSchemaViolation(field.Name, ViolationInvalidType, "%s must be a number", field.Name)
+		}
+		r.NumericValue = &number
+
+	case MeasurementDataTypeBoolean:
+		boolean, ok := value.(bool)
+		if !ok {
+			return newSchemaViolation(field.Name, ViolationInvalidType, "%s must be a boolean", field.Name)
+		}
+		r.BooleanValue = &boolean
+
+	case MeasurementDataTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return newSchemaViolation(field.Name, ViolationInvalidType, "%s must be a string", field.Name)
+		}
+		r.TextValue = &text
+
+	default:
+		return newSchemaViolation(field.Name, ViolationInvalidType, "%s is a %s field, which can't be stored as a reading", field.Name, field.DataType)
+	}
+
+	// The range of a field is in its unit, so check it after the conversion
+	if err := r.ConvertToCanonicalUnit(field.Unit); err != nil {
+		return newSchemaViolation(field.Name, ViolationInvalidUnit, "%v", err)
+	}
+	if r.NumericValue != nil {
+		number := *r.NumericValue
+		if field.Min != nil && number < *field.Min {
+			return newSchemaViolation(field.Name, ViolationOutOfRange, "%s is below the minimum of %g", field.Name, *field.Min)
+		}
+		if field.Max != nil && number > *field.Max {
+			return newSchemaViolation(field.Name, ViolationOutOfRange, "%s is above the maximum of %g", field.Name, *field.Max)
+		}
+	}
+
+	return nil
+}
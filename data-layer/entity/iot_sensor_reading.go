@@ -38,6 +38,19 @@ type IoTSensorReadingFlexible struct {
 	DataSource        *string `json:"data_source" db:"data_source"`                 // 'json', 'text', 'csv'
 	OriginalFieldName *string `json:"original_field_name" db:"original_field_name"` // Original field name
 
+	// Value and unit as sent, when the value was converted to the field's canonical unit
+	OriginalValue *float64 `json:"original_value,omitempty" db:"original_value"`
+	OriginalUnit  *string  `json:"original_unit,omitempty" db:"original_unit"`
+
+	// IsLate is set when the reading arrived after a newer reading of its asset sensor
+	IsLate bool `json:"is_late" db:"is_late"`
+
+	// Timestamp as sent and the clock offset applied, when the reading is stored at
+	// another time, and the anomaly of its time against the receive time
+	DeviceTime    *time.Time `json:"device_time,omitempty" db:"device_time"`
+	ClockOffsetMs *int64     `json:"clock_offset_ms,omitempty" db:"clock_offset_ms"`
+	TimeAnomaly   *string    `json:"time_anomaly,omitempty" db:"time_anomaly"`
+
 	ReadingTime time.Time  `json:"reading_time" db:"reading_time"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at" db:"updated_at"`
@@ -54,14 +67,15 @@ type MeasurementData struct {
 
 // FlexibleReadingRequest represents the request format for flexible IoT sensor readings
 type FlexibleReadingRequest struct {
-	AssetSensorID uuid.UUID              `json:"asset_sensor_id"`
-	SensorTypeID  uuid.UUID              `json:"sensor_type_id"`
-	MacAddress    *string                `json:"mac_address"`
-	LocationID    *uuid.UUID             `json:"location_id"`
-	LocationName  *string                `json:"location_name"`
-	ReadingTime   *time.Time             `json:"reading_time"`
-	Measurements  map[string]interface{} `json:"-"`           // Will be populated by custom unmarshaling
-	DataSource    string                 `json:"data_source"` // 'json', 'text', 'csv'
+	AssetSensorID  uuid.UUID              `json:"asset_sensor_id"`
+	SensorTypeID   uuid.UUID              `json:"sensor_type_id"`
+	MacAddress     *string                `json:"mac_address"`
+	LocationID     *uuid.UUID             `json:"location_id"`
+	LocationName   *string                `json:"location_name"`
+	ReadingTime    *time.Time             `json:"reading_time"`
+	RawReadingTime interface{}            `json:"-"`           // reading_time when it isn't RFC3339
+	Measurements   map[string]interface{} `json:"-"`           // Will be populated by custom unmarshaling
+	DataSource     string                 `json:"data_source"` // 'json', 'text', 'csv'
 }
 
 // UnmarshalJSON custom unmarshaling for FlexibleReadingRequest
@@ -109,11 +123,12 @@ func (r *FlexibleReadingRequest) UnmarshalJSON(data []byte) error {
 		}
 	}
 
-	if val, ok := temp["reading_time"]; ok {
-		if str, ok := val.(string); ok {
-			if parsed, err := time.Parse(time.RFC3339, str); err == nil {
-				r.ReadingTime = &parsed
-			}
+	if val, ok := temp["reading_time"]; ok && val != nil {
+		str, isString := val.(string)
+		if parsed, err := time.Parse(time.RFC3339, str); isString && err == nil {
+			r.ReadingTime = &parsed
+		} else {
+			r.RawReadingTime = val
 		}
 	}
 
@@ -194,6 +209,100 @@ func (r *IoTSensorReadingFlexible) SetValue(value interface{}) error {
 	return nil
 }
 
+// ConvertToCanonicalUnit converts a numeric value to the canonical unit of its field,
+// keeping the value and unit as sent in OriginalValue and OriginalUnit. A reading without
+// a unit is taken to be in the canonical unit.
+func (r *IoTSensorReadingFlexible) ConvertToCanonicalUnit(canonicalUnit string) error {
+	unit := ""
+	if r.MeasurementUnit != nil {
+		unit = *r.MeasurementUnit
+	}
+
+	if r.NumericValue == nil {
+		// Only numbers are converted, other values just get the unit normalized
+		if strings.TrimSpace(unit) == "" {
+			unit = canonicalUnit
+		}
+		r.setUnit(NormalizeUnit(unit))
+		return nil
+	}
+
+	value, resultUnit, converted, err := ToCanonicalUnit(*r.NumericValue, unit, canonicalUnit)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.MeasurementType, err)
+	}
+	if converted {
+		original, originalUnit := *r.NumericValue, strings.TrimSpace(unit)
+		r.OriginalValue = &original
+		r.OriginalUnit = &originalUnit
+		r.NumericValue = &value
+	}
+	r.setUnit(resultUnit)
+	return nil
+}
+
+func (r *IoTSensorReadingFlexible) setUnit(unit string) {
+	if unit == "" {
+		r.MeasurementUnit = nil
+		return
+	}
+	r.MeasurementUnit = &unit
+}
+
+// ReadingAggregate summarises the numeric readings of one measurement stored in one unit
+// within a time bucket, and within a group when the aggregation is grouped
+type ReadingAggregate struct {
+	Bucket          time.Time
+	GroupID         string // ID of the group, or the field name when grouping by measurement field
+	GroupName       string
+	MeasurementType string
+	Unit            string
+	Count           int64
+	Sum             float64
+	Min             float64
+	Max             float64
+	Percentiles     []float64 // In the order of ReadingAggregateQuery.Percentiles
+}
+
+// Groupings of a reading aggregation
+const (
+	AggregateGroupAssetSensor      = "asset_sensor"
+	AggregateGroupAsset            = "asset"
+	AggregateGroupAssetType        = "asset_type"
+	AggregateGroupLocation         = "location"
+	AggregateGroupSensorType       = "sensor_type"
+	AggregateGroupMeasurementField = "measurement_field"
+)
+
+// IsValidAggregateGroup checks whether groupBy is a grouping of a reading aggregation
+func IsValidAggregateGroup(groupBy string) bool {
+	switch groupBy {
+	case AggregateGroupAssetSensor, AggregateGroupAsset, AggregateGroupAssetType,
+		AggregateGroupLocation, AggregateGroupSensorType, AggregateGroupMeasurementField:
+		return true
+	}
+	return false
+}
+
+// ReadingAggregateQuery selects the numeric readings summarised by a reading aggregation.
+// Nil filters match every reading.
+type ReadingAggregateQuery struct {
+	TenantID         *uuid.UUID
+	AssetSensorID    *uuid.UUID
+	AssetID          *uuid.UUID
+	AssetTypeID      *uuid.UUID
+	LocationID       *uuid.UUID
+	SensorTypeID     *uuid.UUID
+	MeasurementTypes []string // Empty for every measurement
+	FromTime         time.Time
+	ToTime           time.Time
+	Interval         AggregateInterval
+	Location         *time.Location // Time zone buckets are aligned to, UTC when nil
+	GroupBy          string         // One of the AggregateGroup constants, empty for a single series
+	Percentiles      []float64      // Fractions between 0 and 1, which rollups can't serve
+	Rollup           *ReadingRollupRange
+}
+
 // ParseMeasurementData parses measurement data from interface{} into structured format
 func ParseMeasurementData(fieldName string, value interface{}) (*MeasurementData, error) {
 	measurement := &MeasurementData{
@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceRecurrence defines how often a maintenance window repeats
+type MaintenanceRecurrence string
+
+const (
+	MaintenanceRecurrenceNone    MaintenanceRecurrence = "none" // One-off window
+	MaintenanceRecurrenceDaily   MaintenanceRecurrence = "daily"
+	MaintenanceRecurrenceWeekly  MaintenanceRecurrence = "weekly"
+	MaintenanceRecurrenceMonthly MaintenanceRecurrence = "monthly" // Same day of month as the first occurrence
+)
+
+// MaintenanceAlertMode defines what happens to alerts raised during a maintenance window
+type MaintenanceAlertMode string
+
+const (
+	MaintenanceAlertSuppress MaintenanceAlertMode = "suppress" // No new alert is raised, open alerts still update and resolve
+	MaintenanceAlertTag      MaintenanceAlertMode = "tag"      // Alerts are raised and tagged with the window, without notifications or escalation
+)
+
+// MaintenanceWindow is a scheduled period during which alerts of the covered assets
+// are suppressed or tagged. A window covers the assets matching any of its scopes.
+type MaintenanceWindow struct {
+	ID              uuid.UUID             `json:"id"`
+	TenantID        uuid.UUID             `json:"tenant_id"`
+	Name            string                `json:"name"`
+	Description     *string               `json:"description,omitempty"`
+	AssetID         *uuid.UUID            `json:"asset_id,omitempty"`
+	LocationID      *uuid.UUID            `json:"location_id,omitempty"`
+	AssetTypeID     *uuid.UUID            `json:"asset_type_id,omitempty"`
+	StartTime       time.Time             `json:"start_time"` // Start of the first occurrence
+	EndTime         time.Time             `json:"end_time"`   // End of the first occurrence
+	Recurrence      MaintenanceRecurrence `json:"recurrence"`
+	RecurrenceUntil *time.Time            `json:"recurrence_until,omitempty"` // No occurrence starts after this time
+	AlertMode       MaintenanceAlertMode  `json:"alert_mode"`
+	SetAssetStatus  bool                  `json:"set_asset_status"` // Flip covered assets to maintenance during the window
+	IsActive        bool                  `json:"is_active"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       *time.Time            `json:"updated_at,omitempty"`
+}
+
+// NewMaintenanceWindow creates a new one-off maintenance window that suppresses alerts
+func NewMaintenanceWindow() *MaintenanceWindow {
+	return &MaintenanceWindow{
+		ID:         uuid.New(),
+		Recurrence: MaintenanceRecurrenceNone,
+		AlertMode:  MaintenanceAlertSuppress,
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Validate checks the schedule, scope and alert mode of the window
+func (w *MaintenanceWindow) Validate() error {
+	if w.AssetID == nil && w.LocationID == nil && w.AssetTypeID == nil {
+		return fmt.Errorf("at least one of asset_id, location_id or asset_type_id is required")
+	}
+	if !w.EndTime.After(w.StartTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+
+	switch w.AlertMode {
+	case MaintenanceAlertSuppress, MaintenanceAlertTag:
+	default:
+		return fmt.Errorf("invalid alert_mode %q", w.AlertMode)
+	}
+
+	var period time.Duration
+	switch w.Recurrence {
+	case MaintenanceRecurrenceNone:
+		if w.RecurrenceUntil != nil {
+			return fmt.Errorf("recurrence_until requires a recurrence")
+		}
+		return nil
+	case MaintenanceRecurrenceDaily:
+		period = 24 * time.Hour
+	case MaintenanceRecurrenceWeekly:
+		period = 7 * 24 * time.Hour
+	case MaintenanceRecurrenceMonthly:
+		period = 28 * 24 * time.Hour
+	default:
+		return fmt.Errorf("invalid recurrence %q", w.Recurrence)
+	}
+
+	if w.EndTime.Sub(w.StartTime) >= period {
+		return fmt.Errorf("a %s window must be shorter than its recurrence period", w.Recurrence)
+	}
+	if w.RecurrenceUntil != nil && w.RecurrenceUntil.Before(w.StartTime) {
+		return fmt.Errorf("recurrence_until must not be before start_time")
+	}
+	return nil
+}
+
+// Covers reports whether the window applies to the asset
+func (w *MaintenanceWindow) Covers(asset *Asset) bool {
+	if asset.TenantID == nil || *asset.TenantID != w.TenantID {
+		return false
+	}
+	return (w.AssetID != nil && *w.AssetID == asset.ID) ||
+		(w.LocationID != nil && *w.LocationID == asset.LocationID) ||
+		(w.AssetTypeID != nil && *w.AssetTypeID == asset.AssetTypeID)
+}
+
+// OccurrenceAt returns the occurrence of the window that contains t, if any
+func (w *MaintenanceWindow) OccurrenceAt(t time.Time) (start, end time.Time, ok bool) {
+	if t.Before(w.StartTime) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start = w.StartTime
+	switch w.Recurrence {
+	case MaintenanceRecurrenceDaily, MaintenanceRecurrenceWeekly:
+		period := 24 * time.Hour
+		if w.Recurrence == MaintenanceRecurrenceWeekly {
+			period = 7 * 24 * time.Hour
+		}
+		start = w.StartTime.Add(t.Sub(w.StartTime) / period * period)
+	case MaintenanceRecurrenceMonthly:
+		months := (t.Year()-w.StartTime.Year())*12 + int(t.Month()-w.StartTime.Month())
+		start = w.StartTime.AddDate(0, months, 0)
+		if start.After(t) {
+			start = w.StartTime.AddDate(0, months-1, 0)
+		}
+	}
+
+	if w.RecurrenceUntil != nil && start.After(*w.RecurrenceUntil) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	end = start.Add(w.EndTime.Sub(w.StartTime))
+	if !t.Before(end) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// ActiveAt reports whether the window is enabled and in progress at t
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	if !w.IsActive {
+		return false
+	}
+	_, _, ok := w.OccurrenceAt(t)
+	return ok
+}
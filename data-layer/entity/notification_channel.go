@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannelType identifies how a notification is delivered
+type NotificationChannelType string
+
+const (
+	NotificationChannelWebhook NotificationChannelType = "webhook" // Generic JSON webhook signed with HMAC-SHA256
+	NotificationChannelEmail   NotificationChannelType = "email"   // Email via SMTP
+	NotificationChannelSlack   NotificationChannelType = "slack"   // Slack/Teams-style incoming webhook
+)
+
+// NotificationChannelConfig holds the type specific settings of a channel
+type NotificationChannelConfig struct {
+	// Webhook and Slack
+	URL     string            `json:"url,omitempty"`
+	Secret  string            `json:"secret,omitempty"` // Webhook signing secret
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Email
+	Recipients   []string `json:"recipients,omitempty"`
+	SMTPHost     string   `json:"smtp_host,omitempty"` // Optional, overrides the server default
+	SMTPPort     int      `json:"smtp_port,omitempty"`
+	SMTPUsername string   `json:"smtp_username,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	SMTPFrom     string   `json:"smtp_from,omitempty"`
+}
+
+// NotificationChannel is a tenant-configured destination for alert notifications
+type NotificationChannel struct {
+	ID        uuid.UUID                 `json:"id"`
+	TenantID  uuid.UUID                 `json:"tenant_id"`
+	Name      string                    `json:"name"`
+	Type      NotificationChannelType   `json:"type"`
+	Config    NotificationChannelConfig `json:"config"`
+	IsActive  bool                      `json:"is_active"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt *time.Time                `json:"updated_at,omitempty"`
+}
+
+// NewNotificationChannel creates a new notification channel with default values
+func NewNotificationChannel() *NotificationChannel {
+	return &NotificationChannel{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate checks that the channel has the settings its type needs
+func (c *NotificationChannel) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch c.Type {
+	case NotificationChannelWebhook, NotificationChannelSlack:
+		u, err := url.Parse(c.Config.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("config.url must be a valid http(s) URL")
+		}
+	case NotificationChannelEmail:
+		if len(c.Config.Recipients) == 0 {
+			return fmt.Errorf("config.recipients must contain at least one address")
+		}
+		for _, recipient := range c.Config.Recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return fmt.Errorf("invalid recipient address %q", recipient)
+			}
+		}
+		if c.Config.SMTPPort < 0 || c.Config.SMTPPort > 65535 {
+			return fmt.Errorf("config.smtp_port is out of range")
+		}
+	default:
+		return fmt.Errorf("unsupported channel type %q", c.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationDeliveryStatus represents the state of a notification delivery
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending NotificationDeliveryStatus = "pending" // Waiting for its next attempt
+	NotificationDeliverySending NotificationDeliveryStatus = "sending" // Claimed by a worker
+	NotificationDeliverySent    NotificationDeliveryStatus = "sent"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "failed" // Gave up after the maximum number of attempts
+)
+
+// NotificationDelivery logs a notification sent (or to be sent) to a channel
+type NotificationDelivery struct {
+	ID            uuid.UUID                  `json:"id"`
+	TenantID      uuid.UUID                  `json:"tenant_id"`
+	ChannelID     uuid.UUID                  `json:"channel_id"`
+	RuleID        *uuid.UUID                 `json:"rule_id,omitempty"`
+	AlertID       *uuid.UUID                 `json:"alert_id,omitempty"`
+	Event         NotificationEvent          `json:"event"`
+	Status        NotificationDeliveryStatus `json:"status"`
+	Attempts      int                        `json:"attempts"`
+	NextAttemptAt time.Time                  `json:"next_attempt_at"`
+	LastError     *string                    `json:"last_error,omitempty"`
+	Payload       json.RawMessage            `json:"payload"`
+	SentAt        *time.Time                 `json:"sent_at,omitempty"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     *time.Time                 `json:"updated_at,omitempty"`
+}
+
+// NewNotificationDelivery creates a pending delivery that is due immediately
+func NewNotificationDelivery() *NotificationDelivery {
+	now := time.Now()
+	return &NotificationDelivery{
+		ID:            uuid.New(),
+		Status:        NotificationDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// RetryDelay returns the exponential backoff before the next attempt, capped at maxDelay
+func RetryDelay(attempts int, baseDelay, maxDelay time.Duration) time.Duration {
+	delay := baseDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationEvent is the alert lifecycle event a notification is sent for
+type NotificationEvent string
+
+const (
+	NotificationEventAlertOpened    NotificationEvent = "alert.opened"
+	NotificationEventAlertResolved  NotificationEvent = "alert.resolved"
+	NotificationEventAlertEscalated NotificationEvent = "alert.escalated"
+	NotificationEventTest           NotificationEvent = "test"
+)
+
+// NotificationRule routes alerts of a tenant to a notification channel.
+// Empty filters match every alert.
+type NotificationRule struct {
+	ID              uuid.UUID           `json:"id"`
+	TenantID        uuid.UUID           `json:"tenant_id"`
+	ChannelID       uuid.UUID           `json:"channel_id"`
+	Name            string              `json:"name"`
+	Severities      []ThresholdSeverity `json:"severities"`              // Match any of these severities
+	AssetID         *uuid.UUID          `json:"asset_id,omitempty"`      // Only alerts for this asset
+	AssetTypeID     *uuid.UUID          `json:"asset_type_id,omitempty"` // Only alerts for assets of this type
+	NotifyOnResolve bool                `json:"notify_on_resolve"`
+	IsActive        bool                `json:"is_active"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       *time.Time          `json:"updated_at,omitempty"`
+}
+
+// NewNotificationRule creates a new notification rule with default values
+func NewNotificationRule() *NotificationRule {
+	return &NotificationRule{
+		ID:              uuid.New(),
+		NotifyOnResolve: true,
+		IsActive:        true,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// Matches reports whether an alert event for an asset of the given type is routed by this rule
+func (r *NotificationRule) Matches(alert *AssetAlert, assetTypeID uuid.UUID, event NotificationEvent) bool {
+	if !r.IsActive || alert.TenantID != r.TenantID {
+		return false
+	}
+	if event == NotificationEventAlertResolved && !r.NotifyOnResolve {
+		return false
+	}
+	if r.AssetID != nil && *r.AssetID != alert.AssetID {
+		return false
+	}
+	if r.AssetTypeID != nil && *r.AssetTypeID != assetTypeID {
+		return false
+	}
+	if len(r.Severities) == 0 {
+		return true
+	}
+	for _, severity := range r.Severities {
+		if severity == alert.Severity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,397 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayloadFormat defines how a payload decoder reads raw payloads
+type PayloadFormat string
+
+const (
+	PayloadFormatJSON   PayloadFormat = "json"   // Values are read from JSON paths
+	PayloadFormatBase64 PayloadFormat = "base64" // Values are read from byte offsets of a base64 frame
+	PayloadFormatHex    PayloadFormat = "hex"    // Values are read from byte offsets of a hex frame
+)
+
+// PayloadValueType defines how a binary field is encoded in a frame
+type PayloadValueType string
+
+const (
+	PayloadValueUint8   PayloadValueType = "uint8"
+	PayloadValueInt8    PayloadValueType = "int8"
+	PayloadValueUint16  PayloadValueType = "uint16"
+	PayloadValueInt16   PayloadValueType = "int16"
+	PayloadValueUint32  PayloadValueType = "uint32"
+	PayloadValueInt32   PayloadValueType = "int32"
+	PayloadValueFloat32 PayloadValueType = "float32"
+	PayloadValueFloat64 PayloadValueType = "float64"
+	PayloadValueBool    PayloadValueType = "bool" // Non-zero byte
+)
+
+// payloadValueSizes holds the size in bytes of each binary value type
+var payloadValueSizes = map[PayloadValueType]int{
+	PayloadValueUint8:   1,
+	PayloadValueInt8:    1,
+	PayloadValueUint16:  2,
+	PayloadValueInt16:   2,
+	PayloadValueUint32:  4,
+	PayloadValueInt32:   4,
+	PayloadValueFloat32: 4,
+	PayloadValueFloat64: 8,
+	PayloadValueBool:    1,
+}
+
+// PayloadFieldSpec describes where a measurement field is found in a payload. JSON decoders
+// use Path; binary decoders use ByteOffset and Type. Numbers are stored as
+// value*Scale + ValueOffset.
+type PayloadFieldSpec struct {
+	Field        string           `json:"field"`                   // Measurement field of the decoder's sensor type
+	Path         string           `json:"path,omitempty"`          // JSON path such as uplink_message.decoded_payload.temp or data[0].value
+	ByteOffset   *int             `json:"byte_offset,omitempty"`   // Offset of the value in a binary frame
+	Type         PayloadValueType `json:"type,omitempty"`          // Encoding of the value in a binary frame
+	LittleEndian bool             `json:"little_endian,omitempty"` // Byte order of multi-byte values, big endian by default
+	Scale        *float64         `json:"scale,omitempty"`
+	ValueOffset  float64          `json:"value_offset,omitempty"`
+	Unit         string           `json:"unit,omitempty"` // Unit of the decoded value, defaults to the field's unit
+}
+
+// PayloadDecoder converts the raw payloads of a sensor type into measurements. A sensor
+// type has at most one decoder; readings of sensor types without one use the flexible
+// JSON format.
+type PayloadDecoder struct {
+	ID            uuid.UUID          `json:"id"`
+	SensorTypeID  uuid.UUID          `json:"sensor_type_id"`
+	Name          string             `json:"name"`
+	Description   *string            `json:"description,omitempty"`
+	Format        PayloadFormat      `json:"format"`
+	FramePath     string             `json:"frame_path,omitempty"`     // JSON path of the encoded frame of binary decoders; empty when the payload is the frame itself
+	TimestampPath string             `json:"timestamp_path,omitempty"` // JSON path of the reading time, RFC3339 or epoch seconds
+	MessageIDPath string             `json:"message_id_path,omitempty"`
+	Fields        []PayloadFieldSpec `json:"fields"`
+	IsActive      bool               `json:"is_active"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"`
+}
+
+// DecodedMeasurement is a measurement value read by a payload decoder
+type DecodedMeasurement struct {
+	Value interface{} `json:"value"`
+	Unit  string      `json:"unit,omitempty"`
+}
+
+// DecodedPayload is the result of decoding a raw payload
+type DecodedPayload struct {
+	Measurements map[string]DecodedMeasurement `json:"measurements"`
+	ReadingTime  *time.Time                    `json:"reading_time,omitempty"`
+	MessageID    string                        `json:"message_id,omitempty"`
+}
+
+// NewPayloadDecoder creates a new active JSON payload decoder
+func NewPayloadDecoder() *PayloadDecoder {
+	return &PayloadDecoder{
+		ID:        uuid.New(),
+		Format:    PayloadFormatJSON,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsBinary reports whether the decoder reads byte offsets of an encoded frame
+func (d *PayloadDecoder) IsBinary() bool {
+	return d.Format == PayloadFormatBase64 || d.Format == PayloadFormatHex
+}
+
+// Validate checks that the decoder is complete and consistent. It doesn't check the
+// measurement fields, which belong to the sensor type.
+func (d *PayloadDecoder) Validate() error {
+	switch d.Format {
+	case PayloadFormatJSON, PayloadFormatBase64, PayloadFormatHex:
+	default:
+		return fmt.Errorf("format must be json, base64 or hex")
+	}
+	if d.Format == PayloadFormatJSON && d.FramePath != "" {
+		return fmt.Errorf("frame_path is only used by base64 and hex decoders")
+	}
+	for name, path := range map[string]string{"frame_path": d.FramePath, "timestamp_path": d.TimestampPath, "message_id_path": d.MessageIDPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := parsePayloadPath(path); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if len(d.Fields) == 0 {
+		return fmt.Errorf("at least one field must be decoded")
+	}
+
+	fields := make(map[string]bool)
+	for i, spec := range d.Fields {
+		if strings.TrimSpace(spec.Field) == "" {
+			return fmt.Errorf("field spec %d needs a field", i+1)
+		}
+		if fields[spec.Field] {
+			return fmt.Errorf("field %s is decoded more than once", spec.Field)
+		}
+		fields[spec.Field] = true
+
+		if spec.Scale != nil && *spec.Scale == 0 {
+			return fmt.Errorf("field %s: scale can't be 0", spec.Field)
+		}
+		if d.IsBinary() {
+			if spec.ByteOffset == nil || *spec.ByteOffset < 0 {
+				return fmt.Errorf("field %s: byte_offset is required and can't be negative", spec.Field)
+			}
+			if _, ok := payloadValueSizes[spec.Type]; !ok {
+				return fmt.Errorf("field %s: unknown type %q", spec.Field, spec.Type)
+			}
+			continue
+		}
+		if spec.Path == "" {
+			return fmt.Errorf("field %s: path is required", spec.Field)
+		}
+		if _, err := parsePayloadPath(spec.Path); err != nil {
+			return fmt.Errorf("field %s: %w", spec.Field, err)
+		}
+	}
+
+	return nil
+}
+
+// Decode converts a raw payload into measurements. Fields missing from a JSON payload
+// are skipped; a binary frame too short for a field is an error.
+func (d *PayloadDecoder) Decode(payload []byte) (*DecodedPayload, error) {
+	var document interface{}
+	if d.Format == PayloadFormatJSON || d.FramePath != "" || d.TimestampPath != "" || d.MessageIDPath != "" {
+		if err := json.Unmarshal(payload, &document); err != nil {
+			if d.Format == PayloadFormatJSON || d.FramePath != "" {
+				return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+			}
+			// A bare frame has no envelope to read the timestamp or message ID from
+			document = nil
+		}
+	}
+
+	result := &DecodedPayload{Measurements: make(map[string]DecodedMeasurement)}
+	if d.IsBinary() {
+		frame, err := d.frame(payload, document)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range d.Fields {
+			value, err := spec.readBinary(frame)
+			if err != nil {
+				return nil, err
+			}
+			result.Measurements[spec.Field] = DecodedMeasurement{Value: value, Unit: spec.Unit}
+		}
+	} else {
+		for _, spec := range d.Fields {
+			value, ok := lookupPayloadPath(document, spec.Path)
+			if !ok || value == nil {
+				continue
+			}
+			if number, isNumber := value.(float64); isNumber {
+				value = spec.scale(number)
+			}
+			result.Measurements[spec.Field] = DecodedMeasurement{Value: value, Unit: spec.Unit}
+		}
+	}
+
+	if len(result.Measurements) == 0 {
+		return nil, fmt.Errorf("payload contains none of the decoded fields")
+	}
+
+	if d.TimestampPath != "" {
+		if value, ok := lookupPayloadPath(document, d.TimestampPath); ok {
+			readingTime, err := parsePayloadTimestamp(value)
+			if err != nil {
+				return nil, err
+			}
+			result.ReadingTime = &readingTime
+		}
+	}
+	if d.MessageIDPath != "" {
+		if value, ok := lookupPayloadPath(document, d.MessageIDPath); ok {
+			switch id := value.(type) {
+			case string:
+				result.MessageID = id
+			case float64:
+				result.MessageID = strconv.FormatFloat(id, 'f', -1, 64)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// frame returns the decoded bytes of a binary payload
+func (d *PayloadDecoder) frame(payload []byte, document interface{}) ([]byte, error) {
+	encoded := strings.TrimSpace(string(payload))
+	if d.FramePath != "" {
+		value, ok := lookupPayloadPath(document, d.FramePath)
+		text, isText := value.(string)
+		if !ok || !isText {
+			return nil, fmt.Errorf("payload has no %s frame at %s", d.Format, d.FramePath)
+		}
+		encoded = strings.TrimSpace(text)
+	}
+
+	var frame []byte
+	var err error
+	if d.Format == PayloadFormatHex {
+		frame, err = hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(encoded, " ", ""), "0x"))
+	} else {
+		frame, err = base64.StdEncoding.DecodeString(encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("frame is not valid %s: %w", d.Format, err)
+	}
+
+	return frame, nil
+}
+
+// readBinary reads the field's value from a frame
+func (s PayloadFieldSpec) readBinary(frame []byte) (interface{}, error) {
+	size := payloadValueSizes[s.Type]
+	offset := *s.ByteOffset
+	if offset+size > len(frame) {
+		return nil, fmt.Errorf("field %s: frame of %d bytes has no %s at byte %d", s.Field, len(frame), s.Type, offset)
+	}
+
+	raw := frame[offset : offset+size]
+	var order binary.ByteOrder = binary.BigEndian
+	if s.LittleEndian {
+		order = binary.LittleEndian
+	}
+
+	var number float64
+	switch s.Type {
+	case PayloadValueBool:
+		return raw[0] != 0, nil
+	case PayloadValueUint8:
+		number = float64(raw[0])
+	case PayloadValueInt8:
+		number = float64(int8(raw[0]))
+	case PayloadValueUint16:
+		number = float64(order.Uint16(raw))
+	case PayloadValueInt16:
+		number = float64(int16(order.Uint16(raw)))
+	case PayloadValueUint32:
+		number = float64(order.Uint32(raw))
+	case PayloadValueInt32:
+		number = float64(int32(order.Uint32(raw)))
+	case PayloadValueFloat32:
+		number = float64(math.Float32frombits(order.Uint32(raw)))
+	case PayloadValueFloat64:
+		number = math.Float64frombits(order.Uint64(raw))
+	}
+
+	return s.scale(number), nil
+}
+
+// scale applies the field's scaling factor and offset to a number
+func (s PayloadFieldSpec) scale(value float64) float64 {
+	if s.Scale != nil {
+		value *= *s.Scale
+	}
+	return value + s.ValueOffset
+}
+
+// parsePayloadTimestamp reads an RFC3339 string or epoch seconds
+func parsePayloadTimestamp(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timestamp %q is not RFC3339", v)
+		}
+		return t, nil
+	case float64:
+		seconds, fraction := math.Modf(v)
+		return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("timestamp must be an RFC3339 string or epoch seconds")
+}
+
+// payloadPathStep is a single step of a JSON path: an object key or an array index
+type payloadPathStep struct {
+	key   string
+	index int
+}
+
+// parsePayloadPath splits a path such as data[0].value or data.0.value into steps
+func parsePayloadPath(path string) ([]payloadPathStep, error) {
+	var steps []payloadPathStep
+	for _, segment := range strings.Split(path, ".") {
+		key := segment
+		var indexes []string
+		if open := strings.IndexByte(segment, '['); open >= 0 {
+			if !strings.HasSuffix(segment, "]") {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			key = segment[:open]
+			indexes = strings.Split(segment[open+1:len(segment)-1], "][")
+		}
+		if key == "" && len(indexes) == 0 {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+
+		if key != "" {
+			if index, err := strconv.Atoi(key); err == nil && index >= 0 {
+				steps = append(steps, payloadPathStep{index: index})
+			} else {
+				steps = append(steps, payloadPathStep{key: key, index: -1})
+			}
+		}
+		for _, text := range indexes {
+			index, err := strconv.Atoi(text)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index in path %q", path)
+			}
+			steps = append(steps, payloadPathStep{index: index})
+		}
+	}
+	return steps, nil
+}
+
+// lookupPayloadPath returns the value at a JSON path of a decoded document
+func lookupPayloadPath(document interface{}, path string) (interface{}, bool) {
+	steps, err := parsePayloadPath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	current := document
+	for _, step := range steps {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			key := step.key
+			if key == "" {
+				key = strconv.Itoa(step.index)
+			}
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			if step.key != "" || step.index >= len(node) {
+				return nil, false
+			}
+			current = node[step.index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
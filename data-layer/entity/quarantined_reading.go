@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuarantineStatus is the review state of a quarantined reading
+type QuarantineStatus string
+
+const (
+	QuarantineStatusPending   QuarantineStatus = "pending"   // Waiting for review
+	QuarantineStatusReplayed  QuarantineStatus = "replayed"  // Stored as readings after review
+	QuarantineStatusDiscarded QuarantineStatus = "discarded" // Dropped after review
+)
+
+// Sources of quarantined readings
+const (
+	ReadingSourceAPI    = "api"    // Reading endpoints
+	ReadingSourceDevice = "device" // Device ingestion endpoints
+	ReadingSourceMQTT   = "mqtt"   // MQTT messages
+	ReadingSourceImport = "import" // NDJSON and CSV imports
+)
+
+// QuarantinedMeasurement is a measurement held back by an ingestion policy, as it was sent
+type QuarantinedMeasurement struct {
+	Label string      `json:"label,omitempty"`
+	Unit  string      `json:"unit,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// QuarantinedReading holds the measurements of a message that an ingestion policy didn't
+// store, with the violations found, until an admin replays or discards them. When the
+// whole message was rejected Rejected is set; otherwise the message's valid measurements
+// were stored and only the others are held here.
+type QuarantinedReading struct {
+	ID                 uuid.UUID                         `json:"id"`
+	TenantID           *uuid.UUID                        `json:"tenant_id,omitempty"`
+	AssetSensorID      uuid.UUID                         `json:"asset_sensor_id"`
+	SensorTypeID       uuid.UUID                         `json:"sensor_type_id"`
+	MacAddress         *string                           `json:"mac_address,omitempty"`
+	MessageID          *string                           `json:"message_id,omitempty"`
+	ReadingTime        time.Time                         `json:"reading_time"`
+	Source             string                            `json:"source"`
+	Policy             IngestionPolicy                   `json:"policy"`
+	Rejected           bool                              `json:"rejected"`
+	Measurements       map[string]QuarantinedMeasurement `json:"measurements"`
+	Violations         []SchemaViolation                 `json:"violations"`
+	Status             QuarantineStatus                  `json:"status"`
+	ReviewedBy         *uuid.UUID                        `json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time                        `json:"reviewed_at,omitempty"`
+	ReviewNote         *string                           `json:"review_note,omitempty"`
+	ReplayedReadingIDs []uuid.UUID                       `json:"replayed_reading_ids,omitempty"`
+	CreatedAt          time.Time                         `json:"created_at"`
+	UpdatedAt          *time.Time                        `json:"updated_at,omitempty"`
+}
+
+// NewQuarantinedReading creates a pending quarantined reading
+func NewQuarantinedReading() *QuarantinedReading {
+	return &QuarantinedReading{
+		ID:           uuid.New(),
+		Status:       QuarantineStatusPending,
+		Measurements: make(map[string]QuarantinedMeasurement),
+		CreatedAt:    time.Now(),
+	}
+}
+
+// IsPending reports whether the reading still awaits review
+func (q *QuarantinedReading) IsPending() bool {
+	return q.Status == QuarantineStatusPending
+}
+
+// MarkReviewed records the outcome of a review
+func (q *QuarantinedReading) MarkReviewed(status QuarantineStatus, reviewedBy *uuid.UUID, note string) {
+	now := time.Now()
+	q.Status = status
+	q.ReviewedBy = reviewedBy
+	q.ReviewedAt = &now
+	if note != "" {
+		q.ReviewNote = &note
+	}
+}
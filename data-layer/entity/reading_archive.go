@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingArchiveFormatNDJSONGzip is the format of archives holding one JSON reading per line,
+// gzip compressed
+const ReadingArchiveFormatNDJSONGzip = "ndjson.gz"
+
+// ReadingArchive is the manifest entry of an archive file holding a tenant's archived
+// readings of a month. Readings archived after a month was exported go to further parts.
+type ReadingArchive struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	TenantID         *uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Month            time.Time  `json:"month" db:"month"`
+	Part             int        `json:"part" db:"part"`
+	Storage          string     `json:"storage" db:"storage"`
+	ObjectKey        string     `json:"object_key" db:"object_key"`
+	Format           string     `json:"format" db:"format"`
+	ReadingCount     int64      `json:"reading_count" db:"reading_count"`
+	FirstReadingTime time.Time  `json:"first_reading_time" db:"first_reading_time"`
+	LastReadingTime  time.Time  `json:"last_reading_time" db:"last_reading_time"`
+	SizeBytes        int64      `json:"size_bytes" db:"size_bytes"`
+	Checksum         string     `json:"checksum" db:"checksum"` // SHA-256 of the file
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	RestoredAt       *time.Time `json:"restored_at" db:"restored_at"`
+}
+
+// ReadingArchiveObjectKey returns the storage key of an archive part
+func ReadingArchiveObjectKey(tenantID *uuid.UUID, month time.Time, part int) string {
+	tenant := "no-tenant"
+	if tenantID != nil {
+		tenant = tenantID.String()
+	}
+	return fmt.Sprintf("iot_sensor_readings/%s/%s/part-%04d.%s", tenant, month.Format("2006-01"), part, ReadingArchiveFormatNDJSONGzip)
+}
+
+// ReadingArchiveGroup is a tenant's month of readings waiting in archived_iot_sensor_readings
+// to be exported
+type ReadingArchiveGroup struct {
+	TenantID     *uuid.UUID `json:"tenant_id"`
+	Month        time.Time  `json:"month"`
+	ReadingCount int64      `json:"reading_count"`
+}
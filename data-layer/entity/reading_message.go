@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// MaxReadingMessageIDLength is the longest client message ID accepted
+const MaxReadingMessageIDLength = 255
+
+// ReadingMessage groups the readings stored from one client message. A message with an ID
+// is stored once per asset sensor: storing it again stores nothing and returns the IDs of
+// the readings stored the first time, so gateways can safely retry. Message IDs are kept
+// for the message TTL of the retention job.
+type ReadingMessage struct {
+	AssetSensorID uuid.UUID
+	MessageID     string // Optional client-supplied ID
+	Readings      []*IoTSensorReadingFlexible
+
+	Duplicate  bool        // Set when the message was already stored
+	ReadingIDs []uuid.UUID // IDs of the message's readings; the original ones for a duplicate
+}
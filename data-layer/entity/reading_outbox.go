@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingEffect is a downstream side effect of storing a reading
+type ReadingEffect string
+
+const (
+	ReadingEffectLastReading     ReadingEffect = "last_reading"     // Update the asset sensor's last reading cache
+	ReadingEffectAlertEvaluation ReadingEffect = "alert_evaluation" // Evaluate thresholds and alert conditions, which queue notifications and webhooks
+)
+
+// ReadingEffects lists the effects recorded for every stored reading
+var ReadingEffects = []ReadingEffect{ReadingEffectLastReading, ReadingEffectAlertEvaluation}
+
+// ReadingOutboxStatus represents the state of a reading outbox event
+type ReadingOutboxStatus string
+
+const (
+	ReadingOutboxPending    ReadingOutboxStatus = "pending"    // Waiting for its next attempt
+	ReadingOutboxProcessing ReadingOutboxStatus = "processing" // Claimed by the dispatcher
+	ReadingOutboxDone       ReadingOutboxStatus = "done"
+	ReadingOutboxFailed     ReadingOutboxStatus = "failed" // Gave up after the maximum number of attempts
+)
+
+// ReadingOutboxEvent is a side effect of a reading, recorded in the transaction that stored
+// the reading and applied afterwards by the outbox dispatcher
+type ReadingOutboxEvent struct {
+	ID             uuid.UUID           `json:"id"`
+	TenantID       *uuid.UUID          `json:"tenant_id,omitempty"`
+	ReadingID      uuid.UUID           `json:"reading_id"`
+	AssetSensorID  uuid.UUID           `json:"asset_sensor_id"`
+	Effect         ReadingEffect       `json:"effect"`
+	IdempotencyKey string              `json:"idempotency_key"` // Unique per reading and effect
+	Status         ReadingOutboxStatus `json:"status"`
+	Attempts       int                 `json:"attempts"`
+	NextAttemptAt  time.Time           `json:"next_attempt_at"`
+	LastError      *string             `json:"last_error,omitempty"`
+	CompletedAt    *time.Time          `json:"completed_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      *time.Time          `json:"updated_at,omitempty"`
+}
+
+// ReadingEffectKey returns the idempotency key of an effect of a reading. Recording the
+// same reading twice records each effect once.
+func ReadingEffectKey(effect ReadingEffect, readingID uuid.UUID) string {
+	return string(effect) + ":" + readingID.String()
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionAction is what happens to readings older than a retention policy keeps
+type RetentionAction string
+
+const (
+	// RetentionActionDelete deletes expired readings
+	RetentionActionDelete RetentionAction = "delete"
+	// RetentionActionArchive moves expired readings to the archived_iot_sensor_readings table,
+	// from where they are exported to archive files
+	RetentionActionArchive RetentionAction = "archive"
+)
+
+// IsValid reports whether the retention action is known
+func (a RetentionAction) IsValid() bool {
+	return a == RetentionActionDelete || a == RetentionActionArchive
+}
+
+// ReadingRetentionPolicy defines how long a tenant's readings are kept. The policy without
+// a tenant is the default for every tenant without a policy of its own, and for readings
+// without a tenant; without a default policy those readings are kept forever.
+type ReadingRetentionPolicy struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	TenantID      *uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	RetentionDays int             `json:"retention_days" db:"retention_days"`
+	Action        RetentionAction `json:"action" db:"action"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     *time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Cutoff returns the reading time before which readings have expired
+func (p *ReadingRetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.UTC().AddDate(0, 0, -p.RetentionDays)
+}
+
+// ReadingPartition is a partition of the iot_sensor_readings table. Monthly partitions
+// hold the readings from From up to To; the default partition holds all other readings.
+type ReadingPartition struct {
+	Name      string    `json:"name"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	IsDefault bool      `json:"is_default"`
+}
+
+// ReadingPartitionMonth returns the start of the UTC month holding t
+func ReadingPartitionMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ReadingPartitionName returns the name of the monthly partition starting at month
+func ReadingPartitionName(month time.Time) string {
+	return fmt.Sprintf("iot_sensor_readings_%04d_%02d", month.Year(), int(month.Month()))
+}
+
+// ReadingTenantFilter selects readings by tenant: those of the listed tenants and, when
+// Others is set, those of every tenant not listed in Excluded, including readings without
+// a tenant
+type ReadingTenantFilter struct {
+	TenantIDs []uuid.UUID
+	Others    bool
+	Excluded  []uuid.UUID
+}
+
+// IsEmpty reports whether the filter selects no readings
+func (f ReadingTenantFilter) IsEmpty() bool {
+	return len(f.TenantIDs) == 0 && !f.Others
+}
+
+// ReadingRetentionResult reports what applying the retention policies did
+type ReadingRetentionResult struct {
+	PartitionsCreated []string `json:"partitions_created"`
+	PartitionsDropped []string `json:"partitions_dropped"`
+	RowsDeleted       int64    `json:"rows_deleted"`
+	RowsArchived      int64    `json:"rows_archived"`
+	MessagesDeleted   int64    `json:"messages_deleted"`
+}
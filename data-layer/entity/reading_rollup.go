@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReadingRollupResolution is the bucket size of a reading rollup table, which keeps the
+// count, sum, minimum and maximum of each asset sensor's numeric readings per
+// measurement, unit and UTC bucket
+type ReadingRollupResolution struct {
+	Name  string        // 1m, 1h or 1d
+	Table string        // Rollup table
+	Trunc string        // date_trunc field of the buckets
+	Step  time.Duration // Length of the buckets
+}
+
+// ReadingRollupResolutions lists the rollup tables from the finest to the coarsest
+var ReadingRollupResolutions = []ReadingRollupResolution{
+	{Name: "1m", Table: "iot_sensor_reading_rollups_1m", Trunc: "minute", Step: time.Minute},
+	{Name: "1h", Table: "iot_sensor_reading_rollups_1h", Trunc: "hour", Step: time.Hour},
+	{Name: "1d", Table: "iot_sensor_reading_rollups_1d", Trunc: "day", Step: 24 * time.Hour},
+}
+
+// ReadingRollupState tracks how far a rollup table has been refreshed. Every reading
+// created before RefreshedThrough is in the rollup.
+type ReadingRollupState struct {
+	Resolution       string     `json:"resolution" db:"resolution"`
+	RefreshedThrough time.Time  `json:"refreshed_through" db:"refreshed_through"`
+	UpdatedAt        *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReadingRollupRange is the part of a reading aggregation read from a rollup table rather
+// than from the readings. From and To are aligned to the rollup's buckets; To is exclusive.
+type ReadingRollupRange struct {
+	Resolution ReadingRollupResolution
+	From       time.Time
+	To         time.Time
+}
+
+// CoversBuckets reports whether every aggregation bucket starting within the range starts
+// on a bucket of the rollup, so each rollup bucket falls in a single aggregation bucket
+func (r ReadingRollupRange) CoversBuckets(buckets []time.Time) bool {
+	for _, bucket := range buckets {
+		if bucket.After(r.From) && bucket.Before(r.To) && !bucket.Equal(bucket.Truncate(r.Resolution.Step)) {
+			return false
+		}
+	}
+	return true
+}
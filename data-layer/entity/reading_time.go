@@ -0,0 +1,285 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Timestamp formats of readings besides Go time layouts
+const (
+	TimestampAuto       = "auto"    // RFC3339, local date-times or epoch seconds/milliseconds, told apart by form
+	TimestampRFC3339    = "rfc3339" // 2006-01-02T15:04:05Z07:00
+	TimestampUnix       = "unix"    // Seconds since the epoch
+	TimestampUnixMillis = "unix_ms" // Milliseconds since the epoch
+)
+
+// epochMillisThreshold tells epoch milliseconds from seconds in auto format: 1e11 seconds
+// is in the year 5138, 1e11 milliseconds in 1973
+const epochMillisThreshold = 1e11
+
+// localTimestampLayouts are the layouts without an offset auto format accepts. They are
+// in the time zone of the sensor; fractional seconds are accepted by every layout.
+var localTimestampLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// ParseTimestamp parses a timestamp sent as a string or a JSON number by format.
+// Timestamps without an offset are in location.
+func ParseTimestamp(value interface{}, format string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		text = v.String()
+	default:
+		return time.Time{}, fmt.Errorf("%v is not a timestamp", value)
+	}
+
+	switch format {
+	case TimestampAuto:
+		if epoch, err := strconv.ParseFloat(text, 64); err == nil {
+			if math.Abs(epoch) >= epochMillisThreshold {
+				return epochTime(epoch, time.Millisecond), nil
+			}
+			return epochTime(epoch, time.Second), nil
+		}
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t, nil
+		}
+		for _, layout := range localTimestampLayouts {
+			if t, err := time.ParseInLocation(layout, text, location); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%q is not an RFC3339, local or epoch timestamp", text)
+
+	case "", TimestampRFC3339:
+		t, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not an RFC3339 timestamp", text)
+		}
+		return t, nil
+
+	case TimestampUnix, TimestampUnixMillis:
+		epoch, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a %s timestamp", text, format)
+		}
+		if format == TimestampUnixMillis {
+			return epochTime(epoch, time.Millisecond), nil
+		}
+		return epochTime(epoch, time.Second), nil
+	}
+
+	t, err := time.ParseInLocation(format, text, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q doesn't match the timestamp format %s", text, format)
+	}
+	return t, nil
+}
+
+// epochTime converts a possibly fractional epoch count of unit to a UTC time
+func epochTime(epoch float64, unit time.Duration) time.Time {
+	whole, fraction := math.Modf(epoch)
+	return time.Unix(0, 0).Add(time.Duration(whole) * unit).Add(time.Duration(fraction * float64(unit))).UTC()
+}
+
+// ValidateTimestampFormat checks that a timestamp format is known or a Go time layout
+// that can read back the timestamps it writes
+func ValidateTimestampFormat(format string) error {
+	switch format {
+	case TimestampAuto, TimestampRFC3339, TimestampUnix, TimestampUnixMillis:
+		return nil
+	}
+
+	reference := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	parsed, err := time.Parse(format, reference.Format(format))
+	if err != nil || parsed.Year() != reference.Year() {
+		return fmt.Errorf("timestamp_format must be auto, rfc3339, unix, unix_ms or a Go time layout such as 2006-01-02 15:04:05")
+	}
+	return nil
+}
+
+// TimeAnomaly describes a reading time that is implausible against the time it was received
+type TimeAnomaly string
+
+const (
+	TimeAnomalyFuture  TimeAnomaly = "future"   // Ahead of the receive time by more than the allowed skew
+	TimeAnomalyFarPast TimeAnomaly = "far_past" // Older than the maximum reading age
+)
+
+// TimeSkewAction decides what happens to a reading with a time anomaly
+type TimeSkewAction string
+
+const (
+	// TimeSkewFlag stores the reading at its time and records the anomaly
+	TimeSkewFlag TimeSkewAction = "flag"
+	// TimeSkewReceiveTime stores the reading at its receive time, keeping the device time
+	TimeSkewReceiveTime TimeSkewAction = "receive_time"
+	// TimeSkewReject rejects the reading
+	TimeSkewReject TimeSkewAction = "reject"
+)
+
+// IsValid reports whether the action is known
+func (a TimeSkewAction) IsValid() bool {
+	switch a {
+	case TimeSkewFlag, TimeSkewReceiveTime, TimeSkewReject:
+		return true
+	}
+	return false
+}
+
+// Defaults of sensors without time settings
+const (
+	DefaultMaxFutureSkewSeconds = 5 * 60            // Five minutes
+	DefaultMaxReadingAgeSeconds = 30 * 24 * 60 * 60 // Thirty days
+)
+
+// SensorTimeSettings describes how the timestamps of an asset sensor's readings are read
+// and corrected. A device with a drifting clock gets a clock offset, which is added to
+// every timestamp it sends.
+type SensorTimeSettings struct {
+	AssetSensorID        uuid.UUID      `json:"asset_sensor_id"`
+	TenantID             *uuid.UUID     `json:"tenant_id,omitempty"`
+	TimestampFormat      string         `json:"timestamp_format"`        // auto, rfc3339, unix, unix_ms or a Go time layout
+	Timezone             string         `json:"timezone"`                // IANA time zone of timestamps without an offset
+	ClockOffsetMs        int64          `json:"clock_offset_ms"`         // Added to the device's timestamps
+	MaxFutureSkewSeconds int            `json:"max_future_skew_seconds"` // How far ahead of the receive time a reading may be
+	MaxAgeSeconds        int64          `json:"max_age_seconds"`         // How old a reading may be on arrival, 0 for no limit
+	SkewAction           TimeSkewAction `json:"skew_action"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            *time.Time     `json:"updated_at,omitempty"`
+}
+
+// NewSensorTimeSettings creates the default time settings of an asset sensor
+func NewSensorTimeSettings(assetSensorID uuid.UUID) *SensorTimeSettings {
+	return &SensorTimeSettings{
+		AssetSensorID:        assetSensorID,
+		TimestampFormat:      TimestampAuto,
+		Timezone:             "UTC",
+		MaxFutureSkewSeconds: DefaultMaxFutureSkewSeconds,
+		MaxAgeSeconds:        DefaultMaxReadingAgeSeconds,
+		SkewAction:           TimeSkewFlag,
+		CreatedAt:            time.Now(),
+	}
+}
+
+// Validate checks that the settings are consistent
+func (s *SensorTimeSettings) Validate() error {
+	if err := ValidateTimestampFormat(s.TimestampFormat); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if s.MaxFutureSkewSeconds < 0 {
+		return fmt.Errorf("max_future_skew_seconds can't be negative")
+	}
+	if s.MaxAgeSeconds < 0 {
+		return fmt.Errorf("max_age_seconds can't be negative")
+	}
+	if !s.SkewAction.IsValid() {
+		return fmt.Errorf("skew_action must be flag, receive_time or reject")
+	}
+	return nil
+}
+
+// ResolvedReadingTime is the time a reading is stored at, with how it was derived from
+// the timestamp the device sent
+type ResolvedReadingTime struct {
+	ReadingTime   time.Time
+	DeviceTime    *time.Time // Timestamp as sent, when the reading is stored at another time
+	ClockOffsetMs *int64     // Clock offset applied to the device time
+	Anomaly       TimeAnomaly
+	Skew          time.Duration // How far the corrected time is from the receive time
+}
+
+// ResolveReadingTime works out the time a reading is stored at. sent is the reading time
+// the request already parsed, raw a timestamp it couldn't parse; a reading with neither is
+// stored at its receive time. The far-past check is skipped unless checkAge is set, since
+// backfills are old by design. A reading rejected by the skew action returns an error.
+func (s *SensorTimeSettings) ResolveReadingTime(sent *time.Time, raw interface{}, receivedAt time.Time, checkAge bool) (*ResolvedReadingTime, error) {
+	if sent == nil && raw == nil {
+		return &ResolvedReadingTime{ReadingTime: receivedAt}, nil
+	}
+
+	var deviceTime time.Time
+	if sent != nil {
+		deviceTime = *sent
+	} else {
+		location, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+		if deviceTime, err = ParseTimestamp(raw, s.TimestampFormat, location); err != nil {
+			return nil, fmt.Errorf("reading_time %v", err)
+		}
+	}
+
+	resolved := &ResolvedReadingTime{ReadingTime: deviceTime}
+	if s.ClockOffsetMs != 0 {
+		offset := s.ClockOffsetMs
+		resolved.ReadingTime = deviceTime.Add(time.Duration(offset) * time.Millisecond)
+		resolved.DeviceTime = &deviceTime
+		resolved.ClockOffsetMs = &offset
+	}
+
+	resolved.Skew = resolved.ReadingTime.Sub(receivedAt)
+	switch {
+	case resolved.Skew > time.Duration(s.MaxFutureSkewSeconds)*time.Second:
+		resolved.Anomaly = TimeAnomalyFuture
+	case checkAge && s.MaxAgeSeconds > 0 && -resolved.Skew > time.Duration(s.MaxAgeSeconds)*time.Second:
+		resolved.Anomaly = TimeAnomalyFarPast
+	}
+	if resolved.Anomaly == "" {
+		return resolved, nil
+	}
+
+	switch s.SkewAction {
+	case TimeSkewReject:
+		return nil, fmt.Errorf("%s", resolved.Warning())
+	case TimeSkewReceiveTime:
+		resolved.ReadingTime = receivedAt
+		resolved.DeviceTime = &deviceTime
+	}
+	return resolved, nil
+}
+
+// Warning describes the anomaly of a reading time, or returns "" when there is none
+func (r *ResolvedReadingTime) Warning() string {
+	switch r.Anomaly {
+	case TimeAnomalyFuture:
+		return fmt.Sprintf("reading_time is %s ahead of the receive time", r.Skew.Round(time.Second))
+	case TimeAnomalyFarPast:
+		return fmt.Sprintf("reading_time is %s older than the receive time", (-r.Skew).Round(time.Second))
+	}
+	return ""
+}
+
+// Apply stores the resolved time on a reading
+func (r *ResolvedReadingTime) Apply(reading *IoTSensorReadingFlexible) {
+	reading.ReadingTime = r.ReadingTime
+	reading.DeviceTime = r.DeviceTime
+	reading.ClockOffsetMs = r.ClockOffsetMs
+	if r.Anomaly != "" {
+		anomaly := string(r.Anomaly)
+		reading.TimeAnomaly = &anomaly
+	}
+}
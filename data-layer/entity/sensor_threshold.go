@@ -2,6 +2,8 @@ package entity
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,20 +26,185 @@ const (
 	ThresholdStatusCritical ThresholdStatus = "critical"
 )
 
+// ThresholdRuleKind selects which value a threshold's min/max range is applied to, or
+// for boolean and text readings which values breach the threshold
+type ThresholdRuleKind string
+
+const (
+	ThresholdRuleStatic     ThresholdRuleKind = "static"      // The reading itself
+	ThresholdRuleDelta      ThresholdRuleKind = "delta"       // Change since the oldest reading in the window
+	ThresholdRuleRate       ThresholdRuleKind = "rate"        // Change per minute across the window
+	ThresholdRuleRollingAvg ThresholdRuleKind = "rolling_avg" // Average of the readings in the window
+	ThresholdRuleRollingMin ThresholdRuleKind = "rolling_min" // Lowest reading in the window
+	ThresholdRuleRollingMax ThresholdRuleKind = "rolling_max" // Highest reading in the window
+
+	ThresholdRuleBooleanEquals   ThresholdRuleKind = "boolean_equals"   // Boolean reading equals the expected value
+	ThresholdRuleBooleanDuration ThresholdRuleKind = "boolean_duration" // Boolean reading has equalled the expected value for the window
+	ThresholdRuleTextEquals      ThresholdRuleKind = "text_equals"      // Text reading equals the single text value
+	ThresholdRuleTextInSet       ThresholdRuleKind = "text_in_set"      // Text reading is one of the text values
+	ThresholdRuleTextRegex       ThresholdRuleKind = "text_regex"       // Text reading matches the text pattern
+)
+
+// AlertTypeValueMatch is the alert type of alerts raised by boolean and text rules
+const AlertTypeValueMatch = "value_match"
+
+// MaxThresholdWindowSeconds bounds how far back windowed rules look (7 days)
+const MaxThresholdWindowSeconds = 7 * 24 * 60 * 60
+
+// MaxThresholdTextValues bounds the number of text values of a text_in_set rule
+const MaxThresholdTextValues = 100
+
+// MaxThresholdTextPatternLength bounds the length of a text_regex pattern
+const MaxThresholdTextPatternLength = 500
+
+// IsValid reports whether the rule kind is known
+func (k ThresholdRuleKind) IsValid() bool {
+	switch k {
+	case ThresholdRuleStatic, ThresholdRuleDelta, ThresholdRuleRate,
+		ThresholdRuleRollingAvg, ThresholdRuleRollingMin, ThresholdRuleRollingMax,
+		ThresholdRuleBooleanEquals, ThresholdRuleBooleanDuration,
+		ThresholdRuleTextEquals, ThresholdRuleTextInSet, ThresholdRuleTextRegex:
+		return true
+	}
+	return false
+}
+
+// IsWindowed reports whether the rule kind applies the min/max range to a value derived
+// from recent readings
+func (k ThresholdRuleKind) IsWindowed() bool {
+	switch k {
+	case ThresholdRuleDelta, ThresholdRuleRate,
+		ThresholdRuleRollingAvg, ThresholdRuleRollingMin, ThresholdRuleRollingMax:
+		return true
+	}
+	return false
+}
+
+// IsBoolean reports whether the rule kind applies to boolean readings
+func (k ThresholdRuleKind) IsBoolean() bool {
+	return k == ThresholdRuleBooleanEquals || k == ThresholdRuleBooleanDuration
+}
+
+// IsText reports whether the rule kind applies to text readings
+func (k ThresholdRuleKind) IsText() bool {
+	return k == ThresholdRuleTextEquals || k == ThresholdRuleTextInSet || k == ThresholdRuleTextRegex
+}
+
+// IsValueMatch reports whether the rule kind matches boolean or text readings instead of
+// comparing numeric readings with a min/max range
+func (k ThresholdRuleKind) IsValueMatch() bool {
+	return k.IsBoolean() || k.IsText()
+}
+
+// ReadingWindowStats summarises the readings of one measurement within a window,
+// including the reading that triggered the evaluation
+type ReadingWindowStats struct {
+	Count      int
+	Avg        float64
+	Min        float64
+	Max        float64
+	FirstValue float64
+	FirstTime  time.Time
+}
+
+// ThresholdAlertRules controls when a breach opens an alert and when it closes again.
+// The zero value opens on the first breaching reading and closes on the first normal one.
+type ThresholdAlertRules struct {
+	Hysteresis            float64 `json:"hysteresis"`              // Value must come back inside the range by this margin to clear
+	BreachDurationSeconds int     `json:"breach_duration_seconds"` // Breach must last at least this long before an alert opens
+	BreachCount           int     `json:"breach_count"`            // Consecutive breaching readings required before an alert opens
+	ClearDurationSeconds  int     `json:"clear_duration_seconds"`  // Value must stay clear this long before an alert closes
+	ClearCount            int     `json:"clear_count"`             // Consecutive clear readings required before an alert closes
+}
+
+// Advance applies one evaluation to the alert progress and reports whether an alert
+// should be opened, updated or closed. breached tells whether the rule is breached;
+// cleared whether the evaluation counts towards closing an open alert, which may be
+// stricter than not breaching (see hysteresis). The progress is modified in place.
+func (r ThresholdAlertRules) Advance(progress *AlertProgress, breached, cleared bool, at time.Time) ThresholdTransition {
+	if breached {
+		if progress.BreachStartedAt == nil {
+			progress.BreachStartedAt = &at
+		}
+		progress.ConsecutiveBreaches++
+	} else {
+		progress.BreachStartedAt = nil
+		progress.ConsecutiveBreaches = 0
+	}
+
+	if !progress.InAlert {
+		if !breached {
+			return ThresholdTransitionNone
+		}
+		if progress.ConsecutiveBreaches < r.BreachCount ||
+			at.Sub(*progress.BreachStartedAt) < time.Duration(r.BreachDurationSeconds)*time.Second {
+			return ThresholdTransitionPending
+		}
+		progress.InAlert = true
+		return ThresholdTransitionOpen
+	}
+
+	// Alert is open: only cleared evaluations count towards closing it
+	if !cleared {
+		progress.ClearStartedAt = nil
+		progress.ConsecutiveClears = 0
+		if breached {
+			return ThresholdTransitionUpdate
+		}
+		return ThresholdTransitionNone
+	}
+
+	if progress.ClearStartedAt == nil {
+		progress.ClearStartedAt = &at
+	}
+	progress.ConsecutiveClears++
+	if progress.ConsecutiveClears < r.ClearCount ||
+		at.Sub(*progress.ClearStartedAt) < time.Duration(r.ClearDurationSeconds)*time.Second {
+		return ThresholdTransitionNone
+	}
+
+	progress.InAlert = false
+	progress.ClearStartedAt = nil
+	progress.ConsecutiveClears = 0
+	return ThresholdTransitionClose
+}
+
+// Validate checks the alert rules for invalid values
+func (r ThresholdAlertRules) Validate() error {
+	if r.Hysteresis < 0 {
+		return fmt.Errorf("hysteresis must not be negative")
+	}
+	if r.BreachDurationSeconds < 0 || r.ClearDurationSeconds < 0 {
+		return fmt.Errorf("breach and clear durations must not be negative")
+	}
+	if r.BreachCount < 0 || r.ClearCount < 0 {
+		return fmt.Errorf("breach and clear counts must not be negative")
+	}
+	return nil
+}
+
 // SensorThreshold defines alert/warning ranges for sensor measurements
 // This defines when to trigger alerts, separate from sensor capability ranges
 type SensorThreshold struct {
-	ID                   uuid.UUID         `json:"id"`
-	TenantID             uuid.UUID         `json:"tenant_id"`
-	AssetSensorID        uuid.UUID         `json:"asset_sensor_id"`        // References the asset sensor
-	MeasurementTypeID    uuid.UUID         `json:"measurement_type_id"`    // References the measurement type
-	MeasurementFieldName string            `json:"measurement_field_name"` // Field name from measurement fields
-	MinValue             *float64          `json:"min_value,omitempty"`    // Alert if value < min_value
-	MaxValue             *float64          `json:"max_value,omitempty"`    // Alert if value > max_value
-	Severity             ThresholdSeverity `json:"severity"`
-	IsActive             bool              `json:"is_active"`
-	CreatedAt            time.Time         `json:"created_at"`
-	UpdatedAt            *time.Time        `json:"updated_at,omitempty"`
+	ID                   uuid.UUID           `json:"id"`
+	TenantID             uuid.UUID           `json:"tenant_id"`
+	AssetSensorID        uuid.UUID           `json:"asset_sensor_id"`        // References the asset sensor
+	MeasurementTypeID    uuid.UUID           `json:"measurement_type_id"`    // References the measurement type
+	MeasurementFieldName string              `json:"measurement_field_name"` // Field name from measurement fields
+	MinValue             *float64            `json:"min_value,omitempty"`    // Alert if value < min_value
+	MaxValue             *float64            `json:"max_value,omitempty"`    // Alert if value > max_value
+	Severity             ThresholdSeverity   `json:"severity"`
+	RuleKind             ThresholdRuleKind   `json:"rule_kind"`
+	WindowSeconds        int                 `json:"window_seconds"`             // Look-back window of windowed rules, or how long a boolean_duration state must hold
+	ExpectedBoolean      *bool               `json:"expected_boolean,omitempty"` // Breaching value of boolean rules
+	TextValues           []string            `json:"text_values,omitempty"`      // Breaching values of text_equals and text_in_set rules
+	TextPattern          *string             `json:"text_pattern,omitempty"`     // Breaching pattern of text_regex rules
+	AlertRules           ThresholdAlertRules `json:"alert_rules"`
+	IsActive             bool                `json:"is_active"`
+	CreatedAt            time.Time           `json:"created_at"`
+	UpdatedAt            *time.Time          `json:"updated_at,omitempty"`
+
+	textPattern *regexp.Regexp // Compiled TextPattern, see CompileTextPattern
 }
 
 // NewSensorThreshold creates a new threshold with default values
@@ -46,11 +213,50 @@ func NewSensorThreshold() *SensorThreshold {
 	return &SensorThreshold{
 		ID:        uuid.New(),
 		Severity:  ThresholdSeverityWarning,
+		RuleKind:  ThresholdRuleStatic,
 		IsActive:  true,
 		CreatedAt: now,
 	}
 }
 
+// Window returns the look-back window of a windowed rule
+func (t *SensorThreshold) Window() time.Duration {
+	return time.Duration(t.WindowSeconds) * time.Second
+}
+
+// DerivedValue computes the value the min/max range is compared against for a
+// windowed rule. It returns false when the window doesn't hold enough readings,
+// in which case the reading should not be evaluated.
+func (t *SensorThreshold) DerivedValue(value float64, at time.Time, stats *ReadingWindowStats) (float64, bool) {
+	if !t.RuleKind.IsWindowed() {
+		return value, true
+	}
+	if stats == nil || stats.Count == 0 {
+		return 0, false
+	}
+
+	switch t.RuleKind {
+	case ThresholdRuleDelta:
+		if stats.Count < 2 {
+			return 0, false
+		}
+		return value - stats.FirstValue, true
+	case ThresholdRuleRate:
+		minutes := at.Sub(stats.FirstTime).Minutes()
+		if stats.Count < 2 || minutes <= 0 {
+			return 0, false
+		}
+		return (value - stats.FirstValue) / minutes, true
+	case ThresholdRuleRollingAvg:
+		return stats.Avg, true
+	case ThresholdRuleRollingMin:
+		return stats.Min, true
+	case ThresholdRuleRollingMax:
+		return stats.Max, true
+	}
+	return 0, false
+}
+
 // CheckValue determines the status of a value against the thresholds
 func (t *SensorThreshold) CheckValue(value float64) ThresholdStatus {
 	// If no limits are set, everything is normal
@@ -81,6 +287,78 @@ func (t *SensorThreshold) IsBreached(value float64) bool {
 	return t.CheckValue(value) != ThresholdStatusNormal
 }
 
+// IsCleared determines if a value is far enough inside the normal range to close an
+// open alert, taking the hysteresis band into account
+func (t *SensorThreshold) IsCleared(value float64) bool {
+	h := t.AlertRules.Hysteresis
+	if t.MinValue != nil && value < *t.MinValue+h {
+		return false
+	}
+	if t.MaxValue != nil && value > *t.MaxValue-h {
+		return false
+	}
+	return true
+}
+
+// Evaluate applies a reading to the threshold state and reports whether an alert
+// should be opened, updated or closed. The state is modified in place.
+func (t *SensorThreshold) Evaluate(state *ThresholdState, value float64, at time.Time) ThresholdTransition {
+	return t.AlertRules.Advance(&state.AlertProgress, t.IsBreached(value), t.IsCleared(value), at)
+}
+
+// MatchValue reports whether a boolean or text reading breaches a value match rule.
+// ok is false when the reading is not of the type the rule applies to.
+func (t *SensorThreshold) MatchValue(value interface{}) (matched bool, ok bool) {
+	switch {
+	case t.RuleKind.IsBoolean():
+		b, isBool := value.(bool)
+		if !isBool || t.ExpectedBoolean == nil {
+			return false, false
+		}
+		return b == *t.ExpectedBoolean, true
+	case t.RuleKind.IsText():
+		text, isText := value.(string)
+		if !isText {
+			return false, false
+		}
+		if t.RuleKind == ThresholdRuleTextRegex {
+			if t.textPattern == nil {
+				if err := t.CompileTextPattern(); err != nil || t.textPattern == nil {
+					return false, false
+				}
+			}
+			return t.textPattern.MatchString(text), true
+		}
+		for _, candidate := range t.TextValues {
+			if text == candidate {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	return false, false
+}
+
+// EvaluateMatch applies the outcome of a value match rule to the threshold state and
+// reports whether an alert should be opened, updated or closed. A boolean_duration rule
+// only opens once the matching state has lasted the window. The state is modified in place.
+func (t *SensorThreshold) EvaluateMatch(state *ThresholdState, matched bool, at time.Time) ThresholdTransition {
+	rules := t.AlertRules
+	if t.RuleKind == ThresholdRuleBooleanDuration && rules.BreachDurationSeconds < t.WindowSeconds {
+		rules.BreachDurationSeconds = t.WindowSeconds
+	}
+	return rules.Advance(&state.AlertProgress, matched, !matched, at)
+}
+
+// MatchTriggerValue returns the numeric value recorded on alerts of a value match rule:
+// 1 or 0 for boolean readings and 0 for text readings
+func MatchTriggerValue(value interface{}) float64 {
+	if b, ok := value.(bool); ok && b {
+		return 1
+	}
+	return 0
+}
+
 // SetThresholds sets the min/max threshold values
 func (t *SensorThreshold) SetThresholds(minValue, maxValue *float64) error {
 	if minValue != nil && maxValue != nil && *minValue >= *maxValue {
@@ -94,6 +372,10 @@ func (t *SensorThreshold) SetThresholds(minValue, maxValue *float64) error {
 
 // GenerateAlertMessage creates an alert message based on the values
 func (t *SensorThreshold) GenerateAlertMessage(value float64, alertType string) string {
+	if t.RuleKind.IsWindowed() {
+		return t.generateWindowedAlertMessage(value, alertType)
+	}
+
 	// Generate default message
 	switch alertType {
 	case "min_breach":
@@ -112,19 +394,157 @@ func (t *SensorThreshold) GenerateAlertMessage(value float64, alertType string)
 		t.MeasurementFieldName, value)
 }
 
+// GenerateMatchAlertMessage creates an alert message for a boolean or text reading that
+// breached a value match rule
+func (t *SensorThreshold) GenerateMatchAlertMessage(value interface{}) string {
+	switch t.RuleKind {
+	case ThresholdRuleBooleanEquals:
+		return fmt.Sprintf("%s is %v", t.MeasurementFieldName, value)
+	case ThresholdRuleBooleanDuration:
+		return fmt.Sprintf("%s has been %v for at least %s", t.MeasurementFieldName, value, t.Window())
+	case ThresholdRuleTextRegex:
+		pattern := ""
+		if t.TextPattern != nil {
+			pattern = *t.TextPattern
+		}
+		return fmt.Sprintf("%s value %q matches pattern %q", t.MeasurementFieldName, value, pattern)
+	case ThresholdRuleTextInSet:
+		return fmt.Sprintf("%s value %q is one of %s", t.MeasurementFieldName, value, strings.Join(t.TextValues, ", "))
+	}
+	return fmt.Sprintf("%s value %q matched threshold", t.MeasurementFieldName, value)
+}
+
+// generateWindowedAlertMessage describes a breach of a windowed rule, where value is
+// the derived value rather than the raw reading
+func (t *SensorThreshold) generateWindowedAlertMessage(value float64, alertType string) string {
+	var subject string
+	switch t.RuleKind {
+	case ThresholdRuleDelta:
+		subject = fmt.Sprintf("%s change over %s", t.MeasurementFieldName, t.Window())
+	case ThresholdRuleRate:
+		subject = fmt.Sprintf("%s rate of change per minute over %s", t.MeasurementFieldName, t.Window())
+	case ThresholdRuleRollingAvg:
+		subject = fmt.Sprintf("%s %s rolling average", t.MeasurementFieldName, t.Window())
+	case ThresholdRuleRollingMin:
+		subject = fmt.Sprintf("%s %s rolling minimum", t.MeasurementFieldName, t.Window())
+	case ThresholdRuleRollingMax:
+		subject = fmt.Sprintf("%s %s rolling maximum", t.MeasurementFieldName, t.Window())
+	}
+
+	switch alertType {
+	case "min_breach":
+		if t.MinValue != nil {
+			return fmt.Sprintf("%s %.2f is below minimum threshold %.2f", subject, value, *t.MinValue)
+		}
+	case "max_breach":
+		if t.MaxValue != nil {
+			return fmt.Sprintf("%s %.2f exceeds maximum threshold %.2f", subject, value, *t.MaxValue)
+		}
+	}
+
+	return fmt.Sprintf("%s %.2f breached threshold", subject, value)
+}
+
 // ValidateConfiguration validates the threshold configuration
 func (t *SensorThreshold) ValidateConfiguration() error {
 	if t.MeasurementFieldName == "" {
 		return fmt.Errorf("measurement field name is required")
 	}
 
+	if err := t.ValidateRuleKind(); err != nil {
+		return err
+	}
+
 	// Validate threshold values if both are set
 	if t.MinValue != nil && t.MaxValue != nil {
 		if *t.MinValue >= *t.MaxValue {
 			return fmt.Errorf("minimum threshold must be less than maximum threshold")
 		}
+		if 2*t.AlertRules.Hysteresis >= *t.MaxValue-*t.MinValue {
+			return fmt.Errorf("hysteresis must be less than half of the threshold range")
+		}
+	}
+
+	return t.AlertRules.Validate()
+}
+
+// ValidateRuleKind checks the rule kind and the settings it uses. An empty rule kind
+// defaults to static, and settings the rule kind doesn't use are cleared.
+func (t *SensorThreshold) ValidateRuleKind() error {
+	if t.RuleKind == "" {
+		t.RuleKind = ThresholdRuleStatic
+	}
+	if !t.RuleKind.IsValid() {
+		return fmt.Errorf("invalid rule kind: %s", t.RuleKind)
+	}
+	if !t.RuleKind.IsBoolean() {
+		t.ExpectedBoolean = nil
+	}
+	if !t.RuleKind.IsText() || t.RuleKind == ThresholdRuleTextRegex {
+		t.TextValues = nil
+	}
+	if t.RuleKind != ThresholdRuleTextRegex {
+		t.TextPattern = nil
+	}
+
+	if t.RuleKind.IsWindowed() || t.RuleKind == ThresholdRuleBooleanDuration {
+		if t.WindowSeconds <= 0 || t.WindowSeconds > MaxThresholdWindowSeconds {
+			return fmt.Errorf("window_seconds must be between 1 and %d for %s rules", MaxThresholdWindowSeconds, t.RuleKind)
+		}
+	} else {
+		t.WindowSeconds = 0
+	}
+
+	if !t.RuleKind.IsValueMatch() {
+		return nil
+	}
+	if t.MinValue != nil || t.MaxValue != nil {
+		return fmt.Errorf("min_value and max_value are not used by %s rules", t.RuleKind)
+	}
+	if t.AlertRules.Hysteresis != 0 {
+		return fmt.Errorf("hysteresis is not supported by %s rules", t.RuleKind)
 	}
 
+	switch t.RuleKind {
+	case ThresholdRuleBooleanEquals, ThresholdRuleBooleanDuration:
+		if t.ExpectedBoolean == nil {
+			return fmt.Errorf("expected_boolean is required for %s rules", t.RuleKind)
+		}
+	case ThresholdRuleTextEquals:
+		if len(t.TextValues) != 1 {
+			return fmt.Errorf("text_values must hold exactly one value for text_equals rules")
+		}
+	case ThresholdRuleTextInSet:
+		if len(t.TextValues) == 0 || len(t.TextValues) > MaxThresholdTextValues {
+			return fmt.Errorf("text_values must hold between 1 and %d values for text_in_set rules", MaxThresholdTextValues)
+		}
+	case ThresholdRuleTextRegex:
+		if t.TextPattern == nil || *t.TextPattern == "" {
+			return fmt.Errorf("text_pattern is required for text_regex rules")
+		}
+		if len(*t.TextPattern) > MaxThresholdTextPatternLength {
+			return fmt.Errorf("text_pattern must not be longer than %d characters", MaxThresholdTextPatternLength)
+		}
+		if _, err := regexp.Compile(*t.TextPattern); err != nil {
+			return fmt.Errorf("invalid text_pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// CompileTextPattern compiles the pattern of a text_regex rule and keeps it on the threshold
+// for MatchValue. The repository compiles it once when it loads the threshold, since
+// thresholds are reloaded for every reading they evaluate.
+func (t *SensorThreshold) CompileTextPattern() error {
+	t.textPattern = nil
+	if t.RuleKind != ThresholdRuleTextRegex || t.TextPattern == nil {
+		return nil
+	}
+	pattern, err := regexp.Compile(*t.TextPattern)
+	if err != nil {
+		return err
+	}
+	t.textPattern = pattern
 	return nil
 }
 
@@ -134,7 +554,9 @@ func (t *SensorThreshold) GetThresholdInfo() map[string]interface{} {
 		"id":                t.ID,
 		"measurement_field": t.MeasurementFieldName,
 		"severity":          t.Severity,
+		"rule_kind":         t.RuleKind,
 		"is_active":         t.IsActive,
+		"alert_rules":       t.AlertRules,
 	}
 
 	if t.MinValue != nil {
@@ -143,6 +565,18 @@ func (t *SensorThreshold) GetThresholdInfo() map[string]interface{} {
 	if t.MaxValue != nil {
 		info["max_value"] = *t.MaxValue
 	}
+	if t.WindowSeconds > 0 {
+		info["window_seconds"] = t.WindowSeconds
+	}
+	if t.ExpectedBoolean != nil {
+		info["expected_boolean"] = *t.ExpectedBoolean
+	}
+	if len(t.TextValues) > 0 {
+		info["text_values"] = t.TextValues
+	}
+	if t.TextPattern != nil {
+		info["text_pattern"] = *t.TextPattern
+	}
 
 	return info
 }
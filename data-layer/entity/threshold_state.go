@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThresholdTransition is the outcome of evaluating a reading against a threshold
+type ThresholdTransition string
+
+const (
+	ThresholdTransitionNone    ThresholdTransition = "none"    // Nothing to do
+	ThresholdTransitionPending ThresholdTransition = "pending" // Breaching, but alert rules are not yet satisfied
+	ThresholdTransitionOpen    ThresholdTransition = "open"    // Open a new alert
+	ThresholdTransitionUpdate  ThresholdTransition = "update"  // Breach continues on the open alert
+	ThresholdTransitionClose   ThresholdTransition = "close"   // Resolve the open alert
+)
+
+// AlertProgress tracks breach/clear progress of an alert rule between readings, so
+// alert rules can span several readings
+type AlertProgress struct {
+	InAlert             bool       `json:"in_alert"`
+	BreachStartedAt     *time.Time `json:"breach_started_at,omitempty"`
+	ConsecutiveBreaches int        `json:"consecutive_breaches"`
+	ClearStartedAt      *time.Time `json:"clear_started_at,omitempty"`
+	ConsecutiveClears   int        `json:"consecutive_clears"`
+	LastReadingID       *uuid.UUID `json:"last_reading_id,omitempty"`   // Last reading applied to the progress
+	LastReadingTime     *time.Time `json:"last_reading_time,omitempty"` // Reading time of the newest reading applied
+}
+
+// Applied reports whether the reading was the last one applied, so a reading delivered
+// again after a retry doesn't advance the progress twice
+func (p *AlertProgress) Applied(readingID uuid.UUID) bool {
+	return p.LastReadingID != nil && *p.LastReadingID == readingID
+}
+
+// IsLate reports whether a reading taken at readingTime is older than the newest reading
+// applied. Progress advances in event-time order, so late readings are not applied: they
+// would otherwise open or resolve alerts on stale data.
+func (p *AlertProgress) IsLate(readingTime time.Time) bool {
+	return p.LastReadingTime != nil && readingTime.Before(*p.LastReadingTime)
+}
+
+// ThresholdState tracks breach/clear progress of a threshold for an asset sensor
+type ThresholdState struct {
+	ThresholdID   uuid.UUID `json:"threshold_id"`
+	AssetSensorID uuid.UUID `json:"asset_sensor_id"`
+	AlertProgress
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,258 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// thresholdStep is one reading applied to a threshold, offset seconds after the first
+type thresholdStep struct {
+	offset int
+	value  float64
+	want   ThresholdTransition
+}
+
+func TestThresholdAlertRulesAdvance(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules ThresholdAlertRules
+		steps []thresholdStep
+	}{
+		{
+			name:  "no rules open and close on the first reading",
+			rules: ThresholdAlertRules{},
+			steps: []thresholdStep{
+				{0, 25, ThresholdTransitionNone},
+				{10, 31, ThresholdTransitionOpen},
+				{20, 35, ThresholdTransitionUpdate},
+				{30, 25, ThresholdTransitionClose},
+				{40, 25, ThresholdTransitionNone},
+			},
+		},
+		{
+			name:  "open after N consecutive breaches",
+			rules: ThresholdAlertRules{BreachCount: 3},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{10, 32, ThresholdTransitionPending},
+				{20, 33, ThresholdTransitionOpen},
+				{30, 34, ThresholdTransitionUpdate},
+			},
+		},
+		{
+			name:  "a normal reading restarts the breach count",
+			rules: ThresholdAlertRules{BreachCount: 2},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{10, 25, ThresholdTransitionNone},
+				{20, 31, ThresholdTransitionPending},
+				{30, 31, ThresholdTransitionOpen},
+			},
+		},
+		{
+			name:  "clear after M consecutive clears",
+			rules: ThresholdAlertRules{ClearCount: 3},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionOpen},
+				{10, 25, ThresholdTransitionNone},
+				{20, 25, ThresholdTransitionNone},
+				{30, 25, ThresholdTransitionClose},
+			},
+		},
+		{
+			name:  "a breach restarts the clear count",
+			rules: ThresholdAlertRules{ClearCount: 2},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionOpen},
+				{10, 25, ThresholdTransitionNone},
+				{20, 31, ThresholdTransitionUpdate},
+				{30, 25, ThresholdTransitionNone},
+				{40, 25, ThresholdTransitionClose},
+			},
+		},
+		{
+			name:  "breach duration gates opening",
+			rules: ThresholdAlertRules{BreachDurationSeconds: 60},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{30, 31, ThresholdTransitionPending},
+				{59, 31, ThresholdTransitionPending},
+				{60, 31, ThresholdTransitionOpen},
+			},
+		},
+		{
+			name:  "a normal reading restarts the breach duration",
+			rules: ThresholdAlertRules{BreachDurationSeconds: 60},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{30, 25, ThresholdTransitionNone},
+				{60, 31, ThresholdTransitionPending},
+				{90, 31, ThresholdTransitionPending},
+				{120, 31, ThresholdTransitionOpen},
+			},
+		},
+		{
+			name:  "breach count and duration must both be met",
+			rules: ThresholdAlertRules{BreachCount: 2, BreachDurationSeconds: 60},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{10, 31, ThresholdTransitionPending},
+				{60, 31, ThresholdTransitionOpen},
+			},
+		},
+		{
+			name:  "clear duration gates closing",
+			rules: ThresholdAlertRules{ClearDurationSeconds: 60},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionOpen},
+				{10, 25, ThresholdTransitionNone},
+				{40, 25, ThresholdTransitionNone},
+				{70, 25, ThresholdTransitionClose},
+			},
+		},
+		{
+			name:  "readings inside the hysteresis band keep the alert open",
+			rules: ThresholdAlertRules{Hysteresis: 2},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionOpen},
+				{10, 29, ThresholdTransitionNone},
+				{20, 28.5, ThresholdTransitionNone},
+				{30, 28, ThresholdTransitionClose},
+			},
+		},
+		{
+			name:  "the hysteresis band does not delay opening",
+			rules: ThresholdAlertRules{Hysteresis: 2},
+			steps: []thresholdStep{
+				{0, 29, ThresholdTransitionNone},
+				{10, 30.5, ThresholdTransitionOpen},
+			},
+		},
+		{
+			name:  "a reading inside the hysteresis band restarts the clear count",
+			rules: ThresholdAlertRules{Hysteresis: 2, ClearCount: 2},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionOpen},
+				{10, 27, ThresholdTransitionNone},
+				{20, 29, ThresholdTransitionNone},
+				{30, 27, ThresholdTransitionNone},
+				{40, 26, ThresholdTransitionClose},
+			},
+		},
+		{
+			name:  "the alert opens again after closing",
+			rules: ThresholdAlertRules{BreachCount: 2},
+			steps: []thresholdStep{
+				{0, 31, ThresholdTransitionPending},
+				{10, 31, ThresholdTransitionOpen},
+				{20, 25, ThresholdTransitionClose},
+				{30, 31, ThresholdTransitionPending},
+				{40, 31, ThresholdTransitionOpen},
+			},
+		},
+	}
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxValue := 30.0
+			threshold := NewSensorThreshold()
+			threshold.MaxValue = &maxValue
+			threshold.AlertRules = tt.rules
+			state := &ThresholdState{ThresholdID: threshold.ID, AssetSensorID: uuid.New()}
+
+			inAlert := false
+			for i, step := range tt.steps {
+				at := start.Add(time.Duration(step.offset) * time.Second)
+				if got := threshold.Evaluate(state, step.value, at); got != step.want {
+					t.Fatalf("step %d (%v at +%ds) = %s, want %s", i, step.value, step.offset, got, step.want)
+				}
+				switch step.want {
+				case ThresholdTransitionOpen:
+					inAlert = true
+				case ThresholdTransitionClose:
+					inAlert = false
+				}
+				if state.InAlert != inAlert {
+					t.Fatalf("step %d: in alert = %v after %s", i, state.InAlert, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateMatchBooleanDurationUsesWindow(t *testing.T) {
+	expected := true
+	threshold := NewSensorThreshold()
+	threshold.RuleKind = ThresholdRuleBooleanDuration
+	threshold.ExpectedBoolean = &expected
+	threshold.WindowSeconds = 300
+	threshold.AlertRules = ThresholdAlertRules{BreachDurationSeconds: 60}
+	state := &ThresholdState{}
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	steps := []struct {
+		offset  int
+		matched bool
+		want    ThresholdTransition
+	}{
+		{0, true, ThresholdTransitionPending},
+		{60, true, ThresholdTransitionPending},
+		{299, true, ThresholdTransitionPending},
+		{300, true, ThresholdTransitionOpen},
+		{310, false, ThresholdTransitionClose},
+	}
+	for i, step := range steps {
+		at := start.Add(time.Duration(step.offset) * time.Second)
+		if got := threshold.EvaluateMatch(state, step.matched, at); got != step.want {
+			t.Fatalf("step %d (%v at +%ds) = %s, want %s", i, step.matched, step.offset, got, step.want)
+		}
+	}
+}
+
+func TestAlertProgressApplied(t *testing.T) {
+	readingID := uuid.New()
+	tests := []struct {
+		name     string
+		progress AlertProgress
+		reading  uuid.UUID
+		want     bool
+	}{
+		{"no reading applied yet", AlertProgress{}, readingID, false},
+		{"same reading delivered again", AlertProgress{LastReadingID: &readingID}, readingID, true},
+		{"next reading", AlertProgress{LastReadingID: &readingID}, uuid.New(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.Applied(tt.reading); got != tt.want {
+				t.Errorf("Applied = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertProgressIsLate(t *testing.T) {
+	last := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		progress AlertProgress
+		at       time.Time
+		want     bool
+	}{
+		{"no reading applied yet", AlertProgress{}, last, false},
+		{"older reading", AlertProgress{LastReadingTime: &last}, last.Add(-time.Second), true},
+		{"reading at the same time", AlertProgress{LastReadingTime: &last}, last, false},
+		{"newer reading", AlertProgress{LastReadingTime: &last}, last.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.IsLate(tt.at); got != tt.want {
+				t.Errorf("IsLate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
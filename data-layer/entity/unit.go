@@ -0,0 +1,196 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MeasurementUnit is a unit of the unit catalog. A value in the unit equals
+// value*Scale + Offset in the base unit of its quantity.
+type MeasurementUnit struct {
+	Symbol   string   `json:"symbol"`
+	Name     string   `json:"name"`
+	Quantity string   `json:"quantity"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Scale    float64  `json:"-"`
+	Offset   float64  `json:"-"`
+}
+
+// unitCatalog lists the units readings can be converted between. The first unit of a
+// quantity is its base unit.
+var unitCatalog = []MeasurementUnit{
+	{Symbol: "°C", Name: "degree Celsius", Quantity: "temperature", Aliases: []string{"C", "degC", "celsius", "ºC"}, Scale: 1},
+	{Symbol: "°F", Name: "degree Fahrenheit", Quantity: "temperature", Aliases: []string{"F", "degF", "fahrenheit", "ºF"}, Scale: 5.0 / 9.0, Offset: -32 * 5.0 / 9.0},
+	{Symbol: "K", Name: "kelvin", Quantity: "temperature", Aliases: []string{"kelvin"}, Scale: 1, Offset: -273.15},
+
+	{Symbol: "Pa", Name: "pascal", Quantity: "pressure", Aliases: []string{"pascal"}, Scale: 1},
+	{Symbol: "hPa", Name: "hectopascal", Quantity: "pressure", Scale: 100},
+	{Symbol: "kPa", Name: "kilopascal", Quantity: "pressure", Scale: 1000},
+	{Symbol: "MPa", Name: "megapascal", Quantity: "pressure", Scale: 1e6},
+	{Symbol: "mbar", Name: "millibar", Quantity: "pressure", Scale: 100},
+	{Symbol: "bar", Name: "bar", Quantity: "pressure", Scale: 1e5},
+	{Symbol: "psi", Name: "pound per square inch", Quantity: "pressure", Scale: 6894.757293168},
+	{Symbol: "atm", Name: "standard atmosphere", Quantity: "pressure", Scale: 101325},
+	{Symbol: "mmHg", Name: "millimetre of mercury", Quantity: "pressure", Scale: 133.322387415},
+
+	{Symbol: "m", Name: "metre", Quantity: "length", Aliases: []string{"meter", "metre"}, Scale: 1},
+	{Symbol: "mm", Name: "millimetre", Quantity: "length", Scale: 0.001},
+	{Symbol: "cm", Name: "centimetre", Quantity: "length", Scale: 0.01},
+	{Symbol: "km", Name: "kilometre", Quantity: "length", Scale: 1000},
+	{Symbol: "in", Name: "inch", Quantity: "length", Aliases: []string{"inch"}, Scale: 0.0254},
+	{Symbol: "ft", Name: "foot", Quantity: "length", Aliases: []string{"foot", "feet"}, Scale: 0.3048},
+
+	{Symbol: "m/s", Name: "metre per second", Quantity: "speed", Aliases: []string{"mps"}, Scale: 1},
+	{Symbol: "km/h", Name: "kilometre per hour", Quantity: "speed", Aliases: []string{"kmh", "kph"}, Scale: 1000.0 / 3600.0},
+	{Symbol: "mph", Name: "mile per hour", Quantity: "speed", Scale: 0.44704},
+	{Symbol: "kn", Name: "knot", Quantity: "speed", Aliases: []string{"knot", "kt"}, Scale: 1852.0 / 3600.0},
+
+	{Symbol: "kg", Name: "kilogram", Quantity: "mass", Scale: 1},
+	{Symbol: "g", Name: "gram", Quantity: "mass", Scale: 0.001},
+	{Symbol: "mg", Name: "milligram", Quantity: "mass", Scale: 1e-6},
+	{Symbol: "t", Name: "tonne", Quantity: "mass", Aliases: []string{"tonne"}, Scale: 1000},
+	{Symbol: "lb", Name: "pound", Quantity: "mass", Aliases: []string{"lbs"}, Scale: 0.45359237},
+
+	{Symbol: "m³", Name: "cubic metre", Quantity: "volume", Aliases: []string{"m3"}, Scale: 1},
+	{Symbol: "L", Name: "litre", Quantity: "volume", Aliases: []string{"l", "liter", "litre"}, Scale: 0.001},
+	{Symbol: "mL", Name: "millilitre", Quantity: "volume", Aliases: []string{"ml"}, Scale: 1e-6},
+	{Symbol: "gal", Name: "US gallon", Quantity: "volume", Scale: 0.003785411784},
+
+	{Symbol: "m³/s", Name: "cubic metre per second", Quantity: "flow", Aliases: []string{"m3/s"}, Scale: 1},
+	{Symbol: "m³/h", Name: "cubic metre per hour", Quantity: "flow", Aliases: []string{"m3/h"}, Scale: 1.0 / 3600.0},
+	{Symbol: "L/s", Name: "litre per second", Quantity: "flow", Aliases: []string{"l/s"}, Scale: 0.001},
+	{Symbol: "L/min", Name: "litre per minute", Quantity: "flow", Aliases: []string{"l/min", "lpm"}, Scale: 0.001 / 60},
+
+	{Symbol: "J", Name: "joule", Quantity: "energy", Scale: 1},
+	{Symbol: "kJ", Name: "kilojoule", Quantity: "energy", Scale: 1000},
+	{Symbol: "MJ", Name: "megajoule", Quantity: "energy", Scale: 1e6},
+	{Symbol: "Wh", Name: "watt hour", Quantity: "energy", Scale: 3600},
+	{Symbol: "kWh", Name: "kilowatt hour", Quantity: "energy", Aliases: []string{"kwh"}, Scale: 3.6e6},
+	{Symbol: "MWh", Name: "megawatt hour", Quantity: "energy", Scale: 3.6e9},
+
+	{Symbol: "W", Name: "watt", Quantity: "power", Scale: 1},
+	{Symbol: "kW", Name: "kilowatt", Quantity: "power", Scale: 1000},
+	{Symbol: "MW", Name: "megawatt", Quantity: "power", Scale: 1e6},
+
+	{Symbol: "V", Name: "volt", Quantity: "voltage", Scale: 1},
+	{Symbol: "mV", Name: "millivolt", Quantity: "voltage", Scale: 0.001},
+	{Symbol: "kV", Name: "kilovolt", Quantity: "voltage", Scale: 1000},
+
+	{Symbol: "A", Name: "ampere", Quantity: "current", Scale: 1},
+	{Symbol: "mA", Name: "milliampere", Quantity: "current", Scale: 0.001},
+
+	{Symbol: "μg/m³", Name: "microgram per cubic metre", Quantity: "mass_concentration", Aliases: []string{"ug/m3", "µg/m³", "µg/m3", "μg/m3"}, Scale: 1},
+	{Symbol: "mg/m³", Name: "milligram per cubic metre", Quantity: "mass_concentration", Aliases: []string{"mg/m3"}, Scale: 1000},
+
+	{Symbol: "ppm", Name: "part per million", Quantity: "ratio", Scale: 1},
+	{Symbol: "ppb", Name: "part per billion", Quantity: "ratio", Scale: 0.001},
+
+	{Symbol: "μS/cm", Name: "microsiemens per centimetre", Quantity: "conductivity", Aliases: []string{"uS/cm", "µS/cm"}, Scale: 1},
+	{Symbol: "mS/cm", Name: "millisiemens per centimetre", Quantity: "conductivity", Scale: 1000},
+	{Symbol: "S/m", Name: "siemens per metre", Quantity: "conductivity", Scale: 10000},
+
+	{Symbol: "%", Name: "percent", Quantity: "percentage", Aliases: []string{"percent", "%RH"}, Scale: 1},
+	{Symbol: "pH", Name: "pH", Quantity: "acidity", Scale: 1},
+	{Symbol: "NTU", Name: "nephelometric turbidity unit", Quantity: "turbidity", Scale: 1},
+}
+
+// unitIndex finds catalog units by symbol or alias. Lookups are case-sensitive since
+// symbols such as mW and MW differ only in case.
+var unitIndex = buildUnitIndex()
+
+func buildUnitIndex() map[string]*MeasurementUnit {
+	index := make(map[string]*MeasurementUnit)
+	for i := range unitCatalog {
+		unit := &unitCatalog[i]
+		index[unit.Symbol] = unit
+		for _, alias := range unit.Aliases {
+			index[alias] = unit
+		}
+	}
+	return index
+}
+
+// UnitCatalog returns the units readings can be converted between
+func UnitCatalog() []MeasurementUnit {
+	units := make([]MeasurementUnit, len(unitCatalog))
+	copy(units, unitCatalog)
+	return units
+}
+
+// LookupUnit finds a catalog unit by its symbol or an alias
+func LookupUnit(unit string) (*MeasurementUnit, bool) {
+	found, ok := unitIndex[strings.TrimSpace(unit)]
+	return found, ok
+}
+
+// NormalizeUnit returns the catalog symbol of a unit, or the trimmed unit when it isn't
+// in the catalog
+func NormalizeUnit(unit string) string {
+	if found, ok := LookupUnit(unit); ok {
+		return found.Symbol
+	}
+	return strings.TrimSpace(unit)
+}
+
+// UnitConversion returns the factors converting a value from one unit to another as
+// value*scale + offset. Both units must be in the catalog and measure the same quantity,
+// unless they are the same unit.
+func UnitConversion(from, to string) (scale, offset float64, err error) {
+	if NormalizeUnit(from) == NormalizeUnit(to) {
+		return 1, 0, nil
+	}
+
+	source, ok := LookupUnit(from)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown unit %q", from)
+	}
+	target, ok := LookupUnit(to)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if source.Quantity != target.Quantity {
+		return 0, 0, fmt.Errorf("can't convert %s (%s) to %s (%s)", source.Symbol, source.Quantity, target.Symbol, target.Quantity)
+	}
+
+	return source.Scale / target.Scale, (source.Offset - target.Offset) / target.Scale, nil
+}
+
+// ConvertUnit converts a value from one unit to another
+func ConvertUnit(value float64, from, to string) (float64, error) {
+	scale, offset, err := UnitConversion(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return roundConverted(value*scale + offset), nil
+}
+
+// roundConverted drops the floating point noise of a conversion, so 100 °C converts to
+// 212 °F rather than 211.99999999999997
+func roundConverted(value float64) float64 {
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 12, 64), 64)
+	if err != nil {
+		return value
+	}
+	return rounded
+}
+
+// ToCanonicalUnit converts a value sent in unit to the canonical unit of a field and
+// returns it with the unit to store. A value without a unit is taken to be in the
+// canonical unit, and a field without a canonical unit keeps the unit as sent. converted
+// reports whether the value changed.
+func ToCanonicalUnit(value float64, unit, canonicalUnit string) (result float64, resultUnit string, converted bool, err error) {
+	unit, canonicalUnit = NormalizeUnit(unit), NormalizeUnit(canonicalUnit)
+	if unit == "" || canonicalUnit == "" || unit == canonicalUnit {
+		if unit == "" {
+			unit = canonicalUnit
+		}
+		return value, unit, false, nil
+	}
+
+	scale, offset, err := UnitConversion(unit, canonicalUnit)
+	if err != nil {
+		return 0, "", false, err
+	}
+	return roundConverted(value*scale + offset), canonicalUnit, true, nil
+}
@@ -0,0 +1,84 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateAlertConditionTable creates the alert_conditions table and the
+// alert_condition_states table tracking breach/clear progress between readings
+func CreateAlertConditionTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS alert_conditions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		asset_id UUID NOT NULL,
+		asset_sensor_id UUID NULL,
+		sensors JSONB NOT NULL DEFAULT '{}',
+		name VARCHAR(255) NOT NULL,
+		description TEXT NULL,
+		expression TEXT NOT NULL,
+		severity VARCHAR(20) NOT NULL DEFAULT 'warning' CHECK (severity IN ('warning', 'critical')),
+		breach_duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (breach_duration_seconds >= 0),
+		breach_count INTEGER NOT NULL DEFAULT 0 CHECK (breach_count >= 0),
+		clear_duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (clear_duration_seconds >= 0),
+		clear_count INTEGER NOT NULL DEFAULT 0 CHECK (clear_count >= 0),
+		max_reading_age_seconds INTEGER NOT NULL DEFAULT 300 CHECK (max_reading_age_seconds > 0),
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_alert_conditions_asset_id
+			FOREIGN KEY (asset_id) REFERENCES assets(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_alert_conditions_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS alert_condition_states (
+		condition_id UUID PRIMARY KEY,
+		in_alert BOOLEAN NOT NULL DEFAULT false,
+		breach_started_at TIMESTAMP NULL,
+		consecutive_breaches INTEGER NOT NULL DEFAULT 0,
+		clear_started_at TIMESTAMP NULL,
+		consecutive_clears INTEGER NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		CONSTRAINT fk_alert_condition_states_condition_id
+			FOREIGN KEY (condition_id) REFERENCES alert_conditions(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_alert_conditions_tenant_id ON alert_conditions(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_alert_conditions_asset_active ON alert_conditions(asset_id, is_active);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create alert_conditions table: %v", err)
+	}
+
+	// Remember the last applied reading so a redelivered reading is applied once
+	_, err = db.Exec(`ALTER TABLE alert_condition_states ADD COLUMN IF NOT EXISTS last_reading_id UUID NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add last_reading_id to alert_condition_states: %v", err)
+	}
+
+	// Remember the newest applied reading time so readings are applied in event-time order
+	_, err = db.Exec(`ALTER TABLE alert_condition_states ADD COLUMN IF NOT EXISTS last_reading_time TIMESTAMP NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add last_reading_time to alert_condition_states: %v", err)
+	}
+
+	log.Println("Alert conditions table created successfully")
+	return nil
+}
+
+// CreateAlertConditionTableIfNotExists creates the alert_conditions table if it doesn't exist
+func CreateAlertConditionTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating alert_conditions table if it doesn't exist...")
+	return CreateAlertConditionTable(db)
+}
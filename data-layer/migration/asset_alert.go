@@ -62,6 +62,86 @@ func CreateAssetAlertTable(db *sql.DB) error {
 		return fmt.Errorf("failed to create asset_alerts table: %v", err)
 	}
 
+	// Add occurrence tracking columns and allow at most one open alert per
+	// asset sensor and threshold. Older duplicates are resolved first so the
+	// unique index can be built on existing data.
+	_, err = db.Exec(`
+	ALTER TABLE asset_alerts
+		ADD COLUMN IF NOT EXISTS last_trigger_value DOUBLE PRECISION NULL,
+		ADD COLUMN IF NOT EXISTS peak_trigger_value DOUBLE PRECISION NULL,
+		ADD COLUMN IF NOT EXISTS occurrence_count INTEGER NOT NULL DEFAULT 1,
+		ADD COLUMN IF NOT EXISTS last_triggered_at TIMESTAMP NULL;
+
+	UPDATE asset_alerts SET
+		last_trigger_value = COALESCE(last_trigger_value, trigger_value),
+		peak_trigger_value = COALESCE(peak_trigger_value, trigger_value),
+		last_triggered_at = COALESCE(last_triggered_at, alert_time)
+	WHERE last_trigger_value IS NULL OR peak_trigger_value IS NULL OR last_triggered_at IS NULL;
+
+	UPDATE asset_alerts a SET
+		is_resolved = true,
+		resolved_time = CURRENT_TIMESTAMP,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE a.is_resolved = false
+		AND EXISTS (
+			SELECT 1 FROM asset_alerts newer
+			WHERE newer.asset_sensor_id = a.asset_sensor_id
+				AND newer.threshold_id = a.threshold_id
+				AND newer.is_resolved = false
+				AND (newer.alert_time, newer.id) > (a.alert_time, a.id)
+		);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_asset_alerts_open_per_threshold
+		ON asset_alerts(asset_sensor_id, threshold_id) WHERE is_resolved = false;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add occurrence tracking to asset_alerts table: %v", err)
+	}
+
+	// Add acknowledgement, assignment and resolution workflow columns
+	_, err = db.Exec(`
+	ALTER TABLE asset_alerts
+		ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP NULL,
+		ADD COLUMN IF NOT EXISTS acknowledged_by UUID NULL,
+		ADD COLUMN IF NOT EXISTS assigned_to UUID NULL,
+		ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP NULL,
+		ADD COLUMN IF NOT EXISTS resolved_by UUID NULL,
+		ADD COLUMN IF NOT EXISTS resolution_code VARCHAR(50) NULL,
+		ADD COLUMN IF NOT EXISTS resolution_note TEXT NULL;
+
+	CREATE INDEX IF NOT EXISTS idx_asset_alerts_assigned_to ON asset_alerts(tenant_id, assigned_to) WHERE is_resolved = false;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add workflow columns to asset_alerts table: %v", err)
+	}
+
+	// Add the maintenance window that tagged the alert
+	_, err = db.Exec(`
+	ALTER TABLE asset_alerts
+		ADD COLUMN IF NOT EXISTS maintenance_window_id UUID NULL;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add maintenance window column to asset_alerts table: %v", err)
+	}
+
+	// Allow alerts raised by alert conditions, which have no threshold, and by boolean
+	// and text value match rules. At most one alert per condition is open at a time.
+	_, err = db.Exec(`
+	ALTER TABLE asset_alerts
+		ADD COLUMN IF NOT EXISTS condition_id UUID NULL,
+		ALTER COLUMN threshold_id DROP NOT NULL,
+		DROP CONSTRAINT IF EXISTS asset_alerts_alert_type_check,
+		ADD CONSTRAINT asset_alerts_alert_type_check
+			CHECK (alert_type IN ('min_breach', 'max_breach', 'value_match', 'condition'));
+
+	CREATE INDEX IF NOT EXISTS idx_asset_alerts_condition_id ON asset_alerts(condition_id);
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_asset_alerts_open_per_condition
+		ON asset_alerts(condition_id) WHERE is_resolved = false AND condition_id IS NOT NULL;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to add condition column to asset_alerts table: %v", err)
+	}
+
 	log.Println("Asset alerts table created successfully")
 	return nil
 }
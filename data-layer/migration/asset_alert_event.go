@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateAssetAlertEventTable creates the asset_alert_events table holding alert timelines
+func CreateAssetAlertEventTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS asset_alert_events (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		alert_id UUID NOT NULL,
+		tenant_id UUID NOT NULL,
+		type VARCHAR(30) NOT NULL,
+		user_id UUID NULL,
+		comment TEXT NULL,
+		details JSONB NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		CONSTRAINT fk_asset_alert_events_alert_id
+			FOREIGN KEY (alert_id) REFERENCES asset_alerts(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_asset_alert_events_alert_id ON asset_alert_events(alert_id, created_at);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create asset_alert_events table: %v", err)
+	}
+
+	log.Println("Asset alert events table created successfully")
+	return nil
+}
+
+// CreateAssetAlertEventTableIfNotExists creates the asset_alert_events table if it doesn't exist
+func CreateAssetAlertEventTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating asset_alert_events table if it doesn't exist...")
+	return CreateAssetAlertEventTable(db)
+}
@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateCSVMappingProfileTable creates the csv_mapping_profiles table, which holds the
+// saved column mappings of CSV reading imports per sensor type
+func CreateCSVMappingProfileTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS csv_mapping_profiles (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		sensor_type_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT NULL,
+		delimiter VARCHAR(4) NOT NULL DEFAULT ',',
+		timestamp_column VARCHAR(255) NOT NULL,
+		timestamp_format VARCHAR(100) NOT NULL DEFAULT 'rfc3339',
+		timezone VARCHAR(100) NOT NULL DEFAULT 'UTC',
+		asset_sensor_column VARCHAR(255) NULL,
+		columns JSONB NOT NULL DEFAULT '[]',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_csv_mapping_profiles_sensor_type_id
+			FOREIGN KEY (sensor_type_id) REFERENCES sensor_types(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT uq_csv_mapping_profiles_name UNIQUE (sensor_type_id, name)
+	);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create csv_mapping_profiles table: %v", err)
+	}
+
+	log.Println("CSV mapping profiles table created successfully")
+	return nil
+}
+
+// CreateCSVMappingProfileTableIfNotExists creates the csv_mapping_profiles table if it doesn't exist
+func CreateCSVMappingProfileTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating csv_mapping_profiles table if it doesn't exist...")
+	return CreateCSVMappingProfileTable(db)
+}
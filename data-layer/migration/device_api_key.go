@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateDeviceAPIKeyTable creates the device_api_keys table
+func CreateDeviceAPIKeyTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS device_api_keys (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		asset_sensor_id UUID NULL,
+		name VARCHAR(255) NOT NULL,
+		key_prefix VARCHAR(32) NOT NULL,
+		key_hash VARCHAR(128) NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		expires_at TIMESTAMP NULL,
+		last_used_at TIMESTAMP NULL,
+		rotated_at TIMESTAMP NULL,
+		revoked_at TIMESTAMP NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT uq_device_api_keys_key_prefix UNIQUE (key_prefix),
+		CONSTRAINT fk_device_api_keys_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_device_api_keys_tenant_id ON device_api_keys(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_device_api_keys_asset_sensor_id ON device_api_keys(asset_sensor_id);
+	CREATE INDEX IF NOT EXISTS idx_device_api_keys_is_active ON device_api_keys(is_active);
+	`
+
+	// Execute the SQL
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create device_api_keys table: %v", err)
+	}
+
+	log.Println("Device API keys table created successfully")
+	return nil
+}
+
+// CreateDeviceAPIKeyTableIfNotExists creates the device_api_keys table if it doesn't exist
+func CreateDeviceAPIKeyTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating device_api_keys table if it doesn't exist...")
+	return CreateDeviceAPIKeyTable(db)
+}
@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateEscalationPolicyTable creates the escalation_policies table
+func CreateEscalationPolicyTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS escalation_policies (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT NULL,
+		severities TEXT[] NOT NULL DEFAULT '{critical}',
+		steps JSONB NOT NULL DEFAULT '[]',
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_escalation_policies_tenant_id ON escalation_policies(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_escalation_policies_active ON escalation_policies(is_active);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create escalation_policies table: %v", err)
+	}
+
+	log.Println("Escalation policies table created successfully")
+	return nil
+}
+
+// CreateEscalationPolicyTableIfNotExists creates the escalation_policies table if it doesn't exist
+func CreateEscalationPolicyTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating escalation_policies table if it doesn't exist...")
+	return CreateEscalationPolicyTable(db)
+}
+
+// CreateAssetAlertEscalationTable creates the asset_alert_escalations table holding
+// the escalation history of alerts
+func CreateAssetAlertEscalationTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS asset_alert_escalations (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		alert_id UUID NOT NULL,
+		tenant_id UUID NOT NULL,
+		policy_id UUID NULL,
+		policy_name VARCHAR(255) NOT NULL,
+		step_index INTEGER NOT NULL,
+		channel_ids UUID[] NOT NULL DEFAULT '{}',
+		user_ids UUID[] NOT NULL DEFAULT '{}',
+		escalated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		CONSTRAINT fk_asset_alert_escalations_alert_id
+			FOREIGN KEY (alert_id) REFERENCES asset_alerts(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_asset_alert_escalations_policy_id
+			FOREIGN KEY (policy_id) REFERENCES escalation_policies(id)
+			ON DELETE SET NULL ON UPDATE CASCADE,
+		-- Each step of a policy fires at most once per alert, even with several schedulers
+		CONSTRAINT uq_asset_alert_escalations_step UNIQUE (alert_id, policy_id, step_index)
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_asset_alert_escalations_alert_id ON asset_alert_escalations(alert_id, escalated_at);
+	CREATE INDEX IF NOT EXISTS idx_asset_alert_escalations_policy_id ON asset_alert_escalations(policy_id);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create asset_alert_escalations table: %v", err)
+	}
+
+	log.Println("Asset alert escalations table created successfully")
+	return nil
+}
+
+// CreateAssetAlertEscalationTableIfNotExists creates the asset_alert_escalations table if it doesn't exist
+func CreateAssetAlertEscalationTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating asset_alert_escalations table if it doesn't exist...")
+	return CreateAssetAlertEscalationTable(db)
+}
@@ -9,7 +9,9 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// CreateIoTSensorReadingTable creates the iot_sensor_readings table with proper foreign key constraints
+// CreateIoTSensorReadingTable creates the iot_sensor_readings table with proper foreign key constraints.
+// The table is partitioned by month on reading_time; readings outside every monthly partition
+// go to the default partition.
 func CreateIoTSensorReadingTable(cfg *config.Config) error {
 	log.Println("Creating iot_sensor_readings table...")
 
@@ -25,7 +27,7 @@ func CreateIoTSensorReadingTable(cfg *config.Config) error {
 	// SQL untuk membuat tabel iot_sensor_readings dengan dukungan flexible measurement data
 	createTableSQL := `
 	CREATE TABLE IF NOT EXISTS iot_sensor_readings (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		id UUID NOT NULL DEFAULT gen_random_uuid(),
 		tenant_id UUID NULL,
 		asset_sensor_id UUID NOT NULL,
 		sensor_type_id UUID NOT NULL,
@@ -46,11 +48,19 @@ func CreateIoTSensorReadingTable(cfg *config.Config) error {
 		-- Additional metadata
 		data_source VARCHAR(100) NULL DEFAULT 'json',          -- 'json', 'text', 'csv', etc.
 		original_field_name VARCHAR(255) NULL,  -- Original field name from JSON/text
+		original_value DOUBLE PRECISION NULL,   -- Value as sent, when converted to the field's canonical unit
+		original_unit VARCHAR(50) NULL,         -- Unit as sent, when converted
+		is_late BOOLEAN NOT NULL DEFAULT false, -- Arrived after a newer reading of the asset sensor
+		device_time TIMESTAMP NULL,             -- Timestamp as sent, when stored at another time
+		clock_offset_ms BIGINT NULL,            -- Clock offset applied to the device time
+		time_anomaly VARCHAR(20) NULL,          -- 'future' or 'far_past' against the receive time
 		
 		reading_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP NULL,
 		
+		-- Partitioned tables need the partition key in the primary key
+		PRIMARY KEY (id, reading_time),
 		CONSTRAINT fk_iot_readings_asset_sensor_id 
 			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id) 
 			ON DELETE CASCADE ON UPDATE CASCADE,
@@ -68,7 +78,10 @@ func CreateIoTSensorReadingTable(cfg *config.Config) error {
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NOT NULL) OR
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NULL) -- Allow all NULL
 		)
-	);
+	) PARTITION BY RANGE (reading_time);
+
+	-- Holds the readings outside every monthly partition
+	CREATE TABLE IF NOT EXISTS iot_sensor_readings_default PARTITION OF iot_sensor_readings DEFAULT;
 
 	-- Create indexes for better query performance
 	CREATE INDEX IF NOT EXISTS idx_iot_readings_tenant_id ON iot_sensor_readings(tenant_id);
@@ -111,13 +124,16 @@ func CreateIoTSensorReadingTableIfNotExists(db *sql.DB) error {
 
 	if exists {
 		log.Println("IoT sensor readings table already exists")
-		return nil
+		if err := addReadingColumns(db, "iot_sensor_readings"); err != nil {
+			return err
+		}
+		return checkIoTSensorReadingPartitioning(db)
 	}
 
 	// Use the same table definition as CreateIoTSensorReadingTable
 	createTableSQL := `
 	CREATE TABLE iot_sensor_readings (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		id UUID NOT NULL DEFAULT gen_random_uuid(),
 		tenant_id UUID NULL,
 		asset_sensor_id UUID NOT NULL,
 		sensor_type_id UUID NOT NULL,
@@ -138,11 +154,19 @@ func CreateIoTSensorReadingTableIfNotExists(db *sql.DB) error {
 		-- Additional metadata
 		data_source VARCHAR(100) NULL DEFAULT 'json',          -- 'json', 'text', 'csv', etc.
 		original_field_name VARCHAR(255) NULL,  -- Original field name from JSON/text
+		original_value DOUBLE PRECISION NULL,   -- Value as sent, when converted to the field's canonical unit
+		original_unit VARCHAR(50) NULL,         -- Unit as sent, when converted
+		is_late BOOLEAN NOT NULL DEFAULT false, -- Arrived after a newer reading of the asset sensor
+		device_time TIMESTAMP NULL,             -- Timestamp as sent, when stored at another time
+		clock_offset_ms BIGINT NULL,            -- Clock offset applied to the device time
+		time_anomaly VARCHAR(20) NULL,          -- 'future' or 'far_past' against the receive time
 		
 		reading_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP NULL,
 		
+		-- Partitioned tables need the partition key in the primary key
+		PRIMARY KEY (id, reading_time),
 		CONSTRAINT fk_iot_readings_asset_sensor_id 
 			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id) 
 			ON DELETE CASCADE ON UPDATE CASCADE,
@@ -160,7 +184,10 @@ func CreateIoTSensorReadingTableIfNotExists(db *sql.DB) error {
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NOT NULL) OR
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NULL) -- Allow all NULL
 		)
-	);
+	) PARTITION BY RANGE (reading_time);
+
+	-- Holds the readings outside every monthly partition
+	CREATE TABLE IF NOT EXISTS iot_sensor_readings_default PARTITION OF iot_sensor_readings DEFAULT;
 
 	-- Create indexes for better query performance
 	CREATE INDEX idx_iot_readings_tenant_id ON iot_sensor_readings(tenant_id);
@@ -186,30 +213,10 @@ func CreateIoTSensorReadingTableIfNotExists(db *sql.DB) error {
 	return nil
 }
 
-// CreateIoTSensorReadingTableDirect creates the iot_sensor_readings table directly with database connection
-func CreateIoTSensorReadingTableDirect(db *sql.DB) error {
-	// Check if table exists
-	var exists bool
-	query := `SELECT EXISTS (
-		SELECT 1 FROM information_schema.tables 
-		WHERE table_schema = 'public' 
-		AND table_name = 'iot_sensor_readings'
-	)`
-
-	err := db.QueryRow(query).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("failed to check if iot_sensor_readings table exists: %v", err)
-	}
-
-	if exists {
-		log.Println("IoT sensor readings table already exists")
-		return nil
-	}
-
-	// Use the same table definition as CreateIoTSensorReadingTable
-	createTableSQL := `
+// createIoTSensorReadingTableSQL uses the same table definition as CreateIoTSensorReadingTable
+const createIoTSensorReadingTableSQL = `
 	CREATE TABLE iot_sensor_readings (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		id UUID NOT NULL DEFAULT gen_random_uuid(),
 		tenant_id UUID NULL,
 		asset_sensor_id UUID NOT NULL,
 		sensor_type_id UUID NOT NULL,
@@ -230,11 +237,19 @@ func CreateIoTSensorReadingTableDirect(db *sql.DB) error {
 		-- Additional metadata
 		data_source VARCHAR(100) NULL DEFAULT 'json',          -- 'json', 'text', 'csv', etc.
 		original_field_name VARCHAR(255) NULL,  -- Original field name from JSON/text
+		original_value DOUBLE PRECISION NULL,   -- Value as sent, when converted to the field's canonical unit
+		original_unit VARCHAR(50) NULL,         -- Unit as sent, when converted
+		is_late BOOLEAN NOT NULL DEFAULT false, -- Arrived after a newer reading of the asset sensor
+		device_time TIMESTAMP NULL,             -- Timestamp as sent, when stored at another time
+		clock_offset_ms BIGINT NULL,            -- Clock offset applied to the device time
+		time_anomaly VARCHAR(20) NULL,          -- 'future' or 'far_past' against the receive time
 		
 		reading_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP NULL,
 		
+		-- Partitioned tables need the partition key in the primary key
+		PRIMARY KEY (id, reading_time),
 		CONSTRAINT fk_iot_readings_asset_sensor_id 
 			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id) 
 			ON DELETE CASCADE ON UPDATE CASCADE,
@@ -252,7 +267,10 @@ func CreateIoTSensorReadingTableDirect(db *sql.DB) error {
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NOT NULL) OR
 			(numeric_value IS NULL AND text_value IS NULL AND boolean_value IS NULL) -- Allow all NULL
 		)
-	);
+	) PARTITION BY RANGE (reading_time);
+
+	-- Holds the readings outside every monthly partition
+	CREATE TABLE IF NOT EXISTS iot_sensor_readings_default PARTITION OF iot_sensor_readings DEFAULT;
 
 	-- Create indexes for better query performance
 	CREATE INDEX idx_iot_readings_tenant_id ON iot_sensor_readings(tenant_id);
@@ -268,8 +286,31 @@ func CreateIoTSensorReadingTableDirect(db *sql.DB) error {
 	CREATE INDEX idx_iot_readings_composite ON iot_sensor_readings(asset_sensor_id, measurement_type, reading_time);
 	`
 
+// CreateIoTSensorReadingTableDirect creates the iot_sensor_readings table directly with database connection
+func CreateIoTSensorReadingTableDirect(db *sql.DB) error {
+	// Check if table exists
+	var exists bool
+	query := `SELECT EXISTS (
+		SELECT 1 FROM information_schema.tables 
+		WHERE table_schema = 'public' 
+		AND table_name = 'iot_sensor_readings'
+	)`
+
+	err := db.QueryRow(query).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check if iot_sensor_readings table exists: %v", err)
+	}
+
+	if exists {
+		log.Println("IoT sensor readings table already exists")
+		if err := addReadingColumns(db, "iot_sensor_readings"); err != nil {
+			return err
+		}
+		return checkIoTSensorReadingPartitioning(db)
+	}
+
 	// Execute the SQL
-	_, err = db.Exec(createTableSQL)
+	_, err = db.Exec(createIoTSensorReadingTableSQL)
 	if err != nil {
 		return fmt.Errorf("failed to create iot_sensor_readings table: %v", err)
 	}
@@ -303,3 +344,34 @@ func DropIoTSensorReadingTable(cfg *config.Config) error {
 	log.Println("IoT sensor readings table dropped successfully")
 	return nil
 }
+
+// addReadingColumns adds the columns introduced after iot_sensor_readings was first created
+// to an existing readings table. It runs on iot_sensor_readings and on
+// archived_iot_sensor_readings, which holds the same columns, so both stay in step.
+func addReadingColumns(db *sql.DB, table string) error {
+	_, err := db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s
+			ADD COLUMN IF NOT EXISTS original_value DOUBLE PRECISION NULL,
+			ADD COLUMN IF NOT EXISTS original_unit VARCHAR(50) NULL;
+	`, table))
+	if err != nil {
+		return fmt.Errorf("failed to add unit conversion columns to %s: %v", table, err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT false`, table))
+	if err != nil {
+		return fmt.Errorf("failed to add is_late to %s: %v", table, err)
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`
+		ALTER TABLE %s
+			ADD COLUMN IF NOT EXISTS device_time TIMESTAMP NULL,
+			ADD COLUMN IF NOT EXISTS clock_offset_ms BIGINT NULL,
+			ADD COLUMN IF NOT EXISTS time_anomaly VARCHAR(20) NULL;
+	`, table))
+	if err != nil {
+		return fmt.Errorf("failed to add reading time correction columns to %s: %v", table, err)
+	}
+
+	return nil
+}
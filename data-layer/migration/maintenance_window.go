@@ -0,0 +1,77 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateMaintenanceWindowTable creates the maintenance_windows table and the
+// maintenance_window_assets table remembering asset statuses changed by windows
+func CreateMaintenanceWindowTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS maintenance_windows (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT NULL,
+		asset_id UUID NULL,
+		location_id UUID NULL,
+		asset_type_id UUID NULL,
+		start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+		end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+		recurrence VARCHAR(20) NOT NULL DEFAULT 'none',
+		recurrence_until TIMESTAMP WITH TIME ZONE NULL,
+		alert_mode VARCHAR(20) NOT NULL DEFAULT 'suppress',
+		set_asset_status BOOLEAN NOT NULL DEFAULT false,
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_maintenance_windows_asset_id
+			FOREIGN KEY (asset_id) REFERENCES assets(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_maintenance_windows_location_id
+			FOREIGN KEY (location_id) REFERENCES locations(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_maintenance_windows_asset_type_id
+			FOREIGN KEY (asset_type_id) REFERENCES asset_types(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT chk_maintenance_windows_scope
+			CHECK (asset_id IS NOT NULL OR location_id IS NOT NULL OR asset_type_id IS NOT NULL),
+		CONSTRAINT chk_maintenance_windows_time
+			CHECK (end_time > start_time)
+	);
+
+	CREATE TABLE IF NOT EXISTS maintenance_window_assets (
+		window_id UUID NOT NULL,
+		asset_id UUID NOT NULL,
+		previous_status VARCHAR(50) NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		PRIMARY KEY (window_id, asset_id),
+		CONSTRAINT fk_maintenance_window_assets_asset_id
+			FOREIGN KEY (asset_id) REFERENCES assets(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_maintenance_windows_tenant_id ON maintenance_windows(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_maintenance_windows_active ON maintenance_windows(tenant_id, is_active);
+	CREATE INDEX IF NOT EXISTS idx_maintenance_windows_asset_id ON maintenance_windows(asset_id);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance_windows table: %v", err)
+	}
+
+	log.Println("Maintenance windows table created successfully")
+	return nil
+}
+
+// CreateMaintenanceWindowTableIfNotExists creates the maintenance_windows table if it doesn't exist
+func CreateMaintenanceWindowTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating maintenance_windows table if it doesn't exist...")
+	return CreateMaintenanceWindowTable(db)
+}
@@ -125,6 +125,69 @@ func runMigrations(cfg *config.Config) error {
 	}
 	log.Println("IoT sensor readings table created successfully")
 
+	// Run reading outbox migration
+	log.Println("Creating reading outbox table...")
+	if err := CreateReadingOutboxTableIfNotExists(db); err != nil {
+		return fmt.Errorf("reading outbox migration failed: %v", err)
+	}
+	log.Println("Reading outbox table created successfully")
+
+	// Run reading message migration
+	log.Println("Creating reading messages table...")
+	if err := CreateReadingMessageTableIfNotExists(db); err != nil {
+		return fmt.Errorf("reading message migration failed: %v", err)
+	}
+	log.Println("Reading messages table created successfully")
+
+	// Run CSV mapping profile migration
+	log.Println("Creating CSV mapping profiles table...")
+	if err := CreateCSVMappingProfileTableIfNotExists(db); err != nil {
+		return fmt.Errorf("CSV mapping profile migration failed: %v", err)
+	}
+	log.Println("CSV mapping profiles table created successfully")
+
+	// Run payload decoder migration
+	log.Println("Creating payload decoders table...")
+	if err := CreatePayloadDecoderTableIfNotExists(db); err != nil {
+		return fmt.Errorf("payload decoder migration failed: %v", err)
+	}
+	log.Println("Payload decoders table created successfully")
+
+	// Run quarantined reading migration
+	log.Println("Creating quarantined readings table...")
+	if err := CreateQuarantinedReadingTableIfNotExists(db); err != nil {
+		return fmt.Errorf("quarantined reading migration failed: %v", err)
+	}
+	log.Println("Quarantined readings table created successfully")
+
+	// Run sensor time settings migration
+	log.Println("Creating sensor time settings table...")
+	if err := CreateSensorTimeSettingsTableIfNotExists(db); err != nil {
+		return fmt.Errorf("sensor time settings migration failed: %v", err)
+	}
+	log.Println("Sensor time settings table created successfully")
+
+	// Run reading rollup migration
+	log.Println("Creating reading rollup tables...")
+	if err := CreateReadingRollupTablesIfNotExists(db); err != nil {
+		return fmt.Errorf("reading rollup migration failed: %v", err)
+	}
+	log.Println("Reading rollup tables created successfully")
+
+	// Run reading retention migration
+	log.Println("Creating reading retention tables...")
+	if err := CreateReadingRetentionTablesIfNotExists(db); err != nil {
+		return fmt.Errorf("reading retention migration failed: %v", err)
+	}
+	log.Println("Reading retention tables created successfully")
+
+	// Run reading archive migration
+	log.Println("Creating reading archives table...")
+	if err := CreateReadingArchiveTableIfNotExists(db); err != nil {
+		return fmt.Errorf("reading archive migration failed: %v", err)
+	}
+	log.Println("Reading archives table created successfully")
+
 	// Run sensor threshold migration
 	log.Println("Creating sensor thresholds table...")
 	if err := CreateSensorThresholdTableIfNotExists(db); err != nil {
@@ -139,6 +202,48 @@ func runMigrations(cfg *config.Config) error {
 	}
 	log.Println("Asset alerts table created successfully")
 
+	// Run sensor threshold state migration
+	log.Println("Creating sensor threshold states table...")
+	if err := CreateSensorThresholdStateTableIfNotExists(db); err != nil {
+		return fmt.Errorf("sensor threshold state migration failed: %v", err)
+	}
+	log.Println("Sensor threshold states table created successfully")
+
+	// Run alert condition migration
+	log.Println("Creating alert conditions table...")
+	if err := CreateAlertConditionTableIfNotExists(db); err != nil {
+		return fmt.Errorf("alert condition migration failed: %v", err)
+	}
+	log.Println("Alert conditions table created successfully")
+
+	// Run asset alert event migration
+	log.Println("Creating asset alert events table...")
+	if err := CreateAssetAlertEventTableIfNotExists(db); err != nil {
+		return fmt.Errorf("asset alert event migration failed: %v", err)
+	}
+	log.Println("Asset alert events table created successfully")
+
+	// Run escalation policy migration
+	log.Println("Creating escalation policies table...")
+	if err := CreateEscalationPolicyTableIfNotExists(db); err != nil {
+		return fmt.Errorf("escalation policy migration failed: %v", err)
+	}
+	log.Println("Escalation policies table created successfully")
+
+	// Run asset alert escalation migration
+	log.Println("Creating asset alert escalations table...")
+	if err := CreateAssetAlertEscalationTableIfNotExists(db); err != nil {
+		return fmt.Errorf("asset alert escalation migration failed: %v", err)
+	}
+	log.Println("Asset alert escalations table created successfully")
+
+	// Run maintenance window migration
+	log.Println("Creating maintenance windows table...")
+	if err := CreateMaintenanceWindowTableIfNotExists(db); err != nil {
+		return fmt.Errorf("maintenance window migration failed: %v", err)
+	}
+	log.Println("Maintenance windows table created successfully")
+
 	// Run asset activity migration
 	log.Println("Creating asset activities table...")
 	if err := CreateAssetActivityTableIfNotExists(db); err != nil {
@@ -160,5 +265,25 @@ func runMigrations(cfg *config.Config) error {
 	}
 	log.Println("Sensor logs table created successfully")
 
+	// Run device API key migration
+	log.Println("Creating device API keys table...")
+	if err := CreateDeviceAPIKeyTableIfNotExists(db); err != nil {
+		return fmt.Errorf("device api key migration failed: %v", err)
+	}
+	log.Println("Device API keys table created successfully")
+
+	// Run notification migrations
+	log.Println("Creating notification tables...")
+	if err := CreateNotificationChannelTableIfNotExists(db); err != nil {
+		return fmt.Errorf("notification channel migration failed: %v", err)
+	}
+	if err := CreateNotificationRuleTableIfNotExists(db); err != nil {
+		return fmt.Errorf("notification rule migration failed: %v", err)
+	}
+	if err := CreateNotificationDeliveryTableIfNotExists(db); err != nil {
+		return fmt.Errorf("notification delivery migration failed: %v", err)
+	}
+	log.Println("Notification tables created successfully")
+
 	return nil
 }
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateNotificationChannelTable creates the notification_channels table
+func CreateNotificationChannelTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS notification_channels (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		type VARCHAR(20) NOT NULL CHECK (type IN ('webhook', 'email', 'slack')),
+		config JSONB NOT NULL DEFAULT '{}'::jsonb,
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_notification_channels_tenant_id ON notification_channels(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_notification_channels_active ON notification_channels(tenant_id, is_active);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create notification_channels table: %v", err)
+	}
+
+	log.Println("Notification channels table created successfully")
+	return nil
+}
+
+// CreateNotificationChannelTableIfNotExists creates the notification_channels table if it doesn't exist
+func CreateNotificationChannelTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating notification_channels table if it doesn't exist...")
+	return CreateNotificationChannelTable(db)
+}
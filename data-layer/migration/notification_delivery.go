@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateNotificationDeliveryTable creates the notification_deliveries table,
+// which is both the delivery log and the retry queue
+func CreateNotificationDeliveryTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS notification_deliveries (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		channel_id UUID NOT NULL,
+		rule_id UUID NULL,
+		alert_id UUID NULL,
+		event VARCHAR(50) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_error TEXT NULL,
+		payload JSONB NOT NULL,
+		sent_at TIMESTAMP NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_notification_deliveries_channel_id
+			FOREIGN KEY (channel_id) REFERENCES notification_channels(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_notification_deliveries_rule_id
+			FOREIGN KEY (rule_id) REFERENCES notification_rules(id)
+			ON DELETE SET NULL ON UPDATE CASCADE,
+		CONSTRAINT fk_notification_deliveries_alert_id
+			FOREIGN KEY (alert_id) REFERENCES asset_alerts(id)
+			ON DELETE SET NULL ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_notification_deliveries_tenant_id ON notification_deliveries(tenant_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_notification_deliveries_alert_id ON notification_deliveries(alert_id);
+	CREATE INDEX IF NOT EXISTS idx_notification_deliveries_due ON notification_deliveries(next_attempt_at)
+		WHERE status IN ('pending', 'sending');
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create notification_deliveries table: %v", err)
+	}
+
+	log.Println("Notification deliveries table created successfully")
+	return nil
+}
+
+// CreateNotificationDeliveryTableIfNotExists creates the notification_deliveries table if it doesn't exist
+func CreateNotificationDeliveryTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating notification_deliveries table if it doesn't exist...")
+	return CreateNotificationDeliveryTable(db)
+}
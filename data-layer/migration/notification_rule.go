@@ -0,0 +1,55 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateNotificationRuleTable creates the notification_rules table
+func CreateNotificationRuleTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS notification_rules (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL,
+		channel_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		severities TEXT[] NOT NULL DEFAULT '{}',
+		asset_id UUID NULL,
+		asset_type_id UUID NULL,
+		notify_on_resolve BOOLEAN NOT NULL DEFAULT true,
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_notification_rules_channel_id
+			FOREIGN KEY (channel_id) REFERENCES notification_channels(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_notification_rules_asset_id
+			FOREIGN KEY (asset_id) REFERENCES assets(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_notification_rules_asset_type_id
+			FOREIGN KEY (asset_type_id) REFERENCES asset_types(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_notification_rules_tenant_id ON notification_rules(tenant_id);
+	CREATE INDEX IF NOT EXISTS idx_notification_rules_channel_id ON notification_rules(channel_id);
+	CREATE INDEX IF NOT EXISTS idx_notification_rules_active ON notification_rules(tenant_id, is_active);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create notification_rules table: %v", err)
+	}
+
+	log.Println("Notification rules table created successfully")
+	return nil
+}
+
+// CreateNotificationRuleTableIfNotExists creates the notification_rules table if it doesn't exist
+func CreateNotificationRuleTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating notification_rules table if it doesn't exist...")
+	return CreateNotificationRuleTable(db)
+}
@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreatePayloadDecoderTable creates the payload_decoders table, which holds the decoder of
+// vendor specific payloads per sensor type
+func CreatePayloadDecoderTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS payload_decoders (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		sensor_type_id UUID NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT NULL,
+		format VARCHAR(20) NOT NULL DEFAULT 'json',
+		frame_path VARCHAR(255) NOT NULL DEFAULT '',
+		timestamp_path VARCHAR(255) NOT NULL DEFAULT '',
+		message_id_path VARCHAR(255) NOT NULL DEFAULT '',
+		fields JSONB NOT NULL DEFAULT '[]',
+		is_active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_payload_decoders_sensor_type_id
+			FOREIGN KEY (sensor_type_id) REFERENCES sensor_types(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT uq_payload_decoders_sensor_type_id UNIQUE (sensor_type_id),
+		CONSTRAINT chk_payload_decoders_format CHECK (format IN ('json', 'base64', 'hex'))
+	);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create payload_decoders table: %v", err)
+	}
+
+	log.Println("Payload decoders table created successfully")
+	return nil
+}
+
+// CreatePayloadDecoderTableIfNotExists creates the payload_decoders table if it doesn't exist
+func CreatePayloadDecoderTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating payload_decoders table if it doesn't exist...")
+	return CreatePayloadDecoderTable(db)
+}
@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateQuarantinedReadingTable creates the quarantined_readings table, which holds the
+// measurements an ingestion policy didn't store until an admin reviews them
+func CreateQuarantinedReadingTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS quarantined_readings (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NULL,
+		asset_sensor_id UUID NOT NULL,
+		sensor_type_id UUID NOT NULL,
+		mac_address VARCHAR(255) NULL,
+		message_id VARCHAR(255) NULL,
+		reading_time TIMESTAMP WITH TIME ZONE NOT NULL,
+		source VARCHAR(20) NOT NULL,
+		policy VARCHAR(20) NOT NULL,
+		rejected BOOLEAN NOT NULL DEFAULT false,
+		measurements JSONB NOT NULL DEFAULT '{}',
+		violations JSONB NOT NULL DEFAULT '[]',
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		reviewed_by UUID NULL,
+		reviewed_at TIMESTAMP WITH TIME ZONE NULL,
+		review_note TEXT NULL,
+		replayed_reading_ids UUID[] NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP WITH TIME ZONE NULL,
+
+		CONSTRAINT fk_quarantined_readings_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT chk_quarantined_readings_policy CHECK (policy IN ('strict', 'warn', 'permissive')),
+		CONSTRAINT chk_quarantined_readings_status CHECK (status IN ('pending', 'replayed', 'discarded'))
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_quarantined_readings_status_created_at ON quarantined_readings(status, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_quarantined_readings_asset_sensor_id ON quarantined_readings(asset_sensor_id);
+	CREATE INDEX IF NOT EXISTS idx_quarantined_readings_sensor_type_id ON quarantined_readings(sensor_type_id);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create quarantined_readings table: %v", err)
+	}
+
+	log.Println("Quarantined readings table created successfully")
+	return nil
+}
+
+// CreateQuarantinedReadingTableIfNotExists creates the quarantined_readings table if it doesn't exist
+func CreateQuarantinedReadingTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating quarantined_readings table if it doesn't exist...")
+	return CreateQuarantinedReadingTable(db)
+}
@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateReadingArchiveTable creates the reading_archives table, the manifest of the archive
+// files the archived readings were exported to
+func CreateReadingArchiveTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS reading_archives (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NULL,
+		month TIMESTAMP NOT NULL,               -- Start of the UTC month of the readings
+		part INTEGER NOT NULL,
+		storage VARCHAR(50) NOT NULL,           -- Storage backend holding the file
+		object_key TEXT NOT NULL,
+		format VARCHAR(20) NOT NULL,            -- 'ndjson.gz'
+		reading_count BIGINT NOT NULL,
+		first_reading_time TIMESTAMP NOT NULL,
+		last_reading_time TIMESTAMP NOT NULL,
+		size_bytes BIGINT NOT NULL,
+		checksum VARCHAR(64) NOT NULL,          -- SHA-256 of the file
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		restored_at TIMESTAMP NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_reading_archives_part
+		ON reading_archives(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), month, part);
+	CREATE INDEX IF NOT EXISTS idx_reading_archives_tenant_month ON reading_archives(tenant_id, month);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("failed to create reading_archives table: %v", err)
+	}
+
+	log.Println("Reading archives table created successfully")
+	return nil
+}
+
+// CreateReadingArchiveTableIfNotExists creates the reading_archives table if it doesn't exist
+func CreateReadingArchiveTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating reading_archives table if it doesn't exist...")
+	return CreateReadingArchiveTable(db)
+}
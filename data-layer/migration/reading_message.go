@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateReadingMessageTable creates the reading_messages table, which records the readings
+// stored for every client message ID so redelivered messages are not stored twice
+func CreateReadingMessageTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS reading_messages (
+		asset_sensor_id UUID NOT NULL,
+		message_id VARCHAR(255) NOT NULL,
+		tenant_id UUID NULL,
+		reading_ids UUID[] NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		PRIMARY KEY (asset_sensor_id, message_id),
+		CONSTRAINT fk_reading_messages_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reading_messages_created_at ON reading_messages(created_at);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create reading_messages table: %v", err)
+	}
+
+	log.Println("Reading messages table created successfully")
+	return nil
+}
+
+// CreateReadingMessageTableIfNotExists creates the reading_messages table if it doesn't exist
+func CreateReadingMessageTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating reading_messages table if it doesn't exist...")
+	return CreateReadingMessageTable(db)
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateReadingOutboxTable creates the reading_outbox table, which records the side
+// effects of stored readings in the same transaction as the readings
+func CreateReadingOutboxTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS reading_outbox (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NULL,
+		reading_id UUID NOT NULL,
+		asset_sensor_id UUID NOT NULL,
+		effect VARCHAR(30) NOT NULL CHECK (effect IN ('last_reading', 'alert_evaluation')),
+		idempotency_key VARCHAR(100) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'done', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_error TEXT NULL,
+		completed_at TIMESTAMP NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT uq_reading_outbox_idempotency_key UNIQUE (idempotency_key)
+	);
+
+	-- Create indexes for better query performance
+	CREATE INDEX IF NOT EXISTS idx_reading_outbox_due ON reading_outbox(next_attempt_at)
+		WHERE status IN ('pending', 'processing');
+	CREATE INDEX IF NOT EXISTS idx_reading_outbox_reading_id ON reading_outbox(reading_id);
+	CREATE INDEX IF NOT EXISTS idx_reading_outbox_completed_at ON reading_outbox(completed_at)
+		WHERE status = 'done';
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create reading_outbox table: %v", err)
+	}
+
+	log.Println("Reading outbox table created successfully")
+	return nil
+}
+
+// CreateReadingOutboxTableIfNotExists creates the reading_outbox table if it doesn't exist
+func CreateReadingOutboxTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating reading_outbox table if it doesn't exist...")
+	return CreateReadingOutboxTable(db)
+}
@@ -0,0 +1,192 @@
+package migration
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateReadingRetentionTables creates the reading_retention_policies table, holding how long
+// each tenant's readings are kept, and the archived_iot_sensor_readings table, holding the
+// expired readings of tenants whose policy archives them. The archive table copies the
+// columns of iot_sensor_readings when it is created; columns added to iot_sensor_readings
+// later are added to both tables by addReadingColumns.
+func CreateReadingRetentionTables(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS reading_retention_policies (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NULL,                  -- NULL for the default policy
+		retention_days INTEGER NOT NULL CHECK (retention_days > 0),
+		action VARCHAR(20) NOT NULL DEFAULT 'delete' CHECK (action IN ('delete', 'archive')),
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL
+	);
+
+	-- One policy per tenant, and a single default policy
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_reading_retention_policies_tenant
+		ON reading_retention_policies(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+	CREATE TABLE IF NOT EXISTS archived_iot_sensor_readings (
+		LIKE iot_sensor_readings INCLUDING DEFAULTS,
+		archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		PRIMARY KEY (id, reading_time)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_archived_iot_readings_tenant_time ON archived_iot_sensor_readings(tenant_id, reading_time);
+	`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("failed to create reading retention tables: %v", err)
+	}
+
+	if err := addReadingColumns(db, "archived_iot_sensor_readings"); err != nil {
+		return err
+	}
+
+	log.Println("Reading retention tables created successfully")
+	return nil
+}
+
+// CreateReadingRetentionTablesIfNotExists creates the reading retention tables if they don't exist
+func CreateReadingRetentionTablesIfNotExists(db *sql.DB) error {
+	log.Println("Creating reading retention tables if they don't exist...")
+	return CreateReadingRetentionTables(db)
+}
+
+// isIoTSensorReadingTablePartitioned reports whether iot_sensor_readings is a partitioned table
+func isIoTSensorReadingTablePartitioned(db *sql.DB) (bool, error) {
+	var partitioned bool
+	err := db.QueryRow(`
+		SELECT c.relkind = 'p'
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = 'iot_sensor_readings'`).Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if iot_sensor_readings is partitioned: %v", err)
+	}
+	return partitioned, nil
+}
+
+// checkIoTSensorReadingPartitioning warns when iot_sensor_readings was created before it
+// was partitioned
+func checkIoTSensorReadingPartitioning(db *sql.DB) error {
+	partitioned, err := isIoTSensorReadingTablePartitioned(db)
+	if err != nil {
+		return err
+	}
+	if !partitioned {
+		log.Println("Warning: iot_sensor_readings is not partitioned; run the partition-readings action of helpers/cmd to partition it")
+	}
+	return nil
+}
+
+// PartitionIoTSensorReadingTable converts an iot_sensor_readings table created before it was
+// partitioned into a partitioned table, with a monthly partition for every month holding
+// readings. The readings are copied in a single transaction that locks the table, so it
+// should run while no readings are being stored.
+func PartitionIoTSensorReadingTable(db *sql.DB) error {
+	partitioned, err := isIoTSensorReadingTablePartitioned(db)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		log.Println("IoT sensor readings table is already partitioned")
+		return nil
+	}
+
+	// The copy needs every column of the partitioned table
+	if err := addReadingColumns(db, "iot_sensor_readings"); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		ALTER TABLE iot_sensor_readings RENAME TO iot_sensor_readings_unpartitioned;
+		ALTER INDEX IF EXISTS iot_sensor_readings_pkey RENAME TO iot_sensor_readings_unpartitioned_pkey;`)
+	if err != nil {
+		return fmt.Errorf("failed to rename iot_sensor_readings: %v", err)
+	}
+
+	// The indexes of the partitioned table take the names of the old ones
+	rows, err := tx.Query(`
+		SELECT indexname FROM pg_indexes
+		WHERE schemaname = 'public' AND tablename = 'iot_sensor_readings_unpartitioned'
+		AND indexname LIKE 'idx_iot_readings_%'`)
+	if err != nil {
+		return fmt.Errorf("failed to list iot_sensor_readings indexes: %v", err)
+	}
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan iot_sensor_readings index: %v", err)
+		}
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating iot_sensor_readings indexes: %v", err)
+	}
+	for _, index := range indexes {
+		if _, err := tx.Exec(fmt.Sprintf(`DROP INDEX %s`, index)); err != nil {
+			return fmt.Errorf("failed to drop index %s: %v", index, err)
+		}
+	}
+
+	if _, err := tx.Exec(createIoTSensorReadingTableSQL); err != nil {
+		return fmt.Errorf("failed to create partitioned iot_sensor_readings table: %v", err)
+	}
+
+	var first, last sql.NullTime
+	err = tx.QueryRow(`SELECT MIN(reading_time), MAX(reading_time) FROM iot_sensor_readings_unpartitioned`).Scan(&first, &last)
+	if err != nil {
+		return fmt.Errorf("failed to get reading time range: %v", err)
+	}
+	if first.Valid {
+		for month := entity.ReadingPartitionMonth(first.Time); !month.After(last.Time); month = month.AddDate(0, 1, 0) {
+			createPartitionSQL := fmt.Sprintf(`CREATE TABLE %s PARTITION OF iot_sensor_readings FOR VALUES FROM ('%s') TO ('%s')`,
+				entity.ReadingPartitionName(month),
+				month.Format("2006-01-02 15:04:05"),
+				month.AddDate(0, 1, 0).Format("2006-01-02 15:04:05"))
+			if _, err := tx.Exec(createPartitionSQL); err != nil {
+				return fmt.Errorf("failed to create partition %s: %v", entity.ReadingPartitionName(month), err)
+			}
+		}
+	}
+
+	// Columns added to the old table after it was created come last, so copy by name
+	var columns string
+	err = tx.QueryRow(`
+		SELECT string_agg(quote_ident(column_name), ', ' ORDER BY ordinal_position)
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = 'iot_sensor_readings'`).Scan(&columns)
+	if err != nil {
+		return fmt.Errorf("failed to get iot_sensor_readings columns: %v", err)
+	}
+
+	copySQL := fmt.Sprintf(`INSERT INTO iot_sensor_readings (%[1]s) SELECT %[1]s FROM iot_sensor_readings_unpartitioned`, columns)
+	result, err := tx.Exec(copySQL)
+	if err != nil {
+		return fmt.Errorf("failed to copy readings into the partitioned table: %v", err)
+	}
+	copied, _ := result.RowsAffected()
+
+	if _, err := tx.Exec(`DROP TABLE iot_sensor_readings_unpartitioned`); err != nil {
+		return fmt.Errorf("failed to drop the unpartitioned readings table: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit iot_sensor_readings partitioning: %v", err)
+	}
+
+	log.Printf("IoT sensor readings table partitioned successfully (%d readings copied)", copied)
+	return nil
+}
@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateReadingRollupTables creates a rollup table per rollup resolution, holding the
+// count, sum, minimum and maximum of each asset sensor's numeric readings per measurement,
+// unit and UTC bucket, and the reading_rollup_state table tracking how far each rollup
+// has been refreshed
+func CreateReadingRollupTables(db *sql.DB) error {
+	for _, resolution := range entity.ReadingRollupResolutions {
+		createTableSQL := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %[1]s (
+			asset_sensor_id UUID NOT NULL,
+			measurement_type VARCHAR(100) NOT NULL,
+			measurement_unit VARCHAR(50) NOT NULL DEFAULT '', -- Empty for readings without a unit
+			bucket TIMESTAMP NOT NULL,                         -- Start of the UTC bucket
+			tenant_id UUID NULL,
+			sensor_type_id UUID NOT NULL,
+			measurement_label VARCHAR(255) NULL,
+			reading_count BIGINT NOT NULL,
+			value_sum DOUBLE PRECISION NOT NULL,
+			value_min DOUBLE PRECISION NOT NULL,
+			value_max DOUBLE PRECISION NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+			PRIMARY KEY (asset_sensor_id, measurement_type, measurement_unit, bucket),
+			CONSTRAINT fk_%[1]s_asset_sensor_id
+				FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+				ON DELETE CASCADE ON UPDATE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_%[1]s_bucket ON %[1]s(bucket);
+		CREATE INDEX IF NOT EXISTS idx_%[1]s_tenant_bucket ON %[1]s(tenant_id, bucket);
+		`, resolution.Table)
+
+		if _, err := db.Exec(createTableSQL); err != nil {
+			return fmt.Errorf("failed to create %s table: %v", resolution.Table, err)
+		}
+	}
+
+	createStateSQL := `
+	CREATE TABLE IF NOT EXISTS reading_rollup_state (
+		resolution VARCHAR(10) PRIMARY KEY,
+		refreshed_through TIMESTAMP NOT NULL, -- Readings created before this are rolled up
+		updated_at TIMESTAMP NULL
+	);
+	`
+
+	if _, err := db.Exec(createStateSQL); err != nil {
+		return fmt.Errorf("failed to create reading_rollup_state table: %v", err)
+	}
+
+	log.Println("Reading rollup tables created successfully")
+	return nil
+}
+
+// CreateReadingRollupTablesIfNotExists creates the reading rollup tables if they don't exist
+func CreateReadingRollupTablesIfNotExists(db *sql.DB) error {
+	log.Println("Creating reading rollup tables if they don't exist...")
+	return CreateReadingRollupTables(db)
+}
@@ -62,6 +62,61 @@ func CreateSensorThresholdTable(db *sql.DB) error {
 		return fmt.Errorf("failed to create sensor_thresholds table: %w", err)
 	}
 
+	// Add alert rule columns to existing tables
+	_, err = db.Exec(`
+		ALTER TABLE sensor_thresholds
+			ADD COLUMN IF NOT EXISTS hysteresis DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (hysteresis >= 0),
+			ADD COLUMN IF NOT EXISTS breach_duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (breach_duration_seconds >= 0),
+			ADD COLUMN IF NOT EXISTS breach_count INTEGER NOT NULL DEFAULT 0 CHECK (breach_count >= 0),
+			ADD COLUMN IF NOT EXISTS clear_duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (clear_duration_seconds >= 0),
+			ADD COLUMN IF NOT EXISTS clear_count INTEGER NOT NULL DEFAULT 0 CHECK (clear_count >= 0);
+	`)
+	if err != nil {
+		log.Printf("Error adding alert rule columns to sensor_thresholds: %v", err)
+		return fmt.Errorf("failed to add alert rule columns to sensor_thresholds: %w", err)
+	}
+
+	// Add rule kind columns; thresholds of different kinds may share a field and severity
+	_, err = db.Exec(`
+		ALTER TABLE sensor_thresholds
+			ADD COLUMN IF NOT EXISTS rule_kind VARCHAR(20) NOT NULL DEFAULT 'static'
+				CHECK (rule_kind IN ('static', 'delta', 'rate', 'rolling_avg', 'rolling_min', 'rolling_max')),
+			ADD COLUMN IF NOT EXISTS window_seconds INTEGER NOT NULL DEFAULT 0 CHECK (window_seconds >= 0);
+
+		ALTER TABLE sensor_thresholds DROP CONSTRAINT IF EXISTS uq_sensor_threshold_field_severity;
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_sensor_threshold_field_severity_rule
+			ON sensor_thresholds(asset_sensor_id, measurement_field_name, severity, rule_kind, window_seconds);
+	`)
+	if err != nil {
+		log.Printf("Error adding rule kind columns to sensor_thresholds: %v", err)
+		return fmt.Errorf("failed to add rule kind columns to sensor_thresholds: %w", err)
+	}
+
+	// Add boolean and text rule columns; these rules set no min/max value
+	_, err = db.Exec(`
+		ALTER TABLE sensor_thresholds
+			ADD COLUMN IF NOT EXISTS expected_boolean BOOLEAN NULL,
+			ADD COLUMN IF NOT EXISTS text_values TEXT[] NULL,
+			ADD COLUMN IF NOT EXISTS text_pattern TEXT NULL;
+
+		ALTER TABLE sensor_thresholds DROP CONSTRAINT IF EXISTS sensor_thresholds_rule_kind_check;
+		ALTER TABLE sensor_thresholds ADD CONSTRAINT sensor_thresholds_rule_kind_check CHECK (rule_kind IN (
+			'static', 'delta', 'rate', 'rolling_avg', 'rolling_min', 'rolling_max',
+			'boolean_equals', 'boolean_duration', 'text_equals', 'text_in_set', 'text_regex'
+		));
+
+		ALTER TABLE sensor_thresholds DROP CONSTRAINT IF EXISTS chk_threshold_values;
+		ALTER TABLE sensor_thresholds DROP CONSTRAINT IF EXISTS chk_threshold_rule_values;
+		ALTER TABLE sensor_thresholds ADD CONSTRAINT chk_threshold_rule_values CHECK (
+			min_value IS NOT NULL OR max_value IS NOT NULL OR expected_boolean IS NOT NULL
+			OR text_values IS NOT NULL OR text_pattern IS NOT NULL
+		);
+	`)
+	if err != nil {
+		log.Printf("Error adding value match columns to sensor_thresholds: %v", err)
+		return fmt.Errorf("failed to add value match columns to sensor_thresholds: %w", err)
+	}
+
 	log.Println("Successfully created sensor_thresholds table")
 	return nil
 }
@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateSensorThresholdStateTable creates the sensor_threshold_states table
+func CreateSensorThresholdStateTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS sensor_threshold_states (
+		threshold_id UUID NOT NULL,
+		asset_sensor_id UUID NOT NULL,
+		in_alert BOOLEAN NOT NULL DEFAULT false,
+		breach_started_at TIMESTAMP NULL,
+		consecutive_breaches INTEGER NOT NULL DEFAULT 0,
+		clear_started_at TIMESTAMP NULL,
+		consecutive_clears INTEGER NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+		PRIMARY KEY (threshold_id, asset_sensor_id),
+		CONSTRAINT fk_sensor_threshold_states_threshold_id
+			FOREIGN KEY (threshold_id) REFERENCES sensor_thresholds(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_sensor_threshold_states_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_sensor_threshold_states_asset_sensor_id ON sensor_threshold_states(asset_sensor_id);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create sensor_threshold_states table: %v", err)
+	}
+
+	// Remember the last applied reading so a redelivered reading is applied once
+	_, err = db.Exec(`ALTER TABLE sensor_threshold_states ADD COLUMN IF NOT EXISTS last_reading_id UUID NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add last_reading_id to sensor_threshold_states: %v", err)
+	}
+
+	// Remember the newest applied reading time so readings are applied in event-time order
+	_, err = db.Exec(`ALTER TABLE sensor_threshold_states ADD COLUMN IF NOT EXISTS last_reading_time TIMESTAMP NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to add last_reading_time to sensor_threshold_states: %v", err)
+	}
+
+	log.Println("Sensor threshold states table created successfully")
+	return nil
+}
+
+// CreateSensorThresholdStateTableIfNotExists creates the sensor_threshold_states table if it doesn't exist
+func CreateSensorThresholdStateTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating sensor_threshold_states table if it doesn't exist...")
+	return CreateSensorThresholdStateTable(db)
+}
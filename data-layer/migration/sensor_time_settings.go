@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// CreateSensorTimeSettingsTable creates the sensor_time_settings table, which holds how the
+// timestamps of an asset sensor's readings are parsed and corrected. Sensors without a row
+// use the defaults.
+func CreateSensorTimeSettingsTable(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS sensor_time_settings (
+		asset_sensor_id UUID PRIMARY KEY,
+		tenant_id UUID NULL,
+		timestamp_format VARCHAR(100) NOT NULL DEFAULT 'auto',
+		timezone VARCHAR(100) NOT NULL DEFAULT 'UTC',
+		clock_offset_ms BIGINT NOT NULL DEFAULT 0,
+		max_future_skew_seconds INTEGER NOT NULL DEFAULT 300,
+		max_age_seconds BIGINT NOT NULL DEFAULT 2592000,
+		skew_action VARCHAR(20) NOT NULL DEFAULT 'flag',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NULL,
+
+		CONSTRAINT fk_sensor_time_settings_asset_sensor_id
+			FOREIGN KEY (asset_sensor_id) REFERENCES asset_sensors(id)
+			ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT chk_sensor_time_settings_skew_action
+			CHECK (skew_action IN ('flag', 'receive_time', 'reject'))
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_sensor_time_settings_tenant_id ON sensor_time_settings(tenant_id);
+	`
+
+	_, err := db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create sensor_time_settings table: %v", err)
+	}
+
+	log.Println("Sensor time settings table created successfully")
+	return nil
+}
+
+// CreateSensorTimeSettingsTableIfNotExists creates the sensor_time_settings table if it doesn't exist
+func CreateSensorTimeSettingsTableIfNotExists(db *sql.DB) error {
+	log.Println("Creating sensor_time_settings table if it doesn't exist...")
+	return CreateSensorTimeSettingsTable(db)
+}
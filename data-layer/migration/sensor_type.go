@@ -17,6 +17,7 @@ func CreateSensorTypeTables(db *sql.DB) error {
 			model VARCHAR(255),
 			version VARCHAR(50) NOT NULL DEFAULT '1.0.0',
 			is_active BOOLEAN NOT NULL DEFAULT true,
+			ingestion_policy VARCHAR(20) NOT NULL DEFAULT 'warn',
 			created_at TIMESTAMP NOT NULL,
 			updated_at TIMESTAMP
 		)
@@ -25,6 +26,15 @@ func CreateSensorTypeTables(db *sql.DB) error {
 		return fmt.Errorf("error creating sensor_types table: %v", err)
 	}
 
+	// Tables created before ingestion policies were added
+	_, err = db.Exec(`
+		ALTER TABLE sensor_types
+			ADD COLUMN IF NOT EXISTS ingestion_policy VARCHAR(20) NOT NULL DEFAULT 'warn'
+	`)
+	if err != nil {
+		return fmt.Errorf("error adding ingestion_policy to sensor_types table: %v", err)
+	}
+
 	return nil
 }
 
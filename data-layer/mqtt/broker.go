@@ -0,0 +1,79 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBrokerClosed is returned when publishing to or subscribing on a closed broker
+var ErrBrokerClosed = errors.New("mqtt broker is closed")
+
+type subscription struct {
+	filter  string
+	qos     byte
+	handler MessageHandler
+}
+
+// InProcessBroker is an embedded broker that delivers messages to subscribers
+// in the same process. It is used for local development and for exercising the
+// ingestion bridge without a network broker.
+type InProcessBroker struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	closed        bool
+}
+
+// NewInProcessBroker creates a new InProcessBroker
+func NewInProcessBroker() *InProcessBroker {
+	return &InProcessBroker{}
+}
+
+// Subscribe registers a handler for all topics matching the filter
+func (b *InProcessBroker) Subscribe(filter string, qos byte, handler MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBrokerClosed
+	}
+
+	b.subscriptions = append(b.subscriptions, subscription{filter: filter, qos: qos, handler: handler})
+	return nil
+}
+
+// Publish delivers a message synchronously to every matching subscription. It returns the
+// errors of the handlers that failed.
+func (b *InProcessBroker) Publish(topic string, payload []byte) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBrokerClosed
+	}
+	var matched []subscription
+	for _, sub := range b.subscriptions {
+		if MatchTopic(sub.filter, topic) {
+			matched = append(matched, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range matched {
+		if err := sub.handler(Message{Topic: topic, Payload: payload, QoS: sub.qos}); err != nil {
+			errs = append(errs, fmt.Errorf("handler of %s failed: %w", sub.filter, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close removes all subscriptions and rejects further publishes
+func (b *InProcessBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.subscriptions = nil
+	return nil
+}
@@ -0,0 +1,530 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types
+const (
+	packetConnect    byte = 1
+	packetConnAck    byte = 2
+	packetPublish    byte = 3
+	packetPubAck     byte = 4
+	packetSubscribe  byte = 8
+	packetSubAck     byte = 9
+	packetPingReq    byte = 12
+	packetPingResp   byte = 13
+	packetDisconnect byte = 14
+	protocolLevel311 byte = 4
+)
+
+// maxReconnectDelay caps the exponential backoff between reconnect attempts
+const maxReconnectDelay = 2 * time.Minute
+
+// Client is a minimal MQTT 3.1.1 client supporting QoS 0 and 1 subscriptions.
+// It reconnects automatically and restores its subscriptions after a reconnect.
+type Client struct {
+	config *MQTTConfig
+
+	mu            sync.Mutex
+	writeMu       sync.Mutex
+	conn          net.Conn
+	subscriptions []subscription
+	packetID      uint16
+	subAcks       map[uint16]chan byte
+
+	messages chan Message
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewClient creates a new MQTT client. Call Connect to establish the session.
+func NewClient(config *MQTTConfig) *Client {
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = 60 * time.Second
+	}
+	if config.ConnectTimeout <= 0 {
+		config.ConnectTimeout = 10 * time.Second
+	}
+
+	return &Client{
+		config:   config,
+		subAcks:  make(map[uint16]chan byte),
+		messages: make(chan Message, 256),
+	}
+}
+
+// Connect dials the broker and starts the background read, keepalive and
+// dispatch loops. The first connection attempt must succeed.
+func (c *Client) Connect(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.setConn(conn)
+	c.resubscribe(conn)
+
+	c.wg.Add(2)
+	go c.dispatchLoop()
+	go c.connectionLoop(conn)
+
+	log.Printf("MQTT client %s connected to %s", c.config.ClientID, c.config.BrokerURL)
+	return nil
+}
+
+// Subscribe registers a handler for a topic filter. QoS is capped at 1.
+func (c *Client) Subscribe(filter string, qos byte, handler MessageHandler) error {
+	if qos > 1 {
+		qos = 1
+	}
+
+	c.mu.Lock()
+	c.subscriptions = append(c.subscriptions, subscription{filter: filter, qos: qos, handler: handler})
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		// Will be subscribed once the connection is (re-)established
+		return nil
+	}
+
+	return c.sendSubscribe(conn, filter, qos)
+}
+
+// Close disconnects from the broker and waits for background loops to stop
+func (c *Client) Close() error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	if conn != nil {
+		_ = c.writePacket(conn, packetDisconnect<<4, nil)
+		conn.Close()
+	}
+
+	c.wg.Wait()
+	return nil
+}
+
+// dial opens the network connection and performs the CONNECT/CONNACK handshake
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	u, err := url.Parse(c.config.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT broker URL: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: c.config.ConnectTimeout}
+	var conn net.Conn
+
+	switch u.Scheme {
+	case "tcp", "mqtt", "":
+		conn, err = dialer.DialContext(ctx, "tcp", u.Host)
+	case "ssl", "tls", "mqtts":
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}).DialContext(ctx, "tcp", u.Host)
+	default:
+		return nil, fmt.Errorf("unsupported MQTT broker scheme: %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	if err := c.handshake(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// handshake sends CONNECT and waits for a successful CONNACK
+func (c *Client) handshake(conn net.Conn) error {
+	var flags byte
+	if c.config.CleanSession {
+		flags |= 0x02
+	}
+
+	var payload []byte
+	payload = appendString(payload, c.config.ClientID)
+	if c.config.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, c.config.Username)
+		if c.config.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, c.config.Password)
+		}
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, protocolLevel311, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(c.config.KeepAlive/time.Second))
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(c.config.ConnectTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	if err := c.writePacket(conn, packetConnect<<4, body); err != nil {
+		return fmt.Errorf("failed to send MQTT CONNECT: %w", err)
+	}
+
+	header, data, err := readPacket(bufio.NewReader(conn))
+	if err != nil {
+		return fmt.Errorf("failed to read MQTT CONNACK: %w", err)
+	}
+	if header>>4 != packetConnAck || len(data) < 2 {
+		return errors.New("unexpected packet while waiting for MQTT CONNACK")
+	}
+	if data[1] != 0 {
+		return fmt.Errorf("MQTT broker refused connection (return code %d)", data[1])
+	}
+
+	return nil
+}
+
+// connectionLoop reads packets from the current connection and reconnects
+// with exponential backoff when the connection drops
+func (c *Client) connectionLoop(conn net.Conn) {
+	defer c.wg.Done()
+
+	delay := time.Second
+	for {
+		err := c.readLoop(conn)
+
+		if c.ctx.Err() != nil {
+			return
+		}
+		log.Printf("MQTT connection lost: %v", err)
+		c.setConn(nil)
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			newConn, err := c.dial(c.ctx)
+			if err == nil {
+				conn = newConn
+				c.setConn(conn)
+				c.resubscribe(conn)
+				delay = time.Second
+				log.Printf("MQTT client %s reconnected to %s", c.config.ClientID, c.config.BrokerURL)
+				break
+			}
+
+			log.Printf("MQTT reconnect failed: %v", err)
+			delay *= 2
+			if delay > maxReconnectDelay {
+				delay = maxReconnectDelay
+			}
+		}
+	}
+}
+
+// readLoop processes incoming packets until the connection fails
+func (c *Client) readLoop(conn net.Conn) error {
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go c.keepAlive(conn, stopPing)
+
+	reader := bufio.NewReader(conn)
+	for {
+		// Broker must answer within 1.5x keepalive (we ping every keepalive/2)
+		conn.SetReadDeadline(time.Now().Add(c.config.KeepAlive * 3 / 2))
+
+		header, data, err := readPacket(reader)
+		if err != nil {
+			return err
+		}
+
+		switch header >> 4 {
+		case packetPublish:
+			msg, packetID, err := decodePublish(header, data)
+			if err != nil {
+				log.Printf("Ignoring malformed MQTT PUBLISH: %v", err)
+				continue
+			}
+			msg.packetID = packetID
+			msg.conn = conn
+
+			// QoS 1 messages are acknowledged by dispatchLoop once they are handled
+			select {
+			case c.messages <- msg:
+			case <-c.ctx.Done():
+				return c.ctx.Err()
+			}
+		case packetSubAck:
+			if len(data) < 3 {
+				continue
+			}
+			packetID := binary.BigEndian.Uint16(data[:2])
+			c.mu.Lock()
+			ch, ok := c.subAcks[packetID]
+			delete(c.subAcks, packetID)
+			c.mu.Unlock()
+			if ok {
+				ch <- data[2]
+			}
+		case packetPingResp, packetPubAck:
+			// Nothing to do
+		default:
+			log.Printf("Ignoring unexpected MQTT packet type %d", header>>4)
+		}
+	}
+}
+
+// keepAlive sends PINGREQ packets until stopped
+func (c *Client) keepAlive(conn net.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.config.KeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.writePacket(conn, packetPingReq<<4, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dispatchLoop delivers messages to handlers in arrival order and acknowledges QoS 1
+// messages after their handlers succeeded
+func (c *Client) dispatchLoop() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case msg := <-c.messages:
+			c.mu.Lock()
+			subs := make([]subscription, len(c.subscriptions))
+			copy(subs, c.subscriptions)
+			c.mu.Unlock()
+
+			var handlerErr error
+			for _, sub := range subs {
+				if MatchTopic(sub.filter, msg.Topic) {
+					if err := sub.handler(msg); err != nil {
+						handlerErr = err
+					}
+				}
+			}
+
+			if msg.QoS == 1 {
+				c.acknowledge(msg, handlerErr)
+			}
+		}
+	}
+}
+
+// acknowledge sends the PUBACK of a handled QoS 1 message. When a handler failed, the
+// message is left unacknowledged and its connection is dropped, so the broker delivers
+// it again when the session is resumed.
+func (c *Client) acknowledge(msg Message, handlerErr error) {
+	if handlerErr != nil {
+		log.Printf("Not acknowledging MQTT message on %s, reconnecting for redelivery: %v", msg.Topic, handlerErr)
+		msg.conn.Close()
+		return
+	}
+
+	if err := c.writePacket(msg.conn, packetPubAck<<4, binary.BigEndian.AppendUint16(nil, msg.packetID)); err != nil {
+		// The connection is gone; the broker delivers the message again on the next one
+		log.Printf("Failed to acknowledge MQTT message on %s: %v", msg.Topic, err)
+	}
+}
+
+// resubscribe restores all registered subscriptions on a fresh connection
+func (c *Client) resubscribe(conn net.Conn) {
+	c.mu.Lock()
+	subs := make([]subscription, len(c.subscriptions))
+	copy(subs, c.subscriptions)
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		// SUBACK is read by readLoop, which starts right after this, so don't wait here
+		if err := c.writeSubscribe(conn, sub.filter, sub.qos); err != nil {
+			log.Printf("Failed to resubscribe to %s: %v", sub.filter, err)
+		}
+	}
+}
+
+// sendSubscribe sends a SUBSCRIBE packet and waits for the broker's SUBACK
+func (c *Client) sendSubscribe(conn net.Conn, filter string, qos byte) error {
+	c.mu.Lock()
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+	packetID := c.packetID
+	ack := make(chan byte, 1)
+	c.subAcks[packetID] = ack
+	c.mu.Unlock()
+
+	if err := c.writeSubscribePacket(conn, packetID, filter, qos); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", filter, err)
+	}
+
+	select {
+	case code := <-ack:
+		if code == 0x80 {
+			return fmt.Errorf("MQTT broker rejected subscription to %s", filter)
+		}
+		log.Printf("Subscribed to MQTT topic %s (granted QoS %d)", filter, code)
+		return nil
+	case <-time.After(c.config.ConnectTimeout):
+		c.mu.Lock()
+		delete(c.subAcks, packetID)
+		c.mu.Unlock()
+		return fmt.Errorf("timed out waiting for SUBACK on %s", filter)
+	}
+}
+
+// writeSubscribe sends a SUBSCRIBE packet without waiting for the SUBACK
+func (c *Client) writeSubscribe(conn net.Conn, filter string, qos byte) error {
+	c.mu.Lock()
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+	packetID := c.packetID
+	c.mu.Unlock()
+
+	return c.writeSubscribePacket(conn, packetID, filter, qos)
+}
+
+func (c *Client) writeSubscribePacket(conn net.Conn, packetID uint16, filter string, qos byte) error {
+	body := binary.BigEndian.AppendUint16(nil, packetID)
+	body = appendString(body, filter)
+	body = append(body, qos)
+
+	return c.writePacket(conn, packetSubscribe<<4|0x02, body)
+}
+
+// writePacket writes a complete control packet, serialising concurrent writers
+func (c *Client) writePacket(conn net.Conn, header byte, body []byte) error {
+	packet := []byte{header}
+	packet = appendRemainingLength(packet, len(body))
+	packet = append(packet, body...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err := conn.Write(packet)
+	return err
+}
+
+func (c *Client) setConn(conn net.Conn) {
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+}
+
+// readPacket reads a single control packet and returns its first header byte and body
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := 0
+	multiplier := 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed MQTT remaining length")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+
+	return header, data, nil
+}
+
+// decodePublish parses a PUBLISH packet body
+func decodePublish(header byte, data []byte) (Message, uint16, error) {
+	qos := (header >> 1) & 0x03
+	if len(data) < 2 {
+		return Message{}, 0, errors.New("packet too short")
+	}
+
+	topicLen := int(binary.BigEndian.Uint16(data[:2]))
+	if len(data) < 2+topicLen {
+		return Message{}, 0, errors.New("topic length exceeds packet")
+	}
+	topic := string(data[2 : 2+topicLen])
+	rest := data[2+topicLen:]
+
+	var packetID uint16
+	if qos > 0 {
+		if len(rest) < 2 {
+			return Message{}, 0, errors.New("missing packet identifier")
+		}
+		packetID = binary.BigEndian.Uint16(rest[:2])
+		rest = rest[2:]
+	}
+
+	payload := make([]byte, len(rest))
+	copy(payload, rest)
+
+	return Message{
+		Topic:    topic,
+		Payload:  payload,
+		QoS:      qos,
+		Retained: header&0x01 == 1,
+	}, packetID, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func appendRemainingLength(b []byte, length int) []byte {
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if length == 0 {
+			return b
+		}
+	}
+}
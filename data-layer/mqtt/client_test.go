@@ -0,0 +1,156 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeBrokerConn is a client connection accepted by the fake broker, after CONNECT was
+// answered
+type fakeBrokerConn struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	cleanSession bool
+}
+
+// startFakeBroker accepts MQTT connections on a local listener and answers their CONNECT
+func startFakeBroker(t *testing.T) (string, <-chan *fakeBrokerConn) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	conns := make(chan *fakeBrokerConn, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			header, data, err := readPacket(reader)
+			if err != nil || header>>4 != packetConnect {
+				conn.Close()
+				continue
+			}
+			// Connect flags follow the protocol name and level
+			flags := data[7]
+			conn.Write([]byte{packetConnAck << 4, 2, 0, 0})
+			conns <- &fakeBrokerConn{conn: conn, reader: reader, cleanSession: flags&0x02 != 0}
+		}
+	}()
+	return "tcp://" + listener.Addr().String(), conns
+}
+
+// expect reads the next packet, failing the test unless it has the given type
+func (b *fakeBrokerConn) expect(t *testing.T, packetType byte) []byte {
+	t.Helper()
+	b.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	header, data, err := readPacket(b.reader)
+	if err != nil {
+		t.Fatalf("failed to read packet type %d: %v", packetType, err)
+	}
+	if header>>4 != packetType {
+		t.Fatalf("received packet type %d, want %d", header>>4, packetType)
+	}
+	return data
+}
+
+// acceptSubscribe answers the client's SUBSCRIBE with a QoS 1 grant
+func (b *fakeBrokerConn) acceptSubscribe(t *testing.T) {
+	t.Helper()
+	data := b.expect(t, packetSubscribe)
+	b.conn.Write([]byte{packetSubAck << 4, 3, data[0], data[1], 1})
+}
+
+// publish sends a QoS 1 PUBLISH to the client
+func (b *fakeBrokerConn) publish(topic string, packetID uint16, payload string) {
+	body := appendString(nil, topic)
+	body = binary.BigEndian.AppendUint16(body, packetID)
+	body = append(body, payload...)
+	packet := appendRemainingLength([]byte{packetPublish<<4 | 0x02}, len(body))
+	b.conn.Write(append(packet, body...))
+}
+
+// connectTestClient connects a client to the fake broker and subscribes handler to
+// sensors/+
+func connectTestClient(t *testing.T, handler MessageHandler) (*Client, *fakeBrokerConn, <-chan *fakeBrokerConn) {
+	t.Helper()
+	url, conns := startFakeBroker(t)
+	client := NewClient(&MQTTConfig{BrokerURL: url, ClientID: "test", ConnectTimeout: 5 * time.Second})
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect returned error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	broker := <-conns
+	subscribed := make(chan error, 1)
+	go func() { subscribed <- client.Subscribe("sensors/+", 1, handler) }()
+	broker.acceptSubscribe(t)
+	if err := <-subscribed; err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	return client, broker, conns
+}
+
+func TestClientAcknowledgesAfterHandler(t *testing.T) {
+	handled := make(chan Message, 1)
+	release := make(chan struct{})
+	_, broker, _ := connectTestClient(t, func(msg Message) error {
+		handled <- msg
+		<-release
+		return nil
+	})
+
+	broker.publish("sensors/a", 7, `{"temperature": 21.5}`)
+	msg := <-handled
+	if msg.Topic != "sensors/a" || string(msg.Payload) != `{"temperature": 21.5}` || msg.QoS != 1 {
+		t.Errorf("handled message %+v", msg)
+	}
+
+	// Nothing is acknowledged while the handler is still running
+	broker.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, _, err := readPacket(broker.reader); err == nil {
+		t.Fatal("received a packet before the handler returned")
+	} else if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	close(release)
+	data := broker.expect(t, packetPubAck)
+	if packetID := binary.BigEndian.Uint16(data); packetID != 7 {
+		t.Errorf("PUBACK for packet %d, want 7", packetID)
+	}
+}
+
+func TestClientLeavesFailedMessageUnacknowledged(t *testing.T) {
+	client, broker, conns := connectTestClient(t, func(msg Message) error {
+		return errors.New("database unavailable")
+	})
+
+	broker.publish("sensors/a", 9, `{"temperature": 21.5}`)
+
+	// The connection is dropped without a PUBACK
+	broker.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if header, _, err := readPacket(broker.reader); err == nil {
+		t.Fatalf("received packet type %d, want the connection to be closed", header>>4)
+	}
+
+	// and the session is resumed, so the broker can deliver the message again
+	select {
+	case resumed := <-conns:
+		if resumed.cleanSession != client.config.CleanSession {
+			t.Errorf("reconnected with clean session %v", resumed.cleanSession)
+		}
+		resumed.acceptSubscribe(t)
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not reconnect")
+	}
+}
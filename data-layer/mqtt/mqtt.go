@@ -0,0 +1,62 @@
+package mqtt
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// MQTTConfig holds MQTT broker connection configuration
+type MQTTConfig struct {
+	BrokerURL      string // e.g. tcp://localhost:1883 or ssl://broker:8883
+	ClientID       string
+	Username       string
+	Password       string
+	KeepAlive      time.Duration
+	ConnectTimeout time.Duration
+	CleanSession   bool
+}
+
+// Message represents a message received from a subscribed topic
+type Message struct {
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retained bool
+
+	packetID uint16   // Identifier a QoS 1 message is acknowledged with
+	conn     net.Conn // Connection a QoS 1 message is acknowledged on
+}
+
+// MessageHandler is called for every message delivered to a subscription. A QoS 1
+// message is only acknowledged once every handler returned nil, so returning an error
+// has the broker deliver the message again.
+type MessageHandler func(msg Message) error
+
+// Subscriber is implemented by anything that can deliver MQTT messages,
+// either a real broker connection (Client) or the InProcessBroker
+type Subscriber interface {
+	Subscribe(filter string, qos byte, handler MessageHandler) error
+	Close() error
+}
+
+// MatchTopic reports whether a topic name matches a subscription filter,
+// supporting the single-level (+) and multi-level (#) wildcards
+func MatchTopic(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
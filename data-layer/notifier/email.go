@@ -0,0 +1,138 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the SMTP server used for email notifications
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// EmailSender sends notifications as plain text email. Channels may override the
+// default SMTP server with their own smtp_* settings.
+type EmailSender struct {
+	defaults SMTPConfig
+}
+
+// NewEmailSender creates an email sender using the default SMTP server
+func NewEmailSender(defaults SMTPConfig) *EmailSender {
+	return &EmailSender{defaults: defaults}
+}
+
+// Send emails the message to the channel recipients
+func (s *EmailSender) Send(ctx context.Context, channel *entity.NotificationChannel, msg *Message) error {
+	cfg := s.defaults
+	if channel.Config.SMTPHost != "" {
+		cfg.Host = channel.Config.SMTPHost
+		cfg.Port = channel.Config.SMTPPort
+		cfg.Username = channel.Config.SMTPUsername
+		cfg.Password = channel.Config.SMTPPassword
+	}
+	if channel.Config.SMTPFrom != "" {
+		cfg.From = channel.Config.SMTPFrom
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 25
+	}
+	if cfg.Host == "" || cfg.From == "" {
+		return fmt.Errorf("smtp host and from address are not configured")
+	}
+
+	return sendMail(ctx, cfg, channel.Config.Recipients, buildEmail(cfg.From, channel.Config.Recipients, msg))
+}
+
+// buildEmail renders an RFC 5322 plain text message
+func buildEmail(from string, to []string, msg *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + stripNewlines(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("X-LecSens-Event: " + string(msg.Event) + "\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// sendMail is smtp.SendMail with a dial timeout and context deadline. STARTTLS is
+// used when the server offers it; authentication only when a username is set.
+func sendMail(ctx context.Context, cfg SMTPConfig, to []string, body []byte) error {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// stripNewlines prevents header injection through the subject
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
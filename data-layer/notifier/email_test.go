@@ -0,0 +1,222 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpEnvelope is a message received by the fake SMTP server
+type smtpEnvelope struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer is a minimal plain SMTP server on a local listener. It rejects recipients
+// listed in reject and hands every accepted message to the messages channel.
+type fakeSMTPServer struct {
+	listener net.Listener
+	reject   map[string]bool
+	messages chan smtpEnvelope
+}
+
+func newFakeSMTPServer(t *testing.T, reject ...string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &fakeSMTPServer{
+		listener: listener,
+		reject:   make(map[string]bool),
+		messages: make(chan smtpEnvelope, 10),
+	}
+	for _, address := range reject {
+		server.reject[address] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+// port returns the port the server listens on
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 fake.smtp ESMTP")
+	var envelope smtpEnvelope
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250-fake.smtp")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			envelope = smtpEnvelope{from: smtpPath(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			recipient := smtpPath(line[len("RCPT TO:"):])
+			if s.reject[recipient] {
+				reply("550 No such user")
+				continue
+			}
+			envelope.to = append(envelope.to, recipient)
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			envelope.data = data.String()
+			s.messages <- envelope
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// smtpPath returns the address of a MAIL FROM or RCPT TO argument, without its parameters
+func smtpPath(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.Index(arg, ">"); strings.HasPrefix(arg, "<") && end > 0 {
+		return arg[1:end]
+	}
+	return arg
+}
+
+func TestEmailSenderSendsMessage(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewEmailSender(SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		From:    "alerts@lecsens.test",
+		Timeout: 5 * time.Second,
+	})
+	channel := &entity.NotificationChannel{
+		Type: entity.NotificationChannelEmail,
+		Config: entity.NotificationChannelConfig{
+			Recipients: []string{"ops@example.com", "oncall@example.com"},
+		},
+	}
+
+	msg := testMessage()
+	msg.Subject = "Temperature too high\r\nBcc: attacker@example.com"
+	msg.Text = "Sensor reported 42.5\nCheck the asset"
+	if err := sender.Send(context.Background(), channel, msg); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	envelope := <-server.messages
+	if envelope.from != "alerts@lecsens.test" {
+		t.Errorf("MAIL FROM = %q, want alerts@lecsens.test", envelope.from)
+	}
+	if strings.Join(envelope.to, ",") != "ops@example.com,oncall@example.com" {
+		t.Errorf("RCPT TO = %v, want both recipients", envelope.to)
+	}
+
+	for _, want := range []string{
+		"From: alerts@lecsens.test\r\n",
+		"To: ops@example.com, oncall@example.com\r\n",
+		"Subject: Temperature too high  Bcc: attacker@example.com\r\n",
+		"X-LecSens-Event: alert.opened\r\n",
+		"\r\n\r\nSensor reported 42.5\r\nCheck the asset\r\n",
+	} {
+		if !strings.Contains(envelope.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, envelope.data)
+		}
+	}
+	if strings.Contains(envelope.data, "\r\nBcc:") {
+		t.Errorf("subject newlines were not stripped:\n%s", envelope.data)
+	}
+}
+
+func TestEmailSenderChannelOverridesServer(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewEmailSender(SMTPConfig{Host: "192.0.2.1", Port: 2525, From: "default@lecsens.test"})
+	channel := &entity.NotificationChannel{
+		Type: entity.NotificationChannelEmail,
+		Config: entity.NotificationChannelConfig{
+			Recipients: []string{"ops@example.com"},
+			SMTPHost:   "127.0.0.1",
+			SMTPPort:   server.port(),
+			SMTPFrom:   "tenant@example.com",
+		},
+	}
+
+	if err := sender.Send(context.Background(), channel, testMessage()); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if envelope := <-server.messages; envelope.from != "tenant@example.com" {
+		t.Errorf("MAIL FROM = %q, want tenant@example.com", envelope.from)
+	}
+}
+
+func TestEmailSenderFailsOnRejectedRecipient(t *testing.T) {
+	server := newFakeSMTPServer(t, "unknown@example.com")
+	sender := NewEmailSender(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "alerts@lecsens.test"})
+	channel := &entity.NotificationChannel{
+		Type: entity.NotificationChannelEmail,
+		Config: entity.NotificationChannelConfig{
+			Recipients: []string{"ops@example.com", "unknown@example.com"},
+		},
+	}
+
+	err := sender.Send(context.Background(), channel, testMessage())
+	if err == nil {
+		t.Fatal("Send returned no error for a rejected recipient")
+	}
+	if !strings.Contains(err.Error(), "unknown@example.com") {
+		t.Errorf("error = %q, want the rejected recipient", err)
+	}
+}
+
+func TestEmailSenderRequiresServer(t *testing.T) {
+	channel := &entity.NotificationChannel{
+		Type:   entity.NotificationChannelEmail,
+		Config: entity.NotificationChannelConfig{Recipients: []string{"ops@example.com"}},
+	}
+	if err := NewEmailSender(SMTPConfig{}).Send(context.Background(), channel, testMessage()); err == nil {
+		t.Fatal("Send returned no error without an smtp server")
+	}
+}
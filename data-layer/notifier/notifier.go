@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"fmt"
+)
+
+// Message is a rendered notification, independent of the channel it is sent to
+type Message struct {
+	Event   entity.NotificationEvent `json:"event"`
+	Subject string                   `json:"subject"`
+	Text    string                   `json:"text"`
+	Data    interface{}              `json:"data,omitempty"` // Structured payload for webhooks
+}
+
+// Sender delivers a message to a single channel
+type Sender interface {
+	Send(ctx context.Context, channel *entity.NotificationChannel, msg *Message) error
+}
+
+// Dispatcher picks the sender matching the channel type
+type Dispatcher struct {
+	senders map[entity.NotificationChannelType]Sender
+}
+
+// NewDispatcher creates a dispatcher for the given senders
+func NewDispatcher(senders map[entity.NotificationChannelType]Sender) *Dispatcher {
+	return &Dispatcher{senders: senders}
+}
+
+// Send delivers the message using the sender registered for the channel type
+func (d *Dispatcher) Send(ctx context.Context, channel *entity.NotificationChannel, msg *Message) error {
+	sender, ok := d.senders[channel.Type]
+	if !ok {
+		return fmt.Errorf("no sender configured for channel type %q", channel.Type)
+	}
+	return sender.Send(ctx, channel, msg)
+}
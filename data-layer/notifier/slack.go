@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SlackSender posts to Slack-compatible incoming webhooks. Microsoft Teams and
+// Mattermost incoming webhooks accept the same {"text": ...} body.
+type SlackSender struct {
+	client *http.Client
+}
+
+// NewSlackSender creates a Slack sender with the given request timeout
+func NewSlackSender(timeout time.Duration) *SlackSender {
+	return &SlackSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the message text to the channel URL
+func (s *SlackSender) Send(ctx context.Context, channel *entity.NotificationChannel, msg *Message) error {
+	text := msg.Text
+	if msg.Subject != "" {
+		text = fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Text)
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range channel.Config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	return doPost(s.client, req)
+}
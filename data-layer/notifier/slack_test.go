@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSlackSenderPostsText(t *testing.T) {
+	server, requests := newStubServer(t, http.StatusOK)
+	channel := &entity.NotificationChannel{
+		Type: entity.NotificationChannelSlack,
+		Config: entity.NotificationChannelConfig{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		},
+	}
+
+	if err := NewSlackSender(5*time.Second).Send(context.Background(), channel, testMessage()); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	req := <-requests
+	if got := req.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want Bearer token", got)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if want := "*Temperature too high*\nSensor reported 42.5"; body["text"] != want {
+		t.Errorf("text = %q, want %q", body["text"], want)
+	}
+	if len(body) != 1 {
+		t.Errorf("payload = %v, want only the text field", body)
+	}
+}
+
+func TestSlackSenderFailsOnErrorStatus(t *testing.T) {
+	server, _ := newStubServer(t, http.StatusNotFound)
+	channel := &entity.NotificationChannel{
+		Type:   entity.NotificationChannelSlack,
+		Config: entity.NotificationChannelConfig{URL: server.URL},
+	}
+
+	if err := NewSlackSender(5*time.Second).Send(context.Background(), channel, testMessage()); err == nil {
+		t.Fatal("Send returned no error for a 404 response")
+	}
+}
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers set on every generic webhook request
+const (
+	WebhookEventHeader     = "X-LecSens-Event"
+	WebhookTimestampHeader = "X-LecSens-Timestamp"
+	WebhookSignatureHeader = "X-LecSens-Signature"
+)
+
+// WebhookSender posts the message as JSON to a generic webhook. When the channel has
+// a secret, the request is signed so receivers can verify it with SignPayload.
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a webhook sender with the given request timeout
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the message to the channel URL
+func (s *WebhookSender) Send(ctx context.Context, channel *entity.NotificationChannel, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range channel.Config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookEventHeader, string(msg.Event))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if channel.Config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignPayload(channel.Config.Secret, timestamp, body))
+	}
+
+	return doPost(s.client, req)
+}
+
+// SignPayload returns the hex HMAC-SHA256 of "{timestamp}.{body}" using the secret
+func SignPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// doPost sends the request and treats any non-2xx response as a failure
+func doPost(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+
+	// Drain so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// capturedRequest is what a stub endpoint received
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newStubServer starts an HTTP stub answering with status and sending every request it
+// receives to the returned channel
+func newStubServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		w.Write([]byte("stub response"))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func testMessage() *Message {
+	return &Message{
+		Event:   entity.NotificationEventAlertOpened,
+		Subject: "Temperature too high",
+		Text:    "Sensor reported 42.5",
+		Data:    map[string]interface{}{"value": 42.5},
+	}
+}
+
+func TestWebhookSenderSignsPayload(t *testing.T) {
+	server, requests := newStubServer(t, http.StatusOK)
+	channel := &entity.NotificationChannel{
+		Type: entity.NotificationChannelWebhook,
+		Config: entity.NotificationChannelConfig{
+			URL:     server.URL,
+			Secret:  "s3cret",
+			Headers: map[string]string{"X-Custom": "value"},
+		},
+	}
+
+	msg := testMessage()
+	if err := NewWebhookSender(5*time.Second).Send(context.Background(), channel, msg); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	req := <-requests
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := req.header.Get("X-Custom"); got != "value" {
+		t.Errorf("X-Custom = %q, want value", got)
+	}
+	if got := req.header.Get(WebhookEventHeader); got != string(msg.Event) {
+		t.Errorf("%s = %q, want %q", WebhookEventHeader, got, msg.Event)
+	}
+
+	timestamp := req.header.Get(WebhookTimestampHeader)
+	if timestamp == "" {
+		t.Fatalf("%s header is missing", WebhookTimestampHeader)
+	}
+	want := "sha256=" + SignPayload("s3cret", timestamp, req.body)
+	if got := req.header.Get(WebhookSignatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+
+	var received Message
+	if err := json.Unmarshal(req.body, &received); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if received.Event != msg.Event || received.Subject != msg.Subject || received.Text != msg.Text {
+		t.Errorf("payload = %+v, want %+v", received, msg)
+	}
+}
+
+func TestWebhookSenderWithoutSecretIsUnsigned(t *testing.T) {
+	server, requests := newStubServer(t, http.StatusNoContent)
+	channel := &entity.NotificationChannel{
+		Type:   entity.NotificationChannelWebhook,
+		Config: entity.NotificationChannelConfig{URL: server.URL},
+	}
+
+	if err := NewWebhookSender(5*time.Second).Send(context.Background(), channel, testMessage()); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	req := <-requests
+	if got := req.header.Get(WebhookSignatureHeader); got != "" {
+		t.Errorf("%s = %q, want no signature", WebhookSignatureHeader, got)
+	}
+}
+
+func TestWebhookSenderFailsOnErrorStatus(t *testing.T) {
+	server, _ := newStubServer(t, http.StatusBadGateway)
+	channel := &entity.NotificationChannel{
+		Type:   entity.NotificationChannelWebhook,
+		Config: entity.NotificationChannelConfig{URL: server.URL},
+	}
+
+	err := NewWebhookSender(5*time.Second).Send(context.Background(), channel, testMessage())
+	if err == nil {
+		t.Fatal("Send returned no error for a 502 response")
+	}
+	if !strings.Contains(err.Error(), "502") || !strings.Contains(err.Error(), "stub response") {
+		t.Errorf("error = %q, want the status and response body", err)
+	}
+}
+
+func TestSignPayload(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	const want = "49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"
+	if got := SignPayload("secret", "1700000000", []byte(`{"a":1}`)); got != want {
+		t.Errorf("SignPayload = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertConditionRepository defines the interface for alert condition operations
+type AlertConditionRepository interface {
+	Create(ctx context.Context, condition *entity.AlertCondition) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.AlertCondition, error)
+	List(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, limit, offset int) ([]*entity.AlertCondition, int, error)
+	GetActiveByAssetID(ctx context.Context, assetID uuid.UUID) ([]*entity.AlertCondition, error)
+	Update(ctx context.Context, condition *entity.AlertCondition) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// alertConditionRepository handles database operations for alert conditions
+type alertConditionRepository struct {
+	*BaseRepository
+}
+
+// NewAlertConditionRepository creates a new AlertConditionRepository
+func NewAlertConditionRepository(db *sql.DB) AlertConditionRepository {
+	return &alertConditionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const alertConditionColumns = `
+	id, tenant_id, asset_id, asset_sensor_id, sensors, name, description, expression,
+	severity, breach_duration_seconds, breach_count, clear_duration_seconds, clear_count,
+	max_reading_age_seconds, is_active, created_at, updated_at`
+
+// Create inserts a new alert condition into the database
+func (r *alertConditionRepository) Create(ctx context.Context, condition *entity.AlertCondition) error {
+	if condition.ID == uuid.Nil {
+		condition.ID = uuid.New()
+	}
+	if condition.CreatedAt.IsZero() {
+		condition.CreatedAt = time.Now()
+	}
+
+	sensors, err := encodeConditionSensors(condition.Sensors)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO alert_conditions (
+			id, tenant_id, asset_id, asset_sensor_id, sensors, name, description, expression,
+			severity, breach_duration_seconds, breach_count, clear_duration_seconds, clear_count,
+			max_reading_age_seconds, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		condition.ID,
+		condition.TenantID,
+		condition.AssetID,
+		condition.AssetSensorID,
+		sensors,
+		condition.Name,
+		condition.Description,
+		condition.Expression,
+		condition.Severity,
+		condition.AlertRules.BreachDurationSeconds,
+		condition.AlertRules.BreachCount,
+		condition.AlertRules.ClearDurationSeconds,
+		condition.AlertRules.ClearCount,
+		condition.MaxReadingAgeSeconds,
+		condition.IsActive,
+		condition.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create alert condition: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an alert condition by its ID
+func (r *alertConditionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AlertCondition, error) {
+	query := `SELECT ` + alertConditionColumns + ` FROM alert_conditions WHERE id = $1`
+
+	condition, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert condition: %w", err)
+	}
+
+	return condition, nil
+}
+
+// List retrieves paginated alert conditions for a tenant, optionally only those of an asset
+func (r *alertConditionRepository) List(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, limit, offset int) ([]*entity.AlertCondition, int, error) {
+	whereClause := `WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if assetID != nil {
+		whereClause += ` AND asset_id = $2`
+		args = append(args, *assetID)
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM alert_conditions ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM alert_conditions %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		alertConditionColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	conditions, err := r.queryConditions(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return conditions, totalCount, nil
+}
+
+// GetActiveByAssetID retrieves the enabled alert conditions of an asset
+func (r *alertConditionRepository) GetActiveByAssetID(ctx context.Context, assetID uuid.UUID) ([]*entity.AlertCondition, error) {
+	query := `SELECT ` + alertConditionColumns + `
+		FROM alert_conditions
+		WHERE asset_id = $1 AND is_active = true
+		ORDER BY created_at`
+
+	return r.queryConditions(ctx, query, assetID)
+}
+
+// Update updates an existing alert condition
+func (r *alertConditionRepository) Update(ctx context.Context, condition *entity.AlertCondition) error {
+	now := time.Now()
+	condition.UpdatedAt = &now
+
+	sensors, err := encodeConditionSensors(condition.Sensors)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE alert_conditions SET
+			asset_id = $2,
+			asset_sensor_id = $3,
+			sensors = $4,
+			name = $5,
+			description = $6,
+			expression = $7,
+			severity = $8,
+			breach_duration_seconds = $9,
+			breach_count = $10,
+			clear_duration_seconds = $11,
+			clear_count = $12,
+			max_reading_age_seconds = $13,
+			is_active = $14,
+			updated_at = $15
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		condition.ID,
+		condition.AssetID,
+		condition.AssetSensorID,
+		sensors,
+		condition.Name,
+		condition.Description,
+		condition.Expression,
+		condition.Severity,
+		condition.AlertRules.BreachDurationSeconds,
+		condition.AlertRules.BreachCount,
+		condition.AlertRules.ClearDurationSeconds,
+		condition.AlertRules.ClearCount,
+		condition.MaxReadingAgeSeconds,
+		condition.IsActive,
+		condition.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update alert condition: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert condition not found")
+	}
+
+	return nil
+}
+
+// Delete removes an alert condition by its ID. Alerts it raised are kept.
+func (r *alertConditionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM alert_conditions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert condition: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert condition not found")
+	}
+
+	return nil
+}
+
+// queryConditions executes a query and returns alert conditions
+func (r *alertConditionRepository) queryConditions(ctx context.Context, query string, args ...interface{}) ([]*entity.AlertCondition, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert conditions: %w", err)
+	}
+	defer rows.Close()
+
+	var conditions []*entity.AlertCondition
+	for rows.Next() {
+		condition, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert condition: %w", err)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert conditions: %w", err)
+	}
+
+	return conditions, nil
+}
+
+// scanRow scans a single alert condition row
+func (r *alertConditionRepository) scanRow(row rowScanner) (*entity.AlertCondition, error) {
+	var condition entity.AlertCondition
+	var sensors []byte
+	err := row.Scan(
+		&condition.ID,
+		&condition.TenantID,
+		&condition.AssetID,
+		&condition.AssetSensorID,
+		&sensors,
+		&condition.Name,
+		&condition.Description,
+		&condition.Expression,
+		&condition.Severity,
+		&condition.AlertRules.BreachDurationSeconds,
+		&condition.AlertRules.BreachCount,
+		&condition.AlertRules.ClearDurationSeconds,
+		&condition.AlertRules.ClearCount,
+		&condition.MaxReadingAgeSeconds,
+		&condition.IsActive,
+		&condition.CreatedAt,
+		&condition.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	condition.Sensors = map[string]uuid.UUID{}
+	if len(sensors) > 0 {
+		if err := json.Unmarshal(sensors, &condition.Sensors); err != nil {
+			return nil, fmt.Errorf("failed to decode alert condition sensors: %w", err)
+		}
+	}
+
+	return &condition, nil
+}
+
+// encodeConditionSensors encodes the sensor aliases of a condition for the JSONB column
+func encodeConditionSensors(sensors map[string]uuid.UUID) ([]byte, error) {
+	if sensors == nil {
+		sensors = map[string]uuid.UUID{}
+	}
+	encoded, err := json.Marshal(sensors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert condition sensors: %w", err)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PendingEscalation is an open, unacknowledged alert together with the next step
+// of a policy that has not fired for it yet
+type PendingEscalation struct {
+	AlertID   uuid.UUID
+	AlertTime time.Time
+	StepIndex int
+}
+
+// AssetAlertEscalationRepository defines the interface for alert escalation history operations
+type AssetAlertEscalationRepository interface {
+	FindPending(ctx context.Context, policy *entity.EscalationPolicy, now time.Time, limit int) ([]PendingEscalation, error)
+	Record(ctx context.Context, alert *entity.AssetAlert, escalation *entity.AssetAlertEscalation) (bool, error)
+	ListByAlert(ctx context.Context, alertID uuid.UUID) ([]*entity.AssetAlertEscalation, error)
+}
+
+// assetAlertEscalationRepository handles database operations for alert escalations
+type assetAlertEscalationRepository struct {
+	*BaseRepository
+}
+
+// NewAssetAlertEscalationRepository creates a new AssetAlertEscalationRepository
+func NewAssetAlertEscalationRepository(db *sql.DB) AssetAlertEscalationRepository {
+	return &assetAlertEscalationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindPending returns the open, unacknowledged alerts of the policy's tenant and severities
+// whose next policy step is due at now, the longest overdue first. The next step of an
+// alert is the one after the steps already fired for it. Alerts raised during a
+// maintenance window are never escalated.
+func (r *assetAlertEscalationRepository) FindPending(ctx context.Context, policy *entity.EscalationPolicy, now time.Time, limit int) ([]PendingEscalation, error) {
+	if len(policy.Steps) == 0 {
+		return nil, nil
+	}
+
+	delays := make([]int64, len(policy.Steps))
+	for i, step := range policy.Steps {
+		delays[i] = int64(step.DelayMinutes)
+	}
+
+	// Steps are ordered by delay, so nothing raised after now minus the first delay is due
+	query := `
+		SELECT id, alert_time, fired
+		FROM (
+			SELECT a.id, a.alert_time, COUNT(e.id)::int AS fired
+			FROM asset_alerts a
+			LEFT JOIN asset_alert_escalations e ON e.alert_id = a.id AND e.policy_id = $2
+			WHERE a.tenant_id = $1
+				AND a.is_resolved = false
+				AND a.acknowledged_at IS NULL
+				AND a.maintenance_window_id IS NULL
+				AND a.severity = ANY($3)
+				AND a.alert_time <= $4
+			GROUP BY a.id, a.alert_time
+		) pending
+		WHERE fired < cardinality($5::int[])
+			AND alert_time + make_interval(mins => ($5::int[])[fired + 1]) <= $6
+		ORDER BY alert_time + make_interval(mins => ($5::int[])[fired + 1])
+		LIMIT $7`
+
+	rows, err := r.DB.QueryContext(ctx, query,
+		policy.TenantID,
+		policy.ID,
+		pq.Array(severityStrings(policy.Severities)),
+		now.Add(-policy.Steps[0].Delay()),
+		pq.Array(delays),
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending escalations: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []PendingEscalation
+	for rows.Next() {
+		var p PendingEscalation
+		if err := rows.Scan(&p.AlertID, &p.AlertTime, &p.StepIndex); err != nil {
+			return nil, fmt.Errorf("failed to scan pending escalation: %w", err)
+		}
+		pending = append(pending, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending escalations: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Record stores an escalation and its timeline entry, and assigns the alert to the first
+// escalation user when nobody is assigned yet. It returns false without changes when
+// the step already fired or the alert was acknowledged or resolved in the meantime.
+func (r *assetAlertEscalationRepository) Record(ctx context.Context, alert *entity.AssetAlert, escalation *entity.AssetAlertEscalation) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO asset_alert_escalations (
+			id, alert_id, tenant_id, policy_id, policy_name, step_index,
+			channel_ids, user_ids, escalated_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+		WHERE EXISTS (
+			SELECT 1 FROM asset_alerts
+			WHERE id = $2 AND is_resolved = false AND acknowledged_at IS NULL
+		)
+		ON CONFLICT ON CONSTRAINT uq_asset_alert_escalations_step DO NOTHING`
+
+	result, err := tx.ExecContext(ctx, query,
+		escalation.ID,
+		escalation.AlertID,
+		escalation.TenantID,
+		escalation.PolicyID,
+		escalation.PolicyName,
+		escalation.StepIndex,
+		pq.Array(escalation.ChannelIDs),
+		pq.Array(escalation.UserIDs),
+		escalation.EscalatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create asset alert escalation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventEscalated, nil)
+	event.Details = map[string]string{
+		"policy_name": escalation.PolicyName,
+		"step":        strconv.Itoa(escalation.StepIndex + 1),
+	}
+	if escalation.PolicyID != nil {
+		event.Details["policy_id"] = escalation.PolicyID.String()
+	}
+	if err := insertAlertEvent(ctx, tx, event); err != nil {
+		return false, err
+	}
+
+	if len(escalation.UserIDs) > 0 {
+		assignee := escalation.UserIDs[0]
+		result, err := tx.ExecContext(ctx, `
+			UPDATE asset_alerts SET assigned_to = $2, assigned_at = $3, updated_at = $3
+			WHERE id = $1 AND assigned_to IS NULL`,
+			alert.ID, assignee, escalation.EscalatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to assign escalated alert: %w", err)
+		}
+		if assigned, err := result.RowsAffected(); err != nil {
+			return false, fmt.Errorf("failed to get rows affected: %w", err)
+		} else if assigned > 0 {
+			event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventAssigned, nil)
+			event.Details = map[string]string{"assigned_to": assignee.String()}
+			if err := insertAlertEvent(ctx, tx, event); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit alert escalation: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListByAlert retrieves the escalation history of an alert in chronological order
+func (r *assetAlertEscalationRepository) ListByAlert(ctx context.Context, alertID uuid.UUID) ([]*entity.AssetAlertEscalation, error) {
+	query := `
+		SELECT id, alert_id, tenant_id, policy_id, policy_name, step_index,
+			channel_ids, user_ids, escalated_at
+		FROM asset_alert_escalations
+		WHERE alert_id = $1
+		ORDER BY escalated_at, step_index`
+
+	rows, err := r.DB.QueryContext(ctx, query, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query asset alert escalations: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []*entity.AssetAlertEscalation
+	for rows.Next() {
+		var escalation entity.AssetAlertEscalation
+		err := rows.Scan(
+			&escalation.ID,
+			&escalation.AlertID,
+			&escalation.TenantID,
+			&escalation.PolicyID,
+			&escalation.PolicyName,
+			&escalation.StepIndex,
+			pq.Array(&escalation.ChannelIDs),
+			pq.Array(&escalation.UserIDs),
+			&escalation.EscalatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset alert escalation: %w", err)
+		}
+		escalations = append(escalations, &escalation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating asset alert escalations: %w", err)
+	}
+
+	return escalations, nil
+}
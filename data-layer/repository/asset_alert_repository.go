@@ -4,6 +4,7 @@ import (
 	"be-lecsens/asset_management/data-layer/entity"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -23,7 +24,11 @@ type AssetAlertRepository interface {
 	GetActiveAlerts(ctx context.Context, tenantID uuid.UUID) ([]*entity.AssetAlert, error)
 	GetActiveAlertsByAssetSensor(ctx context.Context, assetSensorID uuid.UUID) ([]*entity.AssetAlert, error)
 	Update(ctx context.Context, alert *entity.AssetAlert) error
-	ResolveAlert(ctx context.Context, id uuid.UUID) error
+	ResolveAlert(ctx context.Context, id uuid.UUID, resolution entity.AlertResolution) error
+	AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, comment *string) error
+	AssignAlert(ctx context.Context, id uuid.UUID, assignee *uuid.UUID, userID uuid.UUID, comment *string) error
+	CreateAlertEvent(ctx context.Context, event *entity.AssetAlertEvent) error
+	GetAlertEvents(ctx context.Context, alertID uuid.UUID) ([]*entity.AssetAlertEvent, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.AssetAlert, int, error)
 	ListAll(ctx context.Context, limit, offset int) ([]*entity.AssetAlert, int, error)
@@ -46,12 +51,43 @@ type AssetAlertRepository interface {
 		toTime *time.Time,
 	) (map[string]interface{}, error)
 	GetGlobalAlertStatistics(ctx context.Context) (map[string]interface{}, error)
-	CreateAlertFromReading(ctx context.Context, reading *entity.IoTSensorReading, threshold *entity.SensorThreshold, value float64) error
-	ResolveAlertsForReading(ctx context.Context, reading *entity.IoTSensorReading, threshold *entity.SensorThreshold, value float64) error
-	ResolveMultipleAlerts(ctx context.Context, alertIDs []uuid.UUID) (int, int, error)
+	ApplyThresholdEvaluation(
+		ctx context.Context,
+		reading *entity.IoTSensorReading,
+		assetID uuid.UUID,
+		threshold *entity.SensorThreshold,
+		value float64,
+		maintenance AlertMaintenance,
+	) (*entity.AssetAlert, entity.ThresholdTransition, error)
+	ApplyThresholdMatch(
+		ctx context.Context,
+		reading *entity.IoTSensorReading,
+		assetID uuid.UUID,
+		threshold *entity.SensorThreshold,
+		value interface{},
+		matched bool,
+		maintenance AlertMaintenance,
+	) (*entity.AssetAlert, entity.ThresholdTransition, error)
+	ApplyConditionEvaluation(
+		ctx context.Context,
+		reading *entity.IoTSensorReading,
+		assetID uuid.UUID,
+		condition *entity.AlertCondition,
+		matched bool,
+		value float64,
+		message string,
+		maintenance AlertMaintenance,
+	) (*entity.AssetAlert, entity.ThresholdTransition, error)
+	ResolveMultipleAlerts(ctx context.Context, alertIDs []uuid.UUID, resolution entity.AlertResolution) (int, int, error)
 	DeleteMultipleAlerts(ctx context.Context, alertIDs []uuid.UUID) (int, int, error)
 }
 
+// AlertMaintenance describes the maintenance an asset is under when a reading is evaluated
+type AlertMaintenance struct {
+	WindowID *uuid.UUID // Window alerts opened by the reading are tagged with
+	Suppress bool       // Advance the state and resolve alerts, but open no new alert
+}
+
 // assetAlertRepository handles database operations for asset alerts
 type assetAlertRepository struct {
 	*BaseRepository
@@ -64,6 +100,29 @@ func NewAssetAlertRepository(db *sql.DB) AssetAlertRepository {
 	}
 }
 
+// assetAlertColumns is the column list read by scanAlert
+const assetAlertColumns = `id, tenant_id, asset_id, asset_sensor_id, threshold_id,
+	measurement_field_name, alert_time, resolved_time, severity,
+	trigger_value, threshold_min_value, threshold_max_value,
+	alert_message, alert_type, is_resolved, created_at, updated_at,
+	last_trigger_value, peak_trigger_value, occurrence_count, last_triggered_at,
+	acknowledged_at, acknowledged_by, assigned_to, assigned_at,
+	resolved_by, resolution_code, resolution_note, maintenance_window_id,
+	condition_id`
+
+// insertAlertQuery inserts a single asset alert
+const insertAlertQuery = `
+	INSERT INTO asset_alerts (
+		id, tenant_id, asset_id, asset_sensor_id, threshold_id,
+		measurement_field_name, alert_time, severity, trigger_value,
+		threshold_min_value, threshold_max_value, alert_message,
+		alert_type, is_resolved, created_at,
+		last_trigger_value, peak_trigger_value, occurrence_count, last_triggered_at,
+		condition_id, maintenance_window_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+	)`
+
 // Create inserts a new asset alert into the database
 func (r *assetAlertRepository) Create(ctx context.Context, alert *entity.AssetAlert) error {
 	log.Printf("Creating asset alert: %+v", alert)
@@ -81,35 +140,16 @@ func (r *assetAlertRepository) Create(ctx context.Context, alert *entity.AssetAl
 	if alert.AlertTime.IsZero() {
 		alert.AlertTime = now
 	}
+	if alert.OccurrenceCount == 0 {
+		alert.OccurrenceCount = 1
+		alert.LastTriggerValue = alert.TriggerValue
+		alert.PeakTriggerValue = alert.TriggerValue
+	}
+	if alert.LastTriggeredAt == nil {
+		alert.LastTriggeredAt = &alert.AlertTime
+	}
 
-	query := `
-		INSERT INTO asset_alerts (
-			id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			measurement_field_name, alert_time, severity, trigger_value,
-			threshold_min_value, threshold_max_value, alert_message,
-			alert_type, is_resolved, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
-		)`
-
-	_, err := r.DB.ExecContext(ctx, query,
-		alert.ID,
-		alert.TenantID,
-		alert.AssetID,
-		alert.AssetSensorID,
-		alert.ThresholdID,
-		alert.MeasurementFieldName,
-		alert.AlertTime,
-		alert.Severity,
-		alert.TriggerValue,
-		alert.ThresholdMinValue,
-		alert.ThresholdMaxValue,
-		alert.AlertMessage,
-		alert.AlertType,
-		alert.IsResolved,
-		alert.CreatedAt,
-	)
-
+	err := insertAlert(ctx, r.DB, alert)
 	if err != nil {
 		log.Printf("Error creating asset alert: %v", err)
 		return fmt.Errorf("failed to create asset alert: %w", err)
@@ -122,34 +162,11 @@ func (r *assetAlertRepository) Create(ctx context.Context, alert *entity.AssetAl
 // GetByID retrieves an asset alert by its ID
 func (r *assetAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE id = $1`
 
-	var alert entity.AssetAlert
-	err := r.DB.QueryRowContext(ctx, query, id).Scan(
-		&alert.ID,
-		&alert.TenantID,
-		&alert.AssetID,
-		&alert.AssetSensorID,
-		&alert.ThresholdID,
-		&alert.MeasurementFieldName,
-		&alert.AlertTime,
-		&alert.ResolvedTime,
-		&alert.Severity,
-		&alert.TriggerValue,
-		&alert.ThresholdMinValue,
-		&alert.ThresholdMaxValue,
-		&alert.AlertMessage,
-		&alert.AlertType,
-		&alert.IsResolved,
-		&alert.CreatedAt,
-		&alert.UpdatedAt,
-	)
-
+	alert, err := scanAlert(r.DB.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -157,16 +174,13 @@ func (r *assetAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*enti
 		return nil, fmt.Errorf("failed to get asset alert: %w", err)
 	}
 
-	return &alert, nil
+	return alert, nil
 }
 
 // GetByTenantID retrieves all asset alerts for a tenant
 func (r *assetAlertRepository) GetByTenantID(ctx context.Context, tenantID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE tenant_id = $1
 		ORDER BY alert_time DESC`
@@ -177,10 +191,7 @@ func (r *assetAlertRepository) GetByTenantID(ctx context.Context, tenantID uuid.
 // GetByAssetID retrieves alerts for a specific asset
 func (r *assetAlertRepository) GetByAssetID(ctx context.Context, assetID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE asset_id = $1
 		ORDER BY alert_time DESC`
@@ -191,10 +202,7 @@ func (r *assetAlertRepository) GetByAssetID(ctx context.Context, assetID uuid.UU
 // GetByAssetSensorID retrieves alerts for a specific asset sensor
 func (r *assetAlertRepository) GetByAssetSensorID(ctx context.Context, assetSensorID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE asset_sensor_id = $1
 		ORDER BY alert_time DESC`
@@ -205,10 +213,7 @@ func (r *assetAlertRepository) GetByAssetSensorID(ctx context.Context, assetSens
 // GetByMeasurementTypeID retrieves alerts for a specific measurement type
 func (r *assetAlertRepository) GetByMeasurementTypeID(ctx context.Context, measurementTypeID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE threshold_id IN (
 			SELECT id FROM sensor_thresholds WHERE measurement_type_id = $1
@@ -221,10 +226,7 @@ func (r *assetAlertRepository) GetByMeasurementTypeID(ctx context.Context, measu
 // GetActiveAlerts retrieves all active (unresolved) alerts for a tenant
 func (r *assetAlertRepository) GetActiveAlerts(ctx context.Context, tenantID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE tenant_id = $1 AND is_resolved = false
 		ORDER BY alert_time DESC`
@@ -235,10 +237,7 @@ func (r *assetAlertRepository) GetActiveAlerts(ctx context.Context, tenantID uui
 // GetActiveAlertsByAssetSensor retrieves active alerts for a specific asset sensor
 func (r *assetAlertRepository) GetActiveAlertsByAssetSensor(ctx context.Context, assetSensorID uuid.UUID) ([]*entity.AssetAlert, error) {
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE asset_sensor_id = $1 AND is_resolved = false
 		ORDER BY alert_time DESC`
@@ -283,34 +282,180 @@ func (r *assetAlertRepository) Update(ctx context.Context, alert *entity.AssetAl
 	return nil
 }
 
-// ResolveAlert marks an alert as resolved
-func (r *assetAlertRepository) ResolveAlert(ctx context.Context, id uuid.UUID) error {
-	now := time.Now()
+// ResolveAlert marks an alert as resolved and records the resolution in its timeline
+func (r *assetAlertRepository) ResolveAlert(ctx context.Context, id uuid.UUID, resolution entity.AlertResolution) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
 	query := `
 		UPDATE asset_alerts SET
 			is_resolved = true,
 			resolved_time = $2,
+			resolved_by = $3,
+			resolution_code = $4,
+			resolution_note = $5,
 			updated_at = $2
-		WHERE id = $1 AND is_resolved = false`
+		WHERE id = $1 AND is_resolved = false
+		RETURNING tenant_id`
 
-	result, err := r.DB.ExecContext(ctx, query, id, now)
+	alert := &entity.AssetAlert{ID: id}
+	err = tx.QueryRowContext(ctx, query, id, time.Now(), resolution.ResolvedBy, resolution.Code, resolution.Note).Scan(&alert.TenantID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("asset alert not found or already resolved")
+		}
 		return fmt.Errorf("failed to resolve asset alert: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if err := insertAlertEvent(ctx, tx, newResolvedEvent(alert, resolution)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert resolution: %w", err)
+	}
+
+	return nil
+}
+
+// AcknowledgeAlert marks an open alert as acknowledged by a user
+func (r *assetAlertRepository) AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, comment *string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("asset alert not found or already resolved")
+	query := `
+		UPDATE asset_alerts SET
+			acknowledged_at = $2,
+			acknowledged_by = $3,
+			updated_at = $2
+		WHERE id = $1 AND is_resolved = false AND acknowledged_at IS NULL
+		RETURNING tenant_id`
+
+	alert := &entity.AssetAlert{ID: id}
+	if err := tx.QueryRowContext(ctx, query, id, time.Now(), userID).Scan(&alert.TenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("asset alert not found, resolved or already acknowledged")
+		}
+		return fmt.Errorf("failed to acknowledge asset alert: %w", err)
+	}
+
+	event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventAcknowledged, &userID)
+	event.Comment = comment
+	if err := insertAlertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert acknowledgement: %w", err)
+	}
+
+	return nil
+}
+
+// AssignAlert assigns an open alert to a user, or clears the assignment when assignee is nil
+func (r *assetAlertRepository) AssignAlert(ctx context.Context, id uuid.UUID, assignee *uuid.UUID, userID uuid.UUID, comment *string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var assignedAt *time.Time
+	if assignee != nil {
+		assignedAt = &now
+	}
+
+	query := `
+		UPDATE asset_alerts SET
+			assigned_to = $2,
+			assigned_at = $3,
+			updated_at = $4
+		WHERE id = $1 AND is_resolved = false
+		RETURNING tenant_id`
+
+	alert := &entity.AssetAlert{ID: id}
+	if err := tx.QueryRowContext(ctx, query, id, assignee, assignedAt, now).Scan(&alert.TenantID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("asset alert not found or already resolved")
+		}
+		return fmt.Errorf("failed to assign asset alert: %w", err)
+	}
+
+	event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventUnassigned, &userID)
+	if assignee != nil {
+		event.Type = entity.AssetAlertEventAssigned
+		event.Details = map[string]string{"assigned_to": assignee.String()}
+	}
+	event.Comment = comment
+	if err := insertAlertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert assignment: %w", err)
 	}
 
 	return nil
 }
 
+// CreateAlertEvent adds an entry to an alert's timeline
+func (r *assetAlertRepository) CreateAlertEvent(ctx context.Context, event *entity.AssetAlertEvent) error {
+	return insertAlertEvent(ctx, r.DB, event)
+}
+
+// GetAlertEvents retrieves the timeline of an alert, oldest first
+func (r *assetAlertRepository) GetAlertEvents(ctx context.Context, alertID uuid.UUID) ([]*entity.AssetAlertEvent, error) {
+	query := `
+		SELECT id, alert_id, tenant_id, type, user_id, comment, details, created_at
+		FROM asset_alert_events
+		WHERE alert_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.DB.QueryContext(ctx, query, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query asset alert events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.AssetAlertEvent
+	for rows.Next() {
+		var event entity.AssetAlertEvent
+		var details []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.AlertID,
+			&event.TenantID,
+			&event.Type,
+			&event.UserID,
+			&event.Comment,
+			&details,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset alert event: %w", err)
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to decode asset alert event details: %w", err)
+			}
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
 // Delete removes an asset alert by its ID
 func (r *assetAlertRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM asset_alerts WHERE id = $1`
@@ -344,10 +489,7 @@ func (r *assetAlertRepository) List(ctx context.Context, tenantID uuid.UUID, lim
 
 	// Get paginated results
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		WHERE tenant_id = $1
 		ORDER BY alert_time DESC
@@ -383,10 +525,7 @@ func (r *assetAlertRepository) ListWithFilters(
 	baseQuery := `FROM asset_alerts WHERE tenant_id = $1`
 	countQuery := `SELECT COUNT(*) ` + baseQuery
 	selectQuery := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		` + baseQuery
 
 	// Build filter conditions
@@ -495,7 +634,8 @@ func (r *assetAlertRepository) GetAlertStatistics(
 			COUNT(CASE WHEN severity = 'critical' AND is_resolved = false THEN 1 END) as critical_alerts,
 			COUNT(CASE WHEN severity = 'warning' AND is_resolved = false THEN 1 END) as warning_alerts,
 			COUNT(CASE WHEN alert_time >= NOW() - INTERVAL '24 hours' THEN 1 END) as alerts_24h,
-			COUNT(CASE WHEN alert_time >= NOW() - INTERVAL '7 days' THEN 1 END) as alerts_7d
+			COUNT(CASE WHEN alert_time >= NOW() - INTERVAL '7 days' THEN 1 END) as alerts_7d,
+			` + alertWorkflowStatisticsColumns + `
 		` + baseQuery
 
 	var stats struct {
@@ -506,6 +646,7 @@ func (r *assetAlertRepository) GetAlertStatistics(
 		WarningAlerts  int `json:"warning_alerts"`
 		Alerts24h      int `json:"alerts_24h"`
 		Alerts7d       int `json:"alerts_7d"`
+		alertWorkflowStatistics
 	}
 
 	err := r.DB.QueryRowContext(ctx, query, args...).Scan(
@@ -516,6 +657,10 @@ func (r *assetAlertRepository) GetAlertStatistics(
 		&stats.WarningAlerts,
 		&stats.Alerts24h,
 		&stats.Alerts7d,
+		&stats.AcknowledgedAlerts,
+		&stats.UnassignedAlerts,
+		&stats.MTTASeconds,
+		&stats.MTTRSeconds,
 	)
 
 	if err != nil {
@@ -531,6 +676,7 @@ func (r *assetAlertRepository) GetAlertStatistics(
 		"alerts_24h":      stats.Alerts24h,
 		"alerts_7d":       stats.Alerts7d,
 	}
+	stats.addTo(result)
 
 	return result, nil
 }
@@ -547,10 +693,7 @@ func (r *assetAlertRepository) ListAll(ctx context.Context, limit, offset int) (
 
 	// Get paginated results
 	query := `
-		SELECT id, tenant_id, asset_id, asset_sensor_id, threshold_id,
-			   measurement_field_name, alert_time, resolved_time, severity,
-			   trigger_value, threshold_min_value, threshold_max_value,
-			   alert_message, alert_type, is_resolved, created_at, updated_at
+		SELECT ` + assetAlertColumns + `
 		FROM asset_alerts
 		ORDER BY alert_time DESC
 		LIMIT $1 OFFSET $2`
@@ -580,7 +723,8 @@ func (r *assetAlertRepository) GetGlobalAlertStatistics(ctx context.Context) (ma
 			COUNT(CASE WHEN severity = 'warning' AND is_resolved = false THEN 1 END) as warning_alerts,
 			COUNT(CASE WHEN alert_time >= NOW() - INTERVAL '24 hours' THEN 1 END) as alerts_24h,
 			COUNT(CASE WHEN alert_time >= NOW() - INTERVAL '7 days' THEN 1 END) as alerts_7d,
-			COUNT(DISTINCT tenant_id) as total_tenants
+			COUNT(DISTINCT tenant_id) as total_tenants,
+			` + alertWorkflowStatisticsColumns + `
 		FROM asset_alerts`
 
 	var stats struct {
@@ -592,6 +736,7 @@ func (r *assetAlertRepository) GetGlobalAlertStatistics(ctx context.Context) (ma
 		Alerts24h      int `json:"alerts_24h"`
 		Alerts7d       int `json:"alerts_7d"`
 		TotalTenants   int `json:"total_tenants"`
+		alertWorkflowStatistics
 	}
 
 	err := r.DB.QueryRowContext(ctx, query).Scan(
@@ -603,6 +748,10 @@ func (r *assetAlertRepository) GetGlobalAlertStatistics(ctx context.Context) (ma
 		&stats.Alerts24h,
 		&stats.Alerts7d,
 		&stats.TotalTenants,
+		&stats.AcknowledgedAlerts,
+		&stats.UnassignedAlerts,
+		&stats.MTTASeconds,
+		&stats.MTTRSeconds,
 	)
 
 	if err != nil {
@@ -619,10 +768,44 @@ func (r *assetAlertRepository) GetGlobalAlertStatistics(ctx context.Context) (ma
 		"alerts_7d":       stats.Alerts7d,
 		"total_tenants":   stats.TotalTenants,
 	}
+	stats.addTo(result)
 
 	return result, nil
 }
 
+// alertWorkflowStatisticsColumns computes acknowledgement and resolution metrics.
+// MTTA covers every acknowledged alert, MTTR every resolved one (including alerts
+// cleared automatically when the value returned to normal).
+const alertWorkflowStatisticsColumns = `
+			COUNT(CASE WHEN is_resolved = false AND acknowledged_at IS NOT NULL THEN 1 END) as acknowledged_alerts,
+			COUNT(CASE WHEN is_resolved = false AND assigned_to IS NULL THEN 1 END) as unassigned_alerts,
+			AVG(EXTRACT(EPOCH FROM (acknowledged_at - alert_time))) as mtta_seconds,
+			AVG(EXTRACT(EPOCH FROM (resolved_time - alert_time))) FILTER (WHERE is_resolved = true) as mttr_seconds`
+
+// alertWorkflowStatistics holds the values of alertWorkflowStatisticsColumns
+type alertWorkflowStatistics struct {
+	AcknowledgedAlerts int
+	UnassignedAlerts   int
+	MTTASeconds        sql.NullFloat64
+	MTTRSeconds        sql.NullFloat64
+}
+
+// addTo adds the workflow statistics to a statistics result map
+func (s alertWorkflowStatistics) addTo(result map[string]interface{}) {
+	result["acknowledged_alerts"] = s.AcknowledgedAlerts
+	result["unassigned_alerts"] = s.UnassignedAlerts
+	result["mtta_seconds"] = nullFloat64Ptr(s.MTTASeconds)
+	result["mttr_seconds"] = nullFloat64Ptr(s.MTTRSeconds)
+}
+
+// nullFloat64Ptr converts a nullable float to a pointer
+func nullFloat64Ptr(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
 // Helper methods
 
 // queryAlerts executes a query and returns alerts
@@ -641,30 +824,11 @@ func (r *assetAlertRepository) scanRows(rows *sql.Rows) ([]*entity.AssetAlert, e
 	var alerts []*entity.AssetAlert
 
 	for rows.Next() {
-		var alert entity.AssetAlert
-		err := rows.Scan(
-			&alert.ID,
-			&alert.TenantID,
-			&alert.AssetID,
-			&alert.AssetSensorID,
-			&alert.ThresholdID,
-			&alert.MeasurementFieldName,
-			&alert.AlertTime,
-			&alert.ResolvedTime,
-			&alert.Severity,
-			&alert.TriggerValue,
-			&alert.ThresholdMinValue,
-			&alert.ThresholdMaxValue,
-			&alert.AlertMessage,
-			&alert.AlertType,
-			&alert.IsResolved,
-			&alert.CreatedAt,
-			&alert.UpdatedAt,
-		)
+		alert, err := scanAlert(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan asset alert: %w", err)
 		}
-		alerts = append(alerts, &alert)
+		alerts = append(alerts, alert)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -674,108 +838,647 @@ func (r *assetAlertRepository) scanRows(rows *sql.Rows) ([]*entity.AssetAlert, e
 	return alerts, nil
 }
 
-// CreateAlertFromReading creates an alert from an IoT sensor reading
-func (r *assetAlertRepository) CreateAlertFromReading(ctx context.Context, reading *entity.IoTSensorReading, threshold *entity.SensorThreshold, value float64) error {
-	// Check if there's already an active alert for this threshold
-	activeAlerts, err := r.GetActiveAlertsByAssetSensor(ctx, reading.AssetSensorID)
+// scanAlert scans a single asset_alerts row selected with the standard column list
+func scanAlert(row rowScanner) (*entity.AssetAlert, error) {
+	var alert entity.AssetAlert
+	var lastTriggerValue, peakTriggerValue sql.NullFloat64
+	err := row.Scan(
+		&alert.ID,
+		&alert.TenantID,
+		&alert.AssetID,
+		&alert.AssetSensorID,
+		&alert.ThresholdID,
+		&alert.MeasurementFieldName,
+		&alert.AlertTime,
+		&alert.ResolvedTime,
+		&alert.Severity,
+		&alert.TriggerValue,
+		&alert.ThresholdMinValue,
+		&alert.ThresholdMaxValue,
+		&alert.AlertMessage,
+		&alert.AlertType,
+		&alert.IsResolved,
+		&alert.CreatedAt,
+		&alert.UpdatedAt,
+		&lastTriggerValue,
+		&peakTriggerValue,
+		&alert.OccurrenceCount,
+		&alert.LastTriggeredAt,
+		&alert.AcknowledgedAt,
+		&alert.AcknowledgedBy,
+		&alert.AssignedTo,
+		&alert.AssignedAt,
+		&alert.ResolvedBy,
+		&alert.ResolutionCode,
+		&alert.ResolutionNote,
+		&alert.MaintenanceWindowID,
+		&alert.ConditionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	alert.LastTriggerValue = alert.TriggerValue
+	if lastTriggerValue.Valid {
+		alert.LastTriggerValue = lastTriggerValue.Float64
+	}
+	alert.PeakTriggerValue = alert.TriggerValue
+	if peakTriggerValue.Valid {
+		alert.PeakTriggerValue = peakTriggerValue.Float64
+	}
+
+	return &alert, nil
+}
+
+// ApplyThresholdEvaluation evaluates a reading against a threshold and opens, updates or
+// resolves the single open alert for the (asset sensor, threshold) pair according to the
+// threshold's alert rules. The threshold state row is locked for the whole evaluation so
+// concurrent readings for the same sensor are applied one after another. An alert opened
+// during a maintenance window is tagged with it in the same transaction, and none is opened
+// while the maintenance suppresses alerts.
+func (r *assetAlertRepository) ApplyThresholdEvaluation(
+	ctx context.Context,
+	reading *entity.IoTSensorReading,
+	assetID uuid.UUID,
+	threshold *entity.SensorThreshold,
+	value float64,
+	maintenance AlertMaintenance,
+) (*entity.AssetAlert, entity.ThresholdTransition, error) {
+	return r.applyThresholdOutcome(ctx, reading, assetID, threshold, maintenance, thresholdOutcome{
+		evaluate: func(state *entity.ThresholdState, at time.Time) entity.ThresholdTransition {
+			return threshold.Evaluate(state, value, at)
+		},
+		newAlert: func(tenantID uuid.UUID) *entity.AssetAlert {
+			return entity.CreateAlertFromThresholdBreach(tenantID, assetID, reading.AssetSensorID, threshold, value)
+		},
+		value:  value,
+		status: threshold.CheckValue(value),
+	})
+}
+
+// ApplyThresholdMatch applies the outcome of a boolean or text value match rule the same
+// way ApplyThresholdEvaluation applies a numeric reading
+func (r *assetAlertRepository) ApplyThresholdMatch(
+	ctx context.Context,
+	reading *entity.IoTSensorReading,
+	assetID uuid.UUID,
+	threshold *entity.SensorThreshold,
+	value interface{},
+	matched bool,
+	maintenance AlertMaintenance,
+) (*entity.AssetAlert, entity.ThresholdTransition, error) {
+	return r.applyThresholdOutcome(ctx, reading, assetID, threshold, maintenance, thresholdOutcome{
+		evaluate: func(state *entity.ThresholdState, at time.Time) entity.ThresholdTransition {
+			return threshold.EvaluateMatch(state, matched, at)
+		},
+		newAlert: func(tenantID uuid.UUID) *entity.AssetAlert {
+			return entity.CreateAlertFromValueMatch(tenantID, assetID, reading.AssetSensorID, threshold, value)
+		},
+		value:  entity.MatchTriggerValue(value),
+		status: entity.ThresholdStatus(threshold.Severity),
+	})
+}
+
+// thresholdOutcome describes how one reading applies to a threshold
+type thresholdOutcome struct {
+	evaluate func(state *entity.ThresholdState, at time.Time) entity.ThresholdTransition
+	newAlert func(tenantID uuid.UUID) *entity.AssetAlert // Alert to open when the breach opens one
+	value    float64                                     // Value recorded on the alert
+	status   entity.ThresholdStatus                      // Status of an open alert the reading updates
+}
+
+// applyThresholdOutcome locks the threshold state, applies the outcome to it and opens,
+// updates or resolves the open alert of the (asset sensor, threshold) pair accordingly
+func (r *assetAlertRepository) applyThresholdOutcome(
+	ctx context.Context,
+	reading *entity.IoTSensorReading,
+	assetID uuid.UUID,
+	threshold *entity.SensorThreshold,
+	maintenance AlertMaintenance,
+	outcome thresholdOutcome,
+) (*entity.AssetAlert, entity.ThresholdTransition, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to check active alerts: %w", err)
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	state, err := r.lockThresholdState(ctx, tx, threshold.ID, reading.AssetSensorID)
+	if err != nil {
+		return nil, entity.ThresholdTransitionNone, err
+	}
+	if state.Applied(reading.ID) {
+		return nil, entity.ThresholdTransitionNone, nil
 	}
 
-	// Check if there's already an active alert for this specific threshold
-	for _, alert := range activeAlerts {
-		if alert.ThresholdID == threshold.ID && !alert.IsResolved {
-			// Update existing alert with new value
-			alert.TriggerValue = value
-			alert.AlertTime = time.Now()
-			alert.AlertMessage = fmt.Sprintf("Threshold breach continues: %s = %.2f (threshold: %.2f - %.2f)",
-				threshold.MeasurementFieldName, value,
-				*threshold.MinValue, *threshold.MaxValue)
-			return r.Update(ctx, alert)
+	at := reading.ReadingTime
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if state.IsLate(at) {
+		log.Printf("Skipping late reading %s for threshold %s: taken at %s, before the last evaluated reading at %s",
+			reading.ID, threshold.ID, at.Format(time.RFC3339), state.LastReadingTime.Format(time.RFC3339))
+		return nil, entity.ThresholdTransitionNone, nil
+	}
+	state.LastReadingID = &reading.ID
+	state.LastReadingTime = &at
+	transition := outcome.evaluate(state, at)
+
+	var alert *entity.AssetAlert
+	switch transition {
+	case entity.ThresholdTransitionOpen:
+		if maintenance.Suppress {
+			// The breach stays in the state and opens its alert on the first reading after
+			// the maintenance ends
+			transition = entity.ThresholdTransitionNone
+			break
+		}
+		alert, err = r.openThresholdAlert(ctx, tx, reading, threshold, outcome, maintenance, state.ConsecutiveBreaches, at)
+	case entity.ThresholdTransitionUpdate:
+		alert, err = r.getOpenAlertForUpdate(ctx, tx, threshold.ID, reading.AssetSensorID)
+		if err == nil && alert == nil && maintenance.Suppress {
+			transition = entity.ThresholdTransitionNone
+		} else if err == nil && alert == nil {
+			// The open alert was resolved by hand, or suppressed by maintenance, while the
+			// breach continued
+			transition = entity.ThresholdTransitionOpen
+			alert, err = r.openThresholdAlert(ctx, tx, reading, threshold, outcome, maintenance, 1, at)
+		} else if err == nil {
+			alert.RecordOccurrence(outcome.value, at)
+			alert.Status = outcome.status
+			err = r.updateAlertOccurrence(ctx, tx, alert)
 		}
+	case entity.ThresholdTransitionClose:
+		alert, err = r.resolveOpenAlert(ctx, tx, threshold.ID, reading.AssetSensorID, at)
+	}
+	if err != nil {
+		return nil, entity.ThresholdTransitionNone, err
 	}
 
-	// Create new alert
-	alert := &entity.AssetAlert{
-		ID:                   uuid.New(),
-		TenantID:             *reading.TenantID,
-		AssetSensorID:        reading.AssetSensorID,
-		ThresholdID:          threshold.ID,
-		MeasurementFieldName: threshold.MeasurementFieldName,
-		AlertTime:            time.Now(),
-		Severity:             threshold.Severity,
-		TriggerValue:         value,
-		ThresholdMinValue:    threshold.MinValue,
-		ThresholdMaxValue:    threshold.MaxValue,
-		IsResolved:           false,
-		CreatedAt:            time.Now(),
-	}
-
-	// Set alert type and message based on threshold breach
-	if threshold.MinValue != nil && value < *threshold.MinValue {
-		alert.AlertType = "min_breach"
-		alert.AlertMessage = fmt.Sprintf("Value below minimum threshold: %s = %.2f (threshold: %.2f)",
-			threshold.MeasurementFieldName, value, *threshold.MinValue)
-	} else if threshold.MaxValue != nil && value > *threshold.MaxValue {
-		alert.AlertType = "max_breach"
-		alert.AlertMessage = fmt.Sprintf("Value above maximum threshold: %s = %.2f (threshold: %.2f)",
-			threshold.MeasurementFieldName, value, *threshold.MaxValue)
-	}
-
-	return r.Create(ctx, alert)
-}
-
-// ResolveAlertsForReading resolves alerts when reading returns to normal
-func (r *assetAlertRepository) ResolveAlertsForReading(ctx context.Context, reading *entity.IoTSensorReading, threshold *entity.SensorThreshold, value float64) error {
-	// Get active alerts for this sensor
-	activeAlerts, err := r.GetActiveAlertsByAssetSensor(ctx, reading.AssetSensorID)
-	if err != nil {
-		return fmt.Errorf("failed to get active alerts: %w", err)
-	}
-
-	// Check if value is within normal range
-	isNormal := true
-	if threshold.MinValue != nil && value < *threshold.MinValue {
-		isNormal = false
-	}
-	if threshold.MaxValue != nil && value > *threshold.MaxValue {
-		isNormal = false
-	}
-
-	// If value is normal, resolve all active alerts for this threshold
-	if isNormal {
-		for _, alert := range activeAlerts {
-			if alert.ThresholdID == threshold.ID && !alert.IsResolved {
-				alert.IsResolved = true
-				now := time.Now()
-				alert.ResolvedTime = &now
-				alert.AlertMessage = fmt.Sprintf("Alert resolved: %s returned to normal range (%.2f)",
-					threshold.MeasurementFieldName, value)
-				if err := r.Update(ctx, alert); err != nil {
-					return fmt.Errorf("failed to resolve alert: %w", err)
-				}
-			}
+	state.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE sensor_threshold_states SET
+			in_alert = $3,
+			breach_started_at = $4,
+			consecutive_breaches = $5,
+			clear_started_at = $6,
+			consecutive_clears = $7,
+			last_reading_id = $8,
+			last_reading_time = $9,
+			updated_at = $10
+		WHERE threshold_id = $1 AND asset_sensor_id = $2`,
+		state.ThresholdID,
+		state.AssetSensorID,
+		state.InAlert,
+		state.BreachStartedAt,
+		state.ConsecutiveBreaches,
+		state.ClearStartedAt,
+		state.ConsecutiveClears,
+		state.LastReadingID,
+		state.LastReadingTime,
+		state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to save threshold state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to commit threshold evaluation: %w", err)
+	}
+
+	return alert, transition, nil
+}
+
+// lockThresholdState loads the state row for a threshold and asset sensor, creating it
+// when missing, and locks it until the transaction ends. A new row starts in alert when
+// an open alert already exists for the pair.
+func (r *assetAlertRepository) lockThresholdState(ctx context.Context, tx *sql.Tx, thresholdID, assetSensorID uuid.UUID) (*entity.ThresholdState, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO sensor_threshold_states (threshold_id, asset_sensor_id, in_alert, updated_at)
+		VALUES ($1, $2, EXISTS (
+			SELECT 1 FROM asset_alerts
+			WHERE threshold_id = $1 AND asset_sensor_id = $2 AND is_resolved = false
+		), $3)
+		ON CONFLICT (threshold_id, asset_sensor_id) DO NOTHING`,
+		thresholdID, assetSensorID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create threshold state: %w", err)
+	}
+
+	state := &entity.ThresholdState{
+		ThresholdID:   thresholdID,
+		AssetSensorID: assetSensorID,
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT in_alert, breach_started_at, consecutive_breaches,
+			   clear_started_at, consecutive_clears, last_reading_id, last_reading_time, updated_at
+		FROM sensor_threshold_states
+		WHERE threshold_id = $1 AND asset_sensor_id = $2
+		FOR UPDATE`,
+		thresholdID, assetSensorID).Scan(
+		&state.InAlert,
+		&state.BreachStartedAt,
+		&state.ConsecutiveBreaches,
+		&state.ClearStartedAt,
+		&state.ConsecutiveClears,
+		&state.LastReadingID,
+		&state.LastReadingTime,
+		&state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock threshold state: %w", err)
+	}
+
+	return state, nil
+}
+
+// openThresholdAlert inserts a new open alert for a threshold breach
+func (r *assetAlertRepository) openThresholdAlert(
+	ctx context.Context,
+	tx *sql.Tx,
+	reading *entity.IoTSensorReading,
+	threshold *entity.SensorThreshold,
+	outcome thresholdOutcome,
+	maintenance AlertMaintenance,
+	occurrences int,
+	at time.Time,
+) (*entity.AssetAlert, error) {
+	tenantID := threshold.TenantID
+	if reading.TenantID != nil {
+		tenantID = *reading.TenantID
+	}
+
+	alert := outcome.newAlert(tenantID)
+	alert.AlertTime = at
+	alert.LastTriggeredAt = &at
+	alert.OccurrenceCount = occurrences
+	alert.MaintenanceWindowID = maintenance.WindowID
+
+	if err := insertAlert(ctx, tx, alert); err != nil {
+		return nil, fmt.Errorf("failed to open asset alert: %w", err)
+	}
+
+	if err := insertAlertEvent(ctx, tx, entity.NewAssetAlertEvent(alert, entity.AssetAlertEventOpened, nil)); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Opened asset alert %s for threshold %s on asset sensor %s", alert.ID, threshold.ID, reading.AssetSensorID)
+	return alert, nil
+}
+
+// getOpenAlertForUpdate returns the open alert for a threshold and asset sensor, locked
+// until the transaction ends, or nil when there is none
+func (r *assetAlertRepository) getOpenAlertForUpdate(ctx context.Context, tx *sql.Tx, thresholdID, assetSensorID uuid.UUID) (*entity.AssetAlert, error) {
+	query := `
+		SELECT ` + assetAlertColumns + `
+		FROM asset_alerts
+		WHERE threshold_id = $1 AND asset_sensor_id = $2 AND is_resolved = false
+		FOR UPDATE`
+
+	alert, err := scanAlert(tx.QueryRowContext(ctx, query, thresholdID, assetSensorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
 		}
+		return nil, fmt.Errorf("failed to get open asset alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// updateAlertOccurrence stores the occurrence tracking fields of an open alert
+func (r *assetAlertRepository) updateAlertOccurrence(ctx context.Context, tx *sql.Tx, alert *entity.AssetAlert) error {
+	query := `
+		UPDATE asset_alerts SET
+			status = $2,
+			last_trigger_value = $3,
+			peak_trigger_value = $4,
+			occurrence_count = $5,
+			last_triggered_at = $6,
+			updated_at = $7
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query,
+		alert.ID,
+		alert.Status,
+		alert.LastTriggerValue,
+		alert.PeakTriggerValue,
+		alert.OccurrenceCount,
+		alert.LastTriggeredAt,
+		alert.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update asset alert occurrence: %w", err)
 	}
 
 	return nil
 }
 
-// ResolveMultipleAlerts resolves multiple asset alerts
-func (r *assetAlertRepository) ResolveMultipleAlerts(ctx context.Context, alertIDs []uuid.UUID) (int, int, error) {
-	query := `UPDATE asset_alerts SET is_resolved = true, resolved_time = $2, updated_at = $2 WHERE id = ANY($1) AND is_resolved = false`
+// resolveOpenAlert resolves the open alert for a threshold and asset sensor, if any
+func (r *assetAlertRepository) resolveOpenAlert(ctx context.Context, tx *sql.Tx, thresholdID, assetSensorID uuid.UUID, at time.Time) (*entity.AssetAlert, error) {
+	query := `
+		UPDATE asset_alerts SET
+			is_resolved = true,
+			status = 'normal',
+			resolved_time = $3,
+			resolution_code = $4,
+			updated_at = $3
+		WHERE threshold_id = $1 AND asset_sensor_id = $2 AND is_resolved = false
+		RETURNING ` + assetAlertColumns
+
+	alert, err := scanAlert(tx.QueryRowContext(ctx, query, thresholdID, assetSensorID, at, entity.AlertResolutionAutoCleared))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve asset alert: %w", err)
+	}
+
+	resolution := entity.AlertResolution{Code: entity.AlertResolutionAutoCleared}
+	if err := insertAlertEvent(ctx, tx, newResolvedEvent(alert, resolution)); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Resolved asset alert %s for threshold %s on asset sensor %s", alert.ID, thresholdID, assetSensorID)
+	return alert, nil
+}
+
+// ApplyConditionEvaluation applies the outcome of an alert condition to its state and
+// opens, updates or resolves the single open alert of the condition according to its alert
+// rules. The condition state row is locked for the whole evaluation so concurrent readings
+// are applied one after another. value is the triggering reading's numeric value and
+// message describes the field values that met the condition. An alert opened during a
+// maintenance window is tagged with it in the same transaction, and none is opened while
+// the maintenance suppresses alerts.
+func (r *assetAlertRepository) ApplyConditionEvaluation(
+	ctx context.Context,
+	reading *entity.IoTSensorReading,
+	assetID uuid.UUID,
+	condition *entity.AlertCondition,
+	matched bool,
+	value float64,
+	message string,
+	maintenance AlertMaintenance,
+) (*entity.AssetAlert, entity.ThresholdTransition, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.DB.ExecContext(ctx, query, pq.Array(alertIDs), time.Now())
+	state, err := r.lockConditionState(ctx, tx, condition.ID)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to resolve multiple alerts: %w", err)
+		return nil, entity.ThresholdTransitionNone, err
+	}
+	if state.Applied(reading.ID) {
+		return nil, entity.ThresholdTransitionNone, nil
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	at := reading.ReadingTime
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if state.IsLate(at) {
+		log.Printf("Skipping late reading %s for alert condition %s: taken at %s, before the last evaluated reading at %s",
+			reading.ID, condition.ID, at.Format(time.RFC3339), state.LastReadingTime.Format(time.RFC3339))
+		return nil, entity.ThresholdTransitionNone, nil
+	}
+	state.LastReadingID = &reading.ID
+	state.LastReadingTime = &at
+	transition := condition.Evaluate(state, matched, at)
+
+	var alert *entity.AssetAlert
+	switch transition {
+	case entity.ThresholdTransitionOpen:
+		if maintenance.Suppress {
+			// The breach stays in the state and opens its alert on the first reading after
+			// the maintenance ends
+			transition = entity.ThresholdTransitionNone
+			break
+		}
+		alert, err = r.openConditionAlert(ctx, tx, reading, assetID, condition, value, message, maintenance, state.ConsecutiveBreaches, at)
+	case entity.ThresholdTransitionUpdate:
+		alert, err = r.getOpenConditionAlertForUpdate(ctx, tx, condition.ID)
+		if err == nil && alert == nil && maintenance.Suppress {
+			transition = entity.ThresholdTransitionNone
+		} else if err == nil && alert == nil {
+			// The open alert was resolved by hand, or suppressed by maintenance, while the
+			// condition kept holding
+			transition = entity.ThresholdTransitionOpen
+			alert, err = r.openConditionAlert(ctx, tx, reading, assetID, condition, value, message, maintenance, 1, at)
+		} else if err == nil {
+			alert.RecordOccurrence(value, at)
+			alert.Status = entity.ThresholdStatus(condition.Severity)
+			err = r.updateAlertOccurrence(ctx, tx, alert)
+		}
+	case entity.ThresholdTransitionClose:
+		alert, err = r.resolveOpenConditionAlert(ctx, tx, condition.ID, at)
+	}
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, entity.ThresholdTransitionNone, err
 	}
 
-	return int(rowsAffected), len(alertIDs) - int(rowsAffected), nil
+	state.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE alert_condition_states SET
+			in_alert = $2,
+			breach_started_at = $3,
+			consecutive_breaches = $4,
+			clear_started_at = $5,
+			consecutive_clears = $6,
+			last_reading_id = $7,
+			last_reading_time = $8,
+			updated_at = $9
+		WHERE condition_id = $1`,
+		state.ConditionID,
+		state.InAlert,
+		state.BreachStartedAt,
+		state.ConsecutiveBreaches,
+		state.ClearStartedAt,
+		state.ConsecutiveClears,
+		state.LastReadingID,
+		state.LastReadingTime,
+		state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to save alert condition state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, entity.ThresholdTransitionNone, fmt.Errorf("failed to commit alert condition evaluation: %w", err)
+	}
+
+	return alert, transition, nil
+}
+
+// lockConditionState loads the state row of an alert condition, creating it when missing,
+// and locks it until the transaction ends. A new row starts in alert when an open alert
+// already exists for the condition.
+func (r *assetAlertRepository) lockConditionState(ctx context.Context, tx *sql.Tx, conditionID uuid.UUID) (*entity.AlertConditionState, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO alert_condition_states (condition_id, in_alert, updated_at)
+		VALUES ($1, EXISTS (
+			SELECT 1 FROM asset_alerts WHERE condition_id = $1 AND is_resolved = false
+		), $2)
+		ON CONFLICT (condition_id) DO NOTHING`,
+		conditionID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alert condition state: %w", err)
+	}
+
+	state := &entity.AlertConditionState{ConditionID: conditionID}
+	err = tx.QueryRowContext(ctx, `
+		SELECT in_alert, breach_started_at, consecutive_breaches,
+			   clear_started_at, consecutive_clears, last_reading_id, last_reading_time, updated_at
+		FROM alert_condition_states
+		WHERE condition_id = $1
+		FOR UPDATE`,
+		conditionID).Scan(
+		&state.InAlert,
+		&state.BreachStartedAt,
+		&state.ConsecutiveBreaches,
+		&state.ClearStartedAt,
+		&state.ConsecutiveClears,
+		&state.LastReadingID,
+		&state.LastReadingTime,
+		&state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock alert condition state: %w", err)
+	}
+
+	return state, nil
+}
+
+// openConditionAlert inserts a new open alert for an alert condition
+func (r *assetAlertRepository) openConditionAlert(
+	ctx context.Context,
+	tx *sql.Tx,
+	reading *entity.IoTSensorReading,
+	assetID uuid.UUID,
+	condition *entity.AlertCondition,
+	value float64,
+	message string,
+	maintenance AlertMaintenance,
+	occurrences int,
+	at time.Time,
+) (*entity.AssetAlert, error) {
+	alert := entity.CreateAlertFromCondition(condition.TenantID, assetID, reading.AssetSensorID, condition, value, message)
+	alert.AlertTime = at
+	alert.LastTriggeredAt = &at
+	alert.OccurrenceCount = occurrences
+	alert.MaintenanceWindowID = maintenance.WindowID
+
+	if err := insertAlert(ctx, tx, alert); err != nil {
+		return nil, fmt.Errorf("failed to open asset alert: %w", err)
+	}
+
+	if err := insertAlertEvent(ctx, tx, entity.NewAssetAlertEvent(alert, entity.AssetAlertEventOpened, nil)); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Opened asset alert %s for alert condition %s", alert.ID, condition.ID)
+	return alert, nil
+}
+
+// getOpenConditionAlertForUpdate returns the open alert of an alert condition, locked
+// until the transaction ends, or nil when there is none
+func (r *assetAlertRepository) getOpenConditionAlertForUpdate(ctx context.Context, tx *sql.Tx, conditionID uuid.UUID) (*entity.AssetAlert, error) {
+	query := `
+		SELECT ` + assetAlertColumns + `
+		FROM asset_alerts
+		WHERE condition_id = $1 AND is_resolved = false
+		FOR UPDATE`
+
+	alert, err := scanAlert(tx.QueryRowContext(ctx, query, conditionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get open asset alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// resolveOpenConditionAlert resolves the open alert of an alert condition, if any
+func (r *assetAlertRepository) resolveOpenConditionAlert(ctx context.Context, tx *sql.Tx, conditionID uuid.UUID, at time.Time) (*entity.AssetAlert, error) {
+	query := `
+		UPDATE asset_alerts SET
+			is_resolved = true,
+			status = 'normal',
+			resolved_time = $2,
+			resolution_code = $3,
+			updated_at = $2
+		WHERE condition_id = $1 AND is_resolved = false
+		RETURNING ` + assetAlertColumns
+
+	alert, err := scanAlert(tx.QueryRowContext(ctx, query, conditionID, at, entity.AlertResolutionAutoCleared))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve asset alert: %w", err)
+	}
+
+	resolution := entity.AlertResolution{Code: entity.AlertResolutionAutoCleared}
+	if err := insertAlertEvent(ctx, tx, newResolvedEvent(alert, resolution)); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Resolved asset alert %s for alert condition %s", alert.ID, conditionID)
+	return alert, nil
+}
+
+// ResolveMultipleAlerts resolves multiple asset alerts with the same resolution
+func (r *assetAlertRepository) ResolveMultipleAlerts(ctx context.Context, alertIDs []uuid.UUID, resolution entity.AlertResolution) (int, int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE asset_alerts SET
+			is_resolved = true,
+			resolved_time = $2,
+			resolved_by = $3,
+			resolution_code = $4,
+			resolution_note = $5,
+			updated_at = $2
+		WHERE id = ANY($1) AND is_resolved = false
+		RETURNING id, tenant_id`
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(alertIDs), time.Now(), resolution.ResolvedBy, resolution.Code, resolution.Note)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve multiple alerts: %w", err)
+	}
+
+	var resolved []*entity.AssetAlert
+	for rows.Next() {
+		alert := &entity.AssetAlert{}
+		if err := rows.Scan(&alert.ID, &alert.TenantID); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan resolved alert: %w", err)
+		}
+		resolved = append(resolved, alert)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, alert := range resolved {
+		if err := insertAlertEvent(ctx, tx, newResolvedEvent(alert, resolution)); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit alert resolutions: %w", err)
+	}
+
+	return len(resolved), len(alertIDs) - len(resolved), nil
 }
 
 // DeleteMultipleAlerts deletes multiple asset alerts
@@ -794,3 +1497,81 @@ func (r *assetAlertRepository) DeleteMultipleAlerts(ctx context.Context, alertID
 
 	return int(rowsAffected), len(alertIDs) - int(rowsAffected), nil
 }
+
+// insertAlert inserts a single asset alert. Alerts raised by alert conditions have no
+// threshold, which is stored as NULL.
+func insertAlert(ctx context.Context, exec sqlExecer, alert *entity.AssetAlert) error {
+	var thresholdID *uuid.UUID
+	if alert.ThresholdID != uuid.Nil {
+		thresholdID = &alert.ThresholdID
+	}
+
+	_, err := exec.ExecContext(ctx, insertAlertQuery,
+		alert.ID,
+		alert.TenantID,
+		alert.AssetID,
+		alert.AssetSensorID,
+		thresholdID,
+		alert.MeasurementFieldName,
+		alert.AlertTime,
+		alert.Severity,
+		alert.TriggerValue,
+		alert.ThresholdMinValue,
+		alert.ThresholdMaxValue,
+		alert.AlertMessage,
+		alert.AlertType,
+		alert.IsResolved,
+		alert.CreatedAt,
+		alert.LastTriggerValue,
+		alert.PeakTriggerValue,
+		alert.OccurrenceCount,
+		alert.LastTriggeredAt,
+		alert.ConditionID,
+		alert.MaintenanceWindowID,
+	)
+	return err
+}
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertAlertEvent inserts an alert timeline entry
+func insertAlertEvent(ctx context.Context, exec sqlExecer, event *entity.AssetAlertEvent) error {
+	var details []byte
+	if len(event.Details) > 0 {
+		var err error
+		if details, err = json.Marshal(event.Details); err != nil {
+			return fmt.Errorf("failed to encode asset alert event details: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO asset_alert_events (id, alert_id, tenant_id, type, user_id, comment, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := exec.ExecContext(ctx, query,
+		event.ID,
+		event.AlertID,
+		event.TenantID,
+		event.Type,
+		event.UserID,
+		event.Comment,
+		details,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create asset alert event: %w", err)
+	}
+
+	return nil
+}
+
+// newResolvedEvent builds the timeline entry for a resolution
+func newResolvedEvent(alert *entity.AssetAlert, resolution entity.AlertResolution) *entity.AssetAlertEvent {
+	event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventResolved, resolution.ResolvedBy)
+	event.Comment = resolution.Note
+	event.Details = map[string]string{"resolution_code": string(resolution.Code)}
+	return event
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrAmbiguousMacAddress is returned when a MAC address looked up without a tenant is
+// configured on more than one asset sensor
+var ErrAmbiguousMacAddress = errors.New("mac address is configured on more than one asset sensor")
+
 // AssetSensorWithDetails represents an asset sensor with all its related information
 type AssetSensorWithDetails struct {
 	*entity.AssetSensor
@@ -58,9 +62,10 @@ type AssetSensorRepository interface {
 	Update(ctx context.Context, sensor *entity.AssetSensor) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByAssetID(ctx context.Context, assetID uuid.UUID) error
-	UpdateLastReading(ctx context.Context, id uuid.UUID, value float64, readings map[string]interface{}) error
+	UpdateLastReading(ctx context.Context, id uuid.UUID, value float64, readings map[string]interface{}, readingTime time.Time) (bool, error)
 	GetActiveSensors(ctx context.Context) ([]*AssetSensorWithDetails, error)
 	GetSensorsByStatus(ctx context.Context, status string) ([]*AssetSensorWithDetails, error)
+	GetByMacAddress(ctx context.Context, macAddress string, tenantID *uuid.UUID) (*AssetSensorWithDetails, error)
 }
 
 // assetSensorRepository handles database operations for asset sensors
@@ -817,14 +822,14 @@ func (r *assetSensorRepository) DeleteByAssetID(ctx context.Context, assetID uui
 	return nil
 }
 
-// UpdateLastReading updates the last reading value and time for a sensor
-func (r *assetSensorRepository) UpdateLastReading(ctx context.Context, id uuid.UUID, value float64, readings map[string]interface{}) error {
-	now := time.Now()
-
+// UpdateLastReading updates the last reading value and time for a sensor with a reading
+// taken at readingTime. The last reading only moves forward, so it returns false without
+// changing anything when the sensor already has a newer reading or doesn't exist.
+func (r *assetSensorRepository) UpdateLastReading(ctx context.Context, id uuid.UUID, value float64, readings map[string]interface{}, readingTime time.Time) (bool, error) {
 	// Convert readings map to JSON string
 	readingsJSON, err := json.Marshal(readings)
 	if err != nil {
-		return fmt.Errorf("failed to marshal readings: %w", err)
+		return false, fmt.Errorf("failed to marshal readings: %w", err)
 	}
 	readingsStr := string(readingsJSON)
 
@@ -834,23 +839,19 @@ func (r *assetSensorRepository) UpdateLastReading(ctx context.Context, id uuid.U
 			last_reading_time = $2,
 			last_reading_values = $3::jsonb,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $4`
+		WHERE id = $4 AND (last_reading_time IS NULL OR last_reading_time <= $2)`
 
-	result, err := r.DB.ExecContext(ctx, query, value, now, readingsStr, id)
+	result, err := r.DB.ExecContext(ctx, query, value, readingTime, readingsStr, id)
 	if err != nil {
-		return fmt.Errorf("failed to update sensor reading: %w", err)
+		return false, fmt.Errorf("failed to update sensor reading: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("asset sensor not found")
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	return nil
+	return rowsAffected > 0, nil
 }
 
 // GetActiveSensors retrieves all active sensors
@@ -1064,3 +1065,49 @@ func (r *assetSensorRepository) GetSensorsByStatus(ctx context.Context, status s
 
 	return sensors, nil
 }
+
+// GetByMacAddress resolves an asset sensor from the device MAC address configured in
+// its configuration (configuration->>'mac_address'). When tenantID is set only that
+// tenant's sensors are considered, the newest of them winning; otherwise the MAC must be
+// configured on a single sensor, or ErrAmbiguousMacAddress is returned. Returns nil, nil
+// if no sensor is configured with the MAC.
+func (r *assetSensorRepository) GetByMacAddress(ctx context.Context, macAddress string, tenantID *uuid.UUID) (*AssetSensorWithDetails, error) {
+	query := `
+		SELECT id FROM asset_sensors
+		WHERE LOWER(configuration->>'mac_address') = LOWER($1)`
+	args := []interface{}{macAddress}
+	if tenantID != nil {
+		query += ` AND tenant_id = $2`
+		args = append(args, *tenantID)
+	}
+	query += `
+		ORDER BY created_at DESC
+		LIMIT 2`
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset sensor by mac address: %w", err)
+	}
+	defer rows.Close()
+
+	var sensorIDs []uuid.UUID
+	for rows.Next() {
+		var sensorID uuid.UUID
+		if err := rows.Scan(&sensorID); err != nil {
+			return nil, fmt.Errorf("failed to scan asset sensor id: %w", err)
+		}
+		sensorIDs = append(sensorIDs, sensorID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get asset sensor by mac address: %w", err)
+	}
+
+	if len(sensorIDs) == 0 {
+		return nil, nil
+	}
+	if len(sensorIDs) > 1 && tenantID == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousMacAddress, macAddress)
+	}
+
+	return r.GetByID(ctx, sensorIDs[0])
+}
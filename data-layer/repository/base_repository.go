@@ -14,6 +14,11 @@ type BaseRepository struct {
 	DB *sql.DB
 }
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 // NewBaseRepository creates a new BaseRepository
 func NewBaseRepository(db *sql.DB) *BaseRepository {
 	return &BaseRepository{
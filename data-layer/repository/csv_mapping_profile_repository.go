@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CSVMappingProfileRepository defines the interface for CSV mapping profile operations
+type CSVMappingProfileRepository interface {
+	Create(ctx context.Context, profile *entity.CSVMappingProfile) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.CSVMappingProfile, error)
+	List(ctx context.Context, sensorTypeID *uuid.UUID, limit, offset int) ([]*entity.CSVMappingProfile, int, error)
+	Update(ctx context.Context, profile *entity.CSVMappingProfile) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// csvMappingProfileRepository handles database operations for CSV mapping profiles
+type csvMappingProfileRepository struct {
+	*BaseRepository
+}
+
+// NewCSVMappingProfileRepository creates a new CSVMappingProfileRepository
+func NewCSVMappingProfileRepository(db *sql.DB) CSVMappingProfileRepository {
+	return &csvMappingProfileRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const csvMappingProfileColumns = `
+	id, sensor_type_id, name, description, delimiter, timestamp_column, timestamp_format,
+	timezone, asset_sensor_column, columns, created_at, updated_at`
+
+// Create inserts a new CSV mapping profile into the database
+func (r *csvMappingProfileRepository) Create(ctx context.Context, profile *entity.CSVMappingProfile) error {
+	if profile.ID == uuid.Nil {
+		profile.ID = uuid.New()
+	}
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now()
+	}
+
+	columns, err := json.Marshal(profile.Columns)
+	if err != nil {
+		return fmt.Errorf("failed to encode column mappings: %w", err)
+	}
+
+	query := `
+		INSERT INTO csv_mapping_profiles (
+			id, sensor_type_id, name, description, delimiter, timestamp_column,
+			timestamp_format, timezone, asset_sensor_column, columns, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		profile.ID,
+		profile.SensorTypeID,
+		profile.Name,
+		profile.Description,
+		profile.Delimiter,
+		profile.TimestampColumn,
+		profile.TimestampFormat,
+		profile.Timezone,
+		profile.AssetSensorColumn,
+		columns,
+		profile.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV mapping profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a CSV mapping profile by its ID
+func (r *csvMappingProfileRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.CSVMappingProfile, error) {
+	query := `SELECT ` + csvMappingProfileColumns + ` FROM csv_mapping_profiles WHERE id = $1`
+
+	profile, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get CSV mapping profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// List retrieves paginated CSV mapping profiles, optionally of one sensor type
+func (r *csvMappingProfileRepository) List(ctx context.Context, sensorTypeID *uuid.UUID, limit, offset int) ([]*entity.CSVMappingProfile, int, error) {
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM csv_mapping_profiles WHERE ($1::uuid IS NULL OR sensor_type_id = $1)`
+	if err := r.DB.QueryRowContext(ctx, countQuery, sensorTypeID).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := `SELECT ` + csvMappingProfileColumns + `
+		FROM csv_mapping_profiles
+		WHERE ($1::uuid IS NULL OR sensor_type_id = $1)
+		ORDER BY name
+		LIMIT $2 OFFSET $3`
+
+	profiles, err := r.queryProfiles(ctx, query, sensorTypeID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return profiles, totalCount, nil
+}
+
+// Update updates an existing CSV mapping profile
+func (r *csvMappingProfileRepository) Update(ctx context.Context, profile *entity.CSVMappingProfile) error {
+	now := time.Now()
+	profile.UpdatedAt = &now
+
+	columns, err := json.Marshal(profile.Columns)
+	if err != nil {
+		return fmt.Errorf("failed to encode column mappings: %w", err)
+	}
+
+	query := `
+		UPDATE csv_mapping_profiles SET
+			name = $2,
+			description = $3,
+			delimiter = $4,
+			timestamp_column = $5,
+			timestamp_format = $6,
+			timezone = $7,
+			asset_sensor_column = $8,
+			columns = $9,
+			updated_at = $10
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		profile.ID,
+		profile.Name,
+		profile.Description,
+		profile.Delimiter,
+		profile.TimestampColumn,
+		profile.TimestampFormat,
+		profile.Timezone,
+		profile.AssetSensorColumn,
+		columns,
+		profile.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update CSV mapping profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("CSV mapping profile not found")
+	}
+
+	return nil
+}
+
+// Delete removes a CSV mapping profile by its ID
+func (r *csvMappingProfileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM csv_mapping_profiles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete CSV mapping profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("CSV mapping profile not found")
+	}
+
+	return nil
+}
+
+// queryProfiles executes a query and returns CSV mapping profiles
+func (r *csvMappingProfileRepository) queryProfiles(ctx context.Context, query string, args ...interface{}) ([]*entity.CSVMappingProfile, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CSV mapping profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*entity.CSVMappingProfile
+	for rows.Next() {
+		profile, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan CSV mapping profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating CSV mapping profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// scanRow scans a single CSV mapping profile row
+func (r *csvMappingProfileRepository) scanRow(row rowScanner) (*entity.CSVMappingProfile, error) {
+	var profile entity.CSVMappingProfile
+	var columns []byte
+	err := row.Scan(
+		&profile.ID,
+		&profile.SensorTypeID,
+		&profile.Name,
+		&profile.Description,
+		&profile.Delimiter,
+		&profile.TimestampColumn,
+		&profile.TimestampFormat,
+		&profile.Timezone,
+		&profile.AssetSensorColumn,
+		&columns,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(columns, &profile.Columns); err != nil {
+		return nil, fmt.Errorf("failed to decode column mappings: %w", err)
+	}
+
+	return &profile, nil
+}
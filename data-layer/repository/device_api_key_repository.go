@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceAPIKeyRepository defines the interface for device API key operations
+type DeviceAPIKeyRepository interface {
+	Create(ctx context.Context, key *entity.DeviceAPIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.DeviceAPIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*entity.DeviceAPIKey, error)
+	List(ctx context.Context, tenantID uuid.UUID, assetSensorID *uuid.UUID, limit, offset int) ([]*entity.DeviceAPIKey, int, error)
+	UpdateSecret(ctx context.Context, id uuid.UUID, prefix, hash string) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// deviceAPIKeyRepository handles database operations for device API keys
+type deviceAPIKeyRepository struct {
+	*BaseRepository
+}
+
+// NewDeviceAPIKeyRepository creates a new DeviceAPIKeyRepository
+func NewDeviceAPIKeyRepository(db *sql.DB) DeviceAPIKeyRepository {
+	return &deviceAPIKeyRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const deviceAPIKeyColumns = `
+	id, tenant_id, asset_sensor_id, name, key_prefix, key_hash, is_active,
+	expires_at, last_used_at, rotated_at, revoked_at, created_at, updated_at`
+
+// Create inserts a new device API key into the database
+func (r *deviceAPIKeyRepository) Create(ctx context.Context, key *entity.DeviceAPIKey) error {
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO device_api_keys (
+			id, tenant_id, asset_sensor_id, name, key_prefix, key_hash,
+			is_active, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		key.ID,
+		key.TenantID,
+		key.AssetSensorID,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		key.IsActive,
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create device api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a device API key by its ID
+func (r *deviceAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.DeviceAPIKey, error) {
+	query := `SELECT ` + deviceAPIKeyColumns + ` FROM device_api_keys WHERE id = $1`
+
+	key, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get device api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// GetByPrefix retrieves a device API key by its public prefix
+func (r *deviceAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entity.DeviceAPIKey, error) {
+	query := `SELECT ` + deviceAPIKeyColumns + ` FROM device_api_keys WHERE key_prefix = $1`
+
+	key, err := r.scanRow(r.DB.QueryRowContext(ctx, query, prefix))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get device api key by prefix: %w", err)
+	}
+
+	return key, nil
+}
+
+// List retrieves paginated device API keys for a tenant, optionally filtered by asset sensor
+func (r *deviceAPIKeyRepository) List(ctx context.Context, tenantID uuid.UUID, assetSensorID *uuid.UUID, limit, offset int) ([]*entity.DeviceAPIKey, int, error) {
+	whereClause := `WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if assetSensorID != nil {
+		whereClause += ` AND asset_sensor_id = $2`
+		args = append(args, *assetSensorID)
+	}
+
+	// Get total count
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM device_api_keys ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	// Get paginated results
+	query := fmt.Sprintf(`SELECT %s FROM device_api_keys %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		deviceAPIKeyColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query device api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*entity.DeviceAPIKey
+	for rows.Next() {
+		key, err := r.scanRow(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan device api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating device api keys: %w", err)
+	}
+
+	return keys, totalCount, nil
+}
+
+// UpdateSecret replaces the key prefix and hash, invalidating the previous key
+func (r *deviceAPIKeyRepository) UpdateSecret(ctx context.Context, id uuid.UUID, prefix, hash string) error {
+	query := `
+		UPDATE device_api_keys
+		SET key_prefix = $1, key_hash = $2, rotated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND revoked_at IS NULL`
+
+	result, err := r.DB.ExecContext(ctx, query, prefix, hash, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate device api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("device api key not found or revoked")
+	}
+
+	return nil
+}
+
+// Revoke permanently disables a device API key
+func (r *deviceAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE device_api_keys
+		SET is_active = false, revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.DB.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to revoke device api key: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records when a device API key was last used
+func (r *deviceAPIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE device_api_keys SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := r.DB.ExecContext(ctx, query, usedAt, id); err != nil {
+		return fmt.Errorf("failed to update device api key last used time: %w", err)
+	}
+
+	return nil
+}
+
+// scanRow scans a single device API key row
+func (r *deviceAPIKeyRepository) scanRow(row rowScanner) (*entity.DeviceAPIKey, error) {
+	var key entity.DeviceAPIKey
+	err := row.Scan(
+		&key.ID,
+		&key.TenantID,
+		&key.AssetSensorID,
+		&key.Name,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&key.IsActive,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RotatedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+		&key.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EscalationPolicyRepository defines the interface for escalation policy operations
+type EscalationPolicyRepository interface {
+	Create(ctx context.Context, policy *entity.EscalationPolicy) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.EscalationPolicy, error)
+	List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.EscalationPolicy, int, error)
+	GetActive(ctx context.Context) ([]*entity.EscalationPolicy, error)
+	Update(ctx context.Context, policy *entity.EscalationPolicy) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// escalationPolicyRepository handles database operations for escalation policies
+type escalationPolicyRepository struct {
+	*BaseRepository
+}
+
+// NewEscalationPolicyRepository creates a new EscalationPolicyRepository
+func NewEscalationPolicyRepository(db *sql.DB) EscalationPolicyRepository {
+	return &escalationPolicyRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const escalationPolicyColumns = `
+	id, tenant_id, name, description, severities, steps, is_active, created_at, updated_at`
+
+// Create inserts a new escalation policy into the database
+func (r *escalationPolicyRepository) Create(ctx context.Context, policy *entity.EscalationPolicy) error {
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = time.Now()
+	}
+
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode escalation steps: %w", err)
+	}
+
+	query := `
+		INSERT INTO escalation_policies (
+			id, tenant_id, name, description, severities, steps, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		policy.ID,
+		policy.TenantID,
+		policy.Name,
+		policy.Description,
+		pq.Array(severityStrings(policy.Severities)),
+		steps,
+		policy.IsActive,
+		policy.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an escalation policy by its ID
+func (r *escalationPolicyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.EscalationPolicy, error) {
+	query := `SELECT ` + escalationPolicyColumns + ` FROM escalation_policies WHERE id = $1`
+
+	policy, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// List retrieves paginated escalation policies for a tenant
+func (r *escalationPolicyRepository) List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.EscalationPolicy, int, error) {
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM escalation_policies WHERE tenant_id = $1`
+	if err := r.DB.QueryRowContext(ctx, countQuery, tenantID).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := `SELECT ` + escalationPolicyColumns + `
+		FROM escalation_policies
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	policies, err := r.queryPolicies(ctx, query, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return policies, totalCount, nil
+}
+
+// GetActive retrieves the active escalation policies of all tenants
+func (r *escalationPolicyRepository) GetActive(ctx context.Context) ([]*entity.EscalationPolicy, error) {
+	query := `SELECT ` + escalationPolicyColumns + `
+		FROM escalation_policies
+		WHERE is_active = true
+		ORDER BY created_at`
+
+	return r.queryPolicies(ctx, query)
+}
+
+// Update updates an existing escalation policy
+func (r *escalationPolicyRepository) Update(ctx context.Context, policy *entity.EscalationPolicy) error {
+	now := time.Now()
+	policy.UpdatedAt = &now
+
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode escalation steps: %w", err)
+	}
+
+	query := `
+		UPDATE escalation_policies SET
+			name = $2,
+			description = $3,
+			severities = $4,
+			steps = $5,
+			is_active = $6,
+			updated_at = $7
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		policy.ID,
+		policy.Name,
+		policy.Description,
+		pq.Array(severityStrings(policy.Severities)),
+		steps,
+		policy.IsActive,
+		policy.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("escalation policy not found")
+	}
+
+	return nil
+}
+
+// Delete removes an escalation policy by its ID. Escalation history is kept.
+func (r *escalationPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM escalation_policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("escalation policy not found")
+	}
+
+	return nil
+}
+
+// queryPolicies executes a query and returns escalation policies
+func (r *escalationPolicyRepository) queryPolicies(ctx context.Context, query string, args ...interface{}) ([]*entity.EscalationPolicy, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*entity.EscalationPolicy
+	for rows.Next() {
+		policy, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// scanRow scans a single escalation policy row
+func (r *escalationPolicyRepository) scanRow(row rowScanner) (*entity.EscalationPolicy, error) {
+	var policy entity.EscalationPolicy
+	var severities []string
+	var steps []byte
+	err := row.Scan(
+		&policy.ID,
+		&policy.TenantID,
+		&policy.Name,
+		&policy.Description,
+		pq.Array(&severities),
+		&steps,
+		&policy.IsActive,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Severities = make([]entity.ThresholdSeverity, 0, len(severities))
+	for _, severity := range severities {
+		policy.Severities = append(policy.Severities, entity.ThresholdSeverity(severity))
+	}
+	if err := json.Unmarshal(steps, &policy.Steps); err != nil {
+		return nil, fmt.Errorf("failed to decode escalation steps: %w", err)
+	}
+
+	return &policy, nil
+}
@@ -13,8 +13,32 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// iotSensorReadingColumns lists the stored columns of a reading in the order they are
+// written. archived_iot_sensor_readings has the same columns, so the retention and archive
+// copies use this list too; a column added to iot_sensor_readings is added here.
+var iotSensorReadingColumns = []string{
+	"id", "tenant_id", "asset_sensor_id", "sensor_type_id", "mac_address",
+	"location_id", "location_name", "measurement_type", "measurement_label",
+	"measurement_unit", "numeric_value", "text_value", "boolean_value",
+	"data_source", "original_field_name", "original_value", "original_unit",
+	"is_late", "device_time", "clock_offset_ms", "time_anomaly", "reading_time", "created_at", "updated_at",
+}
+
+// readingColumns is the column list of iotSensorReadingColumns
+var readingColumns = strings.Join(iotSensorReadingColumns, ", ")
+
+// insertReadingQuery inserts a single reading with every column of iotSensorReadingColumns
+var insertReadingQuery = func() string {
+	placeholders := make([]string, len(iotSensorReadingColumns))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return fmt.Sprintf("INSERT INTO iot_sensor_readings (%s) VALUES (%s)", readingColumns, strings.Join(placeholders, ", "))
+}()
+
 // IoTSensorReadingWithDetails represents an IoT sensor reading with all its related information
 type IoTSensorReadingWithDetails struct {
 	*entity.IoTSensorReading
@@ -83,11 +107,16 @@ type IoTSensorReadingRepository interface {
 	DeleteByAssetSensorID(ctx context.Context, assetSensorID uuid.UUID) error
 	GetLatestReading(ctx context.Context, assetSensorID uuid.UUID) (*IoTSensorReadingWithDetails, error)
 	GetReadingsInTimeRange(ctx context.Context, assetSensorID uuid.UUID, fromTime, toTime time.Time) ([]*IoTSensorReadingWithDetails, error)
-	GetAggregatedData(ctx context.Context, assetSensorID uuid.UUID, fromTime, toTime time.Time, interval string) ([]map[string]interface{}, error)
+	GetAggregatedData(ctx context.Context, assetSensorID uuid.UUID, fromTime, toTime time.Time, interval string) ([]*entity.ReadingAggregate, error)
+	AggregateReadings(ctx context.Context, query entity.ReadingAggregateQuery) ([]*entity.ReadingAggregate, error)
+	GetWindowStats(ctx context.Context, assetSensorID uuid.UUID, measurementType string, fromTime, toTime time.Time) (*entity.ReadingWindowStats, error)
 	ValidateAndCreate(ctx context.Context, reading *entity.IoTSensorReading) (bool, []string, error)
 	CreateFlexible(ctx context.Context, reading *entity.IoTSensorReadingFlexible) error
 	CreateFlexibleBatch(ctx context.Context, readings []*entity.IoTSensorReadingFlexible) error
+	CreateFlexibleMessages(ctx context.Context, messages []*entity.ReadingMessage) error
+	CopyFlexibleBatch(ctx context.Context, readings []*entity.IoTSensorReadingFlexible, effects []entity.ReadingEffect) error
 	GetFlexibleByID(ctx context.Context, id uuid.UUID) (*entity.IoTSensorReadingFlexible, error)
+	GetLatestMeasurement(ctx context.Context, assetSensorID uuid.UUID, measurementType string, fromTime, toTime time.Time) (*entity.IoTSensorReadingFlexible, error)
 	ListFlexible(ctx context.Context, req IoTSensorReadingListRequest) ([]*entity.IoTSensorReadingFlexible, int, error)
 	ParseTextToFlexibleReading(ctx context.Context, textData, assetSensorID, sensorTypeID, macAddress string) (*entity.IoTSensorReadingFlexible, error)
 	GetDB() *sql.DB
@@ -121,18 +150,13 @@ func (r *iotSensorReadingRepository) Create(ctx context.Context, reading *entity
 	}
 	reading.UpdatedAt = &now
 
-	// Build and execute the insert query
-	query := `
-		INSERT INTO iot_sensor_readings (
-			id, tenant_id, asset_sensor_id, sensor_type_id, mac_address, 
-			location_id, location_name, measurement_type, measurement_label, 
-			measurement_unit, numeric_value, text_value, boolean_value, 
-			data_source, original_field_name, reading_time, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
-		)`
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	_, err := r.DB.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, insertReadingQuery,
 		reading.ID,
 		reading.TenantID,
 		reading.AssetSensorID,
@@ -148,6 +172,12 @@ func (r *iotSensorReadingRepository) Create(ctx context.Context, reading *entity
 		reading.BooleanValue,
 		reading.DataSource,
 		reading.OriginalFieldName,
+		reading.OriginalValue,
+		reading.OriginalUnit,
+		reading.IsLate,
+		reading.DeviceTime,
+		reading.ClockOffsetMs,
+		reading.TimeAnomaly,
 		reading.ReadingTime,
 		reading.CreatedAt,
 		reading.UpdatedAt,
@@ -158,6 +188,14 @@ func (r *iotSensorReadingRepository) Create(ctx context.Context, reading *entity
 		return fmt.Errorf("failed to create IoT sensor reading: %w", err)
 	}
 
+	if err := insertReadingOutbox(ctx, tx, reading, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	log.Printf("Successfully created IoT sensor reading with ID: %s", reading.ID)
 	return nil
 }
@@ -216,6 +254,10 @@ func (r *iotSensorReadingRepository) CreateBatch(ctx context.Context, readings [
 			log.Printf("Error inserting reading %s: %v", reading.ID, err)
 			return fmt.Errorf("failed to insert reading: %w", err)
 		}
+
+		if err := insertReadingOutbox(ctx, tx, reading, now); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -531,76 +573,147 @@ func (r *iotSensorReadingRepository) GetReadingsInTimeRange(ctx context.Context,
 	return r.scanRowsToResults(rows)
 }
 
-// GetAggregatedData retrieves aggregated sensor data for analytics and visualization
-func (r *iotSensorReadingRepository) GetAggregatedData(ctx context.Context, assetSensorID uuid.UUID, fromTime, toTime time.Time, interval string) ([]map[string]interface{}, error) {
-	// Validate interval
-	validIntervals := map[string]bool{
-		"5m": true, "15m": true, "30m": true, "1h": true, "6h": true, "12h": true, "1d": true,
+// GetAggregatedData summarises the numeric readings of an asset sensor per time bucket,
+// measurement and unit. interval is a date_trunc field: hour, day, week or month.
+func (r *iotSensorReadingRepository) GetAggregatedData(ctx context.Context, assetSensorID uuid.UUID, fromTime, toTime time.Time, interval string) ([]*entity.ReadingAggregate, error) {
+	bucketInterval, err := entity.ParseAggregateInterval(interval)
+	if err != nil || !bucketInterval.IsCalendar() {
+		return nil, fmt.Errorf("invalid interval: %s. Valid intervals: hour, day, week, month", interval)
+	}
+
+	return r.AggregateReadings(ctx, entity.ReadingAggregateQuery{
+		AssetSensorID: &assetSensorID,
+		FromTime:      fromTime,
+		ToTime:        toTime,
+		Interval:      bucketInterval,
+	})
+}
+
+// aggregateGroupColumns holds the ID and name expressions of each grouping of a reading
+// aggregation. Names are aggregated, as a group's ID decides its name.
+var aggregateGroupColumns = map[string][2]string{
+	entity.AggregateGroupAssetSensor:      {"r.asset_sensor_id::text", "MIN(s.name)"},
+	entity.AggregateGroupAsset:            {"a.id::text", "MIN(a.name)"},
+	entity.AggregateGroupAssetType:        {"t.id::text", "MIN(t.name)"},
+	entity.AggregateGroupLocation:         {"l.id::text", "MIN(l.name)"},
+	entity.AggregateGroupSensorType:       {"r.sensor_type_id::text", "MIN(st.name)"},
+	entity.AggregateGroupMeasurementField: {"r.measurement_type", "MIN(r.measurement_label)"},
+}
+
+// AggregateReadings summarises the numeric readings matching the query per time bucket,
+// group, measurement and unit. Aggregates are ordered by group, then bucket. Readings of
+// assets without a location form a group with an empty ID when grouping by location.
+// The query's rollup range is read from the rollup table instead of the readings.
+func (r *iotSensorReadingRepository) AggregateReadings(ctx context.Context, q entity.ReadingAggregateQuery) ([]*entity.ReadingAggregate, error) {
+	location := "UTC"
+	if q.Location != nil {
+		location = q.Location.String()
+	}
+
+	// Readings are bucketed by their wall clock time in the query's time zone, and the
+	// buckets converted back to UTC
+	var bucket string
+	var step interface{}
+	switch {
+	case q.Interval.IsCalendar():
+		bucket, step = "date_trunc($3::text, r.reading_time AT TIME ZONE 'UTC' AT TIME ZONE $11)", q.Interval.Calendar
+	case q.Interval.Step >= entity.MinAggregateStep:
+		bucket = "timestamp 'epoch' + floor(extract(epoch FROM r.reading_time AT TIME ZONE 'UTC' AT TIME ZONE $11) / $3::float8) * $3::float8 * interval '1 second'"
+		step = q.Interval.Step.Seconds()
+	default:
+		return nil, fmt.Errorf("invalid interval: %s", q.Interval.Name)
+	}
+
+	groupID, groupName, groupBy := "''", "''", ""
+	if q.GroupBy != "" {
+		columns, ok := aggregateGroupColumns[q.GroupBy]
+		if !ok {
+			return nil, fmt.Errorf("invalid aggregate group: %s", q.GroupBy)
+		}
+		groupID, groupName, groupBy = columns[0], columns[1], columns[0]+", "
+	}
+
+	percentiles := "NULL::float8[]"
+	args := []interface{}{
+		q.FromTime, q.ToTime, step, q.TenantID, q.AssetSensorID, q.AssetID,
+		q.AssetTypeID, q.LocationID, q.SensorTypeID, pq.Array(q.MeasurementTypes), location,
 	}
-	if !validIntervals[interval] {
-		return nil, fmt.Errorf("invalid interval: %s. Valid intervals: 5m, 15m, 30m, 1h, 6h, 12h, 1d", interval)
+	if len(q.Percentiles) > 0 {
+		if q.Rollup != nil {
+			return nil, fmt.Errorf("percentiles can't be computed from a rollup")
+		}
+		args = append(args, pq.Array(q.Percentiles))
+		percentiles = fmt.Sprintf("percentile_cont($%d::float8[]) WITHIN GROUP (ORDER BY r.numeric_value)", len(args))
 	}
 
-	query := `
-		SELECT 
-			date_trunc($4, reading_time) as time_bucket,
-			COUNT(*) as reading_count,
-			AVG(CASE WHEN ppm > 0 THEN ppm END) as avg_ppm,
-			MIN(CASE WHEN ppm > 0 THEN ppm END) as min_ppm,
-			MAX(CASE WHEN ppm > 0 THEN ppm END) as max_ppm,
-			json_agg(
-				CASE WHEN measurement_data IS NOT NULL 
-				THEN measurement_data 
-				ELSE NULL END
-			) FILTER (WHERE measurement_data IS NOT NULL) as measurement_samples
+	// Readings and rollup rows share one shape, so the rollup serves the middle of the
+	// time range and the readings the rest
+	source := `
+		SELECT asset_sensor_id, tenant_id, sensor_type_id, measurement_type, measurement_unit,
+			measurement_label, reading_time, 1::bigint AS reading_count, numeric_value AS value_sum,
+			numeric_value AS value_min, numeric_value AS value_max, numeric_value
 		FROM iot_sensor_readings
-		WHERE asset_sensor_id = $1 
-		  AND reading_time >= $2 
-		  AND reading_time <= $3
-		GROUP BY time_bucket
-		ORDER BY time_bucket ASC`
+		WHERE numeric_value IS NOT NULL`
+	if q.Rollup != nil {
+		args = append(args, q.Rollup.From, q.Rollup.To)
+		source += fmt.Sprintf(`
+		  AND (reading_time < $%[1]d OR reading_time >= $%[2]d)
+		UNION ALL
+		SELECT asset_sensor_id, tenant_id, sensor_type_id, measurement_type, measurement_unit,
+			measurement_label, bucket, reading_count, value_sum, value_min, value_max, NULL
+		FROM %[3]s
+		WHERE bucket >= $%[1]d AND bucket < $%[2]d`, len(args)-1, len(args), q.Rollup.Resolution.Table)
+	}
+
+	// The dimension tables are left joined so readings of deleted assets still count when
+	// no filter or grouping needs them; unused joins are removed by the planner
+	query := fmt.Sprintf(`
+		SELECT 
+			(%[5]s) AT TIME ZONE $11 AT TIME ZONE 'UTC' AS time_bucket,
+			COALESCE(%[1]s, '') AS group_id,
+			COALESCE(%[2]s, '') AS group_name,
+			r.measurement_type,
+			COALESCE(r.measurement_unit, '') AS unit,
+			SUM(r.reading_count)::bigint,
+			SUM(r.value_sum),
+			MIN(r.value_min),
+			MAX(r.value_max),
+			%[3]s
+		FROM (%[6]s) r
+		LEFT JOIN asset_sensors s ON s.id = r.asset_sensor_id
+		LEFT JOIN assets a ON a.id = s.asset_id
+		LEFT JOIN asset_types t ON t.id = a.asset_type_id
+		LEFT JOIN locations l ON l.id = a.location_id
+		LEFT JOIN sensor_types st ON st.id = r.sensor_type_id
+		WHERE r.reading_time >= $1 
+		  AND r.reading_time <= $2
+		  AND ($4::uuid IS NULL OR r.tenant_id = $4)
+		  AND ($5::uuid IS NULL OR r.asset_sensor_id = $5)
+		  AND ($6::uuid IS NULL OR a.id = $6)
+		  AND ($7::uuid IS NULL OR a.asset_type_id = $7)
+		  AND ($8::uuid IS NULL OR a.location_id = $8)
+		  AND ($9::uuid IS NULL OR r.sensor_type_id = $9)
+		  AND (cardinality($10::text[]) = 0 OR r.measurement_type = ANY($10::text[]))
+		GROUP BY %[4]stime_bucket, r.measurement_type, unit
+		ORDER BY group_id ASC, time_bucket ASC, r.measurement_type ASC`, groupID, groupName, percentiles, groupBy, bucket, source)
 
-	rows, err := r.DB.QueryContext(ctx, query, assetSensorID, fromTime, toTime, interval)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get aggregated data: %w", err)
 	}
 	defer rows.Close()
 
-	var results []map[string]interface{}
+	var results []*entity.ReadingAggregate
 	for rows.Next() {
-		var timeBucket time.Time
-		var readingCount int
-		var avgPPM, minPPM, maxPPM sql.NullFloat64
-		var measurementSamples json.RawMessage
-
-		err := rows.Scan(&timeBucket, &readingCount, &avgPPM, &minPPM, &maxPPM, &measurementSamples)
+		var aggregate entity.ReadingAggregate
+		err := rows.Scan(
+			&aggregate.Bucket, &aggregate.GroupID, &aggregate.GroupName, &aggregate.MeasurementType, &aggregate.Unit,
+			&aggregate.Count, &aggregate.Sum, &aggregate.Min, &aggregate.Max, pq.Array(&aggregate.Percentiles),
+		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan aggregated data: %w", err)
 		}
-
-		result := map[string]interface{}{
-			"time":          timeBucket,
-			"reading_count": readingCount,
-		}
-
-		if avgPPM.Valid {
-			result["avg_ppm"] = avgPPM.Float64
-		}
-		if minPPM.Valid {
-			result["min_ppm"] = minPPM.Float64
-		}
-		if maxPPM.Valid {
-			result["max_ppm"] = maxPPM.Float64
-		}
-
-		if measurementSamples != nil {
-			var samples []json.RawMessage
-			if err := json.Unmarshal(measurementSamples, &samples); err == nil {
-				result["measurement_samples"] = samples
-			}
-		}
-
-		results = append(results, result)
+		results = append(results, &aggregate)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -610,6 +723,44 @@ func (r *iotSensorReadingRepository) GetAggregatedData(ctx context.Context, asse
 	return results, nil
 }
 
+// GetWindowStats summarises the numeric readings of one measurement of an asset sensor
+// between fromTime and toTime (inclusive). Count is zero when there are no readings.
+func (r *iotSensorReadingRepository) GetWindowStats(ctx context.Context, assetSensorID uuid.UUID, measurementType string, fromTime, toTime time.Time) (*entity.ReadingWindowStats, error) {
+	query := `
+		SELECT 
+			COUNT(*),
+			AVG(numeric_value),
+			MIN(numeric_value),
+			MAX(numeric_value),
+			(array_agg(numeric_value ORDER BY reading_time ASC))[1],
+			MIN(reading_time)
+		FROM iot_sensor_readings
+		WHERE asset_sensor_id = $1
+		  AND measurement_type = $2
+		  AND numeric_value IS NOT NULL
+		  AND reading_time >= $3
+		  AND reading_time <= $4`
+
+	var stats entity.ReadingWindowStats
+	var avg, minValue, maxValue, firstValue sql.NullFloat64
+	var firstTime sql.NullTime
+
+	err := r.DB.QueryRowContext(ctx, query, assetSensorID, measurementType, fromTime, toTime).Scan(
+		&stats.Count, &avg, &minValue, &maxValue, &firstValue, &firstTime,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading window stats: %w", err)
+	}
+
+	stats.Avg = avg.Float64
+	stats.Min = minValue.Float64
+	stats.Max = maxValue.Float64
+	stats.FirstValue = firstValue.Float64
+	stats.FirstTime = firstTime.Time
+
+	return &stats, nil
+}
+
 // GetByAssetSensorID retrieves IoT sensor readings for a specific asset sensor
 func (r *iotSensorReadingRepository) GetByAssetSensorID(ctx context.Context, assetSensorID uuid.UUID, limit int) ([]*IoTSensorReadingWithDetails, error) {
 	// Validate limit
@@ -1160,18 +1311,14 @@ func (r *iotSensorReadingRepository) CreateFlexible(ctx context.Context, reading
 	}
 	reading.UpdatedAt = &now
 
-	query := `
-		INSERT INTO iot_sensor_readings (
-			id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
-			location_id, location_name, measurement_type, measurement_label,
-			measurement_unit, numeric_value, text_value, boolean_value,
-			data_source, original_field_name, reading_time, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 
-			$11, $12, $13, $14, $15, $16, $17, $18
-		)`
+	// Store the reading and record its side effects atomically
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	_, err := r.DB.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, insertReadingQuery,
 		reading.ID,
 		reading.TenantID,
 		reading.AssetSensorID,
@@ -1187,6 +1334,12 @@ func (r *iotSensorReadingRepository) CreateFlexible(ctx context.Context, reading
 		reading.BooleanValue,
 		reading.DataSource,
 		reading.OriginalFieldName,
+		reading.OriginalValue,
+		reading.OriginalUnit,
+		reading.IsLate,
+		reading.DeviceTime,
+		reading.ClockOffsetMs,
+		reading.TimeAnomaly,
 		reading.ReadingTime,
 		reading.CreatedAt,
 		reading.UpdatedAt,
@@ -1196,6 +1349,14 @@ func (r *iotSensorReadingRepository) CreateFlexible(ctx context.Context, reading
 		return fmt.Errorf("failed to create flexible IoT sensor reading: %w", err)
 	}
 
+	if err := insertReadingOutbox(ctx, tx, reading, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -1205,22 +1366,20 @@ func (r *iotSensorReadingRepository) CreateFlexibleBatch(ctx context.Context, re
 		return nil
 	}
 
+	return r.CreateFlexibleMessages(ctx, []*entity.ReadingMessage{{Readings: readings}})
+}
+
+// CreateFlexibleMessages inserts the readings of several client messages in one transaction.
+// A message with an ID that was already stored for its asset sensor is marked as a
+// duplicate and its readings are skipped; ReadingIDs is set for every message.
+func (r *iotSensorReadingRepository) CreateFlexibleMessages(ctx context.Context, messages []*entity.ReadingMessage) error {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO iot_sensor_readings (
-			id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
-			location_id, location_name, measurement_type, measurement_label,
-			measurement_unit, numeric_value, text_value, boolean_value,
-			data_source, original_field_name, reading_time, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
-			$11, $12, $13, $14, $15, $16, $17, $18
-		)`)
+	stmt, err := tx.PrepareContext(ctx, insertReadingQuery)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -1228,17 +1387,109 @@ func (r *iotSensorReadingRepository) CreateFlexibleBatch(ctx context.Context, re
 
 	now := time.Now()
 
-	for _, reading := range readings {
-		// Set ID if not already set
+	for _, message := range messages {
+		message.Duplicate = false
+		message.ReadingIDs = make([]uuid.UUID, 0, len(message.Readings))
+		for _, reading := range message.Readings {
+			// Set ID if not already set
+			if reading.ID == uuid.Nil {
+				reading.ID = uuid.New()
+			}
+			message.ReadingIDs = append(message.ReadingIDs, reading.ID)
+		}
+
+		if message.MessageID != "" {
+			stored, err := claimReadingMessage(ctx, tx, message, now)
+			if err != nil {
+				return err
+			}
+			if !stored {
+				continue
+			}
+		}
+
+		for _, reading := range message.Readings {
+			// Set timestamps
+			if reading.CreatedAt.IsZero() {
+				reading.CreatedAt = now
+			}
+			reading.UpdatedAt = &now
+
+			_, err = stmt.ExecContext(ctx,
+				reading.ID,
+				reading.TenantID,
+				reading.AssetSensorID,
+				reading.SensorTypeID,
+				reading.MacAddress,
+				reading.LocationID,
+				reading.LocationName,
+				reading.MeasurementType,
+				reading.MeasurementLabel,
+				reading.MeasurementUnit,
+				reading.NumericValue,
+				reading.TextValue,
+				reading.BooleanValue,
+				reading.DataSource,
+				reading.OriginalFieldName,
+				reading.OriginalValue,
+				reading.OriginalUnit,
+				reading.IsLate,
+				reading.DeviceTime,
+				reading.ClockOffsetMs,
+				reading.TimeAnomaly,
+				reading.ReadingTime,
+				reading.CreatedAt,
+				reading.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert flexible reading: %w", err)
+			}
+
+			if err := insertReadingOutbox(ctx, tx, reading, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CopyFlexibleBatch stores readings with COPY, which is much faster than one INSERT per
+// reading for large imports, and records the given effects of every reading in the same
+// transaction. Readings must be validated beforehand: a single bad row fails the batch.
+func (r *iotSensorReadingRepository) CopyFlexibleBatch(ctx context.Context, readings []*entity.IoTSensorReadingFlexible, effects []entity.ReadingEffect) error {
+	if len(readings) == 0 {
+		return nil
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("iot_sensor_readings", iotSensorReadingColumns...))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	readingIDs := make([]uuid.UUID, len(readings))
+
+	for i, reading := range readings {
 		if reading.ID == uuid.Nil {
 			reading.ID = uuid.New()
 		}
-
-		// Set timestamps
 		if reading.CreatedAt.IsZero() {
 			reading.CreatedAt = now
 		}
 		reading.UpdatedAt = &now
+		readingIDs[i] = reading.ID
 
 		_, err = stmt.ExecContext(ctx,
 			reading.ID,
@@ -1256,22 +1507,80 @@ func (r *iotSensorReadingRepository) CreateFlexibleBatch(ctx context.Context, re
 			reading.BooleanValue,
 			reading.DataSource,
 			reading.OriginalFieldName,
+			reading.OriginalValue,
+			reading.OriginalUnit,
+			reading.IsLate,
+			reading.DeviceTime,
+			reading.ClockOffsetMs,
+			reading.TimeAnomaly,
 			reading.ReadingTime,
 			reading.CreatedAt,
 			reading.UpdatedAt,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to insert flexible reading: %w", err)
+			return fmt.Errorf("failed to copy flexible reading: %w", err)
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
+	// An Exec without arguments flushes the buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to copy flexible readings: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to finish copy: %w", err)
+	}
+
+	if err := insertReadingOutboxEffects(ctx, tx, readingIDs, effects, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// claimReadingMessage records a client message ID with the IDs of its readings. When the
+// message was already recorded it marks the message as a duplicate, sets the original
+// reading IDs and returns false. A concurrent insert of the same message waits for the
+// first transaction, so only one of them stores the readings.
+func claimReadingMessage(ctx context.Context, tx *sql.Tx, message *entity.ReadingMessage, createdAt time.Time) (bool, error) {
+	var tenantID *uuid.UUID
+	if len(message.Readings) > 0 {
+		tenantID = message.Readings[0].TenantID
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO reading_messages (asset_sensor_id, message_id, tenant_id, reading_ids, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (asset_sensor_id, message_id) DO NOTHING`,
+		message.AssetSensorID, message.MessageID, tenantID, pq.Array(message.ReadingIDs), createdAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record reading message: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if inserted > 0 {
+		return true, nil
+	}
+
+	var readingIDs []uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT reading_ids FROM reading_messages WHERE asset_sensor_id = $1 AND message_id = $2`,
+		message.AssetSensorID, message.MessageID).Scan(pq.Array(&readingIDs))
+	if err != nil {
+		return false, fmt.Errorf("failed to get stored reading message: %w", err)
+	}
+
+	message.Duplicate = true
+	message.ReadingIDs = readingIDs
+	return false, nil
+}
+
 // GetFlexibleByID retrieves a flexible IoT sensor reading with measurement data
 func (r *iotSensorReadingRepository) GetFlexibleByID(ctx context.Context, id uuid.UUID) (*entity.IoTSensorReadingFlexible, error) {
 	var reading entity.IoTSensorReadingFlexible
@@ -1280,7 +1589,8 @@ func (r *iotSensorReadingRepository) GetFlexibleByID(ctx context.Context, id uui
 		SELECT id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
 			   location_id, location_name, measurement_type, measurement_label,
 			   measurement_unit, numeric_value, text_value, boolean_value,
-			   data_source, original_field_name, reading_time, created_at, updated_at
+			   data_source, original_field_name, original_value, original_unit, is_late,
+			   device_time, clock_offset_ms, time_anomaly, reading_time, created_at, updated_at
 		FROM iot_sensor_readings
 		WHERE id = $1`
 
@@ -1300,6 +1610,12 @@ func (r *iotSensorReadingRepository) GetFlexibleByID(ctx context.Context, id uui
 		&reading.BooleanValue,
 		&reading.DataSource,
 		&reading.OriginalFieldName,
+		&reading.OriginalValue,
+		&reading.OriginalUnit,
+		&reading.IsLate,
+		&reading.DeviceTime,
+		&reading.ClockOffsetMs,
+		&reading.TimeAnomaly,
 		&reading.ReadingTime,
 		&reading.CreatedAt,
 		&reading.UpdatedAt,
@@ -1315,6 +1631,62 @@ func (r *iotSensorReadingRepository) GetFlexibleByID(ctx context.Context, id uui
 	return &reading, nil
 }
 
+// GetLatestMeasurement retrieves the most recent reading of one measurement of an asset
+// sensor between fromTime and toTime (inclusive), or nil when there is none
+func (r *iotSensorReadingRepository) GetLatestMeasurement(ctx context.Context, assetSensorID uuid.UUID, measurementType string, fromTime, toTime time.Time) (*entity.IoTSensorReadingFlexible, error) {
+	var reading entity.IoTSensorReadingFlexible
+
+	query := `
+		SELECT id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
+			   location_id, location_name, measurement_type, measurement_label,
+			   measurement_unit, numeric_value, text_value, boolean_value,
+			   data_source, original_field_name, original_value, original_unit, is_late,
+			   device_time, clock_offset_ms, time_anomaly, reading_time, created_at, updated_at
+		FROM iot_sensor_readings
+		WHERE asset_sensor_id = $1
+		  AND measurement_type = $2
+		  AND reading_time >= $3
+		  AND reading_time <= $4
+		ORDER BY reading_time DESC
+		LIMIT 1`
+
+	err := r.DB.QueryRowContext(ctx, query, assetSensorID, measurementType, fromTime, toTime).Scan(
+		&reading.ID,
+		&reading.TenantID,
+		&reading.AssetSensorID,
+		&reading.SensorTypeID,
+		&reading.MacAddress,
+		&reading.LocationID,
+		&reading.LocationName,
+		&reading.MeasurementType,
+		&reading.MeasurementLabel,
+		&reading.MeasurementUnit,
+		&reading.NumericValue,
+		&reading.TextValue,
+		&reading.BooleanValue,
+		&reading.DataSource,
+		&reading.OriginalFieldName,
+		&reading.OriginalValue,
+		&reading.OriginalUnit,
+		&reading.IsLate,
+		&reading.DeviceTime,
+		&reading.ClockOffsetMs,
+		&reading.TimeAnomaly,
+		&reading.ReadingTime,
+		&reading.CreatedAt,
+		&reading.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest measurement: %w", err)
+	}
+
+	return &reading, nil
+}
+
 // ParseTextToFlexibleReading converts text data to a flexible IoT sensor reading
 func (r *iotSensorReadingRepository) ParseTextToFlexibleReading(ctx context.Context, textData, assetSensorID, sensorTypeID, macAddress string) (*entity.IoTSensorReadingFlexible, error) {
 	assetSensorUUID, err := uuid.Parse(assetSensorID)
@@ -1355,7 +1727,8 @@ func (r *iotSensorReadingRepository) GetFlexibleByAssetSensorID(ctx context.Cont
 		SELECT id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
 			   location_id, location_name, measurement_type, measurement_label,
 			   measurement_unit, numeric_value, text_value, boolean_value,
-			   data_source, original_field_name, reading_time, created_at, updated_at
+			   data_source, original_field_name, original_value, original_unit, is_late,
+			   device_time, clock_offset_ms, time_anomaly, reading_time, created_at, updated_at
 		FROM iot_sensor_readings
 		WHERE asset_sensor_id = $1
 		ORDER BY reading_time DESC, created_at DESC
@@ -1385,6 +1758,12 @@ func (r *iotSensorReadingRepository) GetFlexibleByAssetSensorID(ctx context.Cont
 			&reading.BooleanValue,
 			&reading.DataSource,
 			&reading.OriginalFieldName,
+			&reading.OriginalValue,
+			&reading.OriginalUnit,
+			&reading.IsLate,
+			&reading.DeviceTime,
+			&reading.ClockOffsetMs,
+			&reading.TimeAnomaly,
 			&reading.ReadingTime,
 			&reading.CreatedAt,
 			&reading.UpdatedAt,
@@ -1495,7 +1874,8 @@ func (r *iotSensorReadingRepository) ListFlexible(ctx context.Context, req IoTSe
 		SELECT id, tenant_id, asset_sensor_id, sensor_type_id, mac_address,
 			   location_id, location_name, measurement_type, measurement_label,
 			   measurement_unit, numeric_value, text_value, boolean_value,
-			   data_source, original_field_name, reading_time, created_at, updated_at
+			   data_source, original_field_name, original_value, original_unit, is_late,
+			   device_time, clock_offset_ms, time_anomaly, reading_time, created_at, updated_at
 		FROM iot_sensor_readings
 		%s
 		ORDER BY reading_time DESC, created_at DESC
@@ -1530,6 +1910,12 @@ func (r *iotSensorReadingRepository) ListFlexible(ctx context.Context, req IoTSe
 			&reading.BooleanValue,
 			&reading.DataSource,
 			&reading.OriginalFieldName,
+			&reading.OriginalValue,
+			&reading.OriginalUnit,
+			&reading.IsLate,
+			&reading.DeviceTime,
+			&reading.ClockOffsetMs,
+			&reading.TimeAnomaly,
 			&reading.ReadingTime,
 			&reading.CreatedAt,
 			&reading.UpdatedAt,
@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceWindowRepository defines the interface for maintenance window operations
+type MaintenanceWindowRepository interface {
+	Create(ctx context.Context, window *entity.MaintenanceWindow) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.MaintenanceWindow, error)
+	List(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, limit, offset int) ([]*entity.MaintenanceWindow, int, error)
+	GetActive(ctx context.Context) ([]*entity.MaintenanceWindow, error)
+	GetActiveForAsset(ctx context.Context, asset *entity.Asset, at time.Time) ([]*entity.MaintenanceWindow, error)
+	Update(ctx context.Context, window *entity.MaintenanceWindow) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ApplyAssetStatus(ctx context.Context, window *entity.MaintenanceWindow) (int, error)
+	RestoreAssetStatus(ctx context.Context, windowID uuid.UUID) (int, error)
+}
+
+// maintenanceWindowRepository handles database operations for maintenance windows
+type maintenanceWindowRepository struct {
+	*BaseRepository
+}
+
+// NewMaintenanceWindowRepository creates a new MaintenanceWindowRepository
+func NewMaintenanceWindowRepository(db *sql.DB) MaintenanceWindowRepository {
+	return &maintenanceWindowRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const maintenanceWindowColumns = `
+	id, tenant_id, name, description, asset_id, location_id, asset_type_id,
+	start_time, end_time, recurrence, recurrence_until, alert_mode,
+	set_asset_status, is_active, created_at, updated_at`
+
+// maintenanceScopeCondition matches the assets (aliased a) covered by the window (aliased w)
+const maintenanceScopeCondition = `a.tenant_id = w.tenant_id AND (
+		a.id = w.asset_id OR a.location_id = w.location_id OR a.asset_type_id = w.asset_type_id)`
+
+// Create inserts a new maintenance window into the database
+func (r *maintenanceWindowRepository) Create(ctx context.Context, window *entity.MaintenanceWindow) error {
+	if window.ID == uuid.Nil {
+		window.ID = uuid.New()
+	}
+	if window.CreatedAt.IsZero() {
+		window.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO maintenance_windows (
+			id, tenant_id, name, description, asset_id, location_id, asset_type_id,
+			start_time, end_time, recurrence, recurrence_until, alert_mode,
+			set_asset_status, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		window.ID,
+		window.TenantID,
+		window.Name,
+		window.Description,
+		window.AssetID,
+		window.LocationID,
+		window.AssetTypeID,
+		window.StartTime,
+		window.EndTime,
+		window.Recurrence,
+		window.RecurrenceUntil,
+		window.AlertMode,
+		window.SetAssetStatus,
+		window.IsActive,
+		window.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a maintenance window by its ID
+func (r *maintenanceWindowRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + ` FROM maintenance_windows WHERE id = $1`
+
+	window, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+
+	return window, nil
+}
+
+// List retrieves paginated maintenance windows for a tenant, optionally only those
+// scoped directly to an asset
+func (r *maintenanceWindowRepository) List(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, limit, offset int) ([]*entity.MaintenanceWindow, int, error) {
+	whereClause := `WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if assetID != nil {
+		whereClause += ` AND asset_id = $2`
+		args = append(args, *assetID)
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM maintenance_windows ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM maintenance_windows %s ORDER BY start_time DESC LIMIT $%d OFFSET $%d`,
+		maintenanceWindowColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	windows, err := r.queryWindows(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return windows, totalCount, nil
+}
+
+// GetActive retrieves the enabled maintenance windows of all tenants
+func (r *maintenanceWindowRepository) GetActive(ctx context.Context) ([]*entity.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + `
+		FROM maintenance_windows
+		WHERE is_active = true
+		ORDER BY start_time`
+
+	return r.queryWindows(ctx, query)
+}
+
+// GetActiveForAsset retrieves the enabled windows covering an asset that may have an
+// occurrence at the given time. Callers still have to check the recurrence.
+func (r *maintenanceWindowRepository) GetActiveForAsset(ctx context.Context, asset *entity.Asset, at time.Time) ([]*entity.MaintenanceWindow, error) {
+	if asset.TenantID == nil {
+		return nil, nil
+	}
+
+	query := `SELECT ` + maintenanceWindowColumns + `
+		FROM maintenance_windows
+		WHERE tenant_id = $1
+			AND is_active = true
+			AND (asset_id = $2 OR location_id = $3 OR asset_type_id = $4)
+			AND start_time <= $5
+			AND (recurrence <> 'none' OR end_time > $5)
+		ORDER BY start_time`
+
+	return r.queryWindows(ctx, query, *asset.TenantID, asset.ID, asset.LocationID, asset.AssetTypeID, at)
+}
+
+// Update updates an existing maintenance window
+func (r *maintenanceWindowRepository) Update(ctx context.Context, window *entity.MaintenanceWindow) error {
+	now := time.Now()
+	window.UpdatedAt = &now
+
+	query := `
+		UPDATE maintenance_windows SET
+			name = $2,
+			description = $3,
+			asset_id = $4,
+			location_id = $5,
+			asset_type_id = $6,
+			start_time = $7,
+			end_time = $8,
+			recurrence = $9,
+			recurrence_until = $10,
+			alert_mode = $11,
+			set_asset_status = $12,
+			is_active = $13,
+			updated_at = $14
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		window.ID,
+		window.Name,
+		window.Description,
+		window.AssetID,
+		window.LocationID,
+		window.AssetTypeID,
+		window.StartTime,
+		window.EndTime,
+		window.Recurrence,
+		window.RecurrenceUntil,
+		window.AlertMode,
+		window.SetAssetStatus,
+		window.IsActive,
+		window.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance window not found")
+	}
+
+	return nil
+}
+
+// Delete removes a maintenance window by its ID
+func (r *maintenanceWindowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance window not found")
+	}
+
+	return nil
+}
+
+// ApplyAssetStatus switches the covered assets that are not in maintenance yet to the
+// maintenance status, remembering their previous status. It returns the number of
+// assets switched and is safe to call repeatedly.
+func (r *maintenanceWindowRepository) ApplyAssetStatus(ctx context.Context, window *entity.MaintenanceWindow) (int, error) {
+	query := `
+		WITH targets AS (
+			SELECT a.id, a.status
+			FROM assets a, maintenance_windows w
+			WHERE w.id = $1 AND ` + maintenanceScopeCondition + `
+				AND a.status IS DISTINCT FROM $2
+		), applied AS (
+			INSERT INTO maintenance_window_assets (window_id, asset_id, previous_status, applied_at)
+			SELECT $1, id, status, $3 FROM targets
+			ON CONFLICT (window_id, asset_id) DO NOTHING
+			RETURNING asset_id
+		)
+		UPDATE assets SET status = $2, updated_at = $3
+		WHERE id IN (SELECT asset_id FROM applied)`
+
+	result, err := r.DB.ExecContext(ctx, query, window.ID, entity.AssetStatusMaintenance, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply maintenance asset status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// RestoreAssetStatus restores the status of the assets switched by a window. Assets whose
+// status was changed by someone else meanwhile are left alone. It returns the number of
+// assets restored.
+func (r *maintenanceWindowRepository) RestoreAssetStatus(ctx context.Context, windowID uuid.UUID) (int, error) {
+	query := `
+		WITH released AS (
+			DELETE FROM maintenance_window_assets
+			WHERE window_id = $1
+			RETURNING asset_id, previous_status
+		)
+		UPDATE assets a SET status = COALESCE(released.previous_status, 'active'), updated_at = $3
+		FROM released
+		WHERE a.id = released.asset_id AND a.status = $2`
+
+	result, err := r.DB.ExecContext(ctx, query, windowID, entity.AssetStatusMaintenance, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore maintenance asset status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// queryWindows executes a query and returns maintenance windows
+func (r *maintenanceWindowRepository) queryWindows(ctx context.Context, query string, args ...interface{}) ([]*entity.MaintenanceWindow, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*entity.MaintenanceWindow
+	for rows.Next() {
+		window, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// scanRow scans a single maintenance window row
+func (r *maintenanceWindowRepository) scanRow(row rowScanner) (*entity.MaintenanceWindow, error) {
+	var window entity.MaintenanceWindow
+	err := row.Scan(
+		&window.ID,
+		&window.TenantID,
+		&window.Name,
+		&window.Description,
+		&window.AssetID,
+		&window.LocationID,
+		&window.AssetTypeID,
+		&window.StartTime,
+		&window.EndTime,
+		&window.Recurrence,
+		&window.RecurrenceUntil,
+		&window.AlertMode,
+		&window.SetAssetStatus,
+		&window.IsActive,
+		&window.CreatedAt,
+		&window.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &window, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannelRepository defines the interface for notification channel operations
+type NotificationChannelRepository interface {
+	Create(ctx context.Context, channel *entity.NotificationChannel) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationChannel, error)
+	List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.NotificationChannel, int, error)
+	Update(ctx context.Context, channel *entity.NotificationChannel) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// notificationChannelRepository handles database operations for notification channels
+type notificationChannelRepository struct {
+	*BaseRepository
+}
+
+// NewNotificationChannelRepository creates a new NotificationChannelRepository
+func NewNotificationChannelRepository(db *sql.DB) NotificationChannelRepository {
+	return &notificationChannelRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const notificationChannelColumns = `id, tenant_id, name, type, config, is_active, created_at, updated_at`
+
+// Create inserts a new notification channel into the database
+func (r *notificationChannelRepository) Create(ctx context.Context, channel *entity.NotificationChannel) error {
+	if channel.ID == uuid.Nil {
+		channel.ID = uuid.New()
+	}
+	if channel.CreatedAt.IsZero() {
+		channel.CreatedAt = time.Now()
+	}
+
+	config, err := json.Marshal(channel.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode channel config: %w", err)
+	}
+
+	query := `
+		INSERT INTO notification_channels (id, tenant_id, name, type, config, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		channel.ID,
+		channel.TenantID,
+		channel.Name,
+		channel.Type,
+		config,
+		channel.IsActive,
+		channel.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a notification channel by its ID
+func (r *notificationChannelRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE id = $1`
+
+	channel, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// List retrieves paginated notification channels for a tenant
+func (r *notificationChannelRepository) List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.NotificationChannel, int, error) {
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM notification_channels WHERE tenant_id = $1`
+	if err := r.DB.QueryRowContext(ctx, countQuery, tenantID).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := `SELECT ` + notificationChannelColumns + `
+		FROM notification_channels
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.DB.QueryContext(ctx, query, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []*entity.NotificationChannel
+	for rows.Next() {
+		channel, err := r.scanRow(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating notification channels: %w", err)
+	}
+
+	return channels, totalCount, nil
+}
+
+// Update updates an existing notification channel
+func (r *notificationChannelRepository) Update(ctx context.Context, channel *entity.NotificationChannel) error {
+	now := time.Now()
+	channel.UpdatedAt = &now
+
+	config, err := json.Marshal(channel.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode channel config: %w", err)
+	}
+
+	query := `
+		UPDATE notification_channels SET
+			name = $2,
+			type = $3,
+			config = $4,
+			is_active = $5,
+			updated_at = $6
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		channel.ID,
+		channel.Name,
+		channel.Type,
+		config,
+		channel.IsActive,
+		channel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification channel not found")
+	}
+
+	return nil
+}
+
+// Delete removes a notification channel by its ID
+func (r *notificationChannelRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification channel not found")
+	}
+
+	return nil
+}
+
+// scanRow scans a single notification channel row
+func (r *notificationChannelRepository) scanRow(row rowScanner) (*entity.NotificationChannel, error) {
+	var channel entity.NotificationChannel
+	var config []byte
+	err := row.Scan(
+		&channel.ID,
+		&channel.TenantID,
+		&channel.Name,
+		&channel.Type,
+		&config,
+		&channel.IsActive,
+		&channel.CreatedAt,
+		&channel.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &channel.Config); err != nil {
+			return nil, fmt.Errorf("failed to decode channel config: %w", err)
+		}
+	}
+
+	return &channel, nil
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationDeliveryFilter narrows the delivery log listing
+type NotificationDeliveryFilter struct {
+	ChannelID *uuid.UUID
+	AlertID   *uuid.UUID
+	Status    *entity.NotificationDeliveryStatus
+}
+
+// NotificationDeliveryRepository defines the interface for notification delivery operations
+type NotificationDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.NotificationDelivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationDelivery, error)
+	List(ctx context.Context, tenantID uuid.UUID, filter NotificationDeliveryFilter, limit, offset int) ([]*entity.NotificationDelivery, int, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationDelivery, error)
+	MarkSent(ctx context.Context, id uuid.UUID, attempts int, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string) error
+}
+
+// notificationDeliveryRepository handles database operations for notification deliveries
+type notificationDeliveryRepository struct {
+	*BaseRepository
+}
+
+// NewNotificationDeliveryRepository creates a new NotificationDeliveryRepository
+func NewNotificationDeliveryRepository(db *sql.DB) NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const notificationDeliveryColumns = `
+	id, tenant_id, channel_id, rule_id, alert_id, event, status, attempts,
+	next_attempt_at, last_error, payload, sent_at, created_at, updated_at`
+
+// Create inserts a new notification delivery into the database
+func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = delivery.CreatedAt
+	}
+
+	query := `
+		INSERT INTO notification_deliveries (
+			id, tenant_id, channel_id, rule_id, alert_id, event, status,
+			attempts, next_attempt_at, payload, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.TenantID,
+		delivery.ChannelID,
+		delivery.RuleID,
+		delivery.AlertID,
+		delivery.Event,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		[]byte(delivery.Payload),
+		delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a notification delivery by its ID
+func (r *notificationDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationDelivery, error) {
+	query := `SELECT ` + notificationDeliveryColumns + ` FROM notification_deliveries WHERE id = $1`
+
+	delivery, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// List retrieves the paginated delivery log of a tenant, newest first
+func (r *notificationDeliveryRepository) List(ctx context.Context, tenantID uuid.UUID, filter NotificationDeliveryFilter, limit, offset int) ([]*entity.NotificationDelivery, int, error) {
+	whereClause := `WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if filter.ChannelID != nil {
+		args = append(args, *filter.ChannelID)
+		whereClause += fmt.Sprintf(` AND channel_id = $%d`, len(args))
+	}
+	if filter.AlertID != nil {
+		args = append(args, *filter.AlertID)
+		whereClause += fmt.Sprintf(` AND alert_id = $%d`, len(args))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		whereClause += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM notification_deliveries ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM notification_deliveries %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		notificationDeliveryColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	deliveries, err := r.queryDeliveries(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, totalCount, nil
+}
+
+// ClaimDue leases up to limit due deliveries to the caller. Claimed rows are moved to
+// 'sending' and pushed back by the lease, so a worker that dies mid-send only delays
+// the delivery instead of losing it. SKIP LOCKED lets several instances poll safely.
+func (r *notificationDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationDelivery, error) {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'sending',
+			next_attempt_at = $2,
+			updated_at = $1
+		WHERE id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status IN ('pending', 'sending') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationDeliveryColumns
+
+	return r.queryDeliveries(ctx, query, now, now.Add(lease), limit)
+}
+
+// MarkSent records a successful delivery
+func (r *notificationDeliveryRepository) MarkSent(ctx context.Context, id uuid.UUID, attempts int, sentAt time.Time) error {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'sent',
+			attempts = $2,
+			last_error = NULL,
+			sent_at = $3,
+			updated_at = $3
+		WHERE id = $1`
+
+	if _, err := r.DB.ExecContext(ctx, query, id, attempts, sentAt); err != nil {
+		return fmt.Errorf("failed to mark notification delivery as sent: %w", err)
+	}
+
+	return nil
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *notificationDeliveryRepository) MarkRetry(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'pending',
+			attempts = $2,
+			next_attempt_at = $3,
+			last_error = $4,
+			updated_at = $5
+		WHERE id = $1`
+
+	if _, err := r.DB.ExecContext(ctx, query, id, attempts, nextAttemptAt, lastError, time.Now()); err != nil {
+		return fmt.Errorf("failed to schedule notification delivery retry: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records the final failed attempt of a delivery
+func (r *notificationDeliveryRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string) error {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'failed',
+			attempts = $2,
+			last_error = $3,
+			updated_at = $4
+		WHERE id = $1`
+
+	if _, err := r.DB.ExecContext(ctx, query, id, attempts, lastError, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark notification delivery as failed: %w", err)
+	}
+
+	return nil
+}
+
+// queryDeliveries executes a query and returns notification deliveries
+func (r *notificationDeliveryRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*entity.NotificationDelivery, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.NotificationDelivery
+	for rows.Next() {
+		delivery, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// scanRow scans a single notification delivery row
+func (r *notificationDeliveryRepository) scanRow(row rowScanner) (*entity.NotificationDelivery, error) {
+	var delivery entity.NotificationDelivery
+	var payload []byte
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.TenantID,
+		&delivery.ChannelID,
+		&delivery.RuleID,
+		&delivery.AlertID,
+		&delivery.Event,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&payload,
+		&delivery.SentAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+
+	return &delivery, nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NotificationRuleRepository defines the interface for notification rule operations
+type NotificationRuleRepository interface {
+	Create(ctx context.Context, rule *entity.NotificationRule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationRule, error)
+	List(ctx context.Context, tenantID uuid.UUID, channelID *uuid.UUID, limit, offset int) ([]*entity.NotificationRule, int, error)
+	GetActiveByTenant(ctx context.Context, tenantID uuid.UUID) ([]*entity.NotificationRule, error)
+	Update(ctx context.Context, rule *entity.NotificationRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// notificationRuleRepository handles database operations for notification rules
+type notificationRuleRepository struct {
+	*BaseRepository
+}
+
+// NewNotificationRuleRepository creates a new NotificationRuleRepository
+func NewNotificationRuleRepository(db *sql.DB) NotificationRuleRepository {
+	return &notificationRuleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const notificationRuleColumns = `
+	id, tenant_id, channel_id, name, severities, asset_id, asset_type_id,
+	notify_on_resolve, is_active, created_at, updated_at`
+
+// Create inserts a new notification rule into the database
+func (r *notificationRuleRepository) Create(ctx context.Context, rule *entity.NotificationRule) error {
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO notification_rules (
+			id, tenant_id, channel_id, name, severities, asset_id, asset_type_id,
+			notify_on_resolve, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		rule.ID,
+		rule.TenantID,
+		rule.ChannelID,
+		rule.Name,
+		pq.Array(severityStrings(rule.Severities)),
+		rule.AssetID,
+		rule.AssetTypeID,
+		rule.NotifyOnResolve,
+		rule.IsActive,
+		rule.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a notification rule by its ID
+func (r *notificationRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationRule, error) {
+	query := `SELECT ` + notificationRuleColumns + ` FROM notification_rules WHERE id = $1`
+
+	rule, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// List retrieves paginated notification rules for a tenant, optionally filtered by channel
+func (r *notificationRuleRepository) List(ctx context.Context, tenantID uuid.UUID, channelID *uuid.UUID, limit, offset int) ([]*entity.NotificationRule, int, error) {
+	whereClause := `WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if channelID != nil {
+		whereClause += ` AND channel_id = $2`
+		args = append(args, *channelID)
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM notification_rules ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM notification_rules %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		notificationRuleColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rules, err := r.queryRules(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rules, totalCount, nil
+}
+
+// GetActiveByTenant retrieves the active rules of a tenant whose channel is also active
+func (r *notificationRuleRepository) GetActiveByTenant(ctx context.Context, tenantID uuid.UUID) ([]*entity.NotificationRule, error) {
+	query := `SELECT ` + notificationRuleColumns + `
+		FROM notification_rules
+		WHERE tenant_id = $1 AND is_active = true
+			AND channel_id IN (SELECT id FROM notification_channels WHERE is_active = true)
+		ORDER BY created_at`
+
+	return r.queryRules(ctx, query, tenantID)
+}
+
+// Update updates an existing notification rule
+func (r *notificationRuleRepository) Update(ctx context.Context, rule *entity.NotificationRule) error {
+	now := time.Now()
+	rule.UpdatedAt = &now
+
+	query := `
+		UPDATE notification_rules SET
+			channel_id = $2,
+			name = $3,
+			severities = $4,
+			asset_id = $5,
+			asset_type_id = $6,
+			notify_on_resolve = $7,
+			is_active = $8,
+			updated_at = $9
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		rule.ID,
+		rule.ChannelID,
+		rule.Name,
+		pq.Array(severityStrings(rule.Severities)),
+		rule.AssetID,
+		rule.AssetTypeID,
+		rule.NotifyOnResolve,
+		rule.IsActive,
+		rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification rule not found")
+	}
+
+	return nil
+}
+
+// Delete removes a notification rule by its ID
+func (r *notificationRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM notification_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification rule not found")
+	}
+
+	return nil
+}
+
+// queryRules executes a query and returns notification rules
+func (r *notificationRuleRepository) queryRules(ctx context.Context, query string, args ...interface{}) ([]*entity.NotificationRule, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*entity.NotificationRule
+	for rows.Next() {
+		rule, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// scanRow scans a single notification rule row
+func (r *notificationRuleRepository) scanRow(row rowScanner) (*entity.NotificationRule, error) {
+	var rule entity.NotificationRule
+	var severities []string
+	err := row.Scan(
+		&rule.ID,
+		&rule.TenantID,
+		&rule.ChannelID,
+		&rule.Name,
+		pq.Array(&severities),
+		&rule.AssetID,
+		&rule.AssetTypeID,
+		&rule.NotifyOnResolve,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Severities = make([]entity.ThresholdSeverity, 0, len(severities))
+	for _, severity := range severities {
+		rule.Severities = append(rule.Severities, entity.ThresholdSeverity(severity))
+	}
+
+	return &rule, nil
+}
+
+// severityStrings converts severities for storage in a TEXT[] column
+func severityStrings(severities []entity.ThresholdSeverity) []string {
+	values := make([]string, 0, len(severities))
+	for _, severity := range severities {
+		values = append(values, string(severity))
+	}
+	return values
+}
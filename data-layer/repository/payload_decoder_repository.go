@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayloadDecoderRepository defines the interface for payload decoder operations
+type PayloadDecoderRepository interface {
+	Create(ctx context.Context, decoder *entity.PayloadDecoder) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.PayloadDecoder, error)
+	GetBySensorTypeID(ctx context.Context, sensorTypeID uuid.UUID) (*entity.PayloadDecoder, error)
+	List(ctx context.Context, limit, offset int) ([]*entity.PayloadDecoder, int, error)
+	Update(ctx context.Context, decoder *entity.PayloadDecoder) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// payloadDecoderRepository handles database operations for payload decoders
+type payloadDecoderRepository struct {
+	*BaseRepository
+}
+
+// NewPayloadDecoderRepository creates a new PayloadDecoderRepository
+func NewPayloadDecoderRepository(db *sql.DB) PayloadDecoderRepository {
+	return &payloadDecoderRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const payloadDecoderColumns = `
+	id, sensor_type_id, name, description, format, frame_path, timestamp_path,
+	message_id_path, fields, is_active, created_at, updated_at`
+
+// Create inserts a new payload decoder into the database
+func (r *payloadDecoderRepository) Create(ctx context.Context, decoder *entity.PayloadDecoder) error {
+	if decoder.ID == uuid.Nil {
+		decoder.ID = uuid.New()
+	}
+	if decoder.CreatedAt.IsZero() {
+		decoder.CreatedAt = time.Now()
+	}
+
+	fields, err := json.Marshal(decoder.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode field specs: %w", err)
+	}
+
+	query := `
+		INSERT INTO payload_decoders (
+			id, sensor_type_id, name, description, format, frame_path,
+			timestamp_path, message_id_path, fields, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		decoder.ID,
+		decoder.SensorTypeID,
+		decoder.Name,
+		decoder.Description,
+		decoder.Format,
+		decoder.FramePath,
+		decoder.TimestampPath,
+		decoder.MessageIDPath,
+		fields,
+		decoder.IsActive,
+		decoder.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create payload decoder: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a payload decoder by its ID
+func (r *payloadDecoderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.PayloadDecoder, error) {
+	query := `SELECT ` + payloadDecoderColumns + ` FROM payload_decoders WHERE id = $1`
+
+	decoder, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get payload decoder: %w", err)
+	}
+
+	return decoder, nil
+}
+
+// GetBySensorTypeID retrieves the payload decoder of a sensor type
+func (r *payloadDecoderRepository) GetBySensorTypeID(ctx context.Context, sensorTypeID uuid.UUID) (*entity.PayloadDecoder, error) {
+	query := `SELECT ` + payloadDecoderColumns + ` FROM payload_decoders WHERE sensor_type_id = $1`
+
+	decoder, err := r.scanRow(r.DB.QueryRowContext(ctx, query, sensorTypeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get payload decoder: %w", err)
+	}
+
+	return decoder, nil
+}
+
+// List retrieves paginated payload decoders
+func (r *payloadDecoderRepository) List(ctx context.Context, limit, offset int) ([]*entity.PayloadDecoder, int, error) {
+	var totalCount int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM payload_decoders`).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := `SELECT ` + payloadDecoderColumns + `
+		FROM payload_decoders
+		ORDER BY name
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.DB.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query payload decoders: %w", err)
+	}
+	defer rows.Close()
+
+	var decoders []*entity.PayloadDecoder
+	for rows.Next() {
+		decoder, err := r.scanRow(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan payload decoder: %w", err)
+		}
+		decoders = append(decoders, decoder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating payload decoders: %w", err)
+	}
+
+	return decoders, totalCount, nil
+}
+
+// Update updates an existing payload decoder
+func (r *payloadDecoderRepository) Update(ctx context.Context, decoder *entity.PayloadDecoder) error {
+	now := time.Now()
+	decoder.UpdatedAt = &now
+
+	fields, err := json.Marshal(decoder.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode field specs: %w", err)
+	}
+
+	query := `
+		UPDATE payload_decoders SET
+			name = $2,
+			description = $3,
+			format = $4,
+			frame_path = $5,
+			timestamp_path = $6,
+			message_id_path = $7,
+			fields = $8,
+			is_active = $9,
+			updated_at = $10
+		WHERE id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		decoder.ID,
+		decoder.Name,
+		decoder.Description,
+		decoder.Format,
+		decoder.FramePath,
+		decoder.TimestampPath,
+		decoder.MessageIDPath,
+		fields,
+		decoder.IsActive,
+		decoder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payload decoder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payload decoder not found")
+	}
+
+	return nil
+}
+
+// Delete removes a payload decoder by its ID
+func (r *payloadDecoderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM payload_decoders WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete payload decoder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payload decoder not found")
+	}
+
+	return nil
+}
+
+// scanRow scans a single payload decoder row
+func (r *payloadDecoderRepository) scanRow(row rowScanner) (*entity.PayloadDecoder, error) {
+	var decoder entity.PayloadDecoder
+	var fields []byte
+	err := row.Scan(
+		&decoder.ID,
+		&decoder.SensorTypeID,
+		&decoder.Name,
+		&decoder.Description,
+		&decoder.Format,
+		&decoder.FramePath,
+		&decoder.TimestampPath,
+		&decoder.MessageIDPath,
+		&fields,
+		&decoder.IsActive,
+		&decoder.CreatedAt,
+		&decoder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(fields, &decoder.Fields); err != nil {
+		return nil, fmt.Errorf("failed to decode field specs: %w", err)
+	}
+
+	return &decoder, nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// QuarantinedReadingFilter narrows the quarantine listing
+type QuarantinedReadingFilter struct {
+	TenantID      *uuid.UUID
+	AssetSensorID *uuid.UUID
+	SensorTypeID  *uuid.UUID
+	Status        *entity.QuarantineStatus
+}
+
+// QuarantinedReadingRepository defines the interface for quarantined reading operations
+type QuarantinedReadingRepository interface {
+	Create(ctx context.Context, quarantined *entity.QuarantinedReading) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.QuarantinedReading, error)
+	List(ctx context.Context, filter QuarantinedReadingFilter, limit, offset int) ([]*entity.QuarantinedReading, int, error)
+	UpdateReview(ctx context.Context, quarantined *entity.QuarantinedReading) error
+}
+
+// quarantinedReadingRepository handles database operations for quarantined readings
+type quarantinedReadingRepository struct {
+	*BaseRepository
+}
+
+// NewQuarantinedReadingRepository creates a new QuarantinedReadingRepository
+func NewQuarantinedReadingRepository(db *sql.DB) QuarantinedReadingRepository {
+	return &quarantinedReadingRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const quarantinedReadingColumns = `
+	id, tenant_id, asset_sensor_id, sensor_type_id, mac_address, message_id, reading_time,
+	source, policy, rejected, measurements, violations, status, reviewed_by, reviewed_at,
+	review_note, replayed_reading_ids, created_at, updated_at`
+
+// Create inserts a new quarantined reading into the database
+func (r *quarantinedReadingRepository) Create(ctx context.Context, quarantined *entity.QuarantinedReading) error {
+	if quarantined.ID == uuid.Nil {
+		quarantined.ID = uuid.New()
+	}
+	if quarantined.CreatedAt.IsZero() {
+		quarantined.CreatedAt = time.Now()
+	}
+
+	measurements, err := json.Marshal(quarantined.Measurements)
+	if err != nil {
+		return fmt.Errorf("failed to encode quarantined measurements: %w", err)
+	}
+	violations, err := json.Marshal(quarantined.Violations)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema violations: %w", err)
+	}
+
+	query := `
+		INSERT INTO quarantined_readings (
+			id, tenant_id, asset_sensor_id, sensor_type_id, mac_address, message_id,
+			reading_time, source, policy, rejected, measurements, violations, status, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		quarantined.ID,
+		quarantined.TenantID,
+		quarantined.AssetSensorID,
+		quarantined.SensorTypeID,
+		quarantined.MacAddress,
+		quarantined.MessageID,
+		quarantined.ReadingTime,
+		quarantined.Source,
+		quarantined.Policy,
+		quarantined.Rejected,
+		measurements,
+		violations,
+		quarantined.Status,
+		quarantined.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create quarantined reading: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a quarantined reading by its ID
+func (r *quarantinedReadingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.QuarantinedReading, error) {
+	query := `SELECT ` + quarantinedReadingColumns + ` FROM quarantined_readings WHERE id = $1`
+
+	quarantined, err := r.scanRow(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quarantined reading: %w", err)
+	}
+
+	return quarantined, nil
+}
+
+// List retrieves paginated quarantined readings, newest first
+func (r *quarantinedReadingRepository) List(ctx context.Context, filter QuarantinedReadingFilter, limit, offset int) ([]*entity.QuarantinedReading, int, error) {
+	whereClause := `WHERE 1 = 1`
+	var args []interface{}
+	if filter.TenantID != nil {
+		args = append(args, *filter.TenantID)
+		whereClause += fmt.Sprintf(` AND tenant_id = $%d`, len(args))
+	}
+	if filter.AssetSensorID != nil {
+		args = append(args, *filter.AssetSensorID)
+		whereClause += fmt.Sprintf(` AND asset_sensor_id = $%d`, len(args))
+	}
+	if filter.SensorTypeID != nil {
+		args = append(args, *filter.SensorTypeID)
+		whereClause += fmt.Sprintf(` AND sensor_type_id = $%d`, len(args))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		whereClause += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM quarantined_readings ` + whereClause
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM quarantined_readings %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		quarantinedReadingColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query quarantined readings: %w", err)
+	}
+	defer rows.Close()
+
+	var quarantined []*entity.QuarantinedReading
+	for rows.Next() {
+		reading, err := r.scanRow(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan quarantined reading: %w", err)
+		}
+		quarantined = append(quarantined, reading)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating quarantined readings: %w", err)
+	}
+
+	return quarantined, totalCount, nil
+}
+
+// UpdateReview stores the outcome of a review. Only pending readings are updated, so a
+// reading can't be replayed twice.
+func (r *quarantinedReadingRepository) UpdateReview(ctx context.Context, quarantined *entity.QuarantinedReading) error {
+	now := time.Now()
+	quarantined.UpdatedAt = &now
+
+	query := `
+		UPDATE quarantined_readings SET
+			status = $2,
+			reviewed_by = $3,
+			reviewed_at = $4,
+			review_note = $5,
+			replayed_reading_ids = $6,
+			updated_at = $7
+		WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		quarantined.ID,
+		quarantined.Status,
+		quarantined.ReviewedBy,
+		quarantined.ReviewedAt,
+		quarantined.ReviewNote,
+		pq.Array(quarantined.ReplayedReadingIDs),
+		quarantined.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update quarantined reading: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pending quarantined reading not found")
+	}
+
+	return nil
+}
+
+// scanRow scans a single quarantined reading row
+func (r *quarantinedReadingRepository) scanRow(row rowScanner) (*entity.QuarantinedReading, error) {
+	var quarantined entity.QuarantinedReading
+	var measurements, violations []byte
+	err := row.Scan(
+		&quarantined.ID,
+		&quarantined.TenantID,
+		&quarantined.AssetSensorID,
+		&quarantined.SensorTypeID,
+		&quarantined.MacAddress,
+		&quarantined.MessageID,
+		&quarantined.ReadingTime,
+		&quarantined.Source,
+		&quarantined.Policy,
+		&quarantined.Rejected,
+		&measurements,
+		&violations,
+		&quarantined.Status,
+		&quarantined.ReviewedBy,
+		&quarantined.ReviewedAt,
+		&quarantined.ReviewNote,
+		pq.Array(&quarantined.ReplayedReadingIDs),
+		&quarantined.CreatedAt,
+		&quarantined.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(measurements, &quarantined.Measurements); err != nil {
+		return nil, fmt.Errorf("failed to decode quarantined measurements: %w", err)
+	}
+	if err := json.Unmarshal(violations, &quarantined.Violations); err != nil {
+		return nil, fmt.Errorf("failed to decode schema violations: %w", err)
+	}
+
+	return &quarantined, nil
+}
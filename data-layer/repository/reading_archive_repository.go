@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingArchiveRepository defines the interface for reading archive operations
+type ReadingArchiveRepository interface {
+	ListPendingGroups(ctx context.Context) ([]entity.ReadingArchiveGroup, error)
+	NextPart(ctx context.Context, tenantID *uuid.UUID, month time.Time) (int, error)
+	Export(ctx context.Context, group entity.ReadingArchiveGroup, write func(*entity.IoTSensorReading) error, finish func() (*entity.ReadingArchive, error)) (*entity.ReadingArchive, error)
+	Restore(ctx context.Context, archiveID uuid.UUID, read func() (*entity.IoTSensorReading, error)) (int64, int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ReadingArchive, error)
+	List(ctx context.Context, tenantID *uuid.UUID) ([]*entity.ReadingArchive, error)
+}
+
+// readingArchiveRepository handles database operations for reading archives
+type readingArchiveRepository struct {
+	*BaseRepository
+}
+
+// NewReadingArchiveRepository creates a new ReadingArchiveRepository
+func NewReadingArchiveRepository(db *sql.DB) ReadingArchiveRepository {
+	return &readingArchiveRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const readingArchiveColumns = `id, tenant_id, month, part, storage, object_key, format, reading_count,
+	first_reading_time, last_reading_time, size_bytes, checksum, created_at, restored_at`
+
+// ListPendingGroups retrieves the tenants' months of readings waiting in
+// archived_iot_sensor_readings, oldest first
+func (r *readingArchiveRepository) ListPendingGroups(ctx context.Context) ([]entity.ReadingArchiveGroup, error) {
+	query := `
+		SELECT tenant_id, date_trunc('month', reading_time) AS month, COUNT(*)
+		FROM archived_iot_sensor_readings
+		GROUP BY tenant_id, month
+		ORDER BY month, tenant_id NULLS FIRST`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending reading archives: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []entity.ReadingArchiveGroup
+	for rows.Next() {
+		var group entity.ReadingArchiveGroup
+		if err := rows.Scan(&group.TenantID, &group.Month, &group.ReadingCount); err != nil {
+			return nil, fmt.Errorf("failed to scan pending reading archive: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending reading archives: %w", err)
+	}
+
+	return groups, nil
+}
+
+// NextPart returns the part number of the next archive of a tenant's month
+func (r *readingArchiveRepository) NextPart(ctx context.Context, tenantID *uuid.UUID, month time.Time) (int, error) {
+	var part int
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(part), 0) + 1 FROM reading_archives
+		WHERE tenant_id IS NOT DISTINCT FROM $1 AND month = $2`, tenantID, month).Scan(&part)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next reading archive part: %w", err)
+	}
+	return part, nil
+}
+
+// Export passes the archived readings of a tenant's month to write, ordered by reading
+// time, then records the archive returned by finish and removes the exported readings from
+// archived_iot_sensor_readings. The readings are read from a snapshot, so readings archived
+// meanwhile are left for the next export.
+func (r *readingArchiveRepository) Export(ctx context.Context, group entity.ReadingArchiveGroup, write func(*entity.IoTSensorReading) error, finish func() (*entity.ReadingArchive, error)) (*entity.ReadingArchive, error) {
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	condition := `tenant_id IS NOT DISTINCT FROM $1 AND reading_time >= $2 AND reading_time < $3`
+	args := []interface{}{group.TenantID, group.Month, group.Month.AddDate(0, 1, 0)}
+
+	query := fmt.Sprintf(`SELECT %s FROM archived_iot_sensor_readings WHERE %s ORDER BY reading_time, id`, readingColumns, condition)
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived readings: %w", err)
+	}
+	for rows.Next() {
+		var reading entity.IoTSensorReading
+		err := rows.Scan(
+			&reading.ID, &reading.TenantID, &reading.AssetSensorID, &reading.SensorTypeID,
+			&reading.MacAddress, &reading.LocationID, &reading.LocationName,
+			&reading.MeasurementType, &reading.MeasurementLabel, &reading.MeasurementUnit,
+			&reading.NumericValue, &reading.TextValue, &reading.BooleanValue,
+			&reading.DataSource, &reading.OriginalFieldName, &reading.OriginalValue, &reading.OriginalUnit,
+			&reading.IsLate, &reading.DeviceTime, &reading.ClockOffsetMs, &reading.TimeAnomaly,
+			&reading.ReadingTime, &reading.CreatedAt, &reading.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan archived reading: %w", err)
+		}
+		if err := write(&reading); err != nil {
+			rows.Close()
+			return nil, err
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating archived readings: %w", err)
+	}
+
+	archive, err := finish()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO reading_archives (
+			tenant_id, month, part, storage, object_key, format, reading_count,
+			first_reading_time, last_reading_time, size_bytes, checksum
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at`,
+		archive.TenantID, archive.Month, archive.Part, archive.Storage, archive.ObjectKey, archive.Format,
+		archive.ReadingCount, archive.FirstReadingTime, archive.LastReadingTime, archive.SizeBytes, archive.Checksum,
+	).Scan(&archive.ID, &archive.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record reading archive: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM archived_iot_sensor_readings WHERE `+condition, args...); err != nil {
+		return nil, fmt.Errorf("failed to remove exported readings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reading archive: %w", err)
+	}
+
+	return archive, nil
+}
+
+// Restore inserts the readings returned by read, until it returns io.EOF, back into
+// iot_sensor_readings and marks the archive restored. Readings already stored, or whose
+// asset sensor or sensor type no longer exists, are skipped; a location that no longer
+// exists is cleared. It returns how many readings were restored and skipped.
+func (r *readingArchiveRepository) Restore(ctx context.Context, archiveID uuid.UUID, read func() (*entity.IoTSensorReading, error)) (int64, int64, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`
+		INSERT INTO iot_sensor_readings (%s)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::uuid, $5::varchar,
+			(SELECT id FROM locations WHERE id = $6::uuid), $7::varchar,
+			$8::varchar, $9::varchar, $10::varchar, $11::double precision, $12::text, $13::boolean,
+			$14::varchar, $15::varchar, $16::double precision, $17::varchar, $18::boolean, $19::timestamp,
+			$20::bigint, $21::varchar, $22::timestamp, $23::timestamp, $24::timestamp
+		WHERE EXISTS (SELECT 1 FROM asset_sensors WHERE id = $3::uuid)
+		  AND EXISTS (SELECT 1 FROM sensor_types WHERE id = $4::uuid)
+		ON CONFLICT DO NOTHING`, readingColumns))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare reading restore: %w", err)
+	}
+	defer stmt.Close()
+
+	var restored, skipped int64
+	for {
+		reading, err := read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		result, err := stmt.ExecContext(ctx,
+			reading.ID, reading.TenantID, reading.AssetSensorID, reading.SensorTypeID,
+			reading.MacAddress, reading.LocationID, reading.LocationName,
+			reading.MeasurementType, reading.MeasurementLabel, reading.MeasurementUnit,
+			reading.NumericValue, reading.TextValue, reading.BooleanValue,
+			reading.DataSource, reading.OriginalFieldName, reading.OriginalValue, reading.OriginalUnit,
+			reading.IsLate, reading.DeviceTime, reading.ClockOffsetMs, reading.TimeAnomaly,
+			reading.ReadingTime, reading.CreatedAt, reading.UpdatedAt,
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to restore reading %s: %w", reading.ID, err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		restored += rowsAffected
+		skipped += 1 - rowsAffected
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE reading_archives SET restored_at = CURRENT_TIMESTAMP WHERE id = $1`, archiveID); err != nil {
+		return 0, 0, fmt.Errorf("failed to mark reading archive restored: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit reading restore: %w", err)
+	}
+
+	return restored, skipped, nil
+}
+
+// GetByID retrieves a reading archive by its ID
+func (r *readingArchiveRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReadingArchive, error) {
+	query := fmt.Sprintf(`SELECT %s FROM reading_archives WHERE id = $1`, readingArchiveColumns)
+
+	archive, err := scanReadingArchive(r.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reading archive: %w", err)
+	}
+	return archive, nil
+}
+
+// List retrieves the reading archives, of a tenant when tenantID is set, by month
+func (r *readingArchiveRepository) List(ctx context.Context, tenantID *uuid.UUID) ([]*entity.ReadingArchive, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM reading_archives
+		WHERE $1::uuid IS NULL OR tenant_id = $1
+		ORDER BY month, tenant_id NULLS FIRST, part`, readingArchiveColumns)
+
+	rows, err := r.DB.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading archives: %w", err)
+	}
+	defer rows.Close()
+
+	var archives []*entity.ReadingArchive
+	for rows.Next() {
+		archive, err := scanReadingArchive(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reading archive: %w", err)
+		}
+		archives = append(archives, archive)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading archives: %w", err)
+	}
+
+	return archives, nil
+}
+
+// scanReadingArchive scans a row of readingArchiveColumns
+func scanReadingArchive(row rowScanner) (*entity.ReadingArchive, error) {
+	var archive entity.ReadingArchive
+	err := row.Scan(
+		&archive.ID, &archive.TenantID, &archive.Month, &archive.Part, &archive.Storage, &archive.ObjectKey,
+		&archive.Format, &archive.ReadingCount, &archive.FirstReadingTime, &archive.LastReadingTime,
+		&archive.SizeBytes, &archive.Checksum, &archive.CreatedAt, &archive.RestoredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ReadingOutboxRepository defines the interface for reading outbox operations. Events are
+// recorded by the IoT sensor reading repository in the transaction that stores the readings.
+type ReadingOutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.ReadingOutboxEvent, error)
+	ApplyLastReading(ctx context.Context, event *entity.ReadingOutboxEvent, attempts int, completedAt time.Time) error
+	MarkDone(ctx context.Context, id uuid.UUID, attempts int, completedAt time.Time) error
+	MarkRetry(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string) error
+	CountByStatus(ctx context.Context) (map[entity.ReadingOutboxStatus]int, error)
+	DeleteDoneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// readingOutboxRepository handles database operations for the reading outbox
+type readingOutboxRepository struct {
+	*BaseRepository
+}
+
+// NewReadingOutboxRepository creates a new ReadingOutboxRepository
+func NewReadingOutboxRepository(db *sql.DB) ReadingOutboxRepository {
+	return &readingOutboxRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const readingOutboxColumns = `
+	id, tenant_id, reading_id, asset_sensor_id, effect, idempotency_key, status,
+	attempts, next_attempt_at, last_error, completed_at, created_at, updated_at`
+
+// ClaimDue leases up to limit due events to the caller. Claimed events are moved to
+// 'processing' and pushed back by the lease, so an effect that is never confirmed is
+// applied again once the lease expires. SKIP LOCKED lets several instances poll safely.
+func (r *readingOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.ReadingOutboxEvent, error) {
+	// RETURNING doesn't keep the subquery's order, so sort the claimed events to apply
+	// readings in event-time order, by when they were taken rather than stored
+	query := `
+		WITH claimed AS (
+			UPDATE reading_outbox SET
+				status = 'processing',
+				next_attempt_at = $2,
+				updated_at = $1
+			WHERE id IN (
+				SELECT id FROM reading_outbox
+				WHERE status IN ('pending', 'processing') AND next_attempt_at <= $1
+				ORDER BY next_attempt_at, created_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + readingOutboxColumns + `
+		)
+		SELECT ` + readingOutboxColumns + ` FROM claimed
+		ORDER BY (SELECT r.reading_time FROM iot_sensor_readings r WHERE r.id = claimed.reading_id),
+			created_at, reading_id, effect`
+
+	return r.queryEvents(ctx, query, now, now.Add(lease), limit)
+}
+
+// ApplyLastReading updates the last reading cache of the event's asset sensor from the
+// event's reading and marks the event done in one transaction. The cache only moves
+// forward, so applying an older or the same reading again leaves it unchanged.
+func (r *readingOutboxRepository) ApplyLastReading(ctx context.Context, event *entity.ReadingOutboxEvent, attempts int, completedAt time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE asset_sensors s SET
+			last_reading_time = r.reading_time,
+			last_reading_value = COALESCE(r.numeric_value, s.last_reading_value),
+			last_reading_values = COALESCE(s.last_reading_values, '{}'::jsonb) || jsonb_build_object(
+				r.measurement_type,
+				COALESCE(to_jsonb(r.numeric_value), to_jsonb(r.text_value), to_jsonb(r.boolean_value), 'null'::jsonb)
+			),
+			updated_at = $2
+		FROM iot_sensor_readings r
+		WHERE r.id = $1 AND s.id = r.asset_sensor_id
+			AND (s.last_reading_time IS NULL OR s.last_reading_time <= r.reading_time)`,
+		event.ReadingID, completedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update last reading: %w", err)
+	}
+
+	if err := markOutboxEventDone(ctx, tx, event.ID, attempts, completedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit last reading update: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDone records that an event's effect was applied
+func (r *readingOutboxRepository) MarkDone(ctx context.Context, id uuid.UUID, attempts int, completedAt time.Time) error {
+	return markOutboxEventDone(ctx, r.DB, id, attempts, completedAt)
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *readingOutboxRepository) MarkRetry(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE reading_outbox SET
+			status = 'pending',
+			attempts = $2,
+			next_attempt_at = $3,
+			last_error = $4,
+			updated_at = $5
+		WHERE id = $1`
+
+	if _, err := r.DB.ExecContext(ctx, query, id, attempts, nextAttemptAt, lastError, time.Now()); err != nil {
+		return fmt.Errorf("failed to schedule reading outbox retry: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records the final failed attempt of an event
+func (r *readingOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string) error {
+	query := `
+		UPDATE reading_outbox SET
+			status = 'failed',
+			attempts = $2,
+			last_error = $3,
+			updated_at = $4
+		WHERE id = $1`
+
+	if _, err := r.DB.ExecContext(ctx, query, id, attempts, lastError, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark reading outbox event as failed: %w", err)
+	}
+
+	return nil
+}
+
+// CountByStatus returns the number of outbox events in each status
+func (r *readingOutboxRepository) CountByStatus(ctx context.Context) (map[entity.ReadingOutboxStatus]int, error) {
+	rows, err := r.DB.QueryContext(ctx, `SELECT status, COUNT(*) FROM reading_outbox GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reading outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[entity.ReadingOutboxStatus]int)
+	for rows.Next() {
+		var status entity.ReadingOutboxStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reading outbox count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading outbox counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteDoneBefore removes events completed before the given time
+func (r *readingOutboxRepository) DeleteDoneBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM reading_outbox WHERE status = 'done' AND completed_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete completed reading outbox events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// queryEvents executes a query and returns reading outbox events
+func (r *readingOutboxRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*entity.ReadingOutboxEvent, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reading outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.ReadingOutboxEvent
+	for rows.Next() {
+		event, err := r.scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reading outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// scanRow scans a single reading outbox row
+func (r *readingOutboxRepository) scanRow(row rowScanner) (*entity.ReadingOutboxEvent, error) {
+	var event entity.ReadingOutboxEvent
+	err := row.Scan(
+		&event.ID,
+		&event.TenantID,
+		&event.ReadingID,
+		&event.AssetSensorID,
+		&event.Effect,
+		&event.IdempotencyKey,
+		&event.Status,
+		&event.Attempts,
+		&event.NextAttemptAt,
+		&event.LastError,
+		&event.CompletedAt,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// insertReadingOutbox records every reading effect of a stored reading. It runs in the
+// transaction that stores the reading, so the effects are recorded if and only if the
+// reading is. Effects already recorded for the reading are skipped.
+func insertReadingOutbox(ctx context.Context, exec sqlExecer, reading *entity.IoTSensorReadingFlexible, createdAt time.Time) error {
+	effects := make([]string, len(entity.ReadingEffects))
+	keys := make([]string, len(entity.ReadingEffects))
+	for i, effect := range entity.ReadingEffects {
+		effects[i] = string(effect)
+		keys[i] = entity.ReadingEffectKey(effect, reading.ID)
+	}
+
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO reading_outbox (
+			tenant_id, reading_id, asset_sensor_id, effect, idempotency_key,
+			next_attempt_at, created_at
+		)
+		SELECT $1, $2, $3, e.effect, e.idempotency_key, $4, $4
+		FROM unnest($5::text[], $6::text[]) AS e(effect, idempotency_key)
+		ON CONFLICT (idempotency_key) DO NOTHING`,
+		reading.TenantID,
+		reading.ID,
+		reading.AssetSensorID,
+		createdAt,
+		pq.Array(effects),
+		pq.Array(keys),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record reading outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// insertReadingOutboxEffects records the given effects for stored readings, with one
+// statement for all of them. The idempotency keys are built like entity.ReadingEffectKey.
+func insertReadingOutboxEffects(ctx context.Context, exec sqlExecer, readingIDs []uuid.UUID, effects []entity.ReadingEffect, createdAt time.Time) error {
+	if len(readingIDs) == 0 || len(effects) == 0 {
+		return nil
+	}
+
+	effectNames := make([]string, len(effects))
+	for i, effect := range effects {
+		effectNames[i] = string(effect)
+	}
+
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO reading_outbox (
+			tenant_id, reading_id, asset_sensor_id, effect, idempotency_key,
+			next_attempt_at, created_at
+		)
+		SELECT r.tenant_id, r.id, r.asset_sensor_id, e.effect, e.effect || ':' || r.id::text, $3, $3
+		FROM iot_sensor_readings r
+		CROSS JOIN unnest($2::text[]) AS e(effect)
+		WHERE r.id = ANY($1::uuid[])
+		ON CONFLICT (idempotency_key) DO NOTHING`,
+		pq.Array(readingIDs),
+		pq.Array(effectNames),
+		createdAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record reading outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// markOutboxEventDone records that an event's effect was applied
+func markOutboxEventDone(ctx context.Context, exec sqlExecer, id uuid.UUID, attempts int, completedAt time.Time) error {
+	query := `
+		UPDATE reading_outbox SET
+			status = 'done',
+			attempts = $2,
+			last_error = NULL,
+			completed_at = $3,
+			updated_at = $3
+		WHERE id = $1`
+
+	if _, err := exec.ExecContext(ctx, query, id, attempts, completedAt); err != nil {
+		return fmt.Errorf("failed to mark reading outbox event as done: %w", err)
+	}
+
+	return nil
+}
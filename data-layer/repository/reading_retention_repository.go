@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// partitionBoundPattern matches the bounds of a monthly partition as shown by pg_get_expr
+var partitionBoundPattern = regexp.MustCompile(`FROM \('([^']+)'\) TO \('([^']+)'\)`)
+
+// ReadingRetentionRepository defines the interface for reading partition and retention operations
+type ReadingRetentionRepository interface {
+	IsPartitioned(ctx context.Context) (bool, error)
+	ListPartitions(ctx context.Context) ([]entity.ReadingPartition, error)
+	CreatePartition(ctx context.Context, month time.Time) error
+	DropPartition(ctx context.Context, name string) error
+	PartitionHasReadings(ctx context.Context, name string, filter entity.ReadingTenantFilter) (bool, error)
+	ArchivePartitionReadings(ctx context.Context, name string, filter entity.ReadingTenantFilter) (int64, error)
+	DeleteExpiredReadings(ctx context.Context, filter entity.ReadingTenantFilter, cutoff time.Time, limit int) (int64, error)
+	ArchiveExpiredReadings(ctx context.Context, filter entity.ReadingTenantFilter, cutoff time.Time, limit int) (int64, error)
+	DeleteExpiredMessages(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+
+	ListPolicies(ctx context.Context) ([]*entity.ReadingRetentionPolicy, error)
+	UpsertPolicy(ctx context.Context, policy *entity.ReadingRetentionPolicy) error
+	DeletePolicy(ctx context.Context, tenantID *uuid.UUID) error
+}
+
+// readingRetentionRepository handles database operations for reading partitions and retention
+type readingRetentionRepository struct {
+	*BaseRepository
+}
+
+// NewReadingRetentionRepository creates a new ReadingRetentionRepository
+func NewReadingRetentionRepository(db *sql.DB) ReadingRetentionRepository {
+	return &readingRetentionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// IsPartitioned reports whether iot_sensor_readings is a partitioned table
+func (r *readingRetentionRepository) IsPartitioned(ctx context.Context) (bool, error) {
+	var partitioned bool
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT c.relkind = 'p'
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = 'iot_sensor_readings'`).Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if iot_sensor_readings is partitioned: %w", err)
+	}
+	return partitioned, nil
+}
+
+// ListPartitions retrieves the partitions of iot_sensor_readings, the monthly ones ordered
+// by time followed by the default partition
+func (r *readingRetentionRepository) ListPartitions(ctx context.Context) ([]entity.ReadingPartition, error) {
+	query := `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'iot_sensor_readings'::regclass
+		ORDER BY c.relname`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []entity.ReadingPartition
+	var defaults []entity.ReadingPartition
+	for rows.Next() {
+		var name, bound string
+		if err := rows.Scan(&name, &bound); err != nil {
+			return nil, fmt.Errorf("failed to scan reading partition: %w", err)
+		}
+		if bound == "DEFAULT" {
+			defaults = append(defaults, entity.ReadingPartition{Name: name, IsDefault: true})
+			continue
+		}
+
+		match := partitionBoundPattern.FindStringSubmatch(bound)
+		if match == nil {
+			return nil, fmt.Errorf("unexpected bounds of reading partition %s: %s", name, bound)
+		}
+		from, err := time.Parse("2006-01-02 15:04:05", match[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse lower bound of reading partition %s: %w", name, err)
+		}
+		to, err := time.Parse("2006-01-02 15:04:05", match[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse upper bound of reading partition %s: %w", name, err)
+		}
+		partitions = append(partitions, entity.ReadingPartition{Name: name, From: from, To: to})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading partitions: %w", err)
+	}
+
+	return append(partitions, defaults...), nil
+}
+
+// CreatePartition creates the monthly partition starting at month. Readings of the month
+// already stored in the default partition are moved into the new partition, which is
+// attached once filled.
+func (r *readingRetentionRepository) CreatePartition(ctx context.Context, month time.Time) error {
+	month = entity.ReadingPartitionMonth(month)
+	name := entity.ReadingPartitionName(month)
+	from := month.Format("2006-01-02 15:04:05")
+	to := month.AddDate(0, 1, 0).Format("2006-01-02 15:04:05")
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE %s (LIKE iot_sensor_readings INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, name),
+		fmt.Sprintf(`
+			WITH moved AS (
+				DELETE FROM iot_sensor_readings_default
+				WHERE reading_time >= '%[2]s' AND reading_time < '%[3]s'
+				RETURNING %[4]s
+			)
+			INSERT INTO %[1]s (%[4]s) SELECT %[4]s FROM moved`, name, from, to, readingColumns),
+		fmt.Sprintf(`ALTER TABLE iot_sensor_readings ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`, name, from, to),
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create reading partition %s: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reading partition %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// DropPartition detaches a monthly partition from iot_sensor_readings and drops it
+func (r *readingRetentionRepository) DropPartition(ctx context.Context, name string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE iot_sensor_readings DETACH PARTITION %s`, pq.QuoteIdentifier(name))); err != nil {
+		return fmt.Errorf("failed to detach reading partition %s: %w", name, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, pq.QuoteIdentifier(name))); err != nil {
+		return fmt.Errorf("failed to drop reading partition %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dropping reading partition %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// PartitionHasReadings reports whether a partition holds readings selected by the filter
+func (r *readingRetentionRepository) PartitionHasReadings(ctx context.Context, name string, filter entity.ReadingTenantFilter) (bool, error) {
+	if filter.IsEmpty() {
+		return false, nil
+	}
+
+	where, args := tenantFilterCondition(filter, 1)
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE %s)`, pq.QuoteIdentifier(name), where)
+
+	var exists bool
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check readings of partition %s: %w", name, err)
+	}
+	return exists, nil
+}
+
+// ArchivePartitionReadings copies the readings of a partition selected by the filter to
+// archived_iot_sensor_readings, ahead of dropping the partition
+func (r *readingRetentionRepository) ArchivePartitionReadings(ctx context.Context, name string, filter entity.ReadingTenantFilter) (int64, error) {
+	if filter.IsEmpty() {
+		return 0, nil
+	}
+
+	where, args := tenantFilterCondition(filter, 1)
+	query := fmt.Sprintf(`
+		INSERT INTO archived_iot_sensor_readings (%[1]s)
+		SELECT %[1]s FROM %[2]s WHERE %[3]s
+		ON CONFLICT (id, reading_time) DO NOTHING`, readingColumns, pq.QuoteIdentifier(name), where)
+
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive readings of partition %s: %w", name, err)
+	}
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get archived readings of partition %s: %w", name, err)
+	}
+	return archived, nil
+}
+
+// DeleteExpiredReadings deletes up to limit readings selected by the filter that are older
+// than cutoff
+func (r *readingRetentionRepository) DeleteExpiredReadings(ctx context.Context, filter entity.ReadingTenantFilter, cutoff time.Time, limit int) (int64, error) {
+	if filter.IsEmpty() {
+		return 0, nil
+	}
+
+	where, args := tenantFilterCondition(filter, 3)
+	query := fmt.Sprintf(`
+		DELETE FROM iot_sensor_readings
+		WHERE (id, reading_time) IN (
+			SELECT id, reading_time FROM iot_sensor_readings
+			WHERE reading_time < $1 AND %s
+			LIMIT $2
+		)`, where)
+
+	result, err := r.DB.ExecContext(ctx, query, append([]interface{}{cutoff, limit}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired readings: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted readings: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteExpiredMessages deletes up to limit client message IDs recorded in reading_messages
+// before cutoff
+func (r *readingRetentionRepository) DeleteExpiredMessages(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, `
+		DELETE FROM reading_messages
+		WHERE (asset_sensor_id, message_id) IN (
+			SELECT asset_sensor_id, message_id FROM reading_messages
+			WHERE created_at < $1
+			LIMIT $2
+		)`, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired reading messages: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted reading messages: %w", err)
+	}
+	return deleted, nil
+}
+
+// ArchiveExpiredReadings moves up to limit readings selected by the filter that are older
+// than cutoff to archived_iot_sensor_readings
+func (r *readingRetentionRepository) ArchiveExpiredReadings(ctx context.Context, filter entity.ReadingTenantFilter, cutoff time.Time, limit int) (int64, error) {
+	if filter.IsEmpty() {
+		return 0, nil
+	}
+
+	where, args := tenantFilterCondition(filter, 3)
+	query := fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM iot_sensor_readings
+			WHERE (id, reading_time) IN (
+				SELECT id, reading_time FROM iot_sensor_readings
+				WHERE reading_time < $1 AND %[2]s
+				LIMIT $2
+			)
+			RETURNING %[1]s
+		)
+		INSERT INTO archived_iot_sensor_readings (%[1]s)
+		SELECT %[1]s FROM moved
+		ON CONFLICT (id, reading_time) DO NOTHING`, readingColumns, where)
+
+	result, err := r.DB.ExecContext(ctx, query, append([]interface{}{cutoff, limit}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive expired readings: %w", err)
+	}
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get archived readings: %w", err)
+	}
+	return archived, nil
+}
+
+// ListPolicies retrieves every retention policy, the default policy first
+func (r *readingRetentionRepository) ListPolicies(ctx context.Context) ([]*entity.ReadingRetentionPolicy, error) {
+	query := `
+		SELECT id, tenant_id, retention_days, action, created_at, updated_at
+		FROM reading_retention_policies
+		ORDER BY tenant_id NULLS FIRST`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*entity.ReadingRetentionPolicy
+	for rows.Next() {
+		var policy entity.ReadingRetentionPolicy
+		err := rows.Scan(&policy.ID, &policy.TenantID, &policy.RetentionDays, &policy.Action,
+			&policy.CreatedAt, &policy.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reading retention policy: %w", err)
+		}
+		policies = append(policies, &policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading retention policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// UpsertPolicy creates the retention policy of a tenant, or the default policy, replacing
+// the existing one
+func (r *readingRetentionRepository) UpsertPolicy(ctx context.Context, policy *entity.ReadingRetentionPolicy) error {
+	query := `
+		INSERT INTO reading_retention_policies (id, tenant_id, retention_days, action)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ((COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid))) DO UPDATE SET
+			retention_days = EXCLUDED.retention_days,
+			action = EXCLUDED.action,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at`
+
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+
+	err := r.DB.QueryRowContext(ctx, query, policy.ID, policy.TenantID, policy.RetentionDays, policy.Action).
+		Scan(&policy.ID, &policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save reading retention policy: %w", err)
+	}
+	return nil
+}
+
+// DeletePolicy deletes the retention policy of a tenant, or the default policy when
+// tenantID is nil
+func (r *readingRetentionRepository) DeletePolicy(ctx context.Context, tenantID *uuid.UUID) error {
+	result, err := r.DB.ExecContext(ctx,
+		`DELETE FROM reading_retention_policies WHERE tenant_id IS NOT DISTINCT FROM $1`, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reading retention policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("reading retention policy not found")
+	}
+	return nil
+}
+
+// tenantFilterCondition returns the SQL condition selecting the readings of a tenant filter,
+// numbering its parameters from first
+func tenantFilterCondition(filter entity.ReadingTenantFilter, first int) (string, []interface{}) {
+	condition := fmt.Sprintf(`(tenant_id = ANY($%d::uuid[])`, first)
+	args := []interface{}{pq.Array(uuidStrings(filter.TenantIDs))}
+	if filter.Others {
+		condition += fmt.Sprintf(` OR tenant_id IS NULL OR NOT (tenant_id = ANY($%d::uuid[]))`, first+1)
+		args = append(args, pq.Array(uuidStrings(filter.Excluded)))
+	}
+	return condition + ")", args
+}
+
+// uuidStrings converts UUIDs to strings for array parameters
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
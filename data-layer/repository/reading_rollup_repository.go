@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ReadingRollupRepository defines the interface for reading rollup operations
+type ReadingRollupRepository interface {
+	GetStates(ctx context.Context) (map[string]*entity.ReadingRollupState, error)
+	Refresh(ctx context.Context, resolution entity.ReadingRollupResolution, target time.Time, chunk time.Duration) (time.Time, int64, error)
+}
+
+// readingRollupRepository handles database operations for reading rollups
+type readingRollupRepository struct {
+	*BaseRepository
+}
+
+// NewReadingRollupRepository creates a new ReadingRollupRepository
+func NewReadingRollupRepository(db *sql.DB) ReadingRollupRepository {
+	return &readingRollupRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetStates retrieves how far each rollup has been refreshed, keyed by resolution name.
+// Rollups that were never refreshed are missing.
+func (r *readingRollupRepository) GetStates(ctx context.Context) (map[string]*entity.ReadingRollupState, error) {
+	query := `SELECT resolution, refreshed_through, updated_at FROM reading_rollup_state`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading rollup states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]*entity.ReadingRollupState)
+	for rows.Next() {
+		var state entity.ReadingRollupState
+		if err := rows.Scan(&state.Resolution, &state.RefreshedThrough, &state.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reading rollup state: %w", err)
+		}
+		states[state.Resolution] = &state
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reading rollup states: %w", err)
+	}
+
+	return states, nil
+}
+
+// Refresh rolls up the readings created since the rollup was last refreshed, up to chunk
+// later and no later than target. Each bucket holding such a reading is recomputed from
+// all its readings, so late readings land in the right bucket. A rollup that was never
+// refreshed starts from the oldest reading. It returns how far the rollup is refreshed and
+// how many rollup rows were written.
+func (r *readingRollupRepository) Refresh(ctx context.Context, resolution entity.ReadingRollupResolution, target time.Time, chunk time.Duration) (time.Time, int64, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reading_rollup_state (resolution, refreshed_through)
+		SELECT $1, COALESCE(MIN(created_at), $2) FROM iot_sensor_readings
+		ON CONFLICT (resolution) DO NOTHING`, resolution.Name, target)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to initialize %s rollup state: %w", resolution.Name, err)
+	}
+
+	// Locking the state keeps concurrent refreshes of the rollup from overlapping
+	var from time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT refreshed_through FROM reading_rollup_state WHERE resolution = $1 FOR UPDATE`,
+		resolution.Name).Scan(&from)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to lock %s rollup state: %w", resolution.Name, err)
+	}
+
+	through := from.Add(chunk)
+	if through.After(target) {
+		through = target
+	}
+	if !through.After(from) {
+		return from, 0, nil
+	}
+
+	dirty := fmt.Sprintf(`
+		SELECT DISTINCT asset_sensor_id, date_trunc('%s', reading_time) AS bucket
+		FROM iot_sensor_readings
+		WHERE created_at >= $1 AND created_at < $2 AND numeric_value IS NOT NULL`, resolution.Trunc)
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s ru
+		USING (%s) d
+		WHERE ru.asset_sensor_id = d.asset_sensor_id AND ru.bucket = d.bucket`, resolution.Table, dirty)
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, from, through); err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to clear %s rollup buckets: %w", resolution.Name, err)
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %[1]s (
+			asset_sensor_id, measurement_type, measurement_unit, bucket, tenant_id, sensor_type_id,
+			measurement_label, reading_count, value_sum, value_min, value_max, updated_at
+		)
+		SELECT
+			r.asset_sensor_id,
+			r.measurement_type,
+			COALESCE(r.measurement_unit, ''),
+			d.bucket,
+			(array_agg(r.tenant_id))[1],
+			(array_agg(r.sensor_type_id))[1],
+			MIN(r.measurement_label),
+			COUNT(*),
+			SUM(r.numeric_value),
+			MIN(r.numeric_value),
+			MAX(r.numeric_value),
+			CURRENT_TIMESTAMP
+		FROM (%[2]s) d
+		JOIN iot_sensor_readings r
+		  ON r.asset_sensor_id = d.asset_sensor_id
+		 AND r.reading_time >= d.bucket
+		 AND r.reading_time < d.bucket + interval '1 %[3]s'
+		WHERE r.numeric_value IS NOT NULL
+		GROUP BY r.asset_sensor_id, r.measurement_type, COALESCE(r.measurement_unit, ''), d.bucket
+		ON CONFLICT (asset_sensor_id, measurement_type, measurement_unit, bucket) DO UPDATE SET
+			tenant_id = EXCLUDED.tenant_id,
+			sensor_type_id = EXCLUDED.sensor_type_id,
+			measurement_label = EXCLUDED.measurement_label,
+			reading_count = EXCLUDED.reading_count,
+			value_sum = EXCLUDED.value_sum,
+			value_min = EXCLUDED.value_min,
+			value_max = EXCLUDED.value_max,
+			updated_at = EXCLUDED.updated_at`,
+		resolution.Table, dirty, resolution.Trunc)
+
+	result, err := tx.ExecContext(ctx, insertQuery, from, through)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to refresh %s rollup buckets: %w", resolution.Name, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to get refreshed %s rollup rows: %w", resolution.Name, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE reading_rollup_state SET refreshed_through = $2, updated_at = CURRENT_TIMESTAMP WHERE resolution = $1`,
+		resolution.Name, through)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to update %s rollup state: %w", resolution.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to commit %s rollup refresh: %w", resolution.Name, err)
+	}
+
+	return through, rows, nil
+}
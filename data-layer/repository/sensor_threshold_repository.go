@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // SensorThresholdRepository defines the interface for sensor threshold operations
@@ -40,14 +41,16 @@ func NewSensorThresholdRepository(db *sql.DB) SensorThresholdRepository {
 func (r *sensorThresholdRepository) Create(ctx context.Context, threshold *entity.SensorThreshold) error {
 	log.Printf("Creating sensor threshold: %+v", threshold)
 
-	// Validate threshold values
-	if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
-		return fmt.Errorf("minimum threshold must be less than maximum threshold")
-	}
+	// Validate threshold values; boolean and text rules don't use them
+	if !threshold.RuleKind.IsValueMatch() {
+		if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
+			return fmt.Errorf("minimum threshold must be less than maximum threshold")
+		}
 
-	// Ensure at least one threshold value is set
-	if threshold.MinValue == nil && threshold.MaxValue == nil {
-		return fmt.Errorf("at least one threshold value (min or max) must be set")
+		// Ensure at least one threshold value is set
+		if threshold.MinValue == nil && threshold.MaxValue == nil {
+			return fmt.Errorf("at least one threshold value (min or max) must be set")
+		}
 	}
 
 	// Generate new UUID if not set
@@ -61,33 +64,44 @@ func (r *sensorThresholdRepository) Create(ctx context.Context, threshold *entit
 		threshold.CreatedAt = now
 	}
 
-	// Check for existing threshold with same field and severity
+	if threshold.RuleKind == "" {
+		threshold.RuleKind = entity.ThresholdRuleStatic
+	}
+
+	// Check for existing threshold with same field, severity and rule
 	existsQuery := `
 		SELECT EXISTS (
 			SELECT 1 FROM sensor_thresholds 
 			WHERE asset_sensor_id = $1 
 			AND measurement_field_name = $2 
 			AND severity = $3
+			AND rule_kind = $4
+			AND window_seconds = $5
 		)`
 	var exists bool
 	err := r.DB.QueryRowContext(ctx, existsQuery,
 		threshold.AssetSensorID,
 		threshold.MeasurementFieldName,
-		threshold.Severity).Scan(&exists)
+		threshold.Severity,
+		threshold.RuleKind,
+		threshold.WindowSeconds).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check for existing threshold: %w", err)
 	}
 	if exists {
-		return fmt.Errorf("threshold already exists for this field and severity")
+		return fmt.Errorf("threshold already exists for this field, severity and rule")
 	}
 
 	query := `
 		INSERT INTO sensor_thresholds (
 			id, tenant_id, asset_sensor_id, measurement_type_id,
 			measurement_field_name, min_value, max_value, severity,
+			rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			hysteresis, breach_duration_seconds, breach_count,
+			clear_duration_seconds, clear_count,
 			is_active, created_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 		)`
 
 	_, err = r.DB.ExecContext(ctx, query,
@@ -99,6 +113,16 @@ func (r *sensorThresholdRepository) Create(ctx context.Context, threshold *entit
 		threshold.MinValue,
 		threshold.MaxValue,
 		threshold.Severity,
+		threshold.RuleKind,
+		threshold.WindowSeconds,
+		threshold.ExpectedBoolean,
+		pq.Array(threshold.TextValues),
+		threshold.TextPattern,
+		threshold.AlertRules.Hysteresis,
+		threshold.AlertRules.BreachDurationSeconds,
+		threshold.AlertRules.BreachCount,
+		threshold.AlertRules.ClearDurationSeconds,
+		threshold.AlertRules.ClearCount,
 		threshold.IsActive,
 		threshold.CreatedAt,
 	)
@@ -117,6 +141,9 @@ func (r *sensorThresholdRepository) GetByID(ctx context.Context, id uuid.UUID) (
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		WHERE id = $1`
@@ -131,6 +158,16 @@ func (r *sensorThresholdRepository) GetByID(ctx context.Context, id uuid.UUID) (
 		&threshold.MinValue,
 		&threshold.MaxValue,
 		&threshold.Severity,
+		&threshold.RuleKind,
+		&threshold.WindowSeconds,
+		&threshold.ExpectedBoolean,
+		pq.Array(&threshold.TextValues),
+		&threshold.TextPattern,
+		&threshold.AlertRules.Hysteresis,
+		&threshold.AlertRules.BreachDurationSeconds,
+		&threshold.AlertRules.BreachCount,
+		&threshold.AlertRules.ClearDurationSeconds,
+		&threshold.AlertRules.ClearCount,
 		&threshold.IsActive,
 		&threshold.CreatedAt,
 		&threshold.UpdatedAt,
@@ -142,6 +179,7 @@ func (r *sensorThresholdRepository) GetByID(ctx context.Context, id uuid.UUID) (
 		}
 		return nil, fmt.Errorf("failed to get sensor threshold: %w", err)
 	}
+	compileTextPattern(&threshold)
 
 	return &threshold, nil
 }
@@ -151,6 +189,9 @@ func (r *sensorThresholdRepository) GetByTenantID(ctx context.Context, tenantID
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		WHERE tenant_id = $1
@@ -164,6 +205,9 @@ func (r *sensorThresholdRepository) GetByAssetSensorID(ctx context.Context, asse
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		WHERE asset_sensor_id = $1
@@ -177,6 +221,9 @@ func (r *sensorThresholdRepository) GetByMeasurementTypeID(ctx context.Context,
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		WHERE measurement_type_id = $1
@@ -187,14 +234,16 @@ func (r *sensorThresholdRepository) GetByMeasurementTypeID(ctx context.Context,
 
 // Update updates an existing sensor threshold
 func (r *sensorThresholdRepository) Update(ctx context.Context, threshold *entity.SensorThreshold) error {
-	// Validate threshold values
-	if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
-		return fmt.Errorf("minimum threshold must be less than maximum threshold")
-	}
+	// Validate threshold values; boolean and text rules don't use them
+	if !threshold.RuleKind.IsValueMatch() {
+		if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
+			return fmt.Errorf("minimum threshold must be less than maximum threshold")
+		}
 
-	// Ensure at least one threshold value is set
-	if threshold.MinValue == nil && threshold.MaxValue == nil {
-		return fmt.Errorf("at least one threshold value (min or max) must be set")
+		// Ensure at least one threshold value is set
+		if threshold.MinValue == nil && threshold.MaxValue == nil {
+			return fmt.Errorf("at least one threshold value (min or max) must be set")
+		}
 	}
 
 	// Check if threshold exists
@@ -206,27 +255,36 @@ func (r *sensorThresholdRepository) Update(ctx context.Context, threshold *entit
 		return fmt.Errorf("threshold not found")
 	}
 
-	// Check for duplicate threshold with same field and severity
-	if existing.MeasurementFieldName != threshold.MeasurementFieldName || existing.Severity != threshold.Severity {
+	if threshold.RuleKind == "" {
+		threshold.RuleKind = entity.ThresholdRuleStatic
+	}
+
+	// Check for duplicate threshold with same field, severity and rule
+	if existing.MeasurementFieldName != threshold.MeasurementFieldName || existing.Severity != threshold.Severity ||
+		existing.RuleKind != threshold.RuleKind || existing.WindowSeconds != threshold.WindowSeconds {
 		existsQuery := `
 			SELECT EXISTS (
 				SELECT 1 FROM sensor_thresholds 
 				WHERE asset_sensor_id = $1 
 				AND measurement_field_name = $2 
 				AND severity = $3
-				AND id != $4
+				AND rule_kind = $4
+				AND window_seconds = $5
+				AND id != $6
 			)`
 		var exists bool
 		err := r.DB.QueryRowContext(ctx, existsQuery,
 			threshold.AssetSensorID,
 			threshold.MeasurementFieldName,
 			threshold.Severity,
+			threshold.RuleKind,
+			threshold.WindowSeconds,
 			threshold.ID).Scan(&exists)
 		if err != nil {
 			return fmt.Errorf("failed to check for existing threshold: %w", err)
 		}
 		if exists {
-			return fmt.Errorf("threshold already exists for this field and severity")
+			return fmt.Errorf("threshold already exists for this field, severity and rule")
 		}
 	}
 
@@ -239,7 +297,17 @@ func (r *sensorThresholdRepository) Update(ctx context.Context, threshold *entit
 			max_value = $3,
 			severity = $4,
 			is_active = $5,
-			updated_at = $6
+			updated_at = $6,
+			hysteresis = $7,
+			breach_duration_seconds = $8,
+			breach_count = $9,
+			clear_duration_seconds = $10,
+			clear_count = $11,
+			rule_kind = $12,
+			window_seconds = $13,
+			expected_boolean = $14,
+			text_values = $15,
+			text_pattern = $16
 		WHERE id = $1`
 
 	result, err := r.DB.ExecContext(ctx, query,
@@ -249,6 +317,16 @@ func (r *sensorThresholdRepository) Update(ctx context.Context, threshold *entit
 		threshold.Severity,
 		threshold.IsActive,
 		threshold.UpdatedAt,
+		threshold.AlertRules.Hysteresis,
+		threshold.AlertRules.BreachDurationSeconds,
+		threshold.AlertRules.BreachCount,
+		threshold.AlertRules.ClearDurationSeconds,
+		threshold.AlertRules.ClearCount,
+		threshold.RuleKind,
+		threshold.WindowSeconds,
+		threshold.ExpectedBoolean,
+		pq.Array(threshold.TextValues),
+		threshold.TextPattern,
 	)
 
 	if err != nil {
@@ -302,6 +380,9 @@ func (r *sensorThresholdRepository) List(ctx context.Context, tenantID uuid.UUID
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		WHERE tenant_id = $1
@@ -336,6 +417,9 @@ func (r *sensorThresholdRepository) ListAll(ctx context.Context, limit, offset i
 	query := `
 		SELECT id, tenant_id, asset_sensor_id, measurement_type_id,
 			   measurement_field_name, min_value, max_value, severity,
+			   rule_kind, window_seconds, expected_boolean, text_values, text_pattern,
+			   hysteresis, breach_duration_seconds, breach_count,
+			   clear_duration_seconds, clear_count,
 			   is_active, created_at, updated_at
 		FROM sensor_thresholds
 		ORDER BY created_at DESC
@@ -383,6 +467,16 @@ func (r *sensorThresholdRepository) scanRows(rows *sql.Rows) ([]*entity.SensorTh
 			&threshold.MinValue,
 			&threshold.MaxValue,
 			&threshold.Severity,
+			&threshold.RuleKind,
+			&threshold.WindowSeconds,
+			&threshold.ExpectedBoolean,
+			pq.Array(&threshold.TextValues),
+			&threshold.TextPattern,
+			&threshold.AlertRules.Hysteresis,
+			&threshold.AlertRules.BreachDurationSeconds,
+			&threshold.AlertRules.BreachCount,
+			&threshold.AlertRules.ClearDurationSeconds,
+			&threshold.AlertRules.ClearCount,
 			&threshold.IsActive,
 			&threshold.CreatedAt,
 			&threshold.UpdatedAt,
@@ -390,6 +484,7 @@ func (r *sensorThresholdRepository) scanRows(rows *sql.Rows) ([]*entity.SensorTh
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan sensor threshold: %w", err)
 		}
+		compileTextPattern(&threshold)
 		thresholds = append(thresholds, &threshold)
 	}
 
@@ -399,3 +494,11 @@ func (r *sensorThresholdRepository) scanRows(rows *sql.Rows) ([]*entity.SensorTh
 
 	return thresholds, nil
 }
+
+// compileTextPattern compiles the text pattern of a loaded threshold. A pattern stored
+// before validation rejected it never matches, so it is only logged.
+func compileTextPattern(threshold *entity.SensorThreshold) {
+	if err := threshold.CompileTextPattern(); err != nil {
+		log.Printf("Sensor threshold %s has an invalid text pattern: %v", threshold.ID, err)
+	}
+}
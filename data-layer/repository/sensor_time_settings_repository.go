@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SensorTimeSettingsRepository defines the interface for sensor time settings operations
+type SensorTimeSettingsRepository interface {
+	GetByAssetSensorID(ctx context.Context, assetSensorID uuid.UUID) (*entity.SensorTimeSettings, error)
+	Upsert(ctx context.Context, settings *entity.SensorTimeSettings) error
+	Delete(ctx context.Context, assetSensorID uuid.UUID) error
+}
+
+// sensorTimeSettingsRepository handles database operations for sensor time settings
+type sensorTimeSettingsRepository struct {
+	*BaseRepository
+}
+
+// NewSensorTimeSettingsRepository creates a new SensorTimeSettingsRepository
+func NewSensorTimeSettingsRepository(db *sql.DB) SensorTimeSettingsRepository {
+	return &sensorTimeSettingsRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+const sensorTimeSettingsColumns = `
+	asset_sensor_id, tenant_id, timestamp_format, timezone, clock_offset_ms,
+	max_future_skew_seconds, max_age_seconds, skew_action, created_at, updated_at`
+
+// GetByAssetSensorID retrieves the time settings of an asset sensor
+func (r *sensorTimeSettingsRepository) GetByAssetSensorID(ctx context.Context, assetSensorID uuid.UUID) (*entity.SensorTimeSettings, error) {
+	query := `SELECT ` + sensorTimeSettingsColumns + ` FROM sensor_time_settings WHERE asset_sensor_id = $1`
+
+	var settings entity.SensorTimeSettings
+	err := r.DB.QueryRowContext(ctx, query, assetSensorID).Scan(
+		&settings.AssetSensorID,
+		&settings.TenantID,
+		&settings.TimestampFormat,
+		&settings.Timezone,
+		&settings.ClockOffsetMs,
+		&settings.MaxFutureSkewSeconds,
+		&settings.MaxAgeSeconds,
+		&settings.SkewAction,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sensor time settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// Upsert creates or replaces the time settings of an asset sensor
+func (r *sensorTimeSettingsRepository) Upsert(ctx context.Context, settings *entity.SensorTimeSettings) error {
+	now := time.Now()
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	settings.UpdatedAt = &now
+
+	query := `
+		INSERT INTO sensor_time_settings (
+			asset_sensor_id, tenant_id, timestamp_format, timezone, clock_offset_ms,
+			max_future_skew_seconds, max_age_seconds, skew_action, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (asset_sensor_id) DO UPDATE SET
+			timestamp_format = EXCLUDED.timestamp_format,
+			timezone = EXCLUDED.timezone,
+			clock_offset_ms = EXCLUDED.clock_offset_ms,
+			max_future_skew_seconds = EXCLUDED.max_future_skew_seconds,
+			max_age_seconds = EXCLUDED.max_age_seconds,
+			skew_action = EXCLUDED.skew_action,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		settings.AssetSensorID,
+		settings.TenantID,
+		settings.TimestampFormat,
+		settings.Timezone,
+		settings.ClockOffsetMs,
+		settings.MaxFutureSkewSeconds,
+		settings.MaxAgeSeconds,
+		settings.SkewAction,
+		settings.CreatedAt,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sensor time settings: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the time settings of an asset sensor, returning it to the defaults
+func (r *sensorTimeSettingsRepository) Delete(ctx context.Context, assetSensorID uuid.UUID) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM sensor_time_settings WHERE asset_sensor_id = $1`, assetSensorID)
+	if err != nil {
+		return fmt.Errorf("failed to delete sensor time settings: %w", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"be-lecsens/asset_management/data-layer/entity"
 	"database/sql"
 	"fmt"
 	"time"
@@ -16,8 +17,11 @@ type SensorType struct {
 	Model        string
 	Version      string
 	IsActive     bool
-	CreatedAt    time.Time
-	UpdatedAt    *time.Time
+	// IngestionPolicy decides what happens to readings that don't match the sensor
+	// type's measurement fields
+	IngestionPolicy entity.IngestionPolicy
+	CreatedAt       time.Time
+	UpdatedAt       *time.Time
 }
 
 type SensorTypeRepository struct {
@@ -30,15 +34,19 @@ func NewSensorTypeRepository(db *sql.DB) *SensorTypeRepository {
 
 // Create creates a new sensor type
 func (r *SensorTypeRepository) Create(st *SensorType) error {
+	if st.IngestionPolicy == "" {
+		st.IngestionPolicy = entity.DefaultIngestionPolicy
+	}
+
 	query := `
 		INSERT INTO sensor_types (
 			id, name, description, manufacturer, model,
-			version, is_active, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			version, is_active, ingestion_policy, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := r.db.Exec(query,
 		st.ID, st.Name, st.Description, st.Manufacturer, st.Model,
-		st.Version, st.IsActive, st.CreatedAt, st.UpdatedAt,
+		st.Version, st.IsActive, st.IngestionPolicy, st.CreatedAt, st.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("error creating sensor type: %v", err)
@@ -50,14 +58,14 @@ func (r *SensorTypeRepository) Create(st *SensorType) error {
 func (r *SensorTypeRepository) GetByID(id uuid.UUID) (*SensorType, error) {
 	query := `
 		SELECT id, name, description, manufacturer, model,
-			version, is_active, created_at, updated_at
+			version, is_active, ingestion_policy, created_at, updated_at
 		FROM sensor_types
 		WHERE id = $1
 	`
 	st := &SensorType{}
 	err := r.db.QueryRow(query, id).Scan(
 		&st.ID, &st.Name, &st.Description, &st.Manufacturer, &st.Model,
-		&st.Version, &st.IsActive, &st.CreatedAt, &st.UpdatedAt,
+		&st.Version, &st.IsActive, &st.IngestionPolicy, &st.CreatedAt, &st.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -72,7 +80,7 @@ func (r *SensorTypeRepository) GetByID(id uuid.UUID) (*SensorType, error) {
 func (r *SensorTypeRepository) GetAll() ([]*SensorType, error) {
 	query := `
 		SELECT id, name, description, manufacturer, model,
-			version, is_active, created_at, updated_at
+			version, is_active, ingestion_policy, created_at, updated_at
 		FROM sensor_types
 		ORDER BY created_at DESC
 	`
@@ -87,7 +95,7 @@ func (r *SensorTypeRepository) GetAll() ([]*SensorType, error) {
 		st := &SensorType{}
 		err := rows.Scan(
 			&st.ID, &st.Name, &st.Description, &st.Manufacturer, &st.Model,
-			&st.Version, &st.IsActive, &st.CreatedAt, &st.UpdatedAt,
+			&st.Version, &st.IsActive, &st.IngestionPolicy, &st.CreatedAt, &st.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning sensor type: %v", err)
@@ -102,12 +110,12 @@ func (r *SensorTypeRepository) Update(st *SensorType) error {
 	query := `
 		UPDATE sensor_types
 		SET name = $1, description = $2, manufacturer = $3, model = $4,
-			version = $5, is_active = $6, updated_at = $7
-		WHERE id = $8
+			version = $5, is_active = $6, ingestion_policy = $7, updated_at = $8
+		WHERE id = $9
 	`
 	_, err := r.db.Exec(query,
 		st.Name, st.Description, st.Manufacturer, st.Model,
-		st.Version, st.IsActive, time.Now(), st.ID,
+		st.Version, st.IsActive, st.IngestionPolicy, time.Now(), st.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("error updating sensor type: %v", err)
@@ -129,7 +137,7 @@ func (r *SensorTypeRepository) Delete(id uuid.UUID) error {
 func (r *SensorTypeRepository) GetActive() ([]*SensorType, error) {
 	query := `
 		SELECT id, name, description, manufacturer, model,
-			version, is_active, created_at, updated_at
+			version, is_active, ingestion_policy, created_at, updated_at
 		FROM sensor_types
 		WHERE is_active = true
 		ORDER BY created_at DESC
@@ -145,7 +153,7 @@ func (r *SensorTypeRepository) GetActive() ([]*SensorType, error) {
 		st := &SensorType{}
 		err := rows.Scan(
 			&st.ID, &st.Name, &st.Description, &st.Manufacturer, &st.Model,
-			&st.Version, &st.IsActive, &st.CreatedAt, &st.UpdatedAt,
+			&st.Version, &st.IsActive, &st.IngestionPolicy, &st.CreatedAt, &st.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning sensor type: %v", err)
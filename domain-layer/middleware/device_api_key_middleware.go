@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceAPIKeyContextKey is the gin context key holding the authenticated *entity.DeviceAPIKey
+const DeviceAPIKeyContextKey = "device_api_key"
+
+// DeviceAPIKeyMiddleware authenticates devices and gateways with a per-device API key.
+// The key is read from the X-API-Key header or "Authorization: ApiKey {key}".
+// On success the request is scoped to the key's tenant.
+func DeviceAPIKeyMiddleware(deviceAPIKeyService *service.DeviceAPIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+			if len(parts) == 2 && parts[0] == "ApiKey" {
+				rawKey = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if rawKey == "" {
+			log.Printf("Device API Key Middleware: API key is missing for %s %s", c.Request.Method, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, common.ErrorResponse{
+				Error:   "Unauthorized",
+				Message: "X-API-Key header or Authorization: ApiKey {key} is required",
+			})
+			return
+		}
+
+		key, err := deviceAPIKeyService.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidDeviceAPIKey) {
+				log.Printf("Device API Key Middleware: Rejected API key")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, common.ErrorResponse{
+					Error:   "Unauthorized",
+					Message: err.Error(),
+				})
+				return
+			}
+			log.Printf("Device API Key Middleware: Error authenticating API key: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "Failed to authenticate API key",
+			})
+			return
+		}
+
+		ctx := common.WithTenant(c.Request.Context(), key.TenantID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set("tenant_id", key.TenantID.String())
+		c.Set(DeviceAPIKeyContextKey, key)
+
+		log.Printf("Device API Key Middleware: Authenticated key %s for tenant %s", key.KeyPrefix, key.TenantID)
+		c.Next()
+	}
+}
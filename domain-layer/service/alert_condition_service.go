@@ -0,0 +1,381 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AlertConditionService manages composite alert conditions and evaluates them when
+// readings of the fields they use arrive
+type AlertConditionService struct {
+	conditionRepo      repository.AlertConditionRepository
+	assetRepo          repository.AssetRepository
+	assetSensorRepo    repository.AssetSensorRepository
+	readingRepo        repository.IoTSensorReadingRepository
+	assetAlertRepo     repository.AssetAlertRepository
+	alertNotifier      AlertNotifier
+	maintenanceChecker MaintenanceChecker
+}
+
+// NewAlertConditionService creates a new instance of AlertConditionService.
+// alertNotifier and maintenanceChecker may be nil when not needed.
+func NewAlertConditionService(
+	conditionRepo repository.AlertConditionRepository,
+	assetRepo repository.AssetRepository,
+	assetSensorRepo repository.AssetSensorRepository,
+	readingRepo repository.IoTSensorReadingRepository,
+	assetAlertRepo repository.AssetAlertRepository,
+	alertNotifier AlertNotifier,
+	maintenanceChecker MaintenanceChecker,
+) *AlertConditionService {
+	return &AlertConditionService{
+		conditionRepo:      conditionRepo,
+		assetRepo:          assetRepo,
+		assetSensorRepo:    assetSensorRepo,
+		readingRepo:        readingRepo,
+		assetAlertRepo:     assetAlertRepo,
+		alertNotifier:      alertNotifier,
+		maintenanceChecker: maintenanceChecker,
+	}
+}
+
+// CreateCondition creates an alert condition for a tenant
+func (s *AlertConditionService) CreateCondition(ctx context.Context, tenantID uuid.UUID, req dto.AlertConditionRequest) (*entity.AlertCondition, error) {
+	condition := entity.NewAlertCondition()
+	condition.TenantID = tenantID
+	if err := s.applyConditionRequest(ctx, tenantID, condition, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.conditionRepo.Create(ctx, condition); err != nil {
+		log.Printf("Error creating alert condition: %v", err)
+		return nil, fmt.Errorf("failed to create alert condition: %w", err)
+	}
+
+	log.Printf("Created alert condition %s for tenant %s", condition.ID, tenantID)
+	return condition, nil
+}
+
+// GetCondition retrieves an alert condition of a tenant
+func (s *AlertConditionService) GetCondition(ctx context.Context, tenantID, id uuid.UUID) (*entity.AlertCondition, error) {
+	return s.getTenantCondition(ctx, tenantID, id)
+}
+
+// ListConditions lists the alert conditions of a tenant, optionally only those of an asset
+func (s *AlertConditionService) ListConditions(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, page, limit int) (*dto.AlertConditionListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	conditions, totalCount, err := s.conditionRepo.List(ctx, tenantID, assetID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert conditions: %w", err)
+	}
+	if conditions == nil {
+		conditions = []*entity.AlertCondition{}
+	}
+
+	return &dto.AlertConditionListResponse{
+		Data:       conditions,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateCondition updates an alert condition of a tenant
+func (s *AlertConditionService) UpdateCondition(ctx context.Context, tenantID, id uuid.UUID, req dto.AlertConditionRequest) (*entity.AlertCondition, error) {
+	condition, err := s.getTenantCondition(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyConditionRequest(ctx, tenantID, condition, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.conditionRepo.Update(ctx, condition); err != nil {
+		log.Printf("Error updating alert condition: %v", err)
+		return nil, fmt.Errorf("failed to update alert condition: %w", err)
+	}
+
+	return condition, nil
+}
+
+// DeleteCondition deletes an alert condition of a tenant
+func (s *AlertConditionService) DeleteCondition(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantCondition(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := s.conditionRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting alert condition: %v", err)
+		return fmt.Errorf("failed to delete alert condition: %w", err)
+	}
+
+	log.Printf("Deleted alert condition %s for tenant %s", id, tenantID)
+	return nil
+}
+
+// EvaluateReading evaluates the active conditions of the reading's asset that use the
+// reading's field. The other fields take their latest reading no older than the
+// condition's max reading age; a condition with a field that has no such reading is
+// skipped. No alert is opened while the asset is under suppressing maintenance, though open
+// alerts still update and resolve, and alerts opened during a tagging maintenance window are
+// tagged with it and not notified.
+// A condition that fails to evaluate doesn't stop the others; the failures are returned
+// together so the evaluation can be retried.
+func (s *AlertConditionService) EvaluateReading(ctx context.Context, reading *entity.IoTSensorReadingFlexible) error {
+	value := reading.GetValue()
+	if value == nil {
+		return nil
+	}
+
+	assetSensor, err := s.assetSensorRepo.GetByID(ctx, reading.AssetSensorID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset sensor: %w", err)
+	}
+	if assetSensor == nil {
+		return common.NewNotFoundError("asset sensor", reading.AssetSensorID.String())
+	}
+	assetID := assetSensor.AssetID
+
+	conditions, err := s.conditionRepo.GetActiveByAssetID(ctx, assetID)
+	if err != nil {
+		return fmt.Errorf("failed to get alert conditions: %w", err)
+	}
+
+	var maintenance *MaintenanceStatus
+	maintenanceChecked := false
+	var errs []error
+
+	for _, condition := range conditions {
+		expression, err := condition.ParseExpression()
+		if err != nil {
+			log.Printf("Skipping alert condition %s with invalid expression: %v", condition.ID, err)
+			continue
+		}
+
+		refs := expression.References()
+		if !conditionUsesReading(condition, refs, reading) {
+			continue
+		}
+
+		if !maintenanceChecked && s.maintenanceChecker != nil {
+			maintenanceChecked = true
+			// Evaluate normally if maintenance cannot be checked rather than lose alerts
+			maintenance, err = s.maintenanceChecker.CheckMaintenance(ctx, assetID, reading.ReadingTime)
+			if err != nil {
+				log.Printf("Error checking maintenance for asset %s: %v", assetID, err)
+				maintenance = nil
+			}
+		}
+
+		values, complete, err := s.conditionValues(ctx, condition, refs, reading)
+		if err != nil {
+			log.Printf("Error loading values for alert condition %s: %v", condition.ID, err)
+			errs = append(errs, fmt.Errorf("failed to load values for alert condition %s: %w", condition.ID, err))
+			continue
+		}
+		if !complete {
+			continue
+		}
+
+		matched, err := expression.Evaluate(values)
+		if err != nil {
+			log.Printf("Error evaluating alert condition %s: %v", condition.ID, err)
+			errs = append(errs, fmt.Errorf("failed to evaluate alert condition %s: %w", condition.ID, err))
+			continue
+		}
+
+		if err := s.applyEvaluation(ctx, reading, assetID, condition, refs, values, matched, maintenance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyEvaluation records the outcome of a condition and handles the resulting alert. It
+// returns an error when the outcome could not be recorded.
+func (s *AlertConditionService) applyEvaluation(
+	ctx context.Context,
+	reading *entity.IoTSensorReadingFlexible,
+	assetID uuid.UUID,
+	condition *entity.AlertCondition,
+	refs []entity.ConditionReference,
+	values map[entity.ConditionReference]interface{},
+	matched bool,
+	maintenance *MaintenanceStatus,
+) error {
+	var triggerValue float64
+	if reading.NumericValue != nil {
+		triggerValue = *reading.NumericValue
+	}
+
+	iotReading := &entity.IoTSensorReading{
+		ID:            reading.ID,
+		TenantID:      reading.TenantID,
+		AssetSensorID: reading.AssetSensorID,
+		SensorTypeID:  reading.SensorTypeID,
+		ReadingTime:   reading.ReadingTime,
+		CreatedAt:     reading.CreatedAt,
+		UpdatedAt:     reading.UpdatedAt,
+	}
+
+	message := condition.GenerateAlertMessage(refs, values)
+	alert, transition, err := s.assetAlertRepo.ApplyConditionEvaluation(ctx, iotReading, assetID, condition, matched, triggerValue, message, maintenance.alertMaintenance())
+	if err != nil {
+		log.Printf("Error applying alert condition %s: %v", condition.ID, err)
+		return fmt.Errorf("failed to apply alert condition %s: %w", condition.ID, err)
+	}
+	if alert == nil {
+		return nil
+	}
+
+	log.Printf("Alert condition %s on asset %s: %s (alert %s, occurrences %d)",
+		condition.ID, assetID, transition, alert.ID, alert.OccurrenceCount)
+
+	if s.alertNotifier != nil && alert.MaintenanceWindowID == nil {
+		if err := s.alertNotifier.NotifyAlert(ctx, alert, transition); err != nil {
+			log.Printf("Error queueing notifications for alert %s: %v", alert.ID, err)
+		}
+	}
+	return nil
+}
+
+// conditionValues collects the value of every field of a condition, using the reading
+// itself for its own field. complete is false when a field has no recent reading.
+func (s *AlertConditionService) conditionValues(
+	ctx context.Context,
+	condition *entity.AlertCondition,
+	refs []entity.ConditionReference,
+	reading *entity.IoTSensorReadingFlexible,
+) (map[entity.ConditionReference]interface{}, bool, error) {
+	values := make(map[entity.ConditionReference]interface{}, len(refs))
+	from := reading.ReadingTime.Add(-condition.MaxReadingAge())
+
+	for _, ref := range refs {
+		sensorID, _ := condition.ResolveReference(ref)
+		if sensorID == reading.AssetSensorID && ref.Field == reading.MeasurementType {
+			values[ref] = reading.GetValue()
+			continue
+		}
+
+		latest, err := s.readingRepo.GetLatestMeasurement(ctx, sensorID, ref.Field, from, reading.ReadingTime)
+		if err != nil {
+			return nil, false, err
+		}
+		if latest == nil || latest.GetValue() == nil {
+			return nil, false, nil
+		}
+		values[ref] = latest.GetValue()
+	}
+
+	return values, true, nil
+}
+
+// applyConditionRequest validates a condition request and copies it onto the condition.
+// Every referenced sensor must belong to the condition's asset and every referenced field
+// must be defined by the sensor's measurement types, with types that fit the comparisons.
+func (s *AlertConditionService) applyConditionRequest(ctx context.Context, tenantID uuid.UUID, condition *entity.AlertCondition, req dto.AlertConditionRequest) error {
+	asset, err := s.assetRepo.GetByID(ctx, req.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to validate asset: %w", err)
+	}
+	if asset == nil || asset.TenantID == nil || *asset.TenantID != tenantID {
+		return common.NewValidationError("asset not found", nil)
+	}
+
+	condition.AssetID = req.AssetID
+	condition.AssetSensorID = req.AssetSensorID
+	condition.Sensors = map[string]uuid.UUID{}
+	for alias, sensorID := range req.Sensors {
+		condition.Sensors[alias] = sensorID
+	}
+	condition.Name = strings.TrimSpace(req.Name)
+	condition.Description = req.Description
+	condition.Expression = strings.TrimSpace(req.Expression)
+	if req.Severity != "" {
+		condition.Severity = req.Severity
+	}
+	if req.AlertRules != nil {
+		condition.AlertRules = *req.AlertRules
+	}
+	if req.MaxReadingAgeSeconds != nil {
+		condition.MaxReadingAgeSeconds = *req.MaxReadingAgeSeconds
+	}
+	if req.IsActive != nil {
+		condition.IsActive = *req.IsActive
+	}
+
+	expression, err := condition.Validate()
+	if err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+
+	// Load the field definitions of every sensor the condition may reference
+	fieldTypes := make(map[uuid.UUID]map[string]entity.MeasurementDataType)
+	for _, sensorID := range condition.SensorIDs() {
+		sensor, err := s.assetSensorRepo.GetByID(ctx, sensorID)
+		if err != nil {
+			return fmt.Errorf("failed to validate asset sensor: %w", err)
+		}
+		if sensor == nil || sensor.AssetID != condition.AssetID {
+			return common.NewValidationError(fmt.Sprintf("asset sensor %s not found on asset", sensorID), nil)
+		}
+
+		fields := make(map[string]entity.MeasurementDataType)
+		for _, measurementType := range sensor.MeasurementTypes {
+			for _, field := range measurementType.Fields {
+				fields[field.Name] = entity.MeasurementDataType(field.DataType)
+			}
+		}
+		fieldTypes[sensorID] = fields
+	}
+
+	types := make(map[entity.ConditionReference]entity.MeasurementDataType)
+	for _, ref := range expression.References() {
+		sensorID, _ := condition.ResolveReference(ref)
+		dataType, ok := fieldTypes[sensorID][ref.Field]
+		if !ok {
+			return common.NewValidationError(fmt.Sprintf("field %s is not a measurement field of its sensor", ref), nil)
+		}
+		types[ref] = dataType
+	}
+
+	if err := expression.Check(types); err != nil {
+		return common.NewValidationError("invalid expression: "+err.Error(), nil)
+	}
+
+	return nil
+}
+
+// getTenantCondition loads a condition and ensures it belongs to the tenant
+func (s *AlertConditionService) getTenantCondition(ctx context.Context, tenantID, id uuid.UUID) (*entity.AlertCondition, error) {
+	condition, err := s.conditionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert condition: %w", err)
+	}
+	if condition == nil || condition.TenantID != tenantID {
+		return nil, common.NewNotFoundError("alert condition", id.String())
+	}
+
+	return condition, nil
+}
+
+// conditionUsesReading reports whether one of the condition's fields is the reading's field
+func conditionUsesReading(condition *entity.AlertCondition, refs []entity.ConditionReference, reading *entity.IoTSensorReadingFlexible) bool {
+	for _, ref := range refs {
+		sensorID, ok := condition.ResolveReference(ref)
+		if ok && sensorID == reading.AssetSensorID && ref.Field == reading.MeasurementType {
+			return true
+		}
+	}
+	return false
+}
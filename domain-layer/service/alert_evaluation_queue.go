@@ -0,0 +1,274 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReadingEvaluator evaluates the alert rules for a stored reading
+type ReadingEvaluator func(ctx context.Context, reading *entity.IoTSensorReadingFlexible) error
+
+// EvaluationDone is called with the outcome of a reading's evaluation
+type EvaluationDone func(err error)
+
+// AlertEvaluationStats reports the load of the alert evaluation queue
+type AlertEvaluationStats struct {
+	Workers       int    `json:"workers"`
+	Capacity      int    `json:"capacity"`        // Readings the queue holds across all workers
+	Queued        int    `json:"queued"`          // Readings waiting to be evaluated
+	InFlight      int64  `json:"in_flight"`       // Readings being evaluated
+	Enqueued      uint64 `json:"enqueued"`        // Readings accepted since start
+	Processed     uint64 `json:"processed"`       // Readings evaluated since start
+	Failed        uint64 `json:"failed"`          // Evaluations that returned an error or panicked
+	Blocked       uint64 `json:"blocked"`         // Enqueues that had to wait for a full queue
+	Dropped       uint64 `json:"dropped"`         // Readings not evaluated because the queue stayed full or was stopped
+	TimedOut      uint64 `json:"timed_out"`       // Evaluations that ran past the evaluation timeout
+	Running       bool   `json:"running"`         // Whether workers are accepting readings
+	MaxWaitMillis int64  `json:"max_wait_millis"` // Longest time a reading waited in the queue
+}
+
+// AlertEvaluationQueue evaluates readings on a fixed pool of workers. Each worker owns a
+// bounded queue and every asset sensor is always routed to the same worker, so readings
+// of one sensor are evaluated one at a time in the order they were enqueued. Evaluations
+// run with a context detached from the request that stored the reading.
+type AlertEvaluationQueue struct {
+	shards  []chan queuedReading
+	timeout time.Duration
+
+	evaluate ReadingEvaluator
+	mu       sync.RWMutex // guards running and closing the shards
+	running  bool
+	wg       sync.WaitGroup
+	drained  chan struct{}
+
+	inFlight  atomic.Int64
+	enqueued  atomic.Uint64
+	processed atomic.Uint64
+	failed    atomic.Uint64
+	blocked   atomic.Uint64
+	dropped   atomic.Uint64
+	timedOut  atomic.Uint64
+	maxWait   atomic.Int64
+}
+
+// queuedReading is a reading waiting in the queue
+type queuedReading struct {
+	reading    *entity.IoTSensorReadingFlexible
+	done       EvaluationDone // Optional
+	enqueuedAt time.Time
+}
+
+// NewAlertEvaluationQueue creates a queue with the given number of workers, holding up to
+// queueSize readings in total. timeout bounds a single evaluation.
+func NewAlertEvaluationQueue(workers, queueSize int, timeout time.Duration) *AlertEvaluationQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	perWorker := queueSize / workers
+	if perWorker <= 0 {
+		perWorker = 1
+	}
+
+	shards := make([]chan queuedReading, workers)
+	for i := range shards {
+		shards[i] = make(chan queuedReading, perWorker)
+	}
+
+	return &AlertEvaluationQueue{
+		shards:  shards,
+		timeout: timeout,
+	}
+}
+
+// Start starts the workers, which pass every queued reading to evaluate
+func (q *AlertEvaluationQueue) Start(evaluate ReadingEvaluator) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running {
+		return
+	}
+
+	q.evaluate = evaluate
+	q.running = true
+	q.drained = make(chan struct{})
+	for _, shard := range q.shards {
+		q.wg.Add(1)
+		go q.work(shard)
+	}
+	go func() {
+		q.wg.Wait()
+		close(q.drained)
+	}()
+
+	log.Printf("Alert evaluation queue started (%d workers, capacity %d)", len(q.shards), q.capacity())
+}
+
+// Stop stops accepting readings and waits up to drainTimeout for the queued readings to be
+// evaluated. Readings still queued after that are counted as dropped. A stopped queue
+// cannot be started again.
+func (q *AlertEvaluationQueue) Stop(drainTimeout time.Duration) {
+	q.mu.Lock()
+	if !q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = false
+	for _, shard := range q.shards {
+		close(shard)
+	}
+	q.mu.Unlock()
+
+	queued := q.queued()
+	log.Printf("Alert evaluation queue stopping, draining %d queued readings", queued)
+
+	select {
+	case <-q.drained:
+		log.Println("Alert evaluation queue drained")
+	case <-time.After(drainTimeout):
+		remaining := q.queued()
+		q.dropped.Add(uint64(remaining))
+		log.Printf("Alert evaluation queue drain timed out, %d readings not evaluated", remaining)
+	}
+}
+
+// Enqueue queues a reading for evaluation. When the reading's worker queue is full it waits
+// for room until ctx is done, which slows down the producer instead of growing without
+// bound. It returns false when the reading was dropped. done, if set, is called once the
+// reading was evaluated; it is not called for dropped readings.
+func (q *AlertEvaluationQueue) Enqueue(ctx context.Context, reading *entity.IoTSensorReadingFlexible, done EvaluationDone) bool {
+	// Hold the read lock so the shard isn't closed while sending to it
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if !q.running {
+		q.dropped.Add(1)
+		return false
+	}
+
+	item := queuedReading{reading: reading, done: done, enqueuedAt: time.Now()}
+	shard := q.shards[q.shardFor(reading)]
+
+	select {
+	case shard <- item:
+		q.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	q.blocked.Add(1)
+	select {
+	case shard <- item:
+		q.enqueued.Add(1)
+		return true
+	case <-ctx.Done():
+		q.dropped.Add(1)
+		log.Printf("Alert evaluation queue full, dropped reading %s: %v", reading.ID, ctx.Err())
+		return false
+	}
+}
+
+// Stats returns the current load of the queue
+func (q *AlertEvaluationQueue) Stats() AlertEvaluationStats {
+	q.mu.RLock()
+	running := q.running
+	q.mu.RUnlock()
+
+	return AlertEvaluationStats{
+		Workers:       len(q.shards),
+		Capacity:      q.capacity(),
+		Queued:        q.queued(),
+		InFlight:      q.inFlight.Load(),
+		Enqueued:      q.enqueued.Load(),
+		Processed:     q.processed.Load(),
+		Failed:        q.failed.Load(),
+		Blocked:       q.blocked.Load(),
+		Dropped:       q.dropped.Load(),
+		TimedOut:      q.timedOut.Load(),
+		Running:       running,
+		MaxWaitMillis: q.maxWait.Load(),
+	}
+}
+
+// work evaluates the readings of one shard until it is closed and empty
+func (q *AlertEvaluationQueue) work(shard chan queuedReading) {
+	defer q.wg.Done()
+	for item := range shard {
+		q.recordWait(time.Since(item.enqueuedAt))
+		err := q.process(item.reading)
+		if item.done != nil {
+			item.done(err)
+		}
+	}
+}
+
+// process evaluates a single reading with a detached, bounded context
+func (q *AlertEvaluationQueue) process(reading *entity.IoTSensorReadingFlexible) (err error) {
+	q.inFlight.Add(1)
+	defer q.inFlight.Add(-1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			q.failed.Add(1)
+			log.Printf("Alert evaluation of reading %s panicked: %v", reading.ID, r)
+			err = fmt.Errorf("alert evaluation panicked: %v", r)
+		}
+	}()
+
+	err = q.evaluate(ctx, reading)
+	q.processed.Add(1)
+	if ctx.Err() == context.DeadlineExceeded {
+		q.timedOut.Add(1)
+		log.Printf("Alert evaluation of reading %s timed out after %s", reading.ID, q.timeout)
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		q.failed.Add(1)
+	}
+	return err
+}
+
+// recordWait keeps the longest time a reading waited in the queue
+func (q *AlertEvaluationQueue) recordWait(wait time.Duration) {
+	millis := wait.Milliseconds()
+	for {
+		current := q.maxWait.Load()
+		if millis <= current || q.maxWait.CompareAndSwap(current, millis) {
+			return
+		}
+	}
+}
+
+// shardFor routes all readings of an asset sensor to the same worker
+func (q *AlertEvaluationQueue) shardFor(reading *entity.IoTSensorReadingFlexible) int {
+	h := fnv.New32a()
+	h.Write(reading.AssetSensorID[:])
+	return int(h.Sum32() % uint32(len(q.shards)))
+}
+
+// queued returns the number of readings waiting in the queue
+func (q *AlertEvaluationQueue) queued() int {
+	total := 0
+	for _, shard := range q.shards {
+		total += len(shard)
+	}
+	return total
+}
+
+// capacity returns the number of readings the queue holds
+func (q *AlertEvaluationQueue) capacity() int {
+	total := 0
+	for _, shard := range q.shards {
+		total += cap(shard)
+	}
+	return total
+}
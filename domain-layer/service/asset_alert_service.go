@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -18,6 +19,7 @@ type AssetAlertService struct {
 	assetAlertRepo  repository.AssetAlertRepository
 	assetRepo       repository.AssetRepository
 	assetSensorRepo repository.AssetSensorRepository
+	escalationRepo  repository.AssetAlertEscalationRepository
 }
 
 // NewAssetAlertService creates a new instance of AssetAlertService
@@ -25,11 +27,13 @@ func NewAssetAlertService(
 	assetAlertRepo repository.AssetAlertRepository,
 	assetRepo repository.AssetRepository,
 	assetSensorRepo repository.AssetSensorRepository,
+	escalationRepo repository.AssetAlertEscalationRepository,
 ) *AssetAlertService {
 	return &AssetAlertService{
 		assetAlertRepo:  assetAlertRepo,
 		assetRepo:       assetRepo,
 		assetSensorRepo: assetSensorRepo,
+		escalationRepo:  escalationRepo,
 	}
 }
 
@@ -80,7 +84,14 @@ func (s *AssetAlertService) GetAssetAlertByID(ctx context.Context, id uuid.UUID)
 		return nil, common.NewNotFoundError("asset alert", id.String())
 	}
 
-	return s.toResponseDTO(alert), nil
+	escalations, err := s.escalationRepo.ListByAlert(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert escalations: %w", err)
+	}
+
+	response := s.toResponseDTO(alert)
+	response.Escalations = escalations
+	return response, nil
 }
 
 // GetAssetAlertsByTenant retrieves all asset alerts for a tenant
@@ -150,7 +161,7 @@ func (s *AssetAlertService) ResolveAlert(ctx context.Context, id uuid.UUID) (*en
 	}
 
 	// Resolve the alert
-	if err := s.assetAlertRepo.ResolveAlert(ctx, id); err != nil {
+	if err := s.assetAlertRepo.ResolveAlert(ctx, id, entity.AlertResolution{Code: entity.AlertResolutionOther}); err != nil {
 		log.Printf("Error resolving asset alert: %v", err)
 		return nil, fmt.Errorf("failed to resolve asset alert: %w", err)
 	}
@@ -177,7 +188,7 @@ func (s *AssetAlertService) ResolveAlertsByAssetSensor(ctx context.Context, asse
 
 	resolvedCount := 0
 	for _, alert := range activeAlerts {
-		if err := s.assetAlertRepo.ResolveAlert(ctx, alert.ID); err != nil {
+		if err := s.assetAlertRepo.ResolveAlert(ctx, alert.ID, entity.AlertResolution{Code: entity.AlertResolutionOther}); err != nil {
 			log.Printf("Failed to resolve alert %s: %v", alert.ID, err)
 			continue
 		}
@@ -272,6 +283,11 @@ func (s *AssetAlertService) GetAlertStatistics(ctx context.Context, tenantID uui
 		WarningAlerts:  stats["warning_alerts"].(int),
 		Alerts24h:      stats["alerts_24h"].(int),
 		Alerts7d:       stats["alerts_7d"].(int),
+
+		AcknowledgedAlerts: stats["acknowledged_alerts"].(int),
+		UnassignedAlerts:   stats["unassigned_alerts"].(int),
+		MTTASeconds:        stats["mtta_seconds"].(*float64),
+		MTTRSeconds:        stats["mttr_seconds"].(*float64),
 	}, nil
 }
 
@@ -313,7 +329,7 @@ func (s *AssetAlertService) GetRecentAlerts(ctx context.Context, tenantID uuid.U
 func (s *AssetAlertService) BulkResolveAlerts(ctx context.Context, alertIDs []uuid.UUID) (int, error) {
 	resolvedCount := 0
 	for _, id := range alertIDs {
-		if err := s.assetAlertRepo.ResolveAlert(ctx, id); err != nil {
+		if err := s.assetAlertRepo.ResolveAlert(ctx, id, entity.AlertResolution{Code: entity.AlertResolutionOther}); err != nil {
 			log.Printf("Failed to resolve alert %s: %v", id, err)
 			continue
 		}
@@ -376,10 +392,16 @@ func (s *AssetAlertService) ListAssetAlerts(ctx context.Context, tenantID uuid.U
 	}, nil
 }
 
-// ResolveAssetAlert resolves an asset alert
-func (s *AssetAlertService) ResolveAssetAlert(ctx context.Context, id uuid.UUID) (*dto.AssetAlertResponse, error) {
+// ResolveAssetAlert resolves an asset alert, recording who resolved it and why.
+// userID may be nil when the caller is not identified.
+func (s *AssetAlertService) ResolveAssetAlert(ctx context.Context, id uuid.UUID, userID *uuid.UUID, request dto.ResolveAlertRequest) (*dto.AssetAlertResponse, error) {
 	log.Printf("Resolving asset alert with ID: %s", id)
 
+	resolution, err := buildResolution(userID, request.ResolutionCode, request.Note)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if alert exists and is not already resolved
 	alert, err := s.assetAlertRepo.GetByID(ctx, id)
 	if err != nil {
@@ -393,7 +415,7 @@ func (s *AssetAlertService) ResolveAssetAlert(ctx context.Context, id uuid.UUID)
 	}
 
 	// Resolve the alert
-	if err := s.assetAlertRepo.ResolveAlert(ctx, id); err != nil {
+	if err := s.assetAlertRepo.ResolveAlert(ctx, id, resolution); err != nil {
 		log.Printf("Error resolving asset alert: %v", err)
 		return nil, fmt.Errorf("failed to resolve asset alert: %w", err)
 	}
@@ -404,13 +426,18 @@ func (s *AssetAlertService) ResolveAssetAlert(ctx context.Context, id uuid.UUID)
 		return nil, fmt.Errorf("failed to get resolved alert: %w", err)
 	}
 
-	log.Printf("Successfully resolved asset alert with ID: %s", id)
+	log.Printf("Successfully resolved asset alert with ID: %s (%s)", id, resolution.Code)
 	return s.toResponseDTO(resolvedAlert), nil
 }
 
 // ResolveMultipleAssetAlerts resolves multiple asset alerts
-func (s *AssetAlertService) ResolveMultipleAssetAlerts(ctx context.Context, request dto.ResolveMultipleAlertsRequest) (*dto.ResolveMultipleAlertsResponse, error) {
-	successCount, failureCount, err := s.assetAlertRepo.ResolveMultipleAlerts(ctx, request.AlertIDs)
+func (s *AssetAlertService) ResolveMultipleAssetAlerts(ctx context.Context, userID *uuid.UUID, request dto.ResolveMultipleAlertsRequest) (*dto.ResolveMultipleAlertsResponse, error) {
+	resolution, err := buildResolution(userID, request.ResolutionCode, request.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	successCount, failureCount, err := s.assetAlertRepo.ResolveMultipleAlerts(ctx, request.AlertIDs, resolution)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve multiple alerts: %w", err)
 	}
@@ -422,6 +449,96 @@ func (s *AssetAlertService) ResolveMultipleAssetAlerts(ctx context.Context, requ
 	}, nil
 }
 
+// AcknowledgeAssetAlert marks an open alert of the tenant as acknowledged by the user
+func (s *AssetAlertService) AcknowledgeAssetAlert(ctx context.Context, tenantID, id, userID uuid.UUID, request dto.AcknowledgeAlertRequest) (*dto.AssetAlertResponse, error) {
+	alert, err := s.getTenantAlert(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.IsResolved {
+		return nil, common.NewValidationError("alert is already resolved", nil)
+	}
+	if alert.AcknowledgedAt != nil {
+		return nil, common.NewValidationError("alert is already acknowledged", nil)
+	}
+
+	if err := s.assetAlertRepo.AcknowledgeAlert(ctx, id, userID, trimOptional(request.Comment)); err != nil {
+		log.Printf("Error acknowledging asset alert: %v", err)
+		return nil, fmt.Errorf("failed to acknowledge asset alert: %w", err)
+	}
+
+	log.Printf("Asset alert %s acknowledged by user %s", id, userID)
+	return s.GetAssetAlertByID(ctx, id)
+}
+
+// AssignAssetAlert assigns an open alert of the tenant to a user, or clears the assignment
+func (s *AssetAlertService) AssignAssetAlert(ctx context.Context, tenantID, id, userID uuid.UUID, request dto.AssignAlertRequest) (*dto.AssetAlertResponse, error) {
+	alert, err := s.getTenantAlert(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.IsResolved {
+		return nil, common.NewValidationError("resolved alerts cannot be assigned", nil)
+	}
+	if request.AssignedTo != nil && *request.AssignedTo == uuid.Nil {
+		return nil, common.NewValidationError("assigned_to must be a valid user ID", nil)
+	}
+
+	if err := s.assetAlertRepo.AssignAlert(ctx, id, request.AssignedTo, userID, trimOptional(request.Comment)); err != nil {
+		log.Printf("Error assigning asset alert: %v", err)
+		return nil, fmt.Errorf("failed to assign asset alert: %w", err)
+	}
+
+	if request.AssignedTo != nil {
+		log.Printf("Asset alert %s assigned to user %s by user %s", id, *request.AssignedTo, userID)
+	} else {
+		log.Printf("Asset alert %s unassigned by user %s", id, userID)
+	}
+	return s.GetAssetAlertByID(ctx, id)
+}
+
+// AddAlertComment adds a comment to the timeline of an alert of the tenant
+func (s *AssetAlertService) AddAlertComment(ctx context.Context, tenantID, id, userID uuid.UUID, request dto.AlertCommentRequest) (*entity.AssetAlertEvent, error) {
+	comment := strings.TrimSpace(request.Comment)
+	if comment == "" {
+		return nil, common.NewValidationError("comment is required", nil)
+	}
+
+	alert, err := s.getTenantAlert(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := entity.NewAssetAlertEvent(alert, entity.AssetAlertEventComment, &userID)
+	event.Comment = &comment
+	if err := s.assetAlertRepo.CreateAlertEvent(ctx, event); err != nil {
+		log.Printf("Error adding comment to asset alert: %v", err)
+		return nil, fmt.Errorf("failed to add alert comment: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetAlertTimeline retrieves the timeline of an alert of the tenant
+func (s *AssetAlertService) GetAlertTimeline(ctx context.Context, tenantID, id uuid.UUID) (*dto.AssetAlertTimelineResponse, error) {
+	if _, err := s.getTenantAlert(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.assetAlertRepo.GetAlertEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert timeline: %w", err)
+	}
+	if events == nil {
+		events = []*entity.AssetAlertEvent{}
+	}
+
+	return &dto.AssetAlertTimelineResponse{
+		AlertID: id,
+		Events:  events,
+	}, nil
+}
+
 // DeleteAssetAlert deletes an asset alert
 func (s *AssetAlertService) DeleteAssetAlert(ctx context.Context, id uuid.UUID) error {
 	log.Printf("Deleting asset alert with ID: %s", id)
@@ -502,11 +619,58 @@ func (s *AssetAlertService) GetGlobalAlertStatistics(ctx context.Context) (*dto.
 		Alerts24h:      stats["alerts_24h"].(int),
 		Alerts7d:       stats["alerts_7d"].(int),
 		TotalTenants:   stats["total_tenants"].(int),
+
+		AcknowledgedAlerts: stats["acknowledged_alerts"].(int),
+		UnassignedAlerts:   stats["unassigned_alerts"].(int),
+		MTTASeconds:        stats["mtta_seconds"].(*float64),
+		MTTRSeconds:        stats["mttr_seconds"].(*float64),
 	}, nil
 }
 
 // Helper methods
 
+// getTenantAlert loads an alert and ensures it belongs to the tenant
+func (s *AssetAlertService) getTenantAlert(ctx context.Context, tenantID, id uuid.UUID) (*entity.AssetAlert, error) {
+	alert, err := s.assetAlertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset alert: %w", err)
+	}
+	if alert == nil || alert.TenantID != tenantID {
+		return nil, common.NewNotFoundError("asset alert", id.String())
+	}
+
+	return alert, nil
+}
+
+// buildResolution validates a user supplied resolution code, defaulting to "other"
+func buildResolution(userID *uuid.UUID, code entity.AlertResolutionCode, note *string) (entity.AlertResolution, error) {
+	if code == "" {
+		code = entity.AlertResolutionOther
+	}
+	if !code.IsManual() {
+		return entity.AlertResolution{}, common.NewValidationError(
+			fmt.Sprintf("invalid resolution_code %q (allowed: fixed, false_positive, expected_behavior, duplicate, other)", code), nil)
+	}
+
+	return entity.AlertResolution{
+		Code:       code,
+		Note:       trimOptional(note),
+		ResolvedBy: userID,
+	}, nil
+}
+
+// trimOptional trims an optional text value, returning nil when it is empty
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
 // toResponseDTO converts entity to response DTO
 func (s *AssetAlertService) toResponseDTO(alert *entity.AssetAlert) *dto.AssetAlertResponse {
 	return &dto.AssetAlertResponse{
@@ -515,6 +679,7 @@ func (s *AssetAlertService) toResponseDTO(alert *entity.AssetAlert) *dto.AssetAl
 		AssetID:              alert.AssetID,
 		AssetSensorID:        alert.AssetSensorID,
 		ThresholdID:          alert.ThresholdID,
+		ConditionID:          alert.ConditionID,
 		MeasurementFieldName: alert.MeasurementFieldName,
 		AlertTime:            alert.AlertTime,
 		ResolvedTime:         alert.ResolvedTime,
@@ -525,6 +690,19 @@ func (s *AssetAlertService) toResponseDTO(alert *entity.AssetAlert) *dto.AssetAl
 		AlertMessage:         alert.AlertMessage,
 		AlertType:            alert.AlertType,
 		IsResolved:           alert.IsResolved,
+		LastTriggerValue:     alert.LastTriggerValue,
+		PeakTriggerValue:     alert.PeakTriggerValue,
+		OccurrenceCount:      alert.OccurrenceCount,
+		LastTriggeredAt:      alert.LastTriggeredAt,
+		State:                alert.State(),
+		AcknowledgedAt:       alert.AcknowledgedAt,
+		AcknowledgedBy:       alert.AcknowledgedBy,
+		AssignedTo:           alert.AssignedTo,
+		AssignedAt:           alert.AssignedAt,
+		ResolvedBy:           alert.ResolvedBy,
+		ResolutionCode:       alert.ResolutionCode,
+		ResolutionNote:       alert.ResolutionNote,
+		MaintenanceWindowID:  alert.MaintenanceWindowID,
 		CreatedAt:            alert.CreatedAt,
 		UpdatedAt:            alert.UpdatedAt,
 	}
@@ -260,24 +260,33 @@ func (s *AssetSensorService) DeleteAssetSensors(ctx context.Context, assetID uui
 	return nil
 }
 
-// UpdateSensorReading updates the sensor's reading values
-func (s *AssetSensorService) UpdateSensorReading(ctx context.Context, id uuid.UUID, req *dto.UpdateSensorReadingRequest) error {
+// UpdateSensorReading updates the sensor's reading values. It reports false when the
+// reading is older than the sensor's last reading, which is then kept.
+func (s *AssetSensorService) UpdateSensorReading(ctx context.Context, id uuid.UUID, req *dto.UpdateSensorReadingRequest) (bool, error) {
 	// Validate sensor exists and user has access
 	sensor, err := s.assetSensorRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get asset sensor: %w", err)
+		return false, fmt.Errorf("failed to get asset sensor: %w", err)
 	}
 	if sensor == nil {
-		return common.NewNotFoundError("asset sensor", id.String())
+		return false, common.NewNotFoundError("asset sensor", id.String())
+	}
+
+	readingTime := time.Now()
+	if req.ReadingTime != nil {
+		readingTime = *req.ReadingTime
 	}
 
 	// Update reading in repository
-	err = s.assetSensorRepo.UpdateLastReading(ctx, id, req.Value, req.Readings)
+	updated, err := s.assetSensorRepo.UpdateLastReading(ctx, id, req.Value, req.Readings, readingTime)
 	if err != nil {
-		return fmt.Errorf("failed to update sensor reading: %w", err)
+		return false, fmt.Errorf("failed to update sensor reading: %w", err)
+	}
+	if !updated {
+		log.Printf("Late reading for asset sensor %s at %s kept the newer last reading", id, readingTime.Format(time.RFC3339))
 	}
 
-	return nil
+	return updated, nil
 }
 
 // GetActiveSensors retrieves all active sensors
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CSVMappingProfileService manages the saved column mappings of CSV reading imports and
+// imports CSV files with them
+type CSVMappingProfileService struct {
+	profileRepo             repository.CSVMappingProfileRepository
+	sensorTypeRepo          *repository.SensorTypeRepository
+	measurementTypeRepo     repository.SensorMeasurementTypeRepository
+	measurementFieldRepo    *repository.SensorMeasurementFieldRepository
+	iotSensorReadingService *IoTSensorReadingService
+}
+
+// NewCSVMappingProfileService creates a new instance of CSVMappingProfileService
+func NewCSVMappingProfileService(
+	profileRepo repository.CSVMappingProfileRepository,
+	sensorTypeRepo *repository.SensorTypeRepository,
+	measurementTypeRepo repository.SensorMeasurementTypeRepository,
+	measurementFieldRepo *repository.SensorMeasurementFieldRepository,
+	iotSensorReadingService *IoTSensorReadingService,
+) *CSVMappingProfileService {
+	return &CSVMappingProfileService{
+		profileRepo:             profileRepo,
+		sensorTypeRepo:          sensorTypeRepo,
+		measurementTypeRepo:     measurementTypeRepo,
+		measurementFieldRepo:    measurementFieldRepo,
+		iotSensorReadingService: iotSensorReadingService,
+	}
+}
+
+// CreateProfile creates a CSV mapping profile for a sensor type
+func (s *CSVMappingProfileService) CreateProfile(ctx context.Context, req dto.CSVMappingProfileRequest) (*entity.CSVMappingProfile, error) {
+	profile := entity.NewCSVMappingProfile()
+	profile.SensorTypeID = req.SensorTypeID
+	if err := s.applyProfileRequest(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Create(ctx, profile); err != nil {
+		log.Printf("Error creating CSV mapping profile: %v", err)
+		return nil, fmt.Errorf("failed to create CSV mapping profile: %w", err)
+	}
+
+	log.Printf("Created CSV mapping profile %s (%d columns) for sensor type %s", profile.ID, len(profile.Columns), profile.SensorTypeID)
+	return profile, nil
+}
+
+// GetProfile retrieves a CSV mapping profile
+func (s *CSVMappingProfileService) GetProfile(ctx context.Context, id uuid.UUID) (*entity.CSVMappingProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV mapping profile: %w", err)
+	}
+	if profile == nil {
+		return nil, common.NewNotFoundError("CSV mapping profile", id.String())
+	}
+
+	return profile, nil
+}
+
+// ListProfiles lists CSV mapping profiles, optionally of one sensor type
+func (s *CSVMappingProfileService) ListProfiles(ctx context.Context, sensorTypeID *uuid.UUID, page, limit int) (*dto.CSVMappingProfileListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	profiles, totalCount, err := s.profileRepo.List(ctx, sensorTypeID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSV mapping profiles: %w", err)
+	}
+	if profiles == nil {
+		profiles = []*entity.CSVMappingProfile{}
+	}
+
+	return &dto.CSVMappingProfileListResponse{
+		Data:       profiles,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateProfile updates a CSV mapping profile. Its sensor type can't change.
+func (s *CSVMappingProfileService) UpdateProfile(ctx context.Context, id uuid.UUID, req dto.CSVMappingProfileRequest) (*entity.CSVMappingProfile, error) {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.SensorTypeID != profile.SensorTypeID {
+		return nil, common.NewValidationError("the sensor type of a CSV mapping profile can't be changed", nil)
+	}
+
+	if err := s.applyProfileRequest(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		log.Printf("Error updating CSV mapping profile: %v", err)
+		return nil, fmt.Errorf("failed to update CSV mapping profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// DeleteProfile deletes a CSV mapping profile
+func (s *CSVMappingProfileService) DeleteProfile(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetProfile(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.profileRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting CSV mapping profile: %v", err)
+		return fmt.Errorf("failed to delete CSV mapping profile: %w", err)
+	}
+
+	log.Printf("Deleted CSV mapping profile %s", id)
+	return nil
+}
+
+// Import streams a CSV body into readings using a profile's column mapping. With
+// opts.DryRun the body is only validated and previewed.
+func (s *CSVMappingProfileService) Import(ctx context.Context, id uuid.UUID, body io.Reader, opts ReadingStreamOptions) (*dto.ReadingStreamResponse, error) {
+	profile, err := s.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile.AssetSensorColumn == nil && opts.AssetSensorID == uuid.Nil {
+		return nil, common.NewValidationError(fmt.Sprintf("profile %s has no asset sensor column, asset_sensor_id is required", profile.Name), nil)
+	}
+
+	opts.Format = ReadingStreamCSV
+	opts.Profile = profile
+	return s.iotSensorReadingService.IngestReadingStream(ctx, body, opts)
+}
+
+// applyProfileRequest validates a request and applies it to a profile
+func (s *CSVMappingProfileService) applyProfileRequest(ctx context.Context, profile *entity.CSVMappingProfile, req dto.CSVMappingProfileRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return common.NewValidationError("name is required", nil)
+	}
+
+	profile.Name = req.Name
+	profile.Description = req.Description
+	profile.TimestampColumn = req.TimestampColumn
+	profile.AssetSensorColumn = req.AssetSensorColumn
+	profile.Columns = req.Columns
+	if req.Delimiter != "" {
+		profile.Delimiter = req.Delimiter
+	}
+	if req.TimestampFormat != "" {
+		profile.TimestampFormat = req.TimestampFormat
+	}
+	if req.Timezone != "" {
+		profile.Timezone = req.Timezone
+	}
+
+	if err := profile.Validate(); err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+
+	return s.validateProfileFields(ctx, profile)
+}
+
+// validateProfileFields checks that every mapped field is a storable field of the
+// profile's sensor type
+func (s *CSVMappingProfileService) validateProfileFields(ctx context.Context, profile *entity.CSVMappingProfile) error {
+	sensorType, err := s.sensorTypeRepo.GetByID(profile.SensorTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get sensor type: %w", err)
+	}
+	if sensorType == nil {
+		return common.NewValidationError("sensor type not found", nil)
+	}
+
+	dataTypes, err := sensorTypeFieldDataTypes(ctx, s.measurementTypeRepo, s.measurementFieldRepo, profile.SensorTypeID)
+	if err != nil {
+		return err
+	}
+
+	for _, mapping := range profile.Columns {
+		dataType, ok := dataTypes[mapping.Field]
+		if !ok {
+			return common.NewValidationError(fmt.Sprintf("sensor type %s has no measurement field %s", sensorType.Name, mapping.Field), nil)
+		}
+		switch dataType {
+		case entity.MeasurementDataTypeNumber, entity.MeasurementDataTypeBoolean, entity.MeasurementDataTypeString:
+		default:
+			return common.NewValidationError(fmt.Sprintf("field %s is a %s field, which can't be imported from CSV", mapping.Field, dataType), nil)
+		}
+	}
+
+	return nil
+}
+
+// sensorTypeFieldDataTypes returns the data type of every field of a sensor type's active
+// measurement types, keyed by field name
+func sensorTypeFieldDataTypes(
+	ctx context.Context,
+	measurementTypeRepo repository.SensorMeasurementTypeRepository,
+	measurementFieldRepo *repository.SensorMeasurementFieldRepository,
+	sensorTypeID uuid.UUID,
+) (map[string]entity.MeasurementDataType, error) {
+	measurementTypes, err := measurementTypeRepo.GetBySensorTypeID(ctx, sensorTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurement types: %w", err)
+	}
+
+	dataTypes := make(map[string]entity.MeasurementDataType)
+	for _, measurementType := range measurementTypes {
+		if !measurementType.IsActive {
+			continue
+		}
+		fields, err := measurementFieldRepo.GetByMeasurementTypeID(ctx, measurementType.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get measurement fields: %w", err)
+		}
+		for _, field := range fields {
+			dataTypes[field.Name] = entity.MeasurementDataType(field.DataType)
+		}
+	}
+
+	return dataTypes, nil
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// deviceAPIKeyScheme is the fixed leading segment of every device API key:
+// lsk_<prefix>_<secret>
+const deviceAPIKeyScheme = "lsk"
+
+// ErrInvalidDeviceAPIKey is returned when a device API key is unknown, malformed,
+// revoked or expired
+var ErrInvalidDeviceAPIKey = errors.New("invalid or expired device api key")
+
+// DeviceAPIKeyService handles business logic for device API keys
+type DeviceAPIKeyService struct {
+	deviceAPIKeyRepo repository.DeviceAPIKeyRepository
+	assetSensorRepo  repository.AssetSensorRepository
+}
+
+// NewDeviceAPIKeyService creates a new instance of DeviceAPIKeyService
+func NewDeviceAPIKeyService(
+	deviceAPIKeyRepo repository.DeviceAPIKeyRepository,
+	assetSensorRepo repository.AssetSensorRepository,
+) *DeviceAPIKeyService {
+	return &DeviceAPIKeyService{
+		deviceAPIKeyRepo: deviceAPIKeyRepo,
+		assetSensorRepo:  assetSensorRepo,
+	}
+}
+
+// IssueKey creates a new device API key and returns its plaintext value once
+func (s *DeviceAPIKeyService) IssueKey(ctx context.Context, tenantID uuid.UUID, req dto.CreateDeviceAPIKeyRequest) (*dto.DeviceAPIKeySecretResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, common.NewValidationError("name is required", nil)
+	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		return nil, common.NewValidationError("expires_at must be in the future", nil)
+	}
+
+	key := entity.NewDeviceAPIKey()
+	key.TenantID = tenantID
+	key.Name = req.Name
+	key.ExpiresAt = req.ExpiresAt
+
+	if req.AssetSensorID != nil {
+		assetSensor, err := s.assetSensorRepo.GetByID(ctx, *req.AssetSensorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate asset sensor: %w", err)
+		}
+		if assetSensor == nil {
+			return nil, common.NewValidationError("asset sensor not found", nil)
+		}
+		if assetSensor.TenantID == nil || *assetSensor.TenantID != tenantID {
+			return nil, common.NewValidationError("asset sensor does not belong to this tenant", nil)
+		}
+		key.AssetSensorID = req.AssetSensorID
+	}
+
+	plainKey, prefix, hash, err := generateDeviceAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.KeyPrefix = prefix
+	key.KeyHash = hash
+
+	if err := s.deviceAPIKeyRepo.Create(ctx, key); err != nil {
+		log.Printf("Error creating device API key: %v", err)
+		return nil, fmt.Errorf("failed to create device api key: %w", err)
+	}
+
+	log.Printf("Issued device API key %s (prefix %s) for tenant %s", key.ID, key.KeyPrefix, tenantID)
+	return &dto.DeviceAPIKeySecretResponse{
+		DeviceAPIKeyResponse: dto.DeviceAPIKeyFromEntity(key),
+		APIKey:               plainKey,
+	}, nil
+}
+
+// GetKey retrieves a device API key belonging to the tenant
+func (s *DeviceAPIKeyService) GetKey(ctx context.Context, tenantID, id uuid.UUID) (*dto.DeviceAPIKeyResponse, error) {
+	key, err := s.getTenantKey(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.DeviceAPIKeyFromEntity(key)
+	return &response, nil
+}
+
+// ListKeys retrieves paginated device API keys for a tenant
+func (s *DeviceAPIKeyService) ListKeys(ctx context.Context, tenantID uuid.UUID, assetSensorID *uuid.UUID, page, limit int) (*dto.DeviceAPIKeyListResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	keys, totalCount, err := s.deviceAPIKeyRepo.List(ctx, tenantID, assetSensorID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device api keys: %w", err)
+	}
+
+	data := make([]dto.DeviceAPIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, dto.DeviceAPIKeyFromEntity(key))
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(limit)))
+	return &dto.DeviceAPIKeyListResponse{
+		Data: data,
+		Pagination: dto.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			TotalItems:  int64(totalCount),
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+// RotateKey replaces the secret of an existing key; the old key stops working immediately
+func (s *DeviceAPIKeyService) RotateKey(ctx context.Context, tenantID, id uuid.UUID) (*dto.DeviceAPIKeySecretResponse, error) {
+	key, err := s.getTenantKey(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, common.NewValidationError("revoked keys cannot be rotated", nil)
+	}
+
+	plainKey, prefix, hash, err := generateDeviceAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deviceAPIKeyRepo.UpdateSecret(ctx, id, prefix, hash); err != nil {
+		log.Printf("Error rotating device API key: %v", err)
+		return nil, fmt.Errorf("failed to rotate device api key: %w", err)
+	}
+
+	rotated, err := s.deviceAPIKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotated device api key: %w", err)
+	}
+
+	log.Printf("Rotated device API key %s (new prefix %s)", id, prefix)
+	return &dto.DeviceAPIKeySecretResponse{
+		DeviceAPIKeyResponse: dto.DeviceAPIKeyFromEntity(rotated),
+		APIKey:               plainKey,
+	}, nil
+}
+
+// RevokeKey permanently disables a device API key
+func (s *DeviceAPIKeyService) RevokeKey(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantKey(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := s.deviceAPIKeyRepo.Revoke(ctx, id); err != nil {
+		log.Printf("Error revoking device API key: %v", err)
+		return fmt.Errorf("failed to revoke device api key: %w", err)
+	}
+
+	log.Printf("Revoked device API key %s", id)
+	return nil
+}
+
+// Authenticate validates a plaintext device API key and returns the stored key
+func (s *DeviceAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*entity.DeviceAPIKey, error) {
+	prefix, ok := parseDeviceAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, ErrInvalidDeviceAPIKey
+	}
+
+	key, err := s.deviceAPIKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up device api key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrInvalidDeviceAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashDeviceAPIKey(rawKey)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidDeviceAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, ErrInvalidDeviceAPIKey
+	}
+
+	if err := s.deviceAPIKeyRepo.UpdateLastUsed(ctx, key.ID, now); err != nil {
+		// Not fatal for the request
+		log.Printf("Warning: %v", err)
+	}
+
+	return key, nil
+}
+
+// ScopeReadingRequest binds a flexible reading request to what the key is allowed
+// to write: sensor keys always write to their own sensor, gateway keys may write
+// to any sensor in their tenant. The sensor type is taken from the asset sensor.
+func (s *DeviceAPIKeyService) ScopeReadingRequest(ctx context.Context, key *entity.DeviceAPIKey, req *dto.FlexibleIoTSensorReadingRequest) error {
+	if key.AssetSensorID != nil {
+		if req.AssetSensorID != uuid.Nil && req.AssetSensorID != *key.AssetSensorID {
+			return common.NewValidationError("api key is not allowed to write readings for this asset sensor", nil)
+		}
+		req.AssetSensorID = *key.AssetSensorID
+	}
+
+	if req.AssetSensorID == uuid.Nil {
+		return common.NewValidationError("asset_sensor_id is required for gateway keys", nil)
+	}
+
+	assetSensor, err := s.assetSensorRepo.GetByID(ctx, req.AssetSensorID)
+	if err != nil {
+		return fmt.Errorf("failed to validate asset sensor: %w", err)
+	}
+	if assetSensor == nil {
+		return common.NewValidationError("asset sensor not found", nil)
+	}
+	if assetSensor.TenantID == nil || *assetSensor.TenantID != key.TenantID {
+		return common.NewValidationError("api key is not allowed to write readings for this asset sensor", nil)
+	}
+
+	req.SensorTypeID = assetSensor.SensorTypeID
+	req.Source = entity.ReadingSourceDevice
+	return nil
+}
+
+// getTenantKey loads a key and ensures it belongs to the tenant
+func (s *DeviceAPIKeyService) getTenantKey(ctx context.Context, tenantID, id uuid.UUID) (*entity.DeviceAPIKey, error) {
+	key, err := s.deviceAPIKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device api key: %w", err)
+	}
+	if key == nil || key.TenantID != tenantID {
+		return nil, common.NewNotFoundError("device api key", id.String())
+	}
+
+	return key, nil
+}
+
+// generateDeviceAPIKey creates a random key and returns it with its lookup prefix and hash
+func generateDeviceAPIKey() (plainKey, prefix, hash string, err error) {
+	prefixBytes := make([]byte, 6)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	plainKey = fmt.Sprintf("%s_%s_%s", deviceAPIKeyScheme, prefix, hex.EncodeToString(secretBytes))
+	return plainKey, prefix, hashDeviceAPIKey(plainKey), nil
+}
+
+// hashDeviceAPIKey returns the hex-encoded SHA-256 of the key. Keys carry 256 bits
+// of randomness, so a fast hash is sufficient.
+func hashDeviceAPIKey(plainKey string) string {
+	sum := sha256.Sum256([]byte(plainKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseDeviceAPIKeyPrefix extracts the lookup prefix from a plaintext key
+func parseDeviceAPIKeyPrefix(plainKey string) (string, bool) {
+	parts := strings.Split(plainKey, "_")
+	if len(parts) != 3 || parts[0] != deviceAPIKeyScheme || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
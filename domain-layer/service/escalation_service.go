@@ -0,0 +1,295 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// escalationBatch is the number of alerts escalated per policy and evaluation
+const escalationBatch = 100
+
+// maxEscalationSteps is the maximum number of steps of an escalation policy
+const maxEscalationSteps = 10
+
+// EscalationNotifier is told about escalation steps that fired for an alert
+type EscalationNotifier interface {
+	NotifyEscalation(ctx context.Context, alert *entity.AssetAlert, escalation *entity.AssetAlertEscalation) error
+}
+
+// EscalationService manages escalation policies and escalates open, unacknowledged
+// alerts. A background scheduler fires each policy step once per alert when the
+// alert has stayed unacknowledged for the step's delay.
+type EscalationService struct {
+	policyRepo     repository.EscalationPolicyRepository
+	escalationRepo repository.AssetAlertEscalationRepository
+	assetAlertRepo repository.AssetAlertRepository
+	channelRepo    repository.NotificationChannelRepository
+	notifier       EscalationNotifier
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewEscalationService creates a new instance of EscalationService
+func NewEscalationService(
+	policyRepo repository.EscalationPolicyRepository,
+	escalationRepo repository.AssetAlertEscalationRepository,
+	assetAlertRepo repository.AssetAlertRepository,
+	channelRepo repository.NotificationChannelRepository,
+	notifier EscalationNotifier,
+) *EscalationService {
+	return &EscalationService{
+		policyRepo:     policyRepo,
+		escalationRepo: escalationRepo,
+		assetAlertRepo: assetAlertRepo,
+		channelRepo:    channelRepo,
+		notifier:       notifier,
+	}
+}
+
+// CreatePolicy creates an escalation policy for a tenant
+func (s *EscalationService) CreatePolicy(ctx context.Context, tenantID uuid.UUID, req dto.EscalationPolicyRequest) (*entity.EscalationPolicy, error) {
+	policy := entity.NewEscalationPolicy()
+	policy.TenantID = tenantID
+	if err := s.applyPolicyRequest(ctx, tenantID, policy, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		log.Printf("Error creating escalation policy: %v", err)
+		return nil, fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+
+	log.Printf("Created escalation policy %s (%d steps) for tenant %s", policy.ID, len(policy.Steps), tenantID)
+	return policy, nil
+}
+
+// GetPolicy retrieves an escalation policy of a tenant
+func (s *EscalationService) GetPolicy(ctx context.Context, tenantID, id uuid.UUID) (*entity.EscalationPolicy, error) {
+	return s.getTenantPolicy(ctx, tenantID, id)
+}
+
+// ListPolicies lists the escalation policies of a tenant
+func (s *EscalationService) ListPolicies(ctx context.Context, tenantID uuid.UUID, page, limit int) (*dto.EscalationPolicyListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	policies, totalCount, err := s.policyRepo.List(ctx, tenantID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escalation policies: %w", err)
+	}
+	if policies == nil {
+		policies = []*entity.EscalationPolicy{}
+	}
+
+	return &dto.EscalationPolicyListResponse{
+		Data:       policies,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdatePolicy updates an escalation policy of a tenant. Steps that already fired
+// for open alerts are not fired again.
+func (s *EscalationService) UpdatePolicy(ctx context.Context, tenantID, id uuid.UUID, req dto.EscalationPolicyRequest) (*entity.EscalationPolicy, error) {
+	policy, err := s.getTenantPolicy(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPolicyRequest(ctx, tenantID, policy, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Update(ctx, policy); err != nil {
+		log.Printf("Error updating escalation policy: %v", err)
+		return nil, fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// DeletePolicy deletes an escalation policy of a tenant. Escalation history is kept.
+func (s *EscalationService) DeletePolicy(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantPolicy(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := s.policyRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting escalation policy: %v", err)
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+
+	log.Printf("Deleted escalation policy %s for tenant %s", id, tenantID)
+	return nil
+}
+
+// ProcessDue fires every escalation step that is due and returns how many fired
+func (s *EscalationService) ProcessDue(ctx context.Context) (int, error) {
+	policies, err := s.policyRepo.GetActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get escalation policies: %w", err)
+	}
+
+	now := time.Now()
+	fired := 0
+	for _, policy := range policies {
+		if len(policy.Steps) == 0 {
+			continue
+		}
+
+		pending, err := s.escalationRepo.FindPending(ctx, policy, now, escalationBatch)
+		if err != nil {
+			log.Printf("Error finding pending escalations for policy %s: %v", policy.ID, err)
+			continue
+		}
+
+		for _, p := range pending {
+			if s.escalate(ctx, policy, p) {
+				fired++
+			}
+		}
+	}
+
+	return fired, nil
+}
+
+// Start runs the escalation scheduler at the given interval
+func (s *EscalationService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if _, err := s.ProcessDue(context.Background()); err != nil {
+					log.Printf("Escalation scheduler: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Escalation scheduler started (interval %s)", interval)
+}
+
+// Stop stops the escalation scheduler and waits for the current evaluation to finish
+func (s *EscalationService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Escalation scheduler stopped")
+}
+
+// escalate records a due step for an alert and notifies its targets. It returns
+// false when the step was not recorded, e.g. because another instance already fired it.
+func (s *EscalationService) escalate(ctx context.Context, policy *entity.EscalationPolicy, pending repository.PendingEscalation) bool {
+	alert, err := s.assetAlertRepo.GetByID(ctx, pending.AlertID)
+	if err != nil {
+		log.Printf("Error loading alert %s for escalation: %v", pending.AlertID, err)
+		return false
+	}
+	if alert == nil || !policy.AppliesTo(alert) {
+		return false
+	}
+
+	escalation := entity.NewAssetAlertEscalation(alert, policy, pending.StepIndex)
+	recorded, err := s.escalationRepo.Record(ctx, alert, escalation)
+	if err != nil {
+		log.Printf("Error recording escalation of alert %s: %v", alert.ID, err)
+		return false
+	}
+	if !recorded {
+		return false
+	}
+
+	log.Printf("Escalated alert %s: policy %s step %d", alert.ID, policy.Name, pending.StepIndex+1)
+	if s.notifier != nil {
+		if err := s.notifier.NotifyEscalation(ctx, alert, escalation); err != nil {
+			log.Printf("Error notifying escalation of alert %s: %v", alert.ID, err)
+		}
+	}
+
+	return true
+}
+
+// applyPolicyRequest validates a policy request and copies it onto the policy
+func (s *EscalationService) applyPolicyRequest(ctx context.Context, tenantID uuid.UUID, policy *entity.EscalationPolicy, req dto.EscalationPolicyRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return common.NewValidationError("name is required", nil)
+	}
+
+	severities := req.Severities
+	if len(severities) == 0 {
+		severities = []entity.ThresholdSeverity{entity.ThresholdSeverityCritical}
+	}
+	for _, severity := range severities {
+		switch severity {
+		case entity.ThresholdSeverityWarning, entity.ThresholdSeverityCritical:
+		default:
+			return common.NewValidationError(fmt.Sprintf("invalid severity %q", severity), nil)
+		}
+	}
+
+	if len(req.Steps) == 0 || len(req.Steps) > maxEscalationSteps {
+		return common.NewValidationError(fmt.Sprintf("steps must contain between 1 and %d items", maxEscalationSteps), nil)
+	}
+	for i, step := range req.Steps {
+		if step.DelayMinutes < 0 {
+			return common.NewValidationError(fmt.Sprintf("steps[%d]: delay_minutes must not be negative", i), nil)
+		}
+		if i > 0 && step.DelayMinutes <= req.Steps[i-1].DelayMinutes {
+			return common.NewValidationError(fmt.Sprintf("steps[%d]: delay_minutes must be greater than the previous step", i), nil)
+		}
+		if len(step.ChannelIDs) == 0 && len(step.UserIDs) == 0 {
+			return common.NewValidationError(fmt.Sprintf("steps[%d]: at least one channel or user is required", i), nil)
+		}
+		for _, channelID := range step.ChannelIDs {
+			channel, err := s.channelRepo.GetByID(ctx, channelID)
+			if err != nil {
+				return fmt.Errorf("failed to validate notification channel: %w", err)
+			}
+			if channel == nil || channel.TenantID != tenantID {
+				return common.NewValidationError(fmt.Sprintf("steps[%d]: notification channel %s not found", i, channelID), nil)
+			}
+		}
+	}
+
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.Description = req.Description
+	policy.Severities = severities
+	policy.Steps = req.Steps
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// getTenantPolicy loads a policy and ensures it belongs to the tenant
+func (s *EscalationService) getTenantPolicy(ctx context.Context, tenantID, id uuid.UUID) (*entity.EscalationPolicy, error) {
+	policy, err := s.policyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+	if policy == nil || policy.TenantID != tenantID {
+		return nil, common.NewNotFoundError("escalation policy", id.String())
+	}
+
+	return policy, nil
+}
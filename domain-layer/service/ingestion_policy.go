@@ -0,0 +1,267 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// policyResult is the outcome of applying an ingestion policy to the measurements of a
+// message
+type policyResult struct {
+	readings    []*entity.IoTSensorReadingFlexible       // Readings to store
+	violations  []entity.SchemaViolation                 // Everything that didn't match the fields, in field order
+	quarantined map[string]entity.QuarantinedMeasurement // Measurements held back for review
+	rejected    bool                                     // The whole message was rejected
+}
+
+// warnings describes the violations of a result
+func (r *policyResult) warnings() []string {
+	warnings := make([]string, 0, len(r.violations))
+	for _, violation := range r.violations {
+		warnings = append(warnings, violation.Message)
+	}
+	return warnings
+}
+
+// applyIngestionPolicy validates the measurements of a message against the measurement
+// fields of its sensor type and decides, by policy, which ones are stored. newReading
+// returns a reading with the message's identity for a measurement. Required fields are
+// only checked when checkRequired is set.
+func applyIngestionPolicy(
+	policy entity.IngestionPolicy,
+	fields map[string]entity.SensorMeasurementField,
+	measurements map[string]dto.MeasurementValue,
+	newReading func(name string) *entity.IoTSensorReadingFlexible,
+	checkRequired bool,
+) *policyResult {
+	result := &policyResult{quarantined: make(map[string]entity.QuarantinedMeasurement)}
+
+	// Validate in a stable order, so a message always reports the same violations
+	names := make([]string, 0, len(measurements))
+	for name := range measurements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		measurement := measurements[name]
+
+		reading := newReading(name)
+		if measurement.Unit != "" {
+			unit := measurement.Unit
+			reading.MeasurementUnit = &unit
+		}
+
+		field, known := fields[name]
+		var violation *entity.SchemaViolation
+		if known {
+			violation = reading.SetFieldValue(field, measurement.Value)
+		} else {
+			violation = entity.UnknownFieldViolation(name)
+		}
+
+		label := measurement.Label
+		if label == "" {
+			label = field.Label
+		}
+		if label != "" {
+			reading.MeasurementLabel = &label
+		}
+
+		if violation == nil {
+			result.readings = append(result.readings, reading)
+			continue
+		}
+		result.violations = append(result.violations, *violation)
+
+		if policy != entity.IngestionPolicyPermissive {
+			result.quarantined[name] = entity.QuarantinedMeasurement{
+				Label: measurement.Label,
+				Unit:  measurement.Unit,
+				Value: measurement.Value,
+			}
+			continue
+		}
+
+		// Permissive: store the value as sent when it couldn't be stored by the field's
+		// data type; unit and range violations already left it set
+		if violation.Code == entity.ViolationUnknownField || violation.Code == entity.ViolationInvalidType {
+			reading.SetValue(measurement.Value)
+		}
+		result.readings = append(result.readings, reading)
+	}
+
+	if checkRequired {
+		required := make([]string, 0)
+		for name, field := range fields {
+			if _, sent := measurements[name]; field.Required && !sent {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		for _, name := range required {
+			result.violations = append(result.violations, *entity.MissingFieldViolation(name))
+		}
+	}
+
+	// Strict: a message with any violation is held back whole
+	if policy == entity.IngestionPolicyStrict && len(result.violations) > 0 {
+		result.rejected = true
+		result.readings = nil
+		for _, name := range names {
+			measurement := measurements[name]
+			result.quarantined[name] = entity.QuarantinedMeasurement{
+				Label: measurement.Label,
+				Unit:  measurement.Unit,
+				Value: measurement.Value,
+			}
+		}
+	}
+
+	return result
+}
+
+// measurementFields returns the fields of an asset sensor's active measurement types by
+// name. Fields without a unit get the usual unit of their label.
+func measurementFields(assetSensor *repository.AssetSensorWithDetails) map[string]entity.SensorMeasurementField {
+	fields := make(map[string]entity.SensorMeasurementField)
+	for _, measurementType := range assetSensor.MeasurementTypes {
+		if !measurementType.IsActive {
+			continue
+		}
+		for _, field := range measurementType.Fields {
+			definition := entity.SensorMeasurementField{
+				Name:     field.Name,
+				Label:    field.Label,
+				DataType: entity.MeasurementDataType(field.DataType),
+				Required: field.Required,
+				Unit:     defaultLabelUnit(field.Label),
+				Min:      field.Min,
+				Max:      field.Max,
+			}
+			if field.Unit != nil {
+				definition.Unit = *field.Unit
+			}
+			fields[field.Name] = definition
+		}
+	}
+	return fields
+}
+
+// defaultLabelUnit returns the unit of fields that don't set one, based on their label
+func defaultLabelUnit(label string) string {
+	switch strings.ToLower(label) {
+	case "temperature":
+		return "°C"
+	case "humidity":
+		return "%"
+	case "pressure":
+		return "Pa"
+	case "voltage":
+		return "V"
+	case "current":
+		return "A"
+	case "power":
+		return "W"
+	case "energy":
+		return "kWh"
+	case "speed":
+		return "m/s"
+	case "distance":
+		return "m"
+	case "weight":
+		return "kg"
+	case "volume":
+		return "m³"
+	case "flow":
+		return "m³/s"
+	case "concentration":
+		return "ppm"
+	case "ph":
+		return "pH"
+	case "turbidity":
+		return "NTU"
+	case "conductivity":
+		return "μS/cm"
+	default:
+		return ""
+	}
+}
+
+// ingestionPolicy returns the ingestion policy of a sensor type, or the default policy
+// when it has none
+func (s *IoTSensorReadingService) ingestionPolicy(sensorTypeID uuid.UUID) (entity.IngestionPolicy, error) {
+	sensorType, err := s.sensorTypeRepo.GetByID(sensorTypeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get sensor type: %w", err)
+	}
+	if sensorType == nil || !sensorType.IngestionPolicy.IsValid() {
+		return entity.DefaultIngestionPolicy, nil
+	}
+	return sensorType.IngestionPolicy, nil
+}
+
+// quarantine records the measurements a policy held back for review. It returns nil when
+// nothing was held back or there is no quarantine to record them in.
+func (s *IoTSensorReadingService) quarantine(
+	ctx context.Context,
+	req *dto.FlexibleIoTSensorReadingRequest,
+	tenantID *uuid.UUID,
+	policy entity.IngestionPolicy,
+	result *policyResult,
+	readingTime time.Time,
+) (*entity.QuarantinedReading, error) {
+	if len(result.quarantined) == 0 || s.quarantineRepo == nil {
+		return nil, nil
+	}
+
+	quarantined := entity.NewQuarantinedReading()
+	quarantined.TenantID = tenantID
+	quarantined.AssetSensorID = req.AssetSensorID
+	quarantined.SensorTypeID = req.SensorTypeID
+	quarantined.ReadingTime = readingTime
+	quarantined.Source = req.Source
+	if quarantined.Source == "" {
+		quarantined.Source = entity.ReadingSourceAPI
+	}
+	quarantined.Policy = policy
+	quarantined.Rejected = result.rejected
+	quarantined.Measurements = result.quarantined
+	quarantined.Violations = result.violations
+	if req.MacAddress != "" {
+		macAddress := req.MacAddress
+		quarantined.MacAddress = &macAddress
+	}
+	if req.MessageID != "" {
+		messageID := req.MessageID
+		quarantined.MessageID = &messageID
+	}
+
+	if err := s.quarantineRepo.Create(ctx, quarantined); err != nil {
+		log.Printf("Error quarantining reading of asset sensor %s: %v", req.AssetSensorID, err)
+		return nil, fmt.Errorf("failed to quarantine reading: %w", err)
+	}
+
+	log.Printf("Quarantined %d measurements of asset sensor %s as %s (%s policy)",
+		len(quarantined.Measurements), quarantined.AssetSensorID, quarantined.ID, policy)
+	return quarantined, nil
+}
+
+// rejectionError describes a message none of whose measurements were stored
+func rejectionError(result *policyResult, quarantined *entity.QuarantinedReading) error {
+	message := strings.Join(result.warnings(), "; ")
+	if quarantined != nil {
+		message = fmt.Sprintf("%s (quarantined as %s)", message, quarantined.ID)
+	}
+	return common.NewValidationError(message, nil)
+}
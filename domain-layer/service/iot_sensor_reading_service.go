@@ -7,9 +7,11 @@ import (
 	"be-lecsens/asset_management/helpers/dto"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,7 +27,13 @@ type IoTSensorReadingService struct {
 	assetRepo                 repository.AssetRepository
 	locationRepo              *repository.LocationRepository
 	sensorThresholdService    *SensorThresholdService                    // For threshold checking
+	alertConditionService     *AlertConditionService                     // For composite alert conditions
 	sensorMeasurementTypeRepo repository.SensorMeasurementTypeRepository // For getting measurement types
+	evaluationQueue           *AlertEvaluationQueue                      // Evaluates thresholds and conditions off the request path
+	readingOutbox             *ReadingOutboxService                      // Applies the side effects recorded with stored readings
+	quarantineRepo            repository.QuarantinedReadingRepository    // Holds measurements rejected by ingestion policies
+	timeSettingsRepo          repository.SensorTimeSettingsRepository    // How each sensor's timestamps are parsed and corrected
+	rollupRepo                repository.ReadingRollupRepository         // Serves aggregations over long time ranges
 }
 
 // NewIoTSensorReadingService creates a new instance of IoTSensorReadingService
@@ -36,7 +44,13 @@ func NewIoTSensorReadingService(
 	assetRepo repository.AssetRepository,
 	locationRepo *repository.LocationRepository,
 	sensorThresholdService *SensorThresholdService,
+	alertConditionService *AlertConditionService,
 	sensorMeasurementTypeRepo repository.SensorMeasurementTypeRepository,
+	evaluationQueue *AlertEvaluationQueue,
+	readingOutbox *ReadingOutboxService,
+	quarantineRepo repository.QuarantinedReadingRepository,
+	timeSettingsRepo repository.SensorTimeSettingsRepository,
+	rollupRepo repository.ReadingRollupRepository,
 ) *IoTSensorReadingService {
 	return &IoTSensorReadingService{
 		iotSensorReadingRepo:      iotSensorReadingRepo,
@@ -45,10 +59,42 @@ func NewIoTSensorReadingService(
 		assetRepo:                 assetRepo,
 		locationRepo:              locationRepo,
 		sensorThresholdService:    sensorThresholdService,
+		alertConditionService:     alertConditionService,
 		sensorMeasurementTypeRepo: sensorMeasurementTypeRepo,
+		evaluationQueue:           evaluationQueue,
+		readingOutbox:             readingOutbox,
+		quarantineRepo:            quarantineRepo,
+		timeSettingsRepo:          timeSettingsRepo,
+		rollupRepo:                rollupRepo,
 	}
 }
 
+// StartAlertEvaluation starts the workers that evaluate thresholds and alert conditions
+// for stored readings
+func (s *IoTSensorReadingService) StartAlertEvaluation() {
+	if s.evaluationQueue != nil {
+		s.evaluationQueue.Start(s.checkThresholdsForReading)
+	}
+}
+
+// GetAlertEvaluationStats returns the load of the alert evaluation queue. Without a
+// queue, readings are evaluated unqueued and the stats are empty.
+func (s *IoTSensorReadingService) GetAlertEvaluationStats() AlertEvaluationStats {
+	if s.evaluationQueue == nil {
+		return AlertEvaluationStats{}
+	}
+	return s.evaluationQueue.Stats()
+}
+
+// GetReadingOutboxStats returns the backlog of reading side effects. Without a dispatcher,
+// side effects aren't dispatched from the outbox and the stats are empty.
+func (s *IoTSensorReadingService) GetReadingOutboxStats(ctx context.Context) (*ReadingOutboxStats, error) {
+	if s.readingOutbox == nil {
+		return &ReadingOutboxStats{}, nil
+	}
+	return s.readingOutbox.GetStats(ctx)
+}
+
 // CreateIoTSensorReading creates a new IoT sensor reading
 func (s *IoTSensorReadingService) CreateIoTSensorReading(ctx context.Context, req *dto.CreateIoTSensorReadingRequest) (*dto.IoTSensorReadingResponse, error) {
 	// Validate request
@@ -114,8 +160,8 @@ func (s *IoTSensorReadingService) CreateIoTSensorReading(ctx context.Context, re
 
 	log.Printf("Successfully created IoT sensor reading with ID: %s", reading.ID)
 
-	// Check thresholds for the new reading (non-blocking)
-	go s.checkThresholdsForReading(ctx, reading)
+	// Apply the reading's side effects (non-blocking)
+	s.applyReadingEffects(ctx, []*entity.IoTSensorReadingFlexible{reading})
 
 	// Convert to response DTO
 	return s.toResponseDTO(reading), nil
@@ -187,8 +233,8 @@ func (s *IoTSensorReadingService) CreateBatchIoTSensorReading(ctx context.Contex
 		return nil, fmt.Errorf("failed to create batch IoT sensor readings: %w", err)
 	}
 
-	// Check thresholds for batch readings (non-blocking)
-	go s.checkThresholdsForMultipleReadings(ctx, readings)
+	// Apply the readings' side effects (non-blocking)
+	s.applyReadingEffects(ctx, readings)
 
 	// Convert to response DTOs
 	for _, reading := range readings {
@@ -443,76 +489,291 @@ func (s *IoTSensorReadingService) GetAggregatedData(ctx context.Context, req *dt
 	// Default interval if not specified
 	interval := req.Interval
 	if interval == "" {
-		interval = "hour"
+		interval = entity.AggregateIntervalHour
+	}
+	bucketInterval, err := entity.ParseAggregateInterval(interval)
+	if err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
 	}
 
-	// Validate interval
-	validIntervals := map[string]bool{"hour": true, "day": true, "week": true, "month": true}
-	if !validIntervals[interval] {
-		return nil, common.NewValidationError("invalid interval, must be: hour, day, week, month", nil)
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		return nil, common.NewValidationError(fmt.Sprintf("unknown timezone %q", timezone), nil)
 	}
 
-	var aggregatedData []map[string]interface{}
-	var err error
+	fill := entity.GapFill(req.Fill)
+	if fill == "" {
+		fill = entity.GapFillNone
+	}
+	if !fill.IsValid() {
+		return nil, common.NewValidationError("invalid fill, must be: none, null, previous, linear", nil)
+	}
+
+	buckets, ok := bucketInterval.Buckets(req.FromTime, req.ToTime, location, maxAggregateBuckets)
+	if !ok {
+		return nil, common.NewValidationError(fmt.Sprintf("the time range holds more than %d buckets of %s, use a longer interval", maxAggregateBuckets, interval), nil)
+	}
 
+	targets, err := ParseUnitTargets(req.Units)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GroupBy != "" && !entity.IsValidAggregateGroup(req.GroupBy) {
+		return nil, common.NewValidationError("invalid group_by, must be: asset_sensor, asset, asset_type, location, sensor_type, measurement_field", nil)
+	}
+	if len(req.Percentiles) > maxAggregatePercentiles {
+		return nil, common.NewValidationError(fmt.Sprintf("at most %d percentiles can be requested", maxAggregatePercentiles), nil)
+	}
+	fractions := make([]float64, len(req.Percentiles))
+	for i, percentile := range req.Percentiles {
+		if percentile < 0 || percentile > 100 || math.IsNaN(percentile) {
+			return nil, common.NewValidationError("percentiles must be between 0 and 100", nil)
+		}
+		fractions[i] = percentile / 100
+	}
+
+	// Queries across sensors are limited to the tenant's readings
+	if req.AssetSensorID == nil && req.TenantID == nil {
+		return nil, common.NewValidationError("asset_sensor_id is required for aggregated data queries without a tenant", nil)
+	}
+
+	var assetSensor *repository.AssetSensorWithDetails
 	if req.AssetSensorID != nil {
-		aggregatedData, err = s.iotSensorReadingRepo.GetAggregatedData(ctx, *req.AssetSensorID, req.FromTime, req.ToTime, interval)
-	} else {
-		// If no specific asset sensor, we'll need to implement a general aggregation query
-		return nil, common.NewValidationError("asset_sensor_id is required for aggregated data queries", nil)
+		assetSensor, err = s.assetSensorRepo.GetByID(ctx, *req.AssetSensorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get asset sensor: %w", err)
+		}
+		if assetSensor == nil || (req.TenantID != nil && (assetSensor.TenantID == nil || *assetSensor.TenantID != *req.TenantID)) {
+			return nil, common.NewNotFoundError("asset sensor", req.AssetSensorID.String())
+		}
 	}
 
+	query := entity.ReadingAggregateQuery{
+		TenantID:         req.TenantID,
+		AssetSensorID:    req.AssetSensorID,
+		AssetID:          req.AssetID,
+		AssetTypeID:      req.AssetTypeID,
+		LocationID:       req.LocationID,
+		SensorTypeID:     req.SensorTypeID,
+		MeasurementTypes: req.AggregateBy,
+		FromTime:         req.FromTime,
+		ToTime:           req.ToTime,
+		Interval:         bucketInterval,
+		Location:         location,
+		GroupBy:          req.GroupBy,
+		Percentiles:      fractions,
+	}
+
+	// Percentiles need every reading, the other aggregates can come from a rollup
+	if s.rollupRepo != nil && len(fractions) == 0 {
+		states, err := s.rollupRepo.GetStates(ctx)
+		if err != nil {
+			log.Printf("Error getting reading rollup states, aggregating readings: %v", err)
+		} else {
+			query.Rollup = chooseReadingRollup(states, buckets, req.FromTime, req.ToTime)
+		}
+	}
+
+	aggregates, err := s.iotSensorReadingRepo.AggregateReadings(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get aggregated data: %w", err)
 	}
 
-	// Convert to response format
-	var dataPoints []dto.AggregatedDataPoint
-	totalCount := int64(0)
+	// Measurements are reported in the unit of the sensor's schema, or across sensors in
+	// the unit most of their readings are stored in
+	dominant := dominantUnits(aggregates)
+	unitOf := func(measurementType, unit string) string {
+		if target := targets.Target(unit); target != "" {
+			return target
+		}
+		if assetSensor != nil {
+			if canonical := fieldUnit(assetSensor, measurementType); canonical != "" {
+				return entity.NormalizeUnit(canonical)
+			}
+		}
+		if canonical := dominant[measurementType]; canonical != "" {
+			return canonical
+		}
+		return entity.NormalizeUnit(unit)
+	}
+
+	response := &dto.GetAggregatedDataResponse{
+		FromTime:    req.FromTime,
+		ToTime:      req.ToTime,
+		Interval:    interval,
+		Timezone:    timezone,
+		Fill:        string(fill),
+		AggregateBy: req.AggregateBy,
+		GroupBy:     req.GroupBy,
+		Percentiles: req.Percentiles,
+		RequestedAt: time.Now(),
+	}
+
+	// Convert to response format, with the buckets in the requested time zone
+	for _, aggregate := range aggregates {
+		aggregate.Bucket = aggregate.Bucket.In(location)
+	}
+	for i := range buckets {
+		buckets[i] = buckets[i].In(location)
+	}
+	series := func(aggregates []*entity.ReadingAggregate) ([]dto.AggregatedDataPoint, int64) {
+		dataPoints, count := aggregateDataPoints(aggregates, req.AggregateBy, req.Percentiles, unitOf)
+		return fillDataPoints(dataPoints, buckets, fill), count
+	}
 
-	for _, data := range aggregatedData {
-		point := dto.AggregatedDataPoint{
-			Data: data,
+	if req.GroupBy == "" {
+		response.DataPoints, response.TotalCount = series(aggregates)
+		return response, nil
+	}
+
+	// Aggregates are ordered by group, so each run of a group ID is one group's series
+	response.DataPoints = []dto.AggregatedDataPoint{}
+	response.Groups = []dto.AggregatedGroup{}
+	for start := 0; start < len(aggregates); {
+		end := start + 1
+		for end < len(aggregates) && aggregates[end].GroupID == aggregates[start].GroupID {
+			end++
 		}
 
-		// Extract common fields if they exist
-		if timeVal, ok := data["time"]; ok {
-			if timeStr, ok := timeVal.(string); ok {
-				if parsedTime, err := time.Parse(time.RFC3339, timeStr); err == nil {
-					point.Time = parsedTime
-				}
+		dataPoints, count := series(aggregates[start:end])
+		if count > 0 {
+			response.Groups = append(response.Groups, dto.AggregatedGroup{
+				ID:         aggregates[start].GroupID,
+				Name:       aggregates[start].GroupName,
+				TotalCount: count,
+				DataPoints: dataPoints,
+			})
+			response.TotalCount += count
+		}
+		start = end
+	}
+
+	return response, nil
+}
+
+// maxAggregatePercentiles limits the percentiles of an aggregated data query
+const maxAggregatePercentiles = 10
+
+// dominantUnits returns, for each measurement, the normalized unit most of its aggregated
+// readings are stored in
+func dominantUnits(aggregates []*entity.ReadingAggregate) map[string]string {
+	counts := make(map[string]map[string]int64)
+	for _, aggregate := range aggregates {
+		if aggregate.Unit == "" {
+			continue
+		}
+		if counts[aggregate.MeasurementType] == nil {
+			counts[aggregate.MeasurementType] = make(map[string]int64)
+		}
+		counts[aggregate.MeasurementType][entity.NormalizeUnit(aggregate.Unit)] += aggregate.Count
+	}
+
+	units := make(map[string]string, len(counts))
+	for measurementType, unitCounts := range counts {
+		best := int64(-1)
+		for unit, count := range unitCounts {
+			// Ties go to the first unit in sort order, so the choice is stable
+			if count > best || (count == best && unit < units[measurementType]) {
+				units[measurementType], best = unit, count
 			}
 		}
+	}
+	return units
+}
+
+// percentileKey is the key of a percentile in the percentiles of a data point
+func percentileKey(percentile float64) string {
+	return "p" + strconv.FormatFloat(percentile, 'f', -1, 64)
+}
+
+// aggregateDataPoints merges the aggregates of each time bucket into a data point per
+// bucket. The aggregates of a measurement are converted to the unit returned by unitOf,
+// so readings stored in different units are averaged together. Aggregates that can't be
+// converted are reported under "measurement (unit)". Only the measurements in
+// aggregateBy are kept when it isn't empty. The percentiles of a measurement stored in
+// several units within a bucket are approximated by their count-weighted mean.
+func aggregateDataPoints(aggregates []*entity.ReadingAggregate, aggregateBy []string, percentiles []float64, unitOf func(measurementType, unit string) string) ([]dto.AggregatedDataPoint, int64) {
+	keep := make(map[string]bool, len(aggregateBy))
+	for _, name := range aggregateBy {
+		keep[name] = true
+	}
+
+	dataPoints := []dto.AggregatedDataPoint{}
+	totalCount := int64(0)
+	var point *dto.AggregatedDataPoint
+	counts := make(map[string]int64)
 
-		if countVal, ok := data["count"]; ok {
-			if count, ok := countVal.(int64); ok {
-				point.Count = count
-				totalCount += count
+	for _, aggregate := range aggregates {
+		if len(keep) > 0 && !keep[aggregate.MeasurementType] {
+			continue
+		}
+
+		// Aggregates are ordered by bucket, so a new bucket starts a new data point
+		if point == nil || !point.Time.Equal(aggregate.Bucket) {
+			dataPoints = append(dataPoints, dto.AggregatedDataPoint{
+				Time:     aggregate.Bucket,
+				Averages: make(map[string]float64),
+				Sums:     make(map[string]float64),
+				Mins:     make(map[string]float64),
+				Maxs:     make(map[string]float64),
+				Units:    make(map[string]string),
+			})
+			point = &dataPoints[len(dataPoints)-1]
+			counts = make(map[string]int64)
+		}
+
+		key, unit := aggregate.MeasurementType, unitOf(aggregate.MeasurementType, aggregate.Unit)
+		// Values without a unit are taken to be in the measurement's unit, as at ingestion
+		scale, offset := 1.0, 0.0
+		if aggregate.Unit != "" {
+			var err error
+			if scale, offset, err = entity.UnitConversion(aggregate.Unit, unit); err != nil {
+				key, unit = fmt.Sprintf("%s (%s)", aggregate.MeasurementType, aggregate.Unit), aggregate.Unit
+				scale, offset = 1, 0
 			}
 		}
 
-		// Extract averages, sums, mins, maxs if they exist
-		if avgData, ok := data["averages"].(map[string]interface{}); ok {
-			point.Averages = make(map[string]float64)
-			for k, v := range avgData {
-				if val, ok := v.(float64); ok {
-					point.Averages[k] = val
-				}
+		sum := aggregate.Sum*scale + offset*float64(aggregate.Count)
+		minValue := aggregate.Min*scale + offset
+		maxValue := aggregate.Max*scale + offset
+		if counts[key] == 0 {
+			point.Mins[key], point.Maxs[key] = minValue, maxValue
+		} else {
+			point.Mins[key] = math.Min(point.Mins[key], minValue)
+			point.Maxs[key] = math.Max(point.Maxs[key], maxValue)
+		}
+		counts[key] += aggregate.Count
+		point.Sums[key] += sum
+		point.Averages[key] = point.Sums[key] / float64(counts[key])
+		if len(percentiles) > 0 && len(aggregate.Percentiles) == len(percentiles) {
+			if point.Percentiles == nil {
+				point.Percentiles = make(map[string]map[string]float64)
+			}
+			values := point.Percentiles[key]
+			if values == nil {
+				values = make(map[string]float64, len(percentiles))
+				point.Percentiles[key] = values
+			}
+			weight := float64(aggregate.Count) / float64(counts[key])
+			for i, percentile := range percentiles {
+				name := percentileKey(percentile)
+				values[name] += (aggregate.Percentiles[i]*scale + offset - values[name]) * weight
 			}
 		}
+		if unit != "" {
+			point.Units[key] = unit
+		}
 
-		dataPoints = append(dataPoints, point)
+		point.Count += aggregate.Count
+		totalCount += aggregate.Count
 	}
 
-	return &dto.GetAggregatedDataResponse{
-		DataPoints:  dataPoints,
-		TotalCount:  totalCount,
-		FromTime:    req.FromTime,
-		ToTime:      req.ToTime,
-		Interval:    interval,
-		AggregateBy: req.AggregateBy,
-		RequestedAt: time.Now(),
-	}, nil
+	return dataPoints, totalCount
 }
 
 // ValidateAndCreateReading validates measurement data against schemas and creates reading
@@ -605,32 +866,10 @@ func (s *IoTSensorReadingService) ListFlexibleIoTSensorReadings(ctx context.Cont
 	// Convert to DTOs
 	var responseDTOs []dto.FlexibleIoTSensorReadingResponse
 	for _, reading := range readings {
-		// Convert measurement data to proper format
-		measurementData := make(map[string]dto.MeasurementValue)
-
-		// Add measurement data from the reading
-		measurementValue := dto.MeasurementValue{}
-		if reading.MeasurementLabel != nil {
-			measurementValue.Label = *reading.MeasurementLabel
-		}
-		if reading.MeasurementUnit != nil {
-			measurementValue.Unit = *reading.MeasurementUnit
-		}
-
-		// Set value based on type
-		if reading.NumericValue != nil {
-			measurementValue.Value = *reading.NumericValue
-		} else if reading.TextValue != nil {
-			measurementValue.Value = *reading.TextValue
-		} else if reading.BooleanValue != nil {
-			measurementValue.Value = *reading.BooleanValue
-		}
-
-		measurementData[reading.MeasurementType] = measurementValue
-
+		baseResponse := s.toResponseDTO(reading)
 		responseDTO := dto.FlexibleIoTSensorReadingResponse{
-			IoTSensorReadingResponse: s.toResponseDTO(reading),
-			MeasurementData:          measurementData,
+			IoTSensorReadingResponse: baseResponse,
+			MeasurementData:          baseResponse.MeasurementData,
 		}
 		responseDTOs = append(responseDTOs, responseDTO)
 	}
@@ -705,9 +944,15 @@ func (s *IoTSensorReadingService) toResponseDTO(reading *entity.IoTSensorReading
 		AssetSensorID: reading.AssetSensorID,
 		SensorTypeID:  reading.SensorTypeID,
 		ReadingTime:   reading.ReadingTime,
+		IsLate:        reading.IsLate,
+		DeviceTime:    reading.DeviceTime,
+		ClockOffsetMs: reading.ClockOffsetMs,
 		CreatedAt:     reading.CreatedAt,
 		UpdatedAt:     reading.UpdatedAt,
 	}
+	if reading.TimeAnomaly != nil {
+		response.TimeAnomaly = *reading.TimeAnomaly
+	}
 
 	if reading.TenantID != nil {
 		response.TenantID = *reading.TenantID
@@ -740,6 +985,10 @@ func (s *IoTSensorReadingService) toResponseDTO(reading *entity.IoTSensorReading
 		} else if reading.BooleanValue != nil {
 			measurementValue.Value = *reading.BooleanValue
 		}
+		measurementValue.OriginalValue = reading.OriginalValue
+		if reading.OriginalUnit != nil {
+			measurementValue.OriginalUnit = *reading.OriginalUnit
+		}
 
 		response.MeasurementData = map[string]dto.MeasurementValue{
 			reading.MeasurementType: measurementValue,
@@ -1012,12 +1261,34 @@ func (s *IoTSensorReadingService) GetBatchJSONTemplate(sensorTypes []string, cou
 	return string(jsonBytes), nil
 }
 
-// CreateFlexibleIoTSensorReading creates a flexible IoT sensor reading
+// CreateFlexibleIoTSensorReading creates a flexible IoT sensor reading. Its measurements
+// are validated against the measurement fields of the sensor type and stored according
+// to the sensor type's ingestion policy.
 func (s *IoTSensorReadingService) CreateFlexibleIoTSensorReading(ctx context.Context, req *dto.FlexibleIoTSensorReadingRequest) (*dto.IoTSensorReadingResponse, error) {
+	return s.createFlexibleReading(ctx, req, flexibleReadingOptions{})
+}
+
+// flexibleReadingOptions controls how the measurements of a flexible reading are validated
+type flexibleReadingOptions struct {
+	policy       entity.IngestionPolicy // Used instead of the sensor type's policy when set
+	skipRequired bool                   // Don't report required fields that weren't sent
+	noQuarantine bool                   // Report violations without quarantining anything
+	resolvedTime bool                   // The reading time was already resolved, e.g. when replaying
+}
+
+// createFlexibleReading validates the measurements of a flexible reading by ingestion
+// policy, stores the accepted ones and quarantines the others
+func (s *IoTSensorReadingService) createFlexibleReading(ctx context.Context, req *dto.FlexibleIoTSensorReadingRequest, opts flexibleReadingOptions) (*dto.IoTSensorReadingResponse, error) {
 	// Validate basic required fields
 	if req.AssetSensorID == uuid.Nil {
 		return nil, common.NewValidationError("asset_sensor_id is required", nil)
 	}
+	if err := validateMessageID(req.MessageID); err != nil {
+		return nil, err
+	}
+	if len(req.MeasurementData) == 0 {
+		return nil, common.NewValidationError("measurement data is required", nil)
+	}
 
 	// Validate asset sensor exists and get its tenant_id
 	assetSensor, err := s.assetSensorRepo.GetByID(ctx, req.AssetSensorID)
@@ -1029,243 +1300,153 @@ func (s *IoTSensorReadingService) CreateFlexibleIoTSensorReading(ctx context.Con
 		return nil, common.NewValidationError("asset sensor not found", nil)
 	}
 
-	// Get measurement types and fields for validation
-	measurementTypesQuery := `
-		SELECT 
-			smt.id, smt.name, smt.description, smt.properties_schema,
-			COALESCE(
-				(SELECT json_agg(
-					json_build_object(
-						'id', smf.id,
-						'name', smf.name,
-						'label', smf.label,
-						'description', smf.description,
-						'data_type', smf.data_type,
-						'required', smf.required,
-						'unit', smf.unit,
-						'min', smf.min,
-						'max', smf.max
-					)
-				)
-				FROM sensor_measurement_fields smf
-				WHERE smf.sensor_measurement_type_id = smt.id), '[]'::json
-			) as fields
-		FROM sensor_measurement_types smt
-		WHERE smt.sensor_type_id = $1 AND smt.is_active = true`
-
-	rows, err := s.iotSensorReadingRepo.GetDB().QueryContext(ctx, measurementTypesQuery, req.SensorTypeID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get measurement types and fields: %w", err)
+	policy := opts.policy
+	if policy == "" {
+		if policy, err = s.ingestionPolicy(req.SensorTypeID); err != nil {
+			return nil, err
+		}
 	}
-	defer rows.Close()
-
-	// Map to store valid fields by name
-	validFields := make(map[string]struct {
-		Unit string
-		Min  *float64
-		Max  *float64
-	})
 
-	// Process measurement types and fields
-	for rows.Next() {
-		var mt struct {
-			ID               uuid.UUID
-			Name             string
-			Description      string
-			PropertiesSchema json.RawMessage
-			Fields           []struct {
-				ID          uuid.UUID `json:"id"`
-				Name        string    `json:"name"`
-				Label       string    `json:"label"`
-				Description *string   `json:"description"`
-				DataType    string    `json:"data_type"`
-				Required    bool      `json:"required"`
-				Unit        *string   `json:"unit"`
-				Min         *float64  `json:"min"`
-				Max         *float64  `json:"max"`
-			} `json:"fields"`
+	// Parse and correct the reading time by the sensor's time settings
+	resolved := &entity.ResolvedReadingTime{ReadingTime: time.Now()}
+	if opts.resolvedTime {
+		if req.ReadingTime != nil {
+			resolved.ReadingTime = *req.ReadingTime
 		}
-
-		var fieldsJSON []byte
-		err := rows.Scan(&mt.ID, &mt.Name, &mt.Description, &mt.PropertiesSchema, &fieldsJSON)
+	} else {
+		settings, err := s.readingTimeSettings(ctx, req.AssetSensorID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan measurement type: %w", err)
+			return nil, err
 		}
-
-		// Parse fields JSON
-		if len(fieldsJSON) > 0 && string(fieldsJSON) != "[]" {
-			if err := json.Unmarshal(fieldsJSON, &mt.Fields); err != nil {
-				return nil, fmt.Errorf("failed to parse measurement fields: %w", err)
-			}
-
-			// Add fields to valid fields map
-			for _, field := range mt.Fields {
-				validFields[field.Name] = struct {
-					Unit string
-					Min  *float64
-					Max  *float64
-				}{
-					Unit: func() string {
-						if field.Unit != nil {
-							return *field.Unit
-						}
-						// Auto-fill unit based on label
-						switch strings.ToLower(field.Label) {
-						case "temperature":
-							return "°C"
-						case "humidity":
-							return "%"
-						case "pressure":
-							return "Pa"
-						case "voltage":
-							return "V"
-						case "current":
-							return "A"
-						case "power":
-							return "W"
-						case "energy":
-							return "kWh"
-						case "speed":
-							return "m/s"
-						case "distance":
-							return "m"
-						case "weight":
-							return "kg"
-						case "volume":
-							return "m³"
-						case "flow":
-							return "m³/s"
-						case "concentration":
-							return "ppm"
-						case "ph":
-							return "pH"
-						case "turbidity":
-							return "NTU"
-						case "conductivity":
-							return "μS/cm"
-						default:
-							return ""
-						}
-					}(),
-					Min: field.Min,
-					Max: field.Max,
-				}
-			}
+		if resolved, err = resolveReadingTime(settings, req, resolved.ReadingTime, true); err != nil {
+			return nil, err
 		}
 	}
+	readingTime := resolved.ReadingTime
 
-	// Validate measurement data against valid fields
-	var warnings []string
-	validMeasurementData := make(map[string]dto.MeasurementValue)
-	for key, measurement := range req.MeasurementData {
-		field, exists := validFields[key]
-		if !exists {
-			warnings = append(warnings, fmt.Sprintf("invalid measurement field: %s", key))
-			continue
+	// A reading older than the sensor's last one is stored, but flagged as late
+	isLate := assetSensor.AssetSensor.IsLateReading(readingTime)
+
+	// Validate the measurements against the sensor type's fields
+	result := applyIngestionPolicy(policy, measurementFields(assetSensor), req.MeasurementData, func(name string) *entity.IoTSensorReadingFlexible {
+		reading := &entity.IoTSensorReadingFlexible{
+			ID:              uuid.New(),
+			TenantID:        assetSensor.AssetSensor.TenantID,
+			AssetSensorID:   req.AssetSensorID,
+			SensorTypeID:    req.SensorTypeID,
+			MeasurementType: name,
+			IsLate:          isLate,
 		}
+		resolved.Apply(reading)
+		if req.MacAddress != "" {
+			macAddress := req.MacAddress
+			reading.MacAddress = &macAddress
+		}
+		return reading
+	}, !opts.skipRequired)
 
-		// Validate numeric values against min/max if applicable
-		if numValue, ok := measurement.Value.(float64); ok {
-			if field.Min != nil && numValue < *field.Min {
-				warnings = append(warnings, fmt.Sprintf("value for %s is below minimum allowed value", key))
-				continue
-			}
-			if field.Max != nil && numValue > *field.Max {
-				warnings = append(warnings, fmt.Sprintf("value for %s is above maximum allowed value", key))
-				continue
-			}
+	quarantine := func() (*entity.QuarantinedReading, error) {
+		if opts.noQuarantine {
+			return nil, nil
 		}
+		return s.quarantine(ctx, req, assetSensor.AssetSensor.TenantID, policy, result, readingTime)
+	}
 
-		// Auto-fill unit if not provided
-		if measurement.Unit == "" && field.Unit != "" {
-			measurement.Unit = field.Unit
+	if len(result.readings) == 0 {
+		quarantined, err := quarantine()
+		if err != nil {
+			return nil, err
 		}
+		return nil, rejectionError(result, quarantined)
+	}
 
-		validMeasurementData[key] = measurement
+	// Store all flexible readings of the message at once
+	message := &entity.ReadingMessage{
+		AssetSensorID: req.AssetSensorID,
+		MessageID:     req.MessageID,
+		Readings:      result.readings,
+	}
+	if err := s.iotSensorReadingRepo.CreateFlexibleMessages(ctx, []*entity.ReadingMessage{message}); err != nil {
+		log.Printf("Error creating flexible IoT sensor readings: %v", err)
+		return nil, fmt.Errorf("failed to create flexible IoT sensor readings: %w", err)
 	}
 
-	if len(validMeasurementData) == 0 {
-		return nil, common.NewValidationError(strings.Join(warnings, "; "), nil)
+	if message.Duplicate {
+		log.Printf("Message %s of asset sensor %s was already stored", message.MessageID, message.AssetSensorID)
+		return s.duplicateMessageResponse(ctx, message), nil
 	}
 
-	// Create flexible readings for each valid measurement
-	var flexibleReadings []*entity.IoTSensorReadingFlexible
-	for key, measurement := range validMeasurementData {
-		flexibleReading := &entity.IoTSensorReadingFlexible{
-			ID:            uuid.New(),
-			TenantID:      assetSensor.AssetSensor.TenantID,
-			AssetSensorID: req.AssetSensorID,
-			SensorTypeID:  req.SensorTypeID,
-			ReadingTime:   time.Now(),
-		}
+	log.Printf("Successfully created %d flexible IoT sensor readings", len(result.readings))
 
-		// Set reading time if provided
-		if req.ReadingTime != nil {
-			flexibleReading.ReadingTime = *req.ReadingTime
-		}
+	// Apply the readings' side effects (non-blocking)
+	s.applyReadingEffects(ctx, result.readings)
 
-		// Set MAC address if provided
-		if req.MacAddress != "" {
-			macAddressCopy := req.MacAddress
-			flexibleReading.MacAddress = &macAddressCopy
+	// Convert to response using the first reading as base (all have same basic info)
+	resp := s.toResponseDTO(result.readings[0])
+	resp.ReadingIDs = message.ReadingIDs
+	if warning := resolved.Warning(); warning != "" {
+		resp.Warnings = append(resp.Warnings, warning)
+	}
+	if isLate {
+		log.Printf("Late reading of asset sensor %s taken at %s", req.AssetSensorID, readingTime.Format(time.RFC3339))
+		resp.Warnings = append(resp.Warnings, "reading is older than the last reading of the asset sensor; the last reading was kept")
+	}
+	if len(result.violations) > 0 {
+		resp.Warnings = append(resp.Warnings, result.warnings()...)
+		resp.Message = "Flexible IoT sensor reading created successfully (some fields skipped)"
+		if policy == entity.IngestionPolicyPermissive {
+			resp.Message = "Flexible IoT sensor reading created successfully (some fields don't match their sensor type)"
 		}
 
-		// Set measurement type and label
-		flexibleReading.MeasurementType = key
-		labelCopy := measurement.Label
-		flexibleReading.MeasurementLabel = &labelCopy
-
-		// Set unit if provided
-		if measurement.Unit != "" {
-			unitCopy := measurement.Unit
-			flexibleReading.MeasurementUnit = &unitCopy
+		// The readings are stored, so a failed quarantine only loses the skipped fields
+		quarantined, err := quarantine()
+		if err != nil {
+			resp.Warnings = append(resp.Warnings, "skipped fields could not be quarantined")
+		} else if quarantined != nil {
+			resp.QuarantineID = &quarantined.ID
 		}
-
-		// Set value based on type
-		switch v := measurement.Value.(type) {
-		case float64:
-			valueCopy := v
-			flexibleReading.NumericValue = &valueCopy
-			log.Printf("Set numeric value: %f for measurement type: %s", v, key)
-		case string:
-			valueCopy := v
-			flexibleReading.TextValue = &valueCopy
-			log.Printf("Set text value: %s for measurement type: %s", v, key)
-		case bool:
-			valueCopy := v
-			flexibleReading.BooleanValue = &valueCopy
-			log.Printf("Set boolean value: %t for measurement type: %s", v, key)
-		default:
-			log.Printf("Warning: Unknown value type %T for measurement type: %s", v, key)
-		}
-
-		flexibleReadings = append(flexibleReadings, flexibleReading)
-	}
-
-	log.Printf("Created %d flexible reading entities", len(flexibleReadings))
-
-	// Store all flexible readings using batch create
-	if err := s.iotSensorReadingRepo.CreateFlexibleBatch(ctx, flexibleReadings); err != nil {
-		log.Printf("Error creating flexible IoT sensor readings: %v", err)
-		return nil, fmt.Errorf("failed to create flexible IoT sensor readings: %w", err)
 	}
+	return resp, nil
+}
 
-	log.Printf("Successfully created %d flexible IoT sensor readings", len(flexibleReadings))
+// duplicateMessageResponse describes a message that was already stored, using the first
+// reading stored for it
+func (s *IoTSensorReadingService) duplicateMessageResponse(ctx context.Context, message *entity.ReadingMessage) *dto.IoTSensorReadingResponse {
+	resp := &dto.IoTSensorReadingResponse{AssetSensorID: message.AssetSensorID}
+	if len(message.ReadingIDs) > 0 {
+		original, err := s.iotSensorReadingRepo.GetFlexibleByID(ctx, message.ReadingIDs[0])
+		if err != nil {
+			log.Printf("Error loading original reading %s: %v", message.ReadingIDs[0], err)
+		} else if original != nil {
+			resp = s.toResponseDTO(original)
+		}
+	}
 
-	// Check thresholds for flexible readings (non-blocking)
-	go s.checkThresholdsForMultipleReadings(ctx, flexibleReadings)
+	resp.ReadingIDs = message.ReadingIDs
+	resp.Duplicate = true
+	resp.Message = "Message was already stored, no new readings were created"
+	return resp
+}
 
-	// Convert to response using the first reading as base (all have same basic info)
-	if len(flexibleReadings) > 0 {
-		resp := s.toResponseDTO(flexibleReadings[0])
-		if len(warnings) > 0 {
-			resp.Message = "Flexible IoT sensor reading created successfully (some fields skipped)"
-			resp.Warnings = warnings
+// fieldUnit returns the unit of an asset sensor's measurement field, or "" when the
+// field has none
+func fieldUnit(assetSensor *repository.AssetSensorWithDetails, name string) string {
+	for _, measurementType := range assetSensor.MeasurementTypes {
+		for _, field := range measurementType.Fields {
+			if field.Name == name && field.Unit != nil {
+				return *field.Unit
+			}
 		}
-		return resp, nil
 	}
+	return ""
+}
 
-	return nil, fmt.Errorf("no measurements were processed")
+// validateMessageID checks an optional client message ID
+func validateMessageID(messageID string) error {
+	if len(messageID) > entity.MaxReadingMessageIDLength {
+		return common.NewValidationError(fmt.Sprintf("message_id must be at most %d characters", entity.MaxReadingMessageIDLength), nil)
+	}
+	return nil
 }
 
 // GetFlexibleIoTSensorReading gets a flexible IoT sensor reading by ID
@@ -1283,38 +1464,17 @@ func (s *IoTSensorReadingService) GetFlexibleIoTSensorReading(ctx context.Contex
 	// Convert to response
 	baseResponse := s.toResponseDTO(flexibleReading)
 
-	// Convert measurement data to proper format
-	measurementData := make(map[string]dto.MeasurementValue)
-
-	// Add measurement data from the reading
-	measurementValue := dto.MeasurementValue{}
-	if flexibleReading.MeasurementLabel != nil {
-		measurementValue.Label = *flexibleReading.MeasurementLabel
-	}
-	if flexibleReading.MeasurementUnit != nil {
-		measurementValue.Unit = *flexibleReading.MeasurementUnit
-	}
-
-	// Set value based on type
-	if flexibleReading.NumericValue != nil {
-		measurementValue.Value = *flexibleReading.NumericValue
-	} else if flexibleReading.TextValue != nil {
-		measurementValue.Value = *flexibleReading.TextValue
-	} else if flexibleReading.BooleanValue != nil {
-		measurementValue.Value = *flexibleReading.BooleanValue
-	}
-
-	measurementData[flexibleReading.MeasurementType] = measurementValue
-
 	response := &dto.FlexibleIoTSensorReadingResponse{
 		IoTSensorReadingResponse: baseResponse,
-		MeasurementData:          measurementData,
+		MeasurementData:          baseResponse.MeasurementData,
 	}
 
 	return response, nil
 }
 
-// CreateBulkFlexibleIoTSensorReadings creates multiple flexible IoT sensor readings in batch
+// CreateBulkFlexibleIoTSensorReadings creates multiple flexible IoT sensor readings in batch.
+// Every request is validated by the ingestion policy of its sensor type; requests none of
+// whose measurements were accepted are quarantined and reported without readings.
 func (s *IoTSensorReadingService) CreateBulkFlexibleIoTSensorReadings(ctx context.Context, requests []*dto.FlexibleIoTSensorReadingRequest) ([]*dto.IoTSensorReadingResponse, error) {
 	if len(requests) == 0 {
 		return nil, common.NewValidationError("at least one reading is required", nil)
@@ -1323,8 +1483,21 @@ func (s *IoTSensorReadingService) CreateBulkFlexibleIoTSensorReadings(ctx contex
 		return nil, common.NewValidationError("maximum 1000 readings allowed per batch", nil)
 	}
 
-	var readings []*entity.IoTSensorReadingFlexible
+	// bulkEntry is a request of the batch with the outcome of its ingestion policy
+	type bulkEntry struct {
+		req      *dto.FlexibleIoTSensorReadingRequest
+		tenantID *uuid.UUID
+		policy   entity.IngestionPolicy
+		resolved *entity.ResolvedReadingTime
+		result   *policyResult
+		message  *entity.ReadingMessage // nil when no measurement was accepted
+	}
+
+	var entries []*bulkEntry
+	var messages []*entity.ReadingMessage
 	var responses []*dto.IoTSensorReadingResponse
+	policies := make(map[uuid.UUID]entity.IngestionPolicy)
+	timeSettings := make(map[uuid.UUID]*entity.SensorTimeSettings)
 
 	now := time.Now()
 
@@ -1333,6 +1506,9 @@ func (s *IoTSensorReadingService) CreateBulkFlexibleIoTSensorReadings(ctx contex
 		if req.AssetSensorID == uuid.Nil {
 			return nil, fmt.Errorf("validation error for reading %d: asset_sensor_id is required", i)
 		}
+		if err := validateMessageID(req.MessageID); err != nil {
+			return nil, fmt.Errorf("validation error for reading %d: %w", i, err)
+		}
 
 		// Validate asset sensor exists and get its tenant_id
 		assetSensor, err := s.assetSensorRepo.GetByID(ctx, req.AssetSensorID)
@@ -1343,6 +1519,28 @@ func (s *IoTSensorReadingService) CreateBulkFlexibleIoTSensorReadings(ctx contex
 			return nil, fmt.Errorf("asset sensor not found for reading %d", i)
 		}
 
+		policy, ok := policies[req.SensorTypeID]
+		if !ok {
+			if policy, err = s.ingestionPolicy(req.SensorTypeID); err != nil {
+				return nil, err
+			}
+			policies[req.SensorTypeID] = policy
+		}
+
+		// Parse and correct the reading time by the sensor's time settings
+		settings, ok := timeSettings[req.AssetSensorID]
+		if !ok {
+			if settings, err = s.readingTimeSettings(ctx, req.AssetSensorID); err != nil {
+				return nil, err
+			}
+			timeSettings[req.AssetSensorID] = settings
+		}
+		resolved, err := resolveReadingTime(settings, req, now, true)
+		if err != nil {
+			return nil, fmt.Errorf("validation error for reading %d: %w", i, err)
+		}
+		isLate := assetSensor.AssetSensor.IsLateReading(resolved.ReadingTime)
+
 		// Get location information from asset
 		locationID, locationName, err := s.getLocationFromAssetSensor(ctx, req.AssetSensorID)
 		if err != nil {
@@ -1350,79 +1548,108 @@ func (s *IoTSensorReadingService) CreateBulkFlexibleIoTSensorReadings(ctx contex
 			// Continue without location
 		}
 
-		// Create flexible reading entity
-		flexibleReading := &entity.IoTSensorReadingFlexible{
-			ID:            uuid.New(),
-			AssetSensorID: req.AssetSensorID,
-			SensorTypeID:  req.SensorTypeID,
-			ReadingTime:   now,
-			CreatedAt:     now,
+		// Create one flexible reading entity per measurement
+		newReading := func(name string) *entity.IoTSensorReadingFlexible {
+			flexibleReading := &entity.IoTSensorReadingFlexible{
+				ID:              uuid.New(),
+				TenantID:        assetSensor.AssetSensor.TenantID,
+				AssetSensorID:   req.AssetSensorID,
+				SensorTypeID:    req.SensorTypeID,
+				MeasurementType: name,
+				IsLate:          isLate,
+				CreatedAt:       now,
+			}
+			resolved.Apply(flexibleReading)
+
+			// Set location fields if available
+			if locationID != uuid.Nil {
+				locationIDCopy := locationID
+				flexibleReading.LocationID = &locationIDCopy
+			}
+			if locationName != "" {
+				locationNameCopy := locationName
+				flexibleReading.LocationName = &locationNameCopy
+			}
+
+			// Set MAC address if provided
+			if req.MacAddress != "" {
+				macAddressCopy := req.MacAddress
+				flexibleReading.MacAddress = &macAddressCopy
+			}
+			return flexibleReading
 		}
 
-		// Set location fields if available
-		if locationID != uuid.Nil {
-			locationIDCopy := locationID
-			flexibleReading.LocationID = &locationIDCopy
+		entry := &bulkEntry{
+			req:      req,
+			tenantID: assetSensor.AssetSensor.TenantID,
+			policy:   policy,
+			resolved: resolved,
+			result:   applyIngestionPolicy(policy, measurementFields(assetSensor), req.MeasurementData, newReading, true),
 		}
-		if locationName != "" {
-			locationNameCopy := locationName
-			flexibleReading.LocationName = &locationNameCopy
+		if len(entry.result.readings) > 0 {
+			entry.message = &entity.ReadingMessage{
+				AssetSensorID: req.AssetSensorID,
+				MessageID:     req.MessageID,
+				Readings:      entry.result.readings,
+			}
+			messages = append(messages, entry.message)
 		}
+		entries = append(entries, entry)
+	}
 
-		// Set tenant ID
-		if assetSensor.AssetSensor.TenantID != nil {
-			flexibleReading.TenantID = assetSensor.AssetSensor.TenantID
+	// Store the flexible readings in batch
+	if len(messages) > 0 {
+		if err := s.iotSensorReadingRepo.CreateFlexibleMessages(ctx, messages); err != nil {
+			log.Printf("Error creating flexible IoT sensor readings in batch: %v", err)
+			return nil, fmt.Errorf("failed to create flexible IoT sensor readings in batch: %w", err)
 		}
+	}
 
-		// Set MAC address if provided
-		if req.MacAddress != "" {
-			macAddressCopy := req.MacAddress
-			flexibleReading.MacAddress = &macAddressCopy
+	// Apply the side effects of the readings that were stored (non-blocking)
+	var readings []*entity.IoTSensorReadingFlexible
+	for _, message := range messages {
+		if !message.Duplicate {
+			readings = append(readings, message.Readings...)
 		}
+	}
+	if len(readings) > 0 {
+		s.applyReadingEffects(ctx, readings)
+	}
 
-		// Convert measurement data to proper format
-		for key, measurement := range req.MeasurementData {
-			// Set measurement type
-			flexibleReading.MeasurementType = key
-
-			// Set label if provided
-			if measurement.Label != "" {
-				labelCopy := measurement.Label
-				flexibleReading.MeasurementLabel = &labelCopy
-			}
+	// Convert to responses, quarantining what the policies held back
+	for _, entry := range entries {
+		if entry.message != nil && entry.message.Duplicate {
+			responses = append(responses, s.duplicateMessageResponse(ctx, entry.message))
+			continue
+		}
 
-			// Set unit if provided
-			if measurement.Unit != "" {
-				unitCopy := measurement.Unit
-				flexibleReading.MeasurementUnit = &unitCopy
+		var resp *dto.IoTSensorReadingResponse
+		if entry.message == nil {
+			resp = &dto.IoTSensorReadingResponse{
+				AssetSensorID: entry.req.AssetSensorID,
+				SensorTypeID:  entry.req.SensorTypeID,
+				MacAddress:    entry.req.MacAddress,
+				ReadingTime:   entry.resolved.ReadingTime,
+				Message:       "No measurements were stored",
 			}
+		} else {
+			resp = s.toResponseDTO(entry.message.Readings[0])
+			resp.ReadingIDs = entry.message.ReadingIDs
+		}
 
-			// Set value based on type
-			switch v := measurement.Value.(type) {
-			case float64:
-				valueCopy := v
-				flexibleReading.NumericValue = &valueCopy
-			case string:
-				valueCopy := v
-				flexibleReading.TextValue = &valueCopy
-			case bool:
-				valueCopy := v
-				flexibleReading.BooleanValue = &valueCopy
+		if warning := entry.resolved.Warning(); warning != "" {
+			resp.Warnings = append(resp.Warnings, warning)
+		}
+		if len(entry.result.violations) > 0 {
+			resp.Warnings = append(resp.Warnings, entry.result.warnings()...)
+			quarantined, err := s.quarantine(ctx, entry.req, entry.tenantID, entry.policy, entry.result, entry.resolved.ReadingTime)
+			if err != nil {
+				resp.Warnings = append(resp.Warnings, "skipped fields could not be quarantined")
+			} else if quarantined != nil {
+				resp.QuarantineID = &quarantined.ID
 			}
 		}
-
-		readings = append(readings, flexibleReading)
-	}
-
-	// Store the flexible readings in batch
-	if err := s.iotSensorReadingRepo.CreateFlexibleBatch(ctx, readings); err != nil {
-		log.Printf("Error creating flexible IoT sensor readings in batch: %v", err)
-		return nil, fmt.Errorf("failed to create flexible IoT sensor readings in batch: %w", err)
-	}
-
-	// Convert to responses
-	for _, reading := range readings {
-		responses = append(responses, s.toResponseDTO(reading))
+		responses = append(responses, resp)
 	}
 
 	return responses, nil
@@ -1497,26 +1724,44 @@ func (s *IoTSensorReadingService) ParseTextToFlexibleReading(ctx context.Context
 	}, nil
 }
 
-// checkThresholdsForReading checks if a sensor reading breaches any thresholds and creates alerts
+// checkThresholdsForReading checks if a sensor reading breaches any thresholds or alert
+// conditions and creates alerts. It returns an error when the reading could not be
+// evaluated, so the evaluation can be retried.
 func (s *IoTSensorReadingService) checkThresholdsForReading(
 	ctx context.Context,
 	reading *entity.IoTSensorReadingFlexible,
-) {
+) error {
+	// Composite conditions may use non-numeric fields, so evaluate them first
+	var conditionErr error
+	if s.alertConditionService != nil {
+		if err := s.alertConditionService.EvaluateReading(ctx, reading); err != nil {
+			conditionErr = fmt.Errorf("failed to evaluate alert conditions: %w", err)
+		}
+	}
+
+	return errors.Join(conditionErr, s.checkReadingThresholds(ctx, reading))
+}
+
+// checkReadingThresholds checks a sensor reading against the thresholds of its field
+func (s *IoTSensorReadingService) checkReadingThresholds(
+	ctx context.Context,
+	reading *entity.IoTSensorReadingFlexible,
+) error {
 	// Skip threshold checking if service is not available
 	if s.sensorThresholdService == nil {
-		return
+		return nil
 	}
 
-	// Only check numeric values for threshold breaches
-	if reading.NumericValue == nil {
-		return
+	// Readings without a value can't breach a threshold
+	value := reading.GetValue()
+	if value == nil {
+		return nil
 	}
 
 	// Get measurement types for this sensor type
 	measurementTypes, err := s.sensorMeasurementTypeRepo.GetBySensorTypeID(ctx, reading.SensorTypeID)
 	if err != nil {
-		log.Printf("Failed to get measurement types for threshold checking: %v", err)
-		return
+		return fmt.Errorf("failed to get measurement types for threshold checking: %w", err)
 	}
 
 	// Find the measurement type that matches the reading's measurement type
@@ -1531,19 +1776,18 @@ func (s *IoTSensorReadingService) checkThresholdsForReading(
 	// If no matching measurement type found, skip threshold checking
 	if targetMeasurementTypeID == uuid.Nil {
 		log.Printf("No measurement type found for '%s', skipping threshold check", reading.MeasurementType)
-		return
+		return nil
 	}
 
 	// Get asset information for alert context
 	assetSensor, err := s.assetSensorRepo.GetByID(ctx, reading.AssetSensorID)
 	if err != nil {
-		log.Printf("Failed to get asset sensor for threshold checking: %v", err)
-		return
+		return fmt.Errorf("failed to get asset sensor for threshold checking: %w", err)
 	}
 
 	if assetSensor == nil {
 		log.Printf("Asset sensor not found for threshold checking")
-		return
+		return nil
 	}
 
 	// Convert flexible reading to IoTSensorReading
@@ -1562,34 +1806,76 @@ func (s *IoTSensorReadingService) checkThresholdsForReading(
 		ctx,
 		iotReading,
 		reading.MeasurementType,
-		*reading.NumericValue,
+		value,
 	)
 
 	if err != nil {
-		log.Printf("Error checking thresholds for reading %s: %v", reading.ID, err)
-		return
+		return fmt.Errorf("failed to check thresholds for reading %s: %w", reading.ID, err)
 	}
 
-	log.Printf("Successfully checked thresholds for sensor reading %s (value: %.2f, type: %s)",
-		reading.ID, *reading.NumericValue, reading.MeasurementType)
+	log.Printf("Successfully checked thresholds for sensor reading %s (value: %v, type: %s)",
+		reading.ID, value, reading.MeasurementType)
+	return nil
+}
+
+// applyReadingEffects hands stored readings to the outbox dispatcher, which updates the
+// last reading cache and evaluates alerts from the outbox events recorded with the
+// readings. Without a dispatcher, the readings are only evaluated.
+func (s *IoTSensorReadingService) applyReadingEffects(
+	ctx context.Context,
+	readings []*entity.IoTSensorReadingFlexible,
+) {
+	if s.readingOutbox != nil {
+		s.readingOutbox.Notify()
+		return
+	}
+	s.checkThresholdsForMultipleReadings(ctx, readings)
 }
 
-// checkThresholdsForMultipleReadings checks thresholds for multiple readings in batch
+// checkThresholdsForMultipleReadings queues readings for threshold and condition checking.
+// Queueing only waits when the evaluation queue is full; ctx bounds that wait and is not
+// used by the evaluation itself.
 func (s *IoTSensorReadingService) checkThresholdsForMultipleReadings(
 	ctx context.Context,
 	readings []*entity.IoTSensorReadingFlexible,
 ) {
-	// Skip if threshold service is not available
-	if s.sensorThresholdService == nil {
+	// Skip if neither threshold nor condition checking is available
+	if s.sensorThresholdService == nil && s.alertConditionService == nil {
+		return
+	}
+
+	// Evaluate in event-time order, so readings sent out of order in one batch still
+	// move the alert states forward
+	readings = append([]*entity.IoTSensorReadingFlexible(nil), readings...)
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].ReadingTime.Before(readings[j].ReadingTime)
+	})
+
+	if s.evaluationQueue == nil {
+		// Without a queue, evaluate in the background detached from the request
+		go func() {
+			for _, reading := range readings {
+				if err := s.checkThresholdsForReading(context.Background(), reading); err != nil {
+					log.Printf("Error evaluating alerts for reading %s: %v", reading.ID, err)
+				}
+			}
+		}()
 		return
 	}
 
-	// Process each reading for threshold checking
 	for _, reading := range readings {
-		// Use a separate goroutine for non-blocking threshold checking
-		go func(r *entity.IoTSensorReadingFlexible) {
-			s.checkThresholdsForReading(ctx, r)
-		}(reading)
+		if !s.evaluationQueue.Enqueue(ctx, reading, s.logEvaluationError(reading)) {
+			log.Printf("Alert evaluation skipped for reading %s", reading.ID)
+		}
+	}
+}
+
+// logEvaluationError logs a failed evaluation of a reading that isn't retried
+func (s *IoTSensorReadingService) logEvaluationError(reading *entity.IoTSensorReadingFlexible) EvaluationDone {
+	return func(err error) {
+		if err != nil {
+			log.Printf("Error evaluating alerts for reading %s: %v", reading.ID, err)
+		}
 	}
 }
 
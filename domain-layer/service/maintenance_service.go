@@ -0,0 +1,347 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceStatus describes how alerts of an asset are handled at a point in time
+type MaintenanceStatus struct {
+	Suppress bool                      // Do not open new alerts or notify them
+	Window   *entity.MaintenanceWindow // Window in progress, nil when the asset status alone is maintenance
+}
+
+// alertMaintenance returns how the repository handles alerts opened under the status
+func (m *MaintenanceStatus) alertMaintenance() repository.AlertMaintenance {
+	if m == nil {
+		return repository.AlertMaintenance{}
+	}
+	maintenance := repository.AlertMaintenance{Suppress: m.Suppress}
+	if m.Window != nil {
+		windowID := m.Window.ID
+		maintenance.WindowID = &windowID
+	}
+	return maintenance
+}
+
+// MaintenanceService manages maintenance windows, tells threshold evaluation whether
+// alerts are suppressed or tagged, and switches asset statuses for windows that ask for it
+type MaintenanceService struct {
+	windowRepo    repository.MaintenanceWindowRepository
+	assetRepo     repository.AssetRepository
+	locationRepo  *repository.LocationRepository
+	assetTypeRepo *repository.AssetTypeRepository
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewMaintenanceService creates a new instance of MaintenanceService
+func NewMaintenanceService(
+	windowRepo repository.MaintenanceWindowRepository,
+	assetRepo repository.AssetRepository,
+	locationRepo *repository.LocationRepository,
+	assetTypeRepo *repository.AssetTypeRepository,
+) *MaintenanceService {
+	return &MaintenanceService{
+		windowRepo:    windowRepo,
+		assetRepo:     assetRepo,
+		locationRepo:  locationRepo,
+		assetTypeRepo: assetTypeRepo,
+	}
+}
+
+// CreateWindow creates a maintenance window for a tenant
+func (s *MaintenanceService) CreateWindow(ctx context.Context, tenantID uuid.UUID, req dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	window := entity.NewMaintenanceWindow()
+	window.TenantID = tenantID
+	if err := s.applyWindowRequest(ctx, tenantID, window, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.windowRepo.Create(ctx, window); err != nil {
+		log.Printf("Error creating maintenance window: %v", err)
+		return nil, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	log.Printf("Created maintenance window %s for tenant %s", window.ID, tenantID)
+	now := time.Now()
+	s.syncAssetStatus(ctx, window, now)
+
+	response := dto.MaintenanceWindowFromEntity(window, now)
+	return &response, nil
+}
+
+// GetWindow retrieves a maintenance window of a tenant
+func (s *MaintenanceService) GetWindow(ctx context.Context, tenantID, id uuid.UUID) (*dto.MaintenanceWindowResponse, error) {
+	window, err := s.getTenantWindow(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.MaintenanceWindowFromEntity(window, time.Now())
+	return &response, nil
+}
+
+// ListWindows lists the maintenance windows of a tenant, optionally only those scoped to an asset
+func (s *MaintenanceService) ListWindows(ctx context.Context, tenantID uuid.UUID, assetID *uuid.UUID, page, limit int) (*dto.MaintenanceWindowListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	windows, totalCount, err := s.windowRepo.List(ctx, tenantID, assetID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+
+	now := time.Now()
+	data := make([]dto.MaintenanceWindowResponse, 0, len(windows))
+	for _, window := range windows {
+		data = append(data, dto.MaintenanceWindowFromEntity(window, now))
+	}
+
+	return &dto.MaintenanceWindowListResponse{
+		Data:       data,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateWindow updates a maintenance window of a tenant
+func (s *MaintenanceService) UpdateWindow(ctx context.Context, tenantID, id uuid.UUID, req dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	window, err := s.getTenantWindow(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyWindowRequest(ctx, tenantID, window, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.windowRepo.Update(ctx, window); err != nil {
+		log.Printf("Error updating maintenance window: %v", err)
+		return nil, fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+
+	// The scope may have changed, so release the assets before applying the new window
+	if _, err := s.windowRepo.RestoreAssetStatus(ctx, window.ID); err != nil {
+		log.Printf("Error restoring asset status for maintenance window %s: %v", window.ID, err)
+	}
+	now := time.Now()
+	s.syncAssetStatus(ctx, window, now)
+
+	response := dto.MaintenanceWindowFromEntity(window, now)
+	return &response, nil
+}
+
+// DeleteWindow deletes a maintenance window of a tenant, restoring the asset statuses it changed
+func (s *MaintenanceService) DeleteWindow(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantWindow(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if _, err := s.windowRepo.RestoreAssetStatus(ctx, id); err != nil {
+		return fmt.Errorf("failed to restore asset status: %w", err)
+	}
+
+	if err := s.windowRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting maintenance window: %v", err)
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	log.Printf("Deleted maintenance window %s for tenant %s", id, tenantID)
+	return nil
+}
+
+// CheckMaintenance reports whether alerts of an asset are suppressed or tagged at the
+// given time. It returns nil when the asset is not under maintenance. Suppressing windows
+// take precedence over tagging ones, and an asset whose status is maintenance is
+// suppressed even without a window.
+func (s *MaintenanceService) CheckMaintenance(ctx context.Context, assetID uuid.UUID, at time.Time) (*MaintenanceStatus, error) {
+	asset, err := s.assetRepo.GetByID(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	if asset == nil {
+		return nil, nil
+	}
+
+	windows, err := s.windowRepo.GetActiveForAsset(ctx, asset, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	var status *MaintenanceStatus
+	for _, window := range windows {
+		if !window.Covers(asset) || !window.ActiveAt(at) {
+			continue
+		}
+		if window.AlertMode == entity.MaintenanceAlertSuppress {
+			return &MaintenanceStatus{Suppress: true, Window: window}, nil
+		}
+		if status == nil {
+			status = &MaintenanceStatus{Window: window}
+		}
+	}
+	if status != nil {
+		return status, nil
+	}
+
+	if asset.Status == string(entity.AssetStatusMaintenance) {
+		return &MaintenanceStatus{Suppress: true}, nil
+	}
+	return nil, nil
+}
+
+// ProcessAssetStatus switches asset statuses for every enabled window that starts or
+// ends, and returns how many assets changed
+func (s *MaintenanceService) ProcessAssetStatus(ctx context.Context) (int, error) {
+	windows, err := s.windowRepo.GetActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	now := time.Now()
+	changed := 0
+	for _, window := range windows {
+		changed += s.syncAssetStatus(ctx, window, now)
+	}
+
+	return changed, nil
+}
+
+// Start runs the asset status scheduler at the given interval
+func (s *MaintenanceService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if _, err := s.ProcessAssetStatus(context.Background()); err != nil {
+					log.Printf("Maintenance scheduler: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Maintenance scheduler started (interval %s)", interval)
+}
+
+// Stop stops the asset status scheduler and waits for the current run to finish
+func (s *MaintenanceService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Maintenance scheduler stopped")
+}
+
+// syncAssetStatus puts the covered assets in maintenance while a window that asks for it
+// is in progress and restores them otherwise. It returns how many assets changed.
+func (s *MaintenanceService) syncAssetStatus(ctx context.Context, window *entity.MaintenanceWindow, now time.Time) int {
+	if window.SetAssetStatus && window.ActiveAt(now) {
+		count, err := s.windowRepo.ApplyAssetStatus(ctx, window)
+		if err != nil {
+			log.Printf("Error applying maintenance status for window %s: %v", window.ID, err)
+			return 0
+		}
+		if count > 0 {
+			log.Printf("Maintenance window %s started: %d assets switched to maintenance", window.ID, count)
+		}
+		return count
+	}
+
+	count, err := s.windowRepo.RestoreAssetStatus(ctx, window.ID)
+	if err != nil {
+		log.Printf("Error restoring asset status for window %s: %v", window.ID, err)
+		return 0
+	}
+	if count > 0 {
+		log.Printf("Maintenance window %s ended: %d assets restored", window.ID, count)
+	}
+	return count
+}
+
+// applyWindowRequest validates a window request and copies it onto the window
+func (s *MaintenanceService) applyWindowRequest(ctx context.Context, tenantID uuid.UUID, window *entity.MaintenanceWindow, req dto.MaintenanceWindowRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return common.NewValidationError("name is required", nil)
+	}
+
+	if req.AssetID != nil {
+		asset, err := s.assetRepo.GetByID(ctx, *req.AssetID)
+		if err != nil {
+			return fmt.Errorf("failed to validate asset: %w", err)
+		}
+		if asset == nil || asset.TenantID == nil || *asset.TenantID != tenantID {
+			return common.NewValidationError("asset not found", nil)
+		}
+	}
+	if req.LocationID != nil {
+		if _, err := s.locationRepo.GetByID(ctx, *req.LocationID); err != nil {
+			return common.NewValidationError("location not found", nil)
+		}
+	}
+	if req.AssetTypeID != nil {
+		if _, err := s.assetTypeRepo.GetByID(ctx, *req.AssetTypeID); err != nil {
+			return common.NewValidationError("asset type not found", nil)
+		}
+	}
+
+	window.Name = strings.TrimSpace(req.Name)
+	window.Description = req.Description
+	window.AssetID = req.AssetID
+	window.LocationID = req.LocationID
+	window.AssetTypeID = req.AssetTypeID
+	window.StartTime = req.StartTime
+	window.EndTime = req.EndTime
+	window.Recurrence = entity.MaintenanceRecurrenceNone
+	if req.Recurrence != "" {
+		window.Recurrence = req.Recurrence
+	}
+	window.RecurrenceUntil = req.RecurrenceUntil
+	window.AlertMode = entity.MaintenanceAlertSuppress
+	if req.AlertMode != "" {
+		window.AlertMode = req.AlertMode
+	}
+	if req.SetAssetStatus != nil {
+		window.SetAssetStatus = *req.SetAssetStatus
+	}
+	if req.IsActive != nil {
+		window.IsActive = *req.IsActive
+	}
+
+	if err := window.Validate(); err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+
+	return nil
+}
+
+// getTenantWindow loads a window and ensures it belongs to the tenant
+func (s *MaintenanceService) getTenantWindow(ctx context.Context, tenantID, id uuid.UUID) (*entity.MaintenanceWindow, error) {
+	window, err := s.windowRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+	if window == nil || window.TenantID != tenantID {
+		return nil, common.NewNotFoundError("maintenance window", id.String())
+	}
+
+	return window, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/mqtt"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Topic pattern placeholders understood by the MQTT ingestion bridge
+const (
+	mqttTopicTenantPlaceholder = "{tenant}"
+	mqttTopicMacPlaceholder    = "{mac}"
+)
+
+// ReadingCreator stores the flexible readings of an ingested message
+type ReadingCreator interface {
+	CreateFlexibleIoTSensorReading(ctx context.Context, req *dto.FlexibleIoTSensorReadingRequest) (*dto.IoTSensorReadingResponse, error)
+}
+
+// MQTTIngestionService bridges MQTT messages into IoT sensor readings.
+// Topics follow a configurable pattern such as tenant/{tenant}/sensor/{mac};
+// the MAC address segment resolves the asset sensor configured with that MAC,
+// among the sensors of the tenant in the optional tenant segment. Payloads of
+// sensor types with a payload decoder are decoded with it.
+type MQTTIngestionService struct {
+	subscriber              mqtt.Subscriber
+	iotSensorReadingService ReadingCreator
+	assetSensorRepo         repository.AssetSensorRepository
+	payloadDecoderService   *PayloadDecoderService
+	topicPattern            string
+	qos                     byte
+}
+
+// NewMQTTIngestionService creates a new instance of MQTTIngestionService
+func NewMQTTIngestionService(
+	subscriber mqtt.Subscriber,
+	iotSensorReadingService ReadingCreator,
+	assetSensorRepo repository.AssetSensorRepository,
+	payloadDecoderService *PayloadDecoderService,
+	topicPattern string,
+	qos byte,
+) *MQTTIngestionService {
+	return &MQTTIngestionService{
+		subscriber:              subscriber,
+		iotSensorReadingService: iotSensorReadingService,
+		assetSensorRepo:         assetSensorRepo,
+		payloadDecoderService:   payloadDecoderService,
+		topicPattern:            topicPattern,
+		qos:                     qos,
+	}
+}
+
+// Start subscribes to the topic filter derived from the configured pattern
+func (s *MQTTIngestionService) Start() error {
+	if !strings.Contains(s.topicPattern, mqttTopicMacPlaceholder) {
+		return fmt.Errorf("mqtt topic pattern %q must contain %s", s.topicPattern, mqttTopicMacPlaceholder)
+	}
+
+	filter := s.subscriptionFilter()
+	if err := s.subscriber.Subscribe(filter, s.qos, s.handleMessage); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", filter, err)
+	}
+
+	log.Printf("MQTT ingestion bridge listening on %s", filter)
+	return nil
+}
+
+// Stop closes the underlying subscriber
+func (s *MQTTIngestionService) Stop() error {
+	return s.subscriber.Close()
+}
+
+// handleMessage is the subscriber callback. Messages that can never be stored, being
+// invalid or for an unknown sensor, are logged and dropped since there is no way to
+// report them back to the publishing device; other failures are returned so the
+// message is not acknowledged and the broker delivers it again.
+func (s *MQTTIngestionService) handleMessage(msg mqtt.Message) error {
+	// Not bound to a cancellable context: threshold checks continue in the
+	// background after CreateFlexibleIoTSensorReading returns
+	_, err := s.ProcessMessage(context.Background(), msg.Topic, msg.Payload)
+	if err == nil {
+		return nil
+	}
+	log.Printf("Failed to ingest MQTT message on %s: %v", msg.Topic, err)
+	if common.IsValidationError(err) || common.IsNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// ProcessMessage converts a single MQTT message into flexible IoT sensor readings
+func (s *MQTTIngestionService) ProcessMessage(ctx context.Context, topic string, payload []byte) (*dto.IoTSensorReadingResponse, error) {
+	params, ok := s.parseTopic(topic)
+	if !ok {
+		return nil, common.NewValidationError(fmt.Sprintf("topic %s does not match pattern %s", topic, s.topicPattern), nil)
+	}
+
+	macAddress := params[mqttTopicMacPlaceholder]
+	if macAddress == "" {
+		return nil, common.NewValidationError("mac address segment is empty", nil)
+	}
+
+	// When the topic carries a tenant, only that tenant's sensors are considered
+	var tenantID *uuid.UUID
+	if tenant, hasTenant := params[mqttTopicTenantPlaceholder]; hasTenant {
+		parsed, err := uuid.Parse(tenant)
+		if err != nil {
+			return nil, common.NewValidationError(fmt.Sprintf("tenant segment %q is not a valid tenant id", tenant), nil)
+		}
+		tenantID = &parsed
+	}
+
+	assetSensor, err := s.assetSensorRepo.GetByMacAddress(ctx, macAddress, tenantID)
+	if errors.Is(err, repository.ErrAmbiguousMacAddress) {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve asset sensor: %w", err)
+	}
+	if assetSensor == nil {
+		return nil, common.NewNotFoundError("asset sensor for mac address", macAddress)
+	}
+
+	req := dto.FlexibleIoTSensorReadingRequest{SensorTypeID: assetSensor.SensorTypeID}
+	decoded, err := s.payloadDecoderService.DecodeReading(ctx, &req, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !decoded {
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, common.NewValidationError("payload must be a JSON object", err)
+		}
+	}
+
+	// Devices may also publish plain "field": value pairs
+	if !decoded && len(req.MeasurementData) == 0 {
+		req.MeasurementData = make(map[string]dto.MeasurementValue)
+		for key, value := range req.RawJSON {
+			switch value.(type) {
+			case float64, string, bool:
+				if key == "asset_sensor_id" || key == "sensor_type_id" || key == "mac_address" || key == "reading_time" || key == "message_id" {
+					continue
+				}
+				req.MeasurementData[key] = dto.MeasurementValue{Label: key, Value: value}
+			}
+		}
+	}
+
+	// Identity always comes from the topic, never from the payload
+	req.AssetSensorID = assetSensor.ID
+	req.SensorTypeID = assetSensor.SensorTypeID
+	req.MacAddress = macAddress
+	req.Source = entity.ReadingSourceMQTT
+
+	if assetSensor.TenantID != nil {
+		ctx = common.WithTenant(ctx, *assetSensor.TenantID)
+	}
+
+	return s.iotSensorReadingService.CreateFlexibleIoTSensorReading(ctx, &req)
+}
+
+// subscriptionFilter replaces every placeholder segment with the + wildcard
+func (s *MQTTIngestionService) subscriptionFilter() string {
+	levels := strings.Split(s.topicPattern, "/")
+	for i, level := range levels {
+		if strings.HasPrefix(level, "{") && strings.HasSuffix(level, "}") {
+			levels[i] = "+"
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+// parseTopic extracts placeholder values from a topic using the configured pattern
+func (s *MQTTIngestionService) parseTopic(topic string) (map[string]string, bool) {
+	patternLevels := strings.Split(s.topicPattern, "/")
+	topicLevels := strings.Split(topic, "/")
+	if len(patternLevels) != len(topicLevels) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, level := range patternLevels {
+		if strings.HasPrefix(level, "{") && strings.HasSuffix(level, "}") {
+			params[level] = topicLevels[i]
+			continue
+		}
+		if level != topicLevels[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/mqtt"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// macSensor is an asset sensor configured with a MAC address
+type macSensor struct {
+	macAddress string
+	sensor     *repository.AssetSensorWithDetails
+}
+
+// fakeMacAssetSensorRepository resolves asset sensors by their configured MAC address
+type fakeMacAssetSensorRepository struct {
+	repository.AssetSensorRepository
+	sensors []macSensor
+}
+
+func (r *fakeMacAssetSensorRepository) GetByMacAddress(ctx context.Context, macAddress string, tenantID *uuid.UUID) (*repository.AssetSensorWithDetails, error) {
+	var matches []*repository.AssetSensorWithDetails
+	for _, configured := range r.sensors {
+		if !strings.EqualFold(configured.macAddress, macAddress) {
+			continue
+		}
+		if tenantID != nil && (configured.sensor.TenantID == nil || *configured.sensor.TenantID != *tenantID) {
+			continue
+		}
+		matches = append(matches, configured.sensor)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if len(matches) > 1 && tenantID == nil {
+		return nil, repository.ErrAmbiguousMacAddress
+	}
+	return matches[0], nil
+}
+
+// fakePayloadDecoderRepository has no payload decoders
+type fakePayloadDecoderRepository struct {
+	repository.PayloadDecoderRepository
+}
+
+func (r *fakePayloadDecoderRepository) GetBySensorTypeID(ctx context.Context, sensorTypeID uuid.UUID) (*entity.PayloadDecoder, error) {
+	return nil, nil
+}
+
+// createdReading is a reading handed to recordingReadingCreator
+type createdReading struct {
+	tenantID  uuid.UUID
+	hasTenant bool
+	req       dto.FlexibleIoTSensorReadingRequest
+}
+
+// recordingReadingCreator records the readings it is asked to create
+type recordingReadingCreator struct {
+	readings []createdReading
+	err      error // Returned instead of creating readings when set
+}
+
+func (c *recordingReadingCreator) CreateFlexibleIoTSensorReading(ctx context.Context, req *dto.FlexibleIoTSensorReadingRequest) (*dto.IoTSensorReadingResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	tenantID, hasTenant := common.GetTenantID(ctx)
+	c.readings = append(c.readings, createdReading{tenantID: tenantID, hasTenant: hasTenant, req: *req})
+	return &dto.IoTSensorReadingResponse{AssetSensorID: req.AssetSensorID}, nil
+}
+
+// newTestAssetSensor returns an asset sensor of tenantID configured with macAddress
+func newTestAssetSensor(tenantID uuid.UUID, macAddress string) macSensor {
+	assetSensor := &repository.AssetSensorWithDetails{AssetSensor: entity.NewAssetSensor()}
+	assetSensor.TenantID = &tenantID
+	assetSensor.SensorTypeID = uuid.New()
+	return macSensor{macAddress: macAddress, sensor: assetSensor}
+}
+
+// startTestMQTTIngestion starts an ingestion bridge for topicPattern on an in-process broker
+func startTestMQTTIngestion(t *testing.T, topicPattern string, sensors ...macSensor) (*mqtt.InProcessBroker, *MQTTIngestionService, *recordingReadingCreator) {
+	t.Helper()
+	broker := mqtt.NewInProcessBroker()
+	creator := &recordingReadingCreator{}
+	ingestion := NewMQTTIngestionService(
+		broker,
+		creator,
+		&fakeMacAssetSensorRepository{sensors: sensors},
+		NewPayloadDecoderService(&fakePayloadDecoderRepository{}, nil, nil, nil),
+		topicPattern,
+		1,
+	)
+	if err := ingestion.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	t.Cleanup(func() { ingestion.Stop() })
+	return broker, ingestion, creator
+}
+
+// newTestMQTTIngestion starts an ingestion bridge on an in-process broker for an asset
+// sensor of tenantID configured with macAddress
+func newTestMQTTIngestion(t *testing.T, tenantID uuid.UUID, macAddress string) (*mqtt.InProcessBroker, *MQTTIngestionService, *repository.AssetSensorWithDetails, *recordingReadingCreator) {
+	t.Helper()
+	configured := newTestAssetSensor(tenantID, macAddress)
+	broker, ingestion, creator := startTestMQTTIngestion(t, "tenant/{tenant}/sensor/{mac}", configured)
+	return broker, ingestion, configured.sensor, creator
+}
+
+func TestMQTTIngestionCreatesReadingFromTopic(t *testing.T) {
+	tenantID := uuid.New()
+	broker, _, assetSensor, creator := newTestMQTTIngestion(t, tenantID, "AA:BB:CC:DD:EE:FF")
+
+	// Identity in the payload is ignored in favor of the topic
+	payload := `{"temperature": 21.5, "door_open": true, "asset_sensor_id": "` + uuid.NewString() + `", "message_id": "msg-1"}`
+	topic := "tenant/" + tenantID.String() + "/sensor/aa:bb:cc:dd:ee:ff"
+	if err := broker.Publish(topic, []byte(payload)); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+
+	if len(creator.readings) != 1 {
+		t.Fatalf("created %d readings, want 1", len(creator.readings))
+	}
+	created := creator.readings[0]
+	if created.req.AssetSensorID != assetSensor.ID || created.req.SensorTypeID != assetSensor.SensorTypeID {
+		t.Errorf("reading of asset sensor %s (type %s), want %s (type %s)",
+			created.req.AssetSensorID, created.req.SensorTypeID, assetSensor.ID, assetSensor.SensorTypeID)
+	}
+	if created.req.MacAddress != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("mac address = %q, want the topic segment", created.req.MacAddress)
+	}
+	if created.req.Source != entity.ReadingSourceMQTT {
+		t.Errorf("source = %q, want %q", created.req.Source, entity.ReadingSourceMQTT)
+	}
+	if !created.hasTenant || created.tenantID != tenantID {
+		t.Errorf("reading created in tenant %s (set %v), want %s", created.tenantID, created.hasTenant, tenantID)
+	}
+	if created.req.MessageID != "msg-1" {
+		t.Errorf("message id = %q, want msg-1", created.req.MessageID)
+	}
+
+	fields := created.req.MeasurementData
+	if len(fields) != 2 || fields["temperature"].Value != 21.5 || fields["door_open"].Value != true {
+		t.Errorf("measurement data = %v, want temperature and door_open", fields)
+	}
+}
+
+func TestMQTTIngestionRejectsTenantMismatch(t *testing.T) {
+	tenantID := uuid.New()
+	broker, ingestion, _, creator := newTestMQTTIngestion(t, tenantID, "AA:BB:CC:DD:EE:FF")
+
+	topic := "tenant/" + uuid.NewString() + "/sensor/AA:BB:CC:DD:EE:FF"
+	if err := broker.Publish(topic, []byte(`{"temperature": 21.5}`)); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if len(creator.readings) != 0 {
+		t.Fatalf("created %d readings for another tenant's topic, want 0", len(creator.readings))
+	}
+
+	_, err := ingestion.ProcessMessage(context.Background(), topic, []byte(`{"temperature": 21.5}`))
+	if !common.IsNotFoundError(err) {
+		t.Fatalf("ProcessMessage error = %v, want a not found error", err)
+	}
+
+	_, err = ingestion.ProcessMessage(context.Background(), "tenant/acme/sensor/AA:BB:CC:DD:EE:FF", []byte(`{"temperature": 21.5}`))
+	if !common.IsValidationError(err) {
+		t.Fatalf("ProcessMessage error = %v, want a validation error for an invalid tenant", err)
+	}
+}
+
+func TestMQTTIngestionResolvesSharedMacByTenant(t *testing.T) {
+	tenantA, tenantB := uuid.New(), uuid.New()
+	sensorA := newTestAssetSensor(tenantA, "AA:BB:CC:DD:EE:FF")
+	sensorB := newTestAssetSensor(tenantB, "aa:bb:cc:dd:ee:ff")
+	broker, _, creator := startTestMQTTIngestion(t, "tenant/{tenant}/sensor/{mac}", sensorA, sensorB)
+
+	for _, tenantID := range []uuid.UUID{tenantB, tenantA} {
+		if err := broker.Publish("tenant/"+tenantID.String()+"/sensor/AA:BB:CC:DD:EE:FF", []byte(`{"temperature": 21.5}`)); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+
+	if len(creator.readings) != 2 {
+		t.Fatalf("created %d readings, want 2", len(creator.readings))
+	}
+	if got := creator.readings[0].req.AssetSensorID; got != sensorB.sensor.ID {
+		t.Errorf("tenant B reading stored for asset sensor %s, want %s", got, sensorB.sensor.ID)
+	}
+	if got := creator.readings[1].req.AssetSensorID; got != sensorA.sensor.ID {
+		t.Errorf("tenant A reading stored for asset sensor %s, want %s", got, sensorA.sensor.ID)
+	}
+}
+
+func TestMQTTIngestionRejectsAmbiguousMacWithoutTenant(t *testing.T) {
+	sensorA := newTestAssetSensor(uuid.New(), "AA:BB:CC:DD:EE:FF")
+	sensorB := newTestAssetSensor(uuid.New(), "AA:BB:CC:DD:EE:FF")
+	_, ingestion, creator := startTestMQTTIngestion(t, "sensor/{mac}", sensorA, sensorB)
+
+	_, err := ingestion.ProcessMessage(context.Background(), "sensor/AA:BB:CC:DD:EE:FF", []byte(`{"temperature": 21.5}`))
+	if !common.IsValidationError(err) {
+		t.Fatalf("ProcessMessage error = %v, want a validation error", err)
+	}
+	if len(creator.readings) != 0 {
+		t.Errorf("created %d readings for an ambiguous mac address, want 0", len(creator.readings))
+	}
+}
+
+func TestMQTTIngestionReportsOnlyRetryableFailures(t *testing.T) {
+	tenantID := uuid.New()
+	broker, _, _, creator := newTestMQTTIngestion(t, tenantID, "AA:BB:CC:DD:EE:FF")
+
+	// A message that can never be stored is dropped, so the broker doesn't redeliver it
+	if err := broker.Publish("tenant/"+tenantID.String()+"/sensor/00:00:00:00:00:00", []byte(`{"temperature": 21.5}`)); err != nil {
+		t.Errorf("Publish for an unknown mac address returned error: %v", err)
+	}
+	if err := broker.Publish("tenant/"+tenantID.String()+"/sensor/AA:BB:CC:DD:EE:FF", []byte(`not json`)); err != nil {
+		t.Errorf("Publish of an invalid payload returned error: %v", err)
+	}
+
+	creator.err = errors.New("database unavailable")
+	if err := broker.Publish("tenant/"+tenantID.String()+"/sensor/AA:BB:CC:DD:EE:FF", []byte(`{"temperature": 21.5}`)); err == nil {
+		t.Error("Publish returned no error for a reading that failed to store")
+	}
+}
+
+func TestMQTTIngestionTopicParsing(t *testing.T) {
+	tenantID := uuid.New()
+	broker, ingestion, _, creator := newTestMQTTIngestion(t, tenantID, "AA:BB:CC:DD:EE:FF")
+
+	// Topics outside the subscription never reach the bridge
+	for _, topic := range []string{
+		"tenant/" + tenantID.String() + "/sensor/AA:BB:CC:DD:EE:FF/extra",
+		"tenant/" + tenantID.String() + "/device/AA:BB:CC:DD:EE:FF",
+	} {
+		if err := broker.Publish(topic, []byte(`{"temperature": 21.5}`)); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+	if len(creator.readings) != 0 {
+		t.Fatalf("created %d readings for unsubscribed topics, want 0", len(creator.readings))
+	}
+
+	tests := []struct {
+		name  string
+		topic string
+		check func(error) bool
+	}{
+		{"pattern mismatch", "tenant/" + tenantID.String() + "/device/AA:BB:CC:DD:EE:FF", common.IsValidationError},
+		{"empty mac", "tenant/" + tenantID.String() + "/sensor/", common.IsValidationError},
+		{"unknown mac", "tenant/" + tenantID.String() + "/sensor/00:00:00:00:00:00", common.IsNotFoundError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ingestion.ProcessMessage(context.Background(), tt.topic, []byte(`{"temperature": 21.5}`)); !tt.check(err) {
+				t.Errorf("ProcessMessage error = %v", err)
+			}
+		})
+	}
+
+	if filter := ingestion.subscriptionFilter(); filter != "tenant/+/sensor/+" {
+		t.Errorf("subscription filter = %q, want tenant/+/sensor/+", filter)
+	}
+	params, ok := ingestion.parseTopic("tenant/" + tenantID.String() + "/sensor/AA:BB:CC:DD:EE:FF")
+	if !ok || params["{tenant}"] != tenantID.String() || params["{mac}"] != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("parseTopic = %v, %v", params, ok)
+	}
+}
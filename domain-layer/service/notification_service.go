@@ -0,0 +1,648 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/notifier"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notificationClaimBatch is the number of deliveries processed per poll
+const notificationClaimBatch = 50
+
+// notificationSendLease is how long a claimed delivery stays reserved for a worker
+const notificationSendLease = 2 * time.Minute
+
+// NotificationRetryPolicy controls how failed deliveries are retried
+type NotificationRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // Delay after the first failure, doubled after every attempt
+	MaxDelay    time.Duration
+}
+
+// NotificationService manages notification channels and routing rules and delivers
+// alert notifications. Deliveries are queued in the database and sent by a
+// background worker, so alert evaluation never waits on an external endpoint.
+type NotificationService struct {
+	channelRepo  repository.NotificationChannelRepository
+	ruleRepo     repository.NotificationRuleRepository
+	deliveryRepo repository.NotificationDeliveryRepository
+	assetRepo    repository.AssetRepository
+	dispatcher   *notifier.Dispatcher
+	retryPolicy  NotificationRetryPolicy
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNotificationService creates a new instance of NotificationService
+func NewNotificationService(
+	channelRepo repository.NotificationChannelRepository,
+	ruleRepo repository.NotificationRuleRepository,
+	deliveryRepo repository.NotificationDeliveryRepository,
+	assetRepo repository.AssetRepository,
+	dispatcher *notifier.Dispatcher,
+	retryPolicy NotificationRetryPolicy,
+) *NotificationService {
+	if retryPolicy.MaxAttempts <= 0 {
+		retryPolicy.MaxAttempts = 1
+	}
+	return &NotificationService{
+		channelRepo:  channelRepo,
+		ruleRepo:     ruleRepo,
+		deliveryRepo: deliveryRepo,
+		assetRepo:    assetRepo,
+		dispatcher:   dispatcher,
+		retryPolicy:  retryPolicy,
+	}
+}
+
+// CreateChannel creates a notification channel for the tenant
+func (s *NotificationService) CreateChannel(ctx context.Context, tenantID uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	channel := entity.NewNotificationChannel()
+	channel.TenantID = tenantID
+	channel.Name = strings.TrimSpace(req.Name)
+	channel.Type = req.Type
+	channel.Config = req.Config
+	if req.IsActive != nil {
+		channel.IsActive = *req.IsActive
+	}
+
+	if err := channel.Validate(); err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.channelRepo.Create(ctx, channel); err != nil {
+		log.Printf("Error creating notification channel: %v", err)
+		return nil, fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	log.Printf("Created %s notification channel %s for tenant %s", channel.Type, channel.ID, tenantID)
+	response := dto.NotificationChannelFromEntity(channel)
+	return &response, nil
+}
+
+// GetChannel retrieves a notification channel belonging to the tenant
+func (s *NotificationService) GetChannel(ctx context.Context, tenantID, id uuid.UUID) (*dto.NotificationChannelResponse, error) {
+	channel, err := s.getTenantChannel(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.NotificationChannelFromEntity(channel)
+	return &response, nil
+}
+
+// ListChannels retrieves paginated notification channels for a tenant
+func (s *NotificationService) ListChannels(ctx context.Context, tenantID uuid.UUID, page, limit int) (*dto.NotificationChannelListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	channels, totalCount, err := s.channelRepo.List(ctx, tenantID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+
+	data := make([]dto.NotificationChannelResponse, 0, len(channels))
+	for _, channel := range channels {
+		data = append(data, dto.NotificationChannelFromEntity(channel))
+	}
+
+	return &dto.NotificationChannelListResponse{
+		Data:       data,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateChannel replaces the settings of a notification channel. Empty secrets keep
+// the stored values so clients can round-trip the redacted response.
+func (s *NotificationService) UpdateChannel(ctx context.Context, tenantID, id uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	channel, err := s.getTenantChannel(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config := req.Config
+	if config.Secret == "" || config.Secret == dto.RedactedSecret {
+		config.Secret = channel.Config.Secret
+	}
+	if config.SMTPPassword == "" || config.SMTPPassword == dto.RedactedSecret {
+		config.SMTPPassword = channel.Config.SMTPPassword
+	}
+
+	channel.Name = strings.TrimSpace(req.Name)
+	channel.Type = req.Type
+	channel.Config = config
+	if req.IsActive != nil {
+		channel.IsActive = *req.IsActive
+	}
+
+	if err := channel.Validate(); err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.channelRepo.Update(ctx, channel); err != nil {
+		log.Printf("Error updating notification channel: %v", err)
+		return nil, fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	response := dto.NotificationChannelFromEntity(channel)
+	return &response, nil
+}
+
+// DeleteChannel deletes a notification channel together with its rules and delivery log
+func (s *NotificationService) DeleteChannel(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantChannel(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := s.channelRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting notification channel: %v", err)
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	log.Printf("Deleted notification channel %s", id)
+	return nil
+}
+
+// TestChannel sends a test notification synchronously and reports the outcome
+func (s *NotificationService) TestChannel(ctx context.Context, tenantID, id uuid.UUID) (*dto.NotificationTestResponse, error) {
+	channel, err := s.getTenantChannel(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &notifier.Message{
+		Event:   entity.NotificationEventTest,
+		Subject: "[LecSens] Test notification",
+		Text:    fmt.Sprintf("This is a test notification for channel %q.", channel.Name),
+		Data: map[string]interface{}{
+			"channel_id": channel.ID,
+			"sent_at":    time.Now(),
+		},
+	}
+
+	if err := s.dispatcher.Send(ctx, channel, msg); err != nil {
+		log.Printf("Test notification to channel %s failed: %v", channel.ID, err)
+		return &dto.NotificationTestResponse{Success: false, Error: err.Error()}, nil
+	}
+
+	return &dto.NotificationTestResponse{Success: true}, nil
+}
+
+// CreateRule creates a routing rule for the tenant
+func (s *NotificationService) CreateRule(ctx context.Context, tenantID uuid.UUID, req dto.NotificationRuleRequest) (*entity.NotificationRule, error) {
+	rule := entity.NewNotificationRule()
+	rule.TenantID = tenantID
+	if err := s.applyRuleRequest(ctx, tenantID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		log.Printf("Error creating notification rule: %v", err)
+		return nil, fmt.Errorf("failed to create notification rule: %w", err)
+	}
+
+	log.Printf("Created notification rule %s for channel %s", rule.ID, rule.ChannelID)
+	return rule, nil
+}
+
+// GetRule retrieves a notification rule belonging to the tenant
+func (s *NotificationService) GetRule(ctx context.Context, tenantID, id uuid.UUID) (*entity.NotificationRule, error) {
+	return s.getTenantRule(ctx, tenantID, id)
+}
+
+// ListRules retrieves paginated notification rules for a tenant
+func (s *NotificationService) ListRules(ctx context.Context, tenantID uuid.UUID, channelID *uuid.UUID, page, limit int) (*dto.NotificationRuleListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	rules, totalCount, err := s.ruleRepo.List(ctx, tenantID, channelID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification rules: %w", err)
+	}
+	if rules == nil {
+		rules = []*entity.NotificationRule{}
+	}
+
+	return &dto.NotificationRuleListResponse{
+		Data:       rules,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateRule replaces the settings of a notification rule
+func (s *NotificationService) UpdateRule(ctx context.Context, tenantID, id uuid.UUID, req dto.NotificationRuleRequest) (*entity.NotificationRule, error) {
+	rule, err := s.getTenantRule(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRuleRequest(ctx, tenantID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		log.Printf("Error updating notification rule: %v", err)
+		return nil, fmt.Errorf("failed to update notification rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule deletes a notification rule
+func (s *NotificationService) DeleteRule(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getTenantRule(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting notification rule: %v", err)
+		return fmt.Errorf("failed to delete notification rule: %w", err)
+	}
+
+	log.Printf("Deleted notification rule %s", id)
+	return nil
+}
+
+// ListDeliveries retrieves the paginated delivery log of a tenant
+func (s *NotificationService) ListDeliveries(ctx context.Context, tenantID uuid.UUID, filter repository.NotificationDeliveryFilter, page, limit int) (*dto.NotificationDeliveryListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	deliveries, totalCount, err := s.deliveryRepo.List(ctx, tenantID, filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification deliveries: %w", err)
+	}
+	if deliveries == nil {
+		deliveries = []*entity.NotificationDelivery{}
+	}
+
+	return &dto.NotificationDeliveryListResponse{
+		Data:       deliveries,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// NotifyAlert queues notifications for an alert that was opened or resolved.
+// Other transitions are ignored. Deliveries are sent by the background worker.
+func (s *NotificationService) NotifyAlert(ctx context.Context, alert *entity.AssetAlert, transition entity.ThresholdTransition) error {
+	var event entity.NotificationEvent
+	switch transition {
+	case entity.ThresholdTransitionOpen:
+		event = entity.NotificationEventAlertOpened
+	case entity.ThresholdTransitionClose:
+		event = entity.NotificationEventAlertResolved
+	default:
+		return nil
+	}
+
+	// Queue even if the triggering request is cancelled meanwhile
+	ctx = context.WithoutCancel(ctx)
+
+	rules, err := s.ruleRepo.GetActiveByTenant(ctx, alert.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	asset, err := s.assetRepo.GetByID(ctx, alert.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset: %w", err)
+	}
+	var assetTypeID uuid.UUID
+	assetName := alert.AssetID.String()
+	if asset != nil {
+		assetTypeID = asset.AssetTypeID
+		assetName = asset.Name
+	}
+
+	payload, err := json.Marshal(buildAlertMessage(alert, assetName, event))
+	if err != nil {
+		return fmt.Errorf("failed to encode notification payload: %w", err)
+	}
+
+	// A channel targeted by several matching rules is notified once
+	notified := make(map[uuid.UUID]bool)
+	for _, rule := range rules {
+		if notified[rule.ChannelID] || !rule.Matches(alert, assetTypeID, event) {
+			continue
+		}
+		notified[rule.ChannelID] = true
+
+		ruleID := rule.ID
+		alertID := alert.ID
+		delivery := entity.NewNotificationDelivery()
+		delivery.TenantID = alert.TenantID
+		delivery.ChannelID = rule.ChannelID
+		delivery.RuleID = &ruleID
+		delivery.AlertID = &alertID
+		delivery.Event = event
+		delivery.Payload = payload
+
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			log.Printf("Error queueing notification for alert %s to channel %s: %v", alert.ID, rule.ChannelID, err)
+			continue
+		}
+		log.Printf("Queued %s notification for alert %s to channel %s", event, alert.ID, rule.ChannelID)
+	}
+
+	return nil
+}
+
+// NotifyEscalation queues notifications of an escalation step to its channels.
+// Channels that are inactive or belong to another tenant are skipped.
+func (s *NotificationService) NotifyEscalation(ctx context.Context, alert *entity.AssetAlert, escalation *entity.AssetAlertEscalation) error {
+	if len(escalation.ChannelIDs) == 0 {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	assetName := alert.AssetID.String()
+	asset, err := s.assetRepo.GetByID(ctx, alert.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset: %w", err)
+	}
+	if asset != nil {
+		assetName = asset.Name
+	}
+
+	msg := buildAlertMessage(alert, assetName, entity.NotificationEventAlertEscalated)
+	msg.Text += fmt.Sprintf("\nEscalation: %s, step %d", escalation.PolicyName, escalation.StepIndex+1)
+	msg.Data = map[string]interface{}{
+		"alert":      alert,
+		"asset_name": assetName,
+		"escalation": escalation,
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification payload: %w", err)
+	}
+
+	for _, channelID := range escalation.ChannelIDs {
+		channel, err := s.channelRepo.GetByID(ctx, channelID)
+		if err != nil {
+			log.Printf("Error loading escalation channel %s: %v", channelID, err)
+			continue
+		}
+		if channel == nil || !channel.IsActive || channel.TenantID != alert.TenantID {
+			log.Printf("Skipping escalation of alert %s to missing or inactive channel %s", alert.ID, channelID)
+			continue
+		}
+
+		alertID := alert.ID
+		delivery := entity.NewNotificationDelivery()
+		delivery.TenantID = alert.TenantID
+		delivery.ChannelID = channelID
+		delivery.AlertID = &alertID
+		delivery.Event = entity.NotificationEventAlertEscalated
+		delivery.Payload = payload
+
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			log.Printf("Error queueing escalation for alert %s to channel %s: %v", alert.ID, channelID, err)
+			continue
+		}
+		log.Printf("Queued escalation notification for alert %s to channel %s", alert.ID, channelID)
+	}
+
+	return nil
+}
+
+// ProcessDue sends all deliveries that are due and returns how many were attempted
+func (s *NotificationService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, time.Now(), notificationSendLease, notificationClaimBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		s.deliver(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+// Start runs the delivery worker, polling the queue at the given interval
+func (s *NotificationService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				// Drain full batches before waiting for the next tick
+				for {
+					count, err := s.ProcessDue(context.Background())
+					if err != nil {
+						log.Printf("Notification worker: %v", err)
+					}
+					if err != nil || count < notificationClaimBatch {
+						break
+					}
+				}
+			}
+		}
+	}()
+
+	log.Printf("Notification worker started (poll interval %s)", interval)
+}
+
+// Stop stops the delivery worker and waits for the current batch to finish
+func (s *NotificationService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Notification worker stopped")
+}
+
+// deliver attempts a single delivery and records the outcome
+func (s *NotificationService) deliver(ctx context.Context, delivery *entity.NotificationDelivery) {
+	attempts := delivery.Attempts + 1
+
+	sendErr := func() error {
+		channel, err := s.channelRepo.GetByID(ctx, delivery.ChannelID)
+		if err != nil {
+			return err
+		}
+		if channel == nil || !channel.IsActive {
+			return fmt.Errorf("channel is missing or inactive")
+		}
+
+		var msg notifier.Message
+		if err := json.Unmarshal(delivery.Payload, &msg); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		return s.dispatcher.Send(ctx, channel, &msg)
+	}()
+
+	if sendErr == nil {
+		if err := s.deliveryRepo.MarkSent(ctx, delivery.ID, attempts, time.Now()); err != nil {
+			log.Printf("Error recording notification delivery %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	if attempts >= s.retryPolicy.MaxAttempts {
+		log.Printf("Notification delivery %s failed permanently after %d attempts: %v", delivery.ID, attempts, sendErr)
+		if err := s.deliveryRepo.MarkFailed(ctx, delivery.ID, attempts, sendErr.Error()); err != nil {
+			log.Printf("Error recording notification delivery %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(entity.RetryDelay(attempts, s.retryPolicy.BaseDelay, s.retryPolicy.MaxDelay))
+	log.Printf("Notification delivery %s failed (attempt %d), retrying at %s: %v",
+		delivery.ID, attempts, nextAttemptAt.Format(time.RFC3339), sendErr)
+	if err := s.deliveryRepo.MarkRetry(ctx, delivery.ID, attempts, nextAttemptAt, sendErr.Error()); err != nil {
+		log.Printf("Error recording notification delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// applyRuleRequest validates a rule request and copies it onto the rule
+func (s *NotificationService) applyRuleRequest(ctx context.Context, tenantID uuid.UUID, rule *entity.NotificationRule, req dto.NotificationRuleRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return common.NewValidationError("name is required", nil)
+	}
+
+	channel, err := s.channelRepo.GetByID(ctx, req.ChannelID)
+	if err != nil {
+		return fmt.Errorf("failed to validate notification channel: %w", err)
+	}
+	if channel == nil || channel.TenantID != tenantID {
+		return common.NewValidationError("notification channel not found", nil)
+	}
+
+	for _, severity := range req.Severities {
+		switch severity {
+		case entity.ThresholdSeverityWarning, entity.ThresholdSeverityCritical:
+		default:
+			return common.NewValidationError(fmt.Sprintf("invalid severity %q", severity), nil)
+		}
+	}
+
+	if req.AssetID != nil {
+		asset, err := s.assetRepo.GetByID(ctx, *req.AssetID)
+		if err != nil {
+			return fmt.Errorf("failed to validate asset: %w", err)
+		}
+		if asset == nil || asset.TenantID == nil || *asset.TenantID != tenantID {
+			return common.NewValidationError("asset not found", nil)
+		}
+	}
+
+	rule.ChannelID = req.ChannelID
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Severities = req.Severities
+	rule.AssetID = req.AssetID
+	rule.AssetTypeID = req.AssetTypeID
+	if req.NotifyOnResolve != nil {
+		rule.NotifyOnResolve = *req.NotifyOnResolve
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// getTenantChannel loads a channel and ensures it belongs to the tenant
+func (s *NotificationService) getTenantChannel(ctx context.Context, tenantID, id uuid.UUID) (*entity.NotificationChannel, error) {
+	channel, err := s.channelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+	if channel == nil || channel.TenantID != tenantID {
+		return nil, common.NewNotFoundError("notification channel", id.String())
+	}
+
+	return channel, nil
+}
+
+// getTenantRule loads a rule and ensures it belongs to the tenant
+func (s *NotificationService) getTenantRule(ctx context.Context, tenantID, id uuid.UUID) (*entity.NotificationRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification rule: %w", err)
+	}
+	if rule == nil || rule.TenantID != tenantID {
+		return nil, common.NewNotFoundError("notification rule", id.String())
+	}
+
+	return rule, nil
+}
+
+// buildAlertMessage renders the notification for an alert event
+func buildAlertMessage(alert *entity.AssetAlert, assetName string, event entity.NotificationEvent) *notifier.Message {
+	state := "opened"
+	switch event {
+	case entity.NotificationEventAlertResolved:
+		state = "resolved"
+	case entity.NotificationEventAlertEscalated:
+		state = "escalated"
+	}
+
+	subject := fmt.Sprintf("[LecSens] %s alert %s on %s", strings.ToUpper(string(alert.Severity)), state, assetName)
+	text := fmt.Sprintf("%s\n\nField: %s\nValue: %g (peak %g, %d occurrences)\nAlert time: %s",
+		alert.AlertMessage, alert.MeasurementFieldName, alert.LastTriggerValue, alert.PeakTriggerValue,
+		alert.OccurrenceCount, alert.AlertTime.Format(time.RFC3339))
+	if alert.ResolvedTime != nil {
+		text += fmt.Sprintf("\nResolved time: %s", alert.ResolvedTime.Format(time.RFC3339))
+	}
+
+	return &notifier.Message{
+		Event:   event,
+		Subject: subject,
+		Text:    text,
+		Data: map[string]interface{}{
+			"alert":      alert,
+			"asset_name": assetName,
+		},
+	}
+}
+
+// normalizePagination applies the default and maximum page size
+func normalizePagination(page, limit int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+// buildPaginationInfo builds the pagination block of a list response
+func buildPaginationInfo(page, limit, totalCount int) dto.PaginationInfo {
+	totalPages := int(math.Ceil(float64(totalCount) / float64(limit)))
+	return dto.PaginationInfo{
+		Page:        page,
+		Limit:       limit,
+		TotalItems:  int64(totalCount),
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/notifier"
+	"be-lecsens/asset_management/data-layer/repository"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeNotificationChannelRepository keeps channels in memory
+type fakeNotificationChannelRepository struct {
+	channels map[uuid.UUID]*entity.NotificationChannel
+}
+
+func (r *fakeNotificationChannelRepository) Create(ctx context.Context, channel *entity.NotificationChannel) error {
+	r.channels[channel.ID] = channel
+	return nil
+}
+
+func (r *fakeNotificationChannelRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationChannel, error) {
+	return r.channels[id], nil
+}
+
+func (r *fakeNotificationChannelRepository) List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*entity.NotificationChannel, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeNotificationChannelRepository) Update(ctx context.Context, channel *entity.NotificationChannel) error {
+	r.channels[channel.ID] = channel
+	return nil
+}
+
+func (r *fakeNotificationChannelRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.channels, id)
+	return nil
+}
+
+// fakeNotificationDeliveryRepository is an in-memory delivery log with the claim semantics
+// of the database queue
+type fakeNotificationDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[uuid.UUID]*entity.NotificationDelivery
+}
+
+func (r *fakeNotificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *fakeNotificationDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *delivery
+	return &copied, nil
+}
+
+func (r *fakeNotificationDeliveryRepository) List(ctx context.Context, tenantID uuid.UUID, filter repository.NotificationDeliveryFilter, limit, offset int) ([]*entity.NotificationDelivery, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeNotificationDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*entity.NotificationDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != entity.NotificationDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.Status = entity.NotificationDeliverySending
+		delivery.NextAttemptAt = now.Add(lease)
+		copied := *delivery
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *fakeNotificationDeliveryRepository) MarkSent(ctx context.Context, id uuid.UUID, attempts int, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery := r.deliveries[id]
+	delivery.Status = entity.NotificationDeliverySent
+	delivery.Attempts = attempts
+	delivery.SentAt = &sentAt
+	return nil
+}
+
+func (r *fakeNotificationDeliveryRepository) MarkRetry(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery := r.deliveries[id]
+	delivery.Status = entity.NotificationDeliveryPending
+	delivery.Attempts = attempts
+	delivery.NextAttemptAt = nextAttemptAt
+	delivery.LastError = &lastError
+	return nil
+}
+
+func (r *fakeNotificationDeliveryRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery := r.deliveries[id]
+	delivery.Status = entity.NotificationDeliveryFailed
+	delivery.Attempts = attempts
+	delivery.LastError = &lastError
+	return nil
+}
+
+// makeDue moves a delivery's next attempt into the past, as if its backoff had elapsed
+func (r *fakeNotificationDeliveryRepository) makeDue(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[id].NextAttemptAt = time.Now().Add(-time.Second)
+}
+
+// newTestNotificationService returns a service delivering to a webhook stub that fails the
+// first failures requests, together with its delivery log and a queued delivery
+func newTestNotificationService(t *testing.T, failures int32, retryPolicy NotificationRetryPolicy) (*NotificationService, *fakeNotificationDeliveryRepository, uuid.UUID, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			http.Error(w, "endpoint unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	channel := entity.NewNotificationChannel()
+	channel.Name = "ops webhook"
+	channel.Type = entity.NotificationChannelWebhook
+	channel.Config.URL = server.URL
+	channelRepo := &fakeNotificationChannelRepository{channels: map[uuid.UUID]*entity.NotificationChannel{channel.ID: channel}}
+	deliveryRepo := &fakeNotificationDeliveryRepository{deliveries: make(map[uuid.UUID]*entity.NotificationDelivery)}
+
+	payload, err := json.Marshal(&notifier.Message{Event: entity.NotificationEventTest, Subject: "Test", Text: "Test notification"})
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	delivery := entity.NewNotificationDelivery()
+	delivery.TenantID = channel.TenantID
+	delivery.ChannelID = channel.ID
+	delivery.Event = entity.NotificationEventTest
+	delivery.Payload = payload
+	deliveryRepo.Create(context.Background(), delivery)
+
+	dispatcher := notifier.NewDispatcher(map[entity.NotificationChannelType]notifier.Sender{
+		entity.NotificationChannelWebhook: notifier.NewWebhookSender(5 * time.Second),
+	})
+	service := NewNotificationService(channelRepo, nil, deliveryRepo, nil, dispatcher, retryPolicy)
+	return service, deliveryRepo, delivery.ID, &requests
+}
+
+func TestNotificationServiceRetriesWithBackoff(t *testing.T) {
+	retryPolicy := NotificationRetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 3 * time.Minute}
+	service, deliveryRepo, deliveryID, requests := newTestNotificationService(t, 3, retryPolicy)
+	ctx := context.Background()
+
+	// Every failure is logged and pushes the next attempt back, doubling up to the maximum
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		before := time.Now()
+		if count, err := service.ProcessDue(ctx); err != nil || count != 1 {
+			t.Fatalf("attempt %d: ProcessDue = %d, %v; want 1 delivery", attempt+1, count, err)
+		}
+		after := time.Now()
+
+		delivery, _ := deliveryRepo.GetByID(ctx, deliveryID)
+		if delivery.Status != entity.NotificationDeliveryPending {
+			t.Fatalf("attempt %d: status = %s, want pending", attempt+1, delivery.Status)
+		}
+		if delivery.Attempts != attempt+1 {
+			t.Errorf("attempt %d: attempts = %d", attempt+1, delivery.Attempts)
+		}
+		if delivery.LastError == nil || !strings.Contains(*delivery.LastError, "503") {
+			t.Errorf("attempt %d: last error = %v, want the 503 response", attempt+1, delivery.LastError)
+		}
+		if delivery.NextAttemptAt.Before(before.Add(wantDelay)) || delivery.NextAttemptAt.After(after.Add(wantDelay)) {
+			t.Errorf("attempt %d: next attempt in %s, want %s", attempt+1, delivery.NextAttemptAt.Sub(after), wantDelay)
+		}
+
+		// Nothing is sent before the backoff has elapsed
+		if count, _ := service.ProcessDue(ctx); count != 0 {
+			t.Fatalf("attempt %d: delivery was retried before its backoff elapsed", attempt+1)
+		}
+		deliveryRepo.makeDue(deliveryID)
+	}
+
+	if count, err := service.ProcessDue(ctx); err != nil || count != 1 {
+		t.Fatalf("ProcessDue = %d, %v; want 1 delivery", count, err)
+	}
+	delivery, _ := deliveryRepo.GetByID(ctx, deliveryID)
+	if delivery.Status != entity.NotificationDeliverySent || delivery.SentAt == nil {
+		t.Errorf("status = %s, sent at %v; want sent", delivery.Status, delivery.SentAt)
+	}
+	if delivery.Attempts != 4 {
+		t.Errorf("attempts = %d, want 4", delivery.Attempts)
+	}
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Errorf("endpoint received %d requests, want 4", got)
+	}
+}
+
+func TestNotificationServiceGivesUpAfterMaxAttempts(t *testing.T) {
+	retryPolicy := NotificationRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	service, deliveryRepo, deliveryID, requests := newTestNotificationService(t, 10, retryPolicy)
+	ctx := context.Background()
+
+	service.ProcessDue(ctx)
+	deliveryRepo.makeDue(deliveryID)
+	service.ProcessDue(ctx)
+
+	delivery, _ := deliveryRepo.GetByID(ctx, deliveryID)
+	if delivery.Status != entity.NotificationDeliveryFailed {
+		t.Fatalf("status = %s, want failed", delivery.Status)
+	}
+	if delivery.Attempts != 2 || delivery.LastError == nil {
+		t.Errorf("attempts = %d, last error = %v; want 2 attempts and the error", delivery.Attempts, delivery.LastError)
+	}
+
+	deliveryRepo.makeDue(deliveryID)
+	if count, _ := service.ProcessDue(ctx); count != 0 {
+		t.Error("failed delivery was claimed again")
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("endpoint received %d requests, want 2", got)
+	}
+}
+
+func TestNotificationServiceFailsInactiveChannel(t *testing.T) {
+	retryPolicy := NotificationRetryPolicy{MaxAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	service, deliveryRepo, deliveryID, requests := newTestNotificationService(t, 0, retryPolicy)
+	ctx := context.Background()
+
+	delivery, _ := deliveryRepo.GetByID(ctx, deliveryID)
+	channel, _ := service.channelRepo.GetByID(ctx, delivery.ChannelID)
+	channel.IsActive = false
+
+	service.ProcessDue(ctx)
+
+	delivery, _ = deliveryRepo.GetByID(ctx, deliveryID)
+	if delivery.Status != entity.NotificationDeliveryFailed {
+		t.Errorf("status = %s, want failed", delivery.Status)
+	}
+	if got := atomic.LoadInt32(requests); got != 0 {
+		t.Errorf("endpoint received %d requests for an inactive channel, want 0", got)
+	}
+}
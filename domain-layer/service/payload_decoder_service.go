@@ -0,0 +1,246 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PayloadDecoderService manages the payload decoders of sensor types and converts vendor
+// specific payloads into flexible reading requests
+type PayloadDecoderService struct {
+	decoderRepo          repository.PayloadDecoderRepository
+	sensorTypeRepo       *repository.SensorTypeRepository
+	measurementTypeRepo  repository.SensorMeasurementTypeRepository
+	measurementFieldRepo *repository.SensorMeasurementFieldRepository
+}
+
+// NewPayloadDecoderService creates a new instance of PayloadDecoderService
+func NewPayloadDecoderService(
+	decoderRepo repository.PayloadDecoderRepository,
+	sensorTypeRepo *repository.SensorTypeRepository,
+	measurementTypeRepo repository.SensorMeasurementTypeRepository,
+	measurementFieldRepo *repository.SensorMeasurementFieldRepository,
+) *PayloadDecoderService {
+	return &PayloadDecoderService{
+		decoderRepo:          decoderRepo,
+		sensorTypeRepo:       sensorTypeRepo,
+		measurementTypeRepo:  measurementTypeRepo,
+		measurementFieldRepo: measurementFieldRepo,
+	}
+}
+
+// CreateDecoder creates the payload decoder of a sensor type
+func (s *PayloadDecoderService) CreateDecoder(ctx context.Context, req dto.PayloadDecoderRequest) (*entity.PayloadDecoder, error) {
+	existing, err := s.decoderRepo.GetBySensorTypeID(ctx, req.SensorTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing payload decoder: %w", err)
+	}
+	if existing != nil {
+		return nil, common.NewValidationError(fmt.Sprintf("sensor type already has payload decoder %s", existing.Name), nil)
+	}
+
+	decoder := entity.NewPayloadDecoder()
+	decoder.SensorTypeID = req.SensorTypeID
+	if err := s.applyDecoderRequest(ctx, decoder, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.decoderRepo.Create(ctx, decoder); err != nil {
+		log.Printf("Error creating payload decoder: %v", err)
+		return nil, fmt.Errorf("failed to create payload decoder: %w", err)
+	}
+
+	log.Printf("Created %s payload decoder %s (%d fields) for sensor type %s", decoder.Format, decoder.ID, len(decoder.Fields), decoder.SensorTypeID)
+	return decoder, nil
+}
+
+// GetDecoder retrieves a payload decoder
+func (s *PayloadDecoderService) GetDecoder(ctx context.Context, id uuid.UUID) (*entity.PayloadDecoder, error) {
+	decoder, err := s.decoderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payload decoder: %w", err)
+	}
+	if decoder == nil {
+		return nil, common.NewNotFoundError("payload decoder", id.String())
+	}
+
+	return decoder, nil
+}
+
+// ListDecoders lists payload decoders
+func (s *PayloadDecoderService) ListDecoders(ctx context.Context, page, limit int) (*dto.PayloadDecoderListResponse, error) {
+	page, limit = normalizePagination(page, limit)
+
+	decoders, totalCount, err := s.decoderRepo.List(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payload decoders: %w", err)
+	}
+	if decoders == nil {
+		decoders = []*entity.PayloadDecoder{}
+	}
+
+	return &dto.PayloadDecoderListResponse{
+		Data:       decoders,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// UpdateDecoder updates a payload decoder. Its sensor type can't change.
+func (s *PayloadDecoderService) UpdateDecoder(ctx context.Context, id uuid.UUID, req dto.PayloadDecoderRequest) (*entity.PayloadDecoder, error) {
+	decoder, err := s.GetDecoder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.SensorTypeID != decoder.SensorTypeID {
+		return nil, common.NewValidationError("the sensor type of a payload decoder can't be changed", nil)
+	}
+
+	if err := s.applyDecoderRequest(ctx, decoder, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.decoderRepo.Update(ctx, decoder); err != nil {
+		log.Printf("Error updating payload decoder: %v", err)
+		return nil, fmt.Errorf("failed to update payload decoder: %w", err)
+	}
+
+	return decoder, nil
+}
+
+// DeleteDecoder deletes a payload decoder. Its sensor type goes back to the flexible JSON format.
+func (s *PayloadDecoderService) DeleteDecoder(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetDecoder(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.decoderRepo.Delete(ctx, id); err != nil {
+		log.Printf("Error deleting payload decoder: %v", err)
+		return fmt.Errorf("failed to delete payload decoder: %w", err)
+	}
+
+	log.Printf("Deleted payload decoder %s", id)
+	return nil
+}
+
+// TestDecoder decodes a sample payload without storing anything, also when the decoder
+// is inactive
+func (s *PayloadDecoderService) TestDecoder(ctx context.Context, id uuid.UUID, payload []byte) (*entity.DecodedPayload, error) {
+	decoder, err := s.GetDecoder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := decoder.Decode(payload)
+	if err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+
+	return decoded, nil
+}
+
+// DecodeReading decodes a raw payload into the measurements of a reading request, using
+// the active decoder of the request's sensor type. It reports false when the sensor type
+// has no active decoder. The reading time and message ID of the payload are only used when
+// the request has none.
+func (s *PayloadDecoderService) DecodeReading(ctx context.Context, req *dto.FlexibleIoTSensorReadingRequest, payload []byte) (bool, error) {
+	decoder, err := s.decoderRepo.GetBySensorTypeID(ctx, req.SensorTypeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get payload decoder: %w", err)
+	}
+	if decoder == nil || !decoder.IsActive {
+		return false, nil
+	}
+
+	decoded, err := decoder.Decode(payload)
+	if err != nil {
+		return true, common.NewValidationError(fmt.Sprintf("payload decoder %s: %v", decoder.Name, err), nil)
+	}
+
+	req.MeasurementData = make(map[string]dto.MeasurementValue, len(decoded.Measurements))
+	for field, measurement := range decoded.Measurements {
+		req.MeasurementData[field] = dto.MeasurementValue{
+			Label: field,
+			Unit:  measurement.Unit,
+			Value: measurement.Value,
+		}
+	}
+	if req.ReadingTime == nil {
+		req.ReadingTime = decoded.ReadingTime
+	}
+	if req.MessageID == "" {
+		req.MessageID = decoded.MessageID
+	}
+
+	return true, nil
+}
+
+// applyDecoderRequest validates a request and applies it to a decoder
+func (s *PayloadDecoderService) applyDecoderRequest(ctx context.Context, decoder *entity.PayloadDecoder, req dto.PayloadDecoderRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return common.NewValidationError("name is required", nil)
+	}
+
+	decoder.Name = req.Name
+	decoder.Description = req.Description
+	decoder.FramePath = req.FramePath
+	decoder.TimestampPath = req.TimestampPath
+	decoder.MessageIDPath = req.MessageIDPath
+	decoder.Fields = req.Fields
+	if req.Format != "" {
+		decoder.Format = req.Format
+	}
+	if req.IsActive != nil {
+		decoder.IsActive = *req.IsActive
+	}
+
+	if err := decoder.Validate(); err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+
+	return s.validateDecoderFields(ctx, decoder)
+}
+
+// validateDecoderFields checks that every decoded field belongs to the decoder's sensor
+// type and that binary values fit the field's data type
+func (s *PayloadDecoderService) validateDecoderFields(ctx context.Context, decoder *entity.PayloadDecoder) error {
+	sensorType, err := s.sensorTypeRepo.GetByID(decoder.SensorTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get sensor type: %w", err)
+	}
+	if sensorType == nil {
+		return common.NewValidationError("sensor type not found", nil)
+	}
+
+	dataTypes, err := sensorTypeFieldDataTypes(ctx, s.measurementTypeRepo, s.measurementFieldRepo, decoder.SensorTypeID)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range decoder.Fields {
+		dataType, ok := dataTypes[spec.Field]
+		if !ok {
+			return common.NewValidationError(fmt.Sprintf("sensor type %s has no measurement field %s", sensorType.Name, spec.Field), nil)
+		}
+		if !decoder.IsBinary() {
+			continue
+		}
+
+		expected := entity.MeasurementDataTypeNumber
+		if spec.Type == entity.PayloadValueBool {
+			expected = entity.MeasurementDataTypeBoolean
+		}
+		if dataType != expected {
+			return common.NewValidationError(fmt.Sprintf("field %s is a %s field, which can't be decoded from a %s value", spec.Field, dataType, spec.Type), nil)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/archivestore"
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingArchiveService exports the readings the retention policies archived to compressed
+// files on the archive storage, one file per tenant and month, recording each file in the
+// archive manifest, and restores archives back into iot_sensor_readings. Files hold one JSON
+// reading per line, gzip compressed. A month is exported once the tenant's retention cutoff
+// is past its end, so all its readings are archived; readings archived later go to further
+// parts.
+type ReadingArchiveService struct {
+	archiveRepo   repository.ReadingArchiveRepository
+	retentionRepo repository.ReadingRetentionRepository
+	storage       archivestore.Storage
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReadingArchiveService creates a new instance of ReadingArchiveService
+func NewReadingArchiveService(archiveRepo repository.ReadingArchiveRepository, retentionRepo repository.ReadingRetentionRepository, storage archivestore.Storage) *ReadingArchiveService {
+	return &ReadingArchiveService{
+		archiveRepo:   archiveRepo,
+		retentionRepo: retentionRepo,
+		storage:       storage,
+	}
+}
+
+// ListArchives retrieves the archive manifest, of a tenant when tenantID is set
+func (s *ReadingArchiveService) ListArchives(ctx context.Context, tenantID *uuid.UUID) ([]*entity.ReadingArchive, error) {
+	return s.archiveRepo.List(ctx, tenantID)
+}
+
+// Run exports every tenant's month of archived readings that is complete. It returns the
+// archives written.
+func (s *ReadingArchiveService) Run(ctx context.Context) ([]*entity.ReadingArchive, error) {
+	groups, err := s.archiveRepo.ListPendingGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	policies, err := s.retentionRepo.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var defaultPolicy *entity.ReadingRetentionPolicy
+	tenantPolicies := make(map[uuid.UUID]*entity.ReadingRetentionPolicy)
+	for _, policy := range policies {
+		if policy.TenantID == nil {
+			defaultPolicy = policy
+			continue
+		}
+		tenantPolicies[*policy.TenantID] = policy
+	}
+
+	now := time.Now()
+	var archives []*entity.ReadingArchive
+	for _, group := range groups {
+		// Months the retention policy is still archiving readings of are left for later
+		policy := defaultPolicy
+		if group.TenantID != nil && tenantPolicies[*group.TenantID] != nil {
+			policy = tenantPolicies[*group.TenantID]
+		}
+		if policy != nil && policy.Cutoff(now).Before(group.Month.AddDate(0, 1, 0)) {
+			continue
+		}
+
+		archive, err := s.exportGroup(ctx, group)
+		if err != nil {
+			return archives, err
+		}
+		log.Printf("Archived %d readings to %s", archive.ReadingCount, archive.ObjectKey)
+		archives = append(archives, archive)
+
+		select {
+		case <-s.stop:
+			return archives, nil
+		default:
+		}
+	}
+
+	return archives, nil
+}
+
+// exportGroup writes a tenant's month of archived readings to a new archive part
+func (s *ReadingArchiveService) exportGroup(ctx context.Context, group entity.ReadingArchiveGroup) (*entity.ReadingArchive, error) {
+	part, err := s.archiveRepo.NextPart(ctx, group.TenantID, group.Month)
+	if err != nil {
+		return nil, err
+	}
+	key := entity.ReadingArchiveObjectKey(group.TenantID, group.Month, part)
+
+	file, err := s.storage.Create(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	counter := &countingWriter{}
+	checksum := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(file, counter, checksum))
+	compressed := gzip.NewWriter(buffered)
+	encoder := json.NewEncoder(compressed)
+
+	archive := &entity.ReadingArchive{
+		TenantID:  group.TenantID,
+		Month:     group.Month,
+		Part:      part,
+		Storage:   s.storage.Name(),
+		ObjectKey: key,
+		Format:    entity.ReadingArchiveFormatNDJSONGzip,
+	}
+	closed := false
+
+	write := func(reading *entity.IoTSensorReading) error {
+		if archive.ReadingCount == 0 {
+			archive.FirstReadingTime = reading.ReadingTime
+		}
+		archive.LastReadingTime = reading.ReadingTime
+		archive.ReadingCount++
+		if err := encoder.Encode(reading); err != nil {
+			return fmt.Errorf("failed to write archived reading: %w", err)
+		}
+		return nil
+	}
+	finish := func() (*entity.ReadingArchive, error) {
+		if archive.ReadingCount == 0 {
+			return nil, fmt.Errorf("no readings left to archive in %s", key)
+		}
+		if err := compressed.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress archive: %w", err)
+		}
+		if err := buffered.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		closed = true
+		if err := file.Close(); err != nil {
+			return nil, err
+		}
+		archive.SizeBytes = counter.n
+		archive.Checksum = hex.EncodeToString(checksum.Sum(nil))
+		return archive, nil
+	}
+
+	recorded, err := s.archiveRepo.Export(ctx, group, write, finish)
+	if err != nil {
+		// The file is not in the manifest, so remove it
+		if !closed {
+			file.Close()
+		}
+		if deleteErr := s.storage.Delete(ctx, key); deleteErr != nil {
+			log.Printf("Failed to remove unrecorded archive %s: %v", key, deleteErr)
+		}
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// Restore reloads an archive back into iot_sensor_readings. The file is checked against the
+// manifest's checksum before the readings are committed. It returns how many readings were
+// restored and how many were skipped, being stored already or belonging to an asset sensor
+// or sensor type that no longer exists.
+func (s *ReadingArchiveService) Restore(ctx context.Context, archiveID uuid.UUID) (int64, int64, error) {
+	archive, err := s.archiveRepo.GetByID(ctx, archiveID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if archive == nil {
+		return 0, 0, common.NewNotFoundError("reading archive", archiveID.String())
+	}
+	if archive.Storage != s.storage.Name() {
+		return 0, 0, common.NewValidationError(fmt.Sprintf("archive is kept on %s storage, not %s", archive.Storage, s.storage.Name()), nil)
+	}
+	if archive.Format != entity.ReadingArchiveFormatNDJSONGzip {
+		return 0, 0, common.NewValidationError(fmt.Sprintf("unsupported archive format: %s", archive.Format), nil)
+	}
+
+	file, err := s.storage.Open(ctx, archive.ObjectKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	checksum := sha256.New()
+	compressed, err := gzip.NewReader(io.TeeReader(file, checksum))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read archive %s: %w", archive.ObjectKey, err)
+	}
+	decoder := json.NewDecoder(compressed)
+
+	read := func() (*entity.IoTSensorReading, error) {
+		var reading entity.IoTSensorReading
+		err := decoder.Decode(&reading)
+		if err == io.EOF {
+			return nil, verifyArchiveChecksum(archive, file, checksum)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archived reading: %w", err)
+		}
+		return &reading, nil
+	}
+
+	restored, skipped, err := s.archiveRepo.Restore(ctx, archive.ID, read)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	log.Printf("Restored %d readings from %s (%d skipped)", restored, archive.ObjectKey, skipped)
+	return restored, skipped, nil
+}
+
+// verifyArchiveChecksum reads the rest of an archive file into its checksum and compares it
+// with the manifest's. It returns io.EOF when they match.
+func verifyArchiveChecksum(archive *entity.ReadingArchive, file io.Reader, checksum hash.Hash) error {
+	if _, err := io.Copy(checksum, file); err != nil {
+		return fmt.Errorf("failed to read archive %s: %w", archive.ObjectKey, err)
+	}
+	if hex.EncodeToString(checksum.Sum(nil)) != archive.Checksum {
+		return fmt.Errorf("archive %s does not match its checksum", archive.ObjectKey)
+	}
+	return io.EOF
+}
+
+// Start exports the completed months of archived readings every interval until Stop is called
+func (s *ReadingArchiveService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if _, err := s.Run(context.Background()); err != nil {
+					log.Printf("Reading archive job: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Reading archive job started (interval %s)", interval)
+}
+
+// Stop stops the archive job and waits for the current export to finish
+func (s *ReadingArchiveService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Reading archive job stopped")
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/helpers/dto"
+	"sort"
+	"time"
+)
+
+// maxAggregateBuckets limits the buckets of an aggregated data query
+const maxAggregateBuckets = 10000
+
+// fillDataPoints returns a data point for every bucket, filling the buckets without
+// readings by the gap fill strategy. Each measurement is filled on its own, so a bucket
+// holding readings of one measurement still gets the others filled in. Sums and counts
+// are never filled, as the bucket holds no readings. The data points are filled in place.
+func fillDataPoints(dataPoints []dto.AggregatedDataPoint, buckets []time.Time, fill entity.GapFill) []dto.AggregatedDataPoint {
+	if fill == entity.GapFillNone {
+		return dataPoints
+	}
+
+	byTime := make(map[int64]dto.AggregatedDataPoint, len(dataPoints))
+	for _, point := range dataPoints {
+		byTime[point.Time.Unix()] = point
+	}
+
+	filled := make([]dto.AggregatedDataPoint, len(buckets))
+	measurements := make(map[string]bool)
+	for i, bucket := range buckets {
+		point, ok := byTime[bucket.Unix()]
+		if !ok {
+			point = dto.AggregatedDataPoint{
+				Averages: make(map[string]float64),
+				Sums:     make(map[string]float64),
+				Mins:     make(map[string]float64),
+				Maxs:     make(map[string]float64),
+				Units:    make(map[string]string),
+			}
+		}
+		point.Time = bucket
+		for measurement := range point.Averages {
+			measurements[measurement] = true
+		}
+		filled[i] = point
+	}
+
+	if fill == entity.GapFillNull {
+		return filled
+	}
+
+	names := make([]string, 0, len(measurements))
+	for measurement := range measurements {
+		names = append(names, measurement)
+	}
+	sort.Strings(names)
+
+	for _, measurement := range names {
+		previous := -1
+		for i := range filled {
+			if _, ok := filled[i].Averages[measurement]; ok {
+				if fill == entity.GapFillLinear && previous >= 0 && i-previous > 1 {
+					interpolate(filled, measurement, previous, i)
+				}
+				previous = i
+				continue
+			}
+			if fill == entity.GapFillPrevious && previous >= 0 {
+				fillValues(&filled[i], filled[previous], filled[previous], measurement, 0)
+			}
+		}
+	}
+
+	return filled
+}
+
+// interpolate fills a measurement in the buckets between from and to, which both have
+// values, by linear interpolation over time
+func interpolate(points []dto.AggregatedDataPoint, measurement string, from, to int) {
+	span := float64(points[to].Time.Sub(points[from].Time))
+	for i := from + 1; i < to; i++ {
+		weight := float64(points[i].Time.Sub(points[from].Time)) / span
+		fillValues(&points[i], points[from], points[to], measurement, weight)
+	}
+}
+
+// fillValues fills in the values of a measurement in a data point, between those of the
+// data points from and to by weight: 0 takes the values of from, 1 those of to
+func fillValues(point *dto.AggregatedDataPoint, from, to dto.AggregatedDataPoint, measurement string, weight float64) {
+	between := func(a, b float64) float64 {
+		return a + (b-a)*weight
+	}
+
+	point.Averages[measurement] = between(from.Averages[measurement], to.Averages[measurement])
+	point.Mins[measurement] = between(from.Mins[measurement], to.Mins[measurement])
+	point.Maxs[measurement] = between(from.Maxs[measurement], to.Maxs[measurement])
+	if unit, ok := from.Units[measurement]; ok {
+		point.Units[measurement] = unit
+	}
+	if percentiles := from.Percentiles[measurement]; percentiles != nil {
+		if point.Percentiles == nil {
+			point.Percentiles = make(map[string]map[string]float64)
+		}
+		values := make(map[string]float64, len(percentiles))
+		for name, value := range percentiles {
+			values[name] = between(value, to.Percentiles[measurement][name])
+		}
+		point.Percentiles[measurement] = values
+	}
+	point.Filled = append(point.Filled, measurement)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// readingOutboxPurgeInterval is how often completed outbox events are purged
+const readingOutboxPurgeInterval = time.Hour
+
+// ReadingOutboxPolicy controls how the outbox dispatcher claims and retries events
+type ReadingOutboxPolicy struct {
+	BatchSize   int           // Events claimed per poll
+	Lease       time.Duration // How long a claimed event stays reserved before it is applied again
+	MaxAttempts int
+	BaseDelay   time.Duration // Delay after the first failure, doubled after every attempt
+	MaxDelay    time.Duration
+	Retention   time.Duration // How long completed events are kept
+}
+
+// ReadingOutboxStats reports the backlog of the reading outbox
+type ReadingOutboxStats struct {
+	Pending    int `json:"pending"`
+	Processing int `json:"processing"`
+	Done       int `json:"done"`
+	Failed     int `json:"failed"` // Events that gave up after the maximum number of attempts
+}
+
+// ReadingOutboxService dispatches the side effects of stored readings. The effects are
+// recorded as outbox events in the transaction that stores the readings and applied here
+// at least once: an event is only marked done after its effect was applied, and an event
+// whose worker died is claimed again once its lease expires. Applying an effect twice is
+// harmless; the last reading cache only moves forward and alert states remember the last
+// reading they applied.
+type ReadingOutboxService struct {
+	outboxRepo      repository.ReadingOutboxRepository
+	readingRepo     repository.IoTSensorReadingRepository
+	evaluationQueue *AlertEvaluationQueue
+	policy          ReadingOutboxPolicy
+
+	wake      chan struct{}
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	lastPurge time.Time
+}
+
+// NewReadingOutboxService creates a new instance of ReadingOutboxService. Alert evaluation
+// events are applied on the evaluation queue, so readings of a sensor keep their order.
+func NewReadingOutboxService(
+	outboxRepo repository.ReadingOutboxRepository,
+	readingRepo repository.IoTSensorReadingRepository,
+	evaluationQueue *AlertEvaluationQueue,
+	policy ReadingOutboxPolicy,
+) *ReadingOutboxService {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 1
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	return &ReadingOutboxService{
+		outboxRepo:      outboxRepo,
+		readingRepo:     readingRepo,
+		evaluationQueue: evaluationQueue,
+		policy:          policy,
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the dispatcher so newly stored readings don't wait for the next poll
+func (s *ReadingOutboxService) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ProcessDue applies all events that are due and returns how many were claimed
+func (s *ReadingOutboxService) ProcessDue(ctx context.Context) (int, error) {
+	events, err := s.outboxRepo.ClaimDue(ctx, time.Now(), s.policy.Lease, s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim reading outbox events: %w", err)
+	}
+
+	for _, event := range events {
+		s.dispatch(ctx, event)
+	}
+
+	return len(events), nil
+}
+
+// GetStats returns the number of outbox events in each status
+func (s *ReadingOutboxService) GetStats(ctx context.Context) (*ReadingOutboxStats, error) {
+	counts, err := s.outboxRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadingOutboxStats{
+		Pending:    counts[entity.ReadingOutboxPending],
+		Processing: counts[entity.ReadingOutboxProcessing],
+		Done:       counts[entity.ReadingOutboxDone],
+		Failed:     counts[entity.ReadingOutboxFailed],
+	}, nil
+}
+
+// Start runs the dispatcher, polling the outbox at the given interval and whenever
+// new readings are stored
+func (s *ReadingOutboxService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+
+			// Drain full batches before waiting again
+			for {
+				count, err := s.ProcessDue(context.Background())
+				if err != nil {
+					log.Printf("Reading outbox dispatcher: %v", err)
+				}
+				if err != nil || count < s.policy.BatchSize {
+					break
+				}
+			}
+			s.purge(context.Background())
+		}
+	}()
+
+	log.Printf("Reading outbox dispatcher started (poll interval %s)", interval)
+}
+
+// Stop stops the dispatcher and waits for the current batch to be handed off. Events
+// still being applied are completed by their worker or claimed again after their lease.
+func (s *ReadingOutboxService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Reading outbox dispatcher stopped")
+}
+
+// dispatch applies a single event and records the outcome
+func (s *ReadingOutboxService) dispatch(ctx context.Context, event *entity.ReadingOutboxEvent) {
+	attempts := event.Attempts + 1
+
+	switch event.Effect {
+	case entity.ReadingEffectLastReading:
+		err := s.outboxRepo.ApplyLastReading(ctx, event, attempts, time.Now())
+		if err != nil {
+			s.retry(ctx, event, attempts, err)
+		}
+
+	case entity.ReadingEffectAlertEvaluation:
+		reading, err := s.readingRepo.GetFlexibleByID(ctx, event.ReadingID)
+		if err != nil {
+			s.retry(ctx, event, attempts, err)
+			return
+		}
+		if reading == nil {
+			// The reading was deleted meanwhile, there is nothing to evaluate
+			s.complete(ctx, event, attempts, nil)
+			return
+		}
+		if s.evaluationQueue == nil {
+			s.complete(ctx, event, attempts, fmt.Errorf("alert evaluation is not configured"))
+			return
+		}
+
+		// The event completes when the queue has evaluated the reading
+		queued := s.evaluationQueue.Enqueue(ctx, reading, func(err error) {
+			s.complete(context.Background(), event, attempts, err)
+		})
+		if !queued {
+			s.retry(ctx, event, attempts, fmt.Errorf("alert evaluation queue is not accepting readings"))
+		}
+
+	default:
+		log.Printf("Reading outbox event %s has unknown effect %q", event.ID, event.Effect)
+		if err := s.outboxRepo.MarkFailed(ctx, event.ID, attempts, "unknown effect"); err != nil {
+			log.Printf("Error recording reading outbox event %s: %v", event.ID, err)
+		}
+	}
+}
+
+// complete marks an event done, or schedules a retry when applying it failed
+func (s *ReadingOutboxService) complete(ctx context.Context, event *entity.ReadingOutboxEvent, attempts int, applyErr error) {
+	if applyErr != nil {
+		s.retry(ctx, event, attempts, applyErr)
+		return
+	}
+	if err := s.outboxRepo.MarkDone(ctx, event.ID, attempts, time.Now()); err != nil {
+		log.Printf("Error recording reading outbox event %s: %v", event.ID, err)
+	}
+}
+
+// retry records a failed attempt and schedules the next one, giving up after the
+// maximum number of attempts
+func (s *ReadingOutboxService) retry(ctx context.Context, event *entity.ReadingOutboxEvent, attempts int, applyErr error) {
+	if attempts >= s.policy.MaxAttempts {
+		log.Printf("Reading outbox event %s (%s of reading %s) failed permanently after %d attempts: %v",
+			event.ID, event.Effect, event.ReadingID, attempts, applyErr)
+		if err := s.outboxRepo.MarkFailed(ctx, event.ID, attempts, applyErr.Error()); err != nil {
+			log.Printf("Error recording reading outbox event %s: %v", event.ID, err)
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(entity.RetryDelay(attempts, s.policy.BaseDelay, s.policy.MaxDelay))
+	log.Printf("Reading outbox event %s (%s of reading %s) failed (attempt %d), retrying at %s: %v",
+		event.ID, event.Effect, event.ReadingID, attempts, nextAttemptAt.Format(time.RFC3339), applyErr)
+	if err := s.outboxRepo.MarkRetry(ctx, event.ID, attempts, nextAttemptAt, applyErr.Error()); err != nil {
+		log.Printf("Error recording reading outbox event %s: %v", event.ID, err)
+	}
+}
+
+// purge removes completed events past the retention, at most once per purge interval
+func (s *ReadingOutboxService) purge(ctx context.Context) {
+	if s.policy.Retention <= 0 || time.Since(s.lastPurge) < readingOutboxPurgeInterval {
+		return
+	}
+	s.lastPurge = time.Now()
+
+	deleted, err := s.outboxRepo.DeleteDoneBefore(ctx, time.Now().Add(-s.policy.Retention))
+	if err != nil {
+		log.Printf("Reading outbox dispatcher: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Purged %d completed reading outbox events", deleted)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// ReadingQuarantineService lets admins review the readings held back by ingestion
+// policies and replay or discard them
+type ReadingQuarantineService struct {
+	quarantineRepo          repository.QuarantinedReadingRepository
+	iotSensorReadingService *IoTSensorReadingService
+}
+
+// NewReadingQuarantineService creates a new instance of ReadingQuarantineService
+func NewReadingQuarantineService(
+	quarantineRepo repository.QuarantinedReadingRepository,
+	iotSensorReadingService *IoTSensorReadingService,
+) *ReadingQuarantineService {
+	return &ReadingQuarantineService{
+		quarantineRepo:          quarantineRepo,
+		iotSensorReadingService: iotSensorReadingService,
+	}
+}
+
+// ListQuarantinedReadings lists quarantined readings, newest first
+func (s *ReadingQuarantineService) ListQuarantinedReadings(ctx context.Context, filter repository.QuarantinedReadingFilter, page, limit int) (*dto.QuarantinedReadingListResponse, error) {
+	if filter.Status != nil {
+		switch *filter.Status {
+		case entity.QuarantineStatusPending, entity.QuarantineStatusReplayed, entity.QuarantineStatusDiscarded:
+		default:
+			return nil, common.NewValidationError(fmt.Sprintf("invalid status %s, must be pending, replayed or discarded", *filter.Status), nil)
+		}
+	}
+	page, limit = normalizePagination(page, limit)
+
+	readings, totalCount, err := s.quarantineRepo.List(ctx, filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined readings: %w", err)
+	}
+	if readings == nil {
+		readings = []*entity.QuarantinedReading{}
+	}
+
+	return &dto.QuarantinedReadingListResponse{
+		Data:       readings,
+		Pagination: buildPaginationInfo(page, limit, totalCount),
+	}, nil
+}
+
+// GetQuarantinedReading retrieves a quarantined reading
+func (s *ReadingQuarantineService) GetQuarantinedReading(ctx context.Context, id uuid.UUID) (*entity.QuarantinedReading, error) {
+	quarantined, err := s.quarantineRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarantined reading: %w", err)
+	}
+	if quarantined == nil {
+		return nil, common.NewNotFoundError("quarantined reading", id.String())
+	}
+
+	return quarantined, nil
+}
+
+// ReplayQuarantinedReading stores the measurements of a pending quarantined reading. They
+// are checked with the strict policy against the current fields of the sensor type, so
+// a reading can be replayed once its fields were fixed; force stores them as they are.
+// A rejected message is replayed with its message ID, so it is stored at most once.
+func (s *ReadingQuarantineService) ReplayQuarantinedReading(ctx context.Context, id uuid.UUID, req dto.ReplayQuarantinedReadingRequest, reviewedBy *uuid.UUID) (*dto.ReplayQuarantinedReadingResponse, error) {
+	quarantined, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	readingReq := &dto.FlexibleIoTSensorReadingRequest{
+		AssetSensorID:   quarantined.AssetSensorID,
+		SensorTypeID:    quarantined.SensorTypeID,
+		ReadingTime:     &quarantined.ReadingTime,
+		Source:          quarantined.Source,
+		MeasurementData: make(map[string]dto.MeasurementValue, len(quarantined.Measurements)),
+	}
+	if quarantined.MacAddress != nil {
+		readingReq.MacAddress = *quarantined.MacAddress
+	}
+	if quarantined.Rejected && quarantined.MessageID != nil {
+		readingReq.MessageID = *quarantined.MessageID
+	}
+	for name, measurement := range quarantined.Measurements {
+		readingReq.MeasurementData[name] = dto.MeasurementValue{
+			Label: measurement.Label,
+			Unit:  measurement.Unit,
+			Value: measurement.Value,
+		}
+	}
+
+	opts := flexibleReadingOptions{
+		policy: entity.IngestionPolicyStrict,
+		// The other measurements of a partly stored message were stored with it
+		skipRequired: !quarantined.Rejected,
+		noQuarantine: true,
+		// The quarantined time was already parsed and corrected on arrival
+		resolvedTime: true,
+	}
+	if req.Force {
+		opts.policy = entity.IngestionPolicyPermissive
+	}
+
+	reading, err := s.iotSensorReadingService.createFlexibleReading(ctx, readingReq, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined.ReplayedReadingIDs = reading.ReadingIDs
+	quarantined.MarkReviewed(entity.QuarantineStatusReplayed, reviewedBy, req.Note)
+	if err := s.quarantineRepo.UpdateReview(ctx, quarantined); err != nil {
+		log.Printf("Error marking quarantined reading %s as replayed: %v", id, err)
+		return nil, fmt.Errorf("failed to update quarantined reading: %w", err)
+	}
+
+	log.Printf("Replayed quarantined reading %s as %d readings", id, len(reading.ReadingIDs))
+	return &dto.ReplayQuarantinedReadingResponse{
+		Quarantine: quarantined,
+		Reading:    reading,
+	}, nil
+}
+
+// DiscardQuarantinedReading drops a pending quarantined reading without storing it
+func (s *ReadingQuarantineService) DiscardQuarantinedReading(ctx context.Context, id uuid.UUID, req dto.DiscardQuarantinedReadingRequest, reviewedBy *uuid.UUID) (*entity.QuarantinedReading, error) {
+	quarantined, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined.MarkReviewed(entity.QuarantineStatusDiscarded, reviewedBy, req.Note)
+	if err := s.quarantineRepo.UpdateReview(ctx, quarantined); err != nil {
+		log.Printf("Error discarding quarantined reading %s: %v", id, err)
+		return nil, fmt.Errorf("failed to update quarantined reading: %w", err)
+	}
+
+	log.Printf("Discarded quarantined reading %s", id)
+	return quarantined, nil
+}
+
+// getPending loads a quarantined reading that still awaits review
+func (s *ReadingQuarantineService) getPending(ctx context.Context, id uuid.UUID) (*entity.QuarantinedReading, error) {
+	quarantined, err := s.GetQuarantinedReading(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !quarantined.IsPending() {
+		return nil, common.NewValidationError(fmt.Sprintf("quarantined reading was already %s", quarantined.Status), nil)
+	}
+
+	return quarantined, nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingRetentionService keeps the monthly partitions of iot_sensor_readings created ahead
+// of time and enforces the reading retention policies. Monthly partitions whose readings
+// have all expired are dropped, after archiving the readings of tenants whose policy
+// archives them; expired readings in the remaining partitions are deleted or archived in
+// batches. Client message IDs recorded to drop redelivered messages are deleted once they
+// are older than the message TTL.
+type ReadingRetentionService struct {
+	retentionRepo   repository.ReadingRetentionRepository
+	partitionsAhead int
+	batchSize       int
+	messageTTL      time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReadingRetentionService creates a new instance of ReadingRetentionService.
+// partitionsAhead is how many monthly partitions are created after the current month's, and
+// batchSize how many expired readings are deleted or archived per statement. Message IDs are
+// kept forever when messageTTL is 0.
+func NewReadingRetentionService(retentionRepo repository.ReadingRetentionRepository, partitionsAhead, batchSize int, messageTTL time.Duration) *ReadingRetentionService {
+	if partitionsAhead < 0 {
+		partitionsAhead = 0
+	}
+	if batchSize <= 0 {
+		batchSize = 10000
+	}
+	return &ReadingRetentionService{
+		retentionRepo:   retentionRepo,
+		partitionsAhead: partitionsAhead,
+		batchSize:       batchSize,
+		messageTTL:      messageTTL,
+	}
+}
+
+// ListPartitions retrieves the partitions of iot_sensor_readings
+func (s *ReadingRetentionService) ListPartitions(ctx context.Context) ([]entity.ReadingPartition, error) {
+	partitioned, err := s.retentionRepo.IsPartitioned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !partitioned {
+		return nil, common.NewValidationError("iot_sensor_readings is not partitioned", nil)
+	}
+	return s.retentionRepo.ListPartitions(ctx)
+}
+
+// EnsurePartitions creates the monthly partitions from the current month through the
+// partitions ahead that don't exist yet. It returns the names of the created partitions.
+func (s *ReadingRetentionService) EnsurePartitions(ctx context.Context) ([]string, error) {
+	partitions, err := s.ListPartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(partitions))
+	for _, partition := range partitions {
+		existing[partition.Name] = true
+	}
+
+	var created []string
+	month := entity.ReadingPartitionMonth(time.Now())
+	for i := 0; i <= s.partitionsAhead; i++ {
+		name := entity.ReadingPartitionName(month)
+		if !existing[name] {
+			if err := s.retentionRepo.CreatePartition(ctx, month); err != nil {
+				return created, err
+			}
+			log.Printf("Created reading partition %s", name)
+			created = append(created, name)
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return created, nil
+}
+
+// ListPolicies retrieves every reading retention policy
+func (s *ReadingRetentionService) ListPolicies(ctx context.Context) ([]*entity.ReadingRetentionPolicy, error) {
+	return s.retentionRepo.ListPolicies(ctx)
+}
+
+// SetPolicy sets the retention policy of a tenant, or the default policy when tenantID is nil
+func (s *ReadingRetentionService) SetPolicy(ctx context.Context, tenantID *uuid.UUID, retentionDays int, action entity.RetentionAction) (*entity.ReadingRetentionPolicy, error) {
+	if retentionDays <= 0 {
+		return nil, common.NewValidationError("retention days must be greater than 0", nil)
+	}
+	if !action.IsValid() {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid retention action: %s", action), nil)
+	}
+
+	policy := &entity.ReadingRetentionPolicy{
+		TenantID:      tenantID,
+		RetentionDays: retentionDays,
+		Action:        action,
+	}
+	if err := s.retentionRepo.UpsertPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy deletes the retention policy of a tenant, or the default policy when
+// tenantID is nil
+func (s *ReadingRetentionService) DeletePolicy(ctx context.Context, tenantID *uuid.UUID) error {
+	return s.retentionRepo.DeletePolicy(ctx, tenantID)
+}
+
+// Run creates the partitions ahead, when the readings are partitioned, applies the
+// retention policies and deletes the expired message IDs
+func (s *ReadingRetentionService) Run(ctx context.Context) (*entity.ReadingRetentionResult, error) {
+	result := &entity.ReadingRetentionResult{}
+
+	partitioned, err := s.retentionRepo.IsPartitioned(ctx)
+	if err != nil {
+		return result, err
+	}
+	if partitioned {
+		created, err := s.EnsurePartitions(ctx)
+		result.PartitionsCreated = created
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if err := s.applyPolicies(ctx, partitioned, result); err != nil {
+		return result, err
+	}
+	if err := s.expireMessages(ctx, result); err != nil {
+		return result, err
+	}
+
+	if len(result.PartitionsDropped) > 0 || result.RowsDeleted > 0 || result.RowsArchived > 0 || result.MessagesDeleted > 0 {
+		log.Printf("Applied reading retention: %d partitions dropped, %d readings deleted, %d readings archived, %d message IDs deleted",
+			len(result.PartitionsDropped), result.RowsDeleted, result.RowsArchived, result.MessagesDeleted)
+	}
+	return result, nil
+}
+
+// applyPolicies drops the monthly partitions whose readings have all expired, then deletes
+// or archives the expired readings left in the other partitions
+func (s *ReadingRetentionService) applyPolicies(ctx context.Context, partitioned bool, result *entity.ReadingRetentionResult) error {
+	policies, err := s.retentionRepo.ListPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var defaultPolicy *entity.ReadingRetentionPolicy
+	var tenantPolicies []*entity.ReadingRetentionPolicy
+	var tenantIDs []uuid.UUID
+	for _, policy := range policies {
+		if policy.TenantID == nil {
+			defaultPolicy = policy
+			continue
+		}
+		tenantPolicies = append(tenantPolicies, policy)
+		tenantIDs = append(tenantIDs, *policy.TenantID)
+	}
+
+	if partitioned {
+		partitions, err := s.retentionRepo.ListPartitions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, partition := range partitions {
+			if partition.IsDefault {
+				continue
+			}
+
+			// A tenant's readings in the partition have all expired when its cutoff is past
+			// the partition's end
+			keep := entity.ReadingTenantFilter{Excluded: tenantIDs}
+			archive := entity.ReadingTenantFilter{Excluded: tenantIDs}
+			expired := false
+			for _, policy := range tenantPolicies {
+				if policy.Cutoff(now).Before(partition.To) {
+					keep.TenantIDs = append(keep.TenantIDs, *policy.TenantID)
+					continue
+				}
+				expired = true
+				if policy.Action == entity.RetentionActionArchive {
+					archive.TenantIDs = append(archive.TenantIDs, *policy.TenantID)
+				}
+			}
+			if defaultPolicy == nil || defaultPolicy.Cutoff(now).Before(partition.To) {
+				keep.Others = true
+			} else {
+				expired = true
+				archive.Others = defaultPolicy.Action == entity.RetentionActionArchive
+			}
+			if !expired {
+				continue
+			}
+
+			live, err := s.retentionRepo.PartitionHasReadings(ctx, partition.Name, keep)
+			if err != nil {
+				return err
+			}
+			if live {
+				continue
+			}
+
+			archived, err := s.retentionRepo.ArchivePartitionReadings(ctx, partition.Name, archive)
+			if err != nil {
+				return err
+			}
+			result.RowsArchived += archived
+
+			if err := s.retentionRepo.DropPartition(ctx, partition.Name); err != nil {
+				return err
+			}
+			log.Printf("Dropped expired reading partition %s (%d readings archived)", partition.Name, archived)
+			result.PartitionsDropped = append(result.PartitionsDropped, partition.Name)
+		}
+	}
+
+	for _, policy := range tenantPolicies {
+		filter := entity.ReadingTenantFilter{TenantIDs: []uuid.UUID{*policy.TenantID}}
+		if err := s.expireReadings(ctx, policy, filter, now, result); err != nil {
+			return err
+		}
+	}
+	if defaultPolicy != nil {
+		filter := entity.ReadingTenantFilter{Others: true, Excluded: tenantIDs}
+		if err := s.expireReadings(ctx, defaultPolicy, filter, now, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expireReadings deletes or archives, by the policy's action, the readings selected by the
+// filter that are older than the policy keeps
+func (s *ReadingRetentionService) expireReadings(ctx context.Context, policy *entity.ReadingRetentionPolicy, filter entity.ReadingTenantFilter, now time.Time, result *entity.ReadingRetentionResult) error {
+	cutoff := policy.Cutoff(now)
+	for {
+		var rows int64
+		var err error
+		if policy.Action == entity.RetentionActionArchive {
+			rows, err = s.retentionRepo.ArchiveExpiredReadings(ctx, filter, cutoff, s.batchSize)
+			result.RowsArchived += rows
+		} else {
+			rows, err = s.retentionRepo.DeleteExpiredReadings(ctx, filter, cutoff, s.batchSize)
+			result.RowsDeleted += rows
+		}
+		if err != nil {
+			return err
+		}
+		if rows < int64(s.batchSize) {
+			return nil
+		}
+
+		// Leave the rest of a long backlog for the next run when stopping
+		select {
+		case <-s.stop:
+			return nil
+		default:
+		}
+	}
+}
+
+// expireMessages deletes the client message IDs older than the message TTL in batches. A
+// message redelivered after its ID was deleted is stored again.
+func (s *ReadingRetentionService) expireMessages(ctx context.Context, result *entity.ReadingRetentionResult) error {
+	if s.messageTTL <= 0 {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-s.messageTTL)
+	for {
+		rows, err := s.retentionRepo.DeleteExpiredMessages(ctx, cutoff, s.batchSize)
+		result.MessagesDeleted += rows
+		if err != nil {
+			return err
+		}
+		if rows < int64(s.batchSize) {
+			return nil
+		}
+
+		select {
+		case <-s.stop:
+			return nil
+		default:
+		}
+	}
+}
+
+// Start creates the partitions ahead and applies the retention policies every interval
+// until Stop is called
+func (s *ReadingRetentionService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.Run(context.Background()); err != nil {
+				log.Printf("Reading retention job: %v", err)
+			}
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Printf("Reading retention job started (interval %s)", interval)
+}
+
+// Stop stops the retention job and waits for the current run to finish
+func (s *ReadingRetentionService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Reading retention job stopped")
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ReadingRollupService keeps the reading rollup tables up to date. Each refresh rolls up
+// the readings stored since the last one, recomputing every bucket they fall in, so late
+// readings are counted in their bucket. Readings are only rolled up once they have been
+// stored for the safety lag, which leaves time for slow transactions to commit.
+type ReadingRollupService struct {
+	rollupRepo repository.ReadingRollupRepository
+	safetyLag  time.Duration
+	chunk      time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReadingRollupService creates a new instance of ReadingRollupService. chunk is how
+// much time of stored readings is rolled up per transaction.
+func NewReadingRollupService(rollupRepo repository.ReadingRollupRepository, safetyLag, chunk time.Duration) *ReadingRollupService {
+	if chunk <= 0 {
+		chunk = time.Hour
+	}
+	return &ReadingRollupService{
+		rollupRepo: rollupRepo,
+		safetyLag:  safetyLag,
+		chunk:      chunk,
+	}
+}
+
+// Refresh brings every rollup up to the readings stored before the safety lag. It returns
+// how many rollup rows were written.
+func (s *ReadingRollupService) Refresh(ctx context.Context) (int64, error) {
+	target := time.Now().Add(-s.safetyLag)
+
+	total := int64(0)
+	for _, resolution := range entity.ReadingRollupResolutions {
+		for {
+			through, rows, err := s.rollupRepo.Refresh(ctx, resolution, target, s.chunk)
+			if err != nil {
+				return total, fmt.Errorf("failed to refresh %s rollup: %w", resolution.Name, err)
+			}
+			total += rows
+			if !through.Before(target) {
+				break
+			}
+
+			// A long backlog is rolled up over several chunks; leave the rest for the next
+			// refresh when stopping
+			select {
+			case <-s.stop:
+				return total, nil
+			default:
+			}
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Refreshed reading rollups: %d rows written", total)
+	}
+	return total, nil
+}
+
+// Start refreshes the rollups every interval until Stop is called
+func (s *ReadingRollupService) Start(interval time.Duration) {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if _, err := s.Refresh(context.Background()); err != nil {
+					log.Printf("Reading rollup refresher: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Reading rollup refresher started (interval %s)", interval)
+}
+
+// Stop stops the rollup refresher and waits for the current refresh to finish
+func (s *ReadingRollupService) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.wg.Wait()
+	log.Println("Reading rollup refresher stopped")
+}
+
+// chooseReadingRollup picks the coarsest rollup that can serve an aggregation over the
+// buckets, or nil when the readings must be aggregated directly. A rollup serves the
+// whole rollup buckets within the time range that it has been refreshed through, and only
+// when no aggregation bucket starts within one of its buckets.
+func chooseReadingRollup(states map[string]*entity.ReadingRollupState, buckets []time.Time, fromTime, toTime time.Time) *entity.ReadingRollupRange {
+	for i := len(entity.ReadingRollupResolutions) - 1; i >= 0; i-- {
+		resolution := entity.ReadingRollupResolutions[i]
+		state := states[resolution.Name]
+		if state == nil {
+			continue
+		}
+
+		end := toTime
+		if state.RefreshedThrough.Before(end) {
+			end = state.RefreshedThrough
+		}
+		rollup := entity.ReadingRollupRange{
+			Resolution: resolution,
+			From:       fromTime.Truncate(resolution.Step),
+			To:         end.Truncate(resolution.Step),
+		}
+		if rollup.From.Before(fromTime) {
+			rollup.From = rollup.From.Add(resolution.Step)
+		}
+
+		if rollup.To.After(rollup.From) && rollup.CoversBuckets(buckets) {
+			return &rollup
+		}
+	}
+	return nil
+}
@@ -0,0 +1,623 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ReadingStreamFormat is the body format of a streamed reading import
+type ReadingStreamFormat string
+
+const (
+	ReadingStreamNDJSON ReadingStreamFormat = "ndjson" // One flexible reading request per line
+	ReadingStreamCSV    ReadingStreamFormat = "csv"    // A header row, then one row per reading time
+)
+
+const (
+	readingStreamChunkSize    = 5000    // Readings copied per transaction
+	readingStreamMaxLineBytes = 1 << 20 // Longest NDJSON line accepted
+	readingStreamMaxErrors    = 1000    // Rejected lines listed in the result
+	readingStreamPreviewSize  = 20      // Readings shown by a dry run
+)
+
+// Columns of a CSV import with a fixed meaning; every other column is a measurement field
+const (
+	csvColumnAssetSensorID = "asset_sensor_id"
+	csvColumnReadingTime   = "reading_time"
+	csvColumnMacAddress    = "mac_address"
+)
+
+// ReadingStreamOptions controls a streamed reading import
+type ReadingStreamOptions struct {
+	Format        ReadingStreamFormat
+	AssetSensorID uuid.UUID                 // Asset sensor of lines that don't name one
+	Backfill      bool                      // Historical data: only update the last reading cache, don't evaluate alerts
+	Profile       *entity.CSVMappingProfile // Column mapping of a CSV import, optional
+	DryRun        bool                      // Validate and preview the readings without storing them
+}
+
+// streamLine is a parsed line of a streamed import
+type streamLine struct {
+	number     int
+	request    *dto.FlexibleIoTSensorReadingRequest
+	textValues bool  // Values are unparsed text, converted by the field's data type
+	err        error // The line couldn't be parsed
+}
+
+// streamDecoder reads the lines of a streamed import. It returns io.EOF after the last
+// line; any other error ends the import.
+type streamDecoder interface {
+	next() (*streamLine, error)
+}
+
+// streamSensor is an asset sensor referenced by a streamed import, with the measurement
+// fields its readings are validated against
+type streamSensor struct {
+	tenantID        *uuid.UUID
+	sensorTypeID    uuid.UUID
+	fields          map[string]entity.SensorMeasurementField
+	policy          entity.IngestionPolicy
+	timeSettings    *entity.SensorTimeSettings
+	lastReadingTime *time.Time // Last reading of the sensor when the import started
+	err             error      // The sensor can't receive readings
+}
+
+// IngestReadingStream stores the readings of an NDJSON or CSV body. The body is read
+// line by line and stored with COPY in chunks, so imports of any size run in constant
+// memory. Every line is validated against the measurement fields of its asset sensor by
+// the ingestion policy of its sensor type; lines the policy rejects are quarantined and
+// reported with their line number, and the import continues. Chunks that were stored stay
+// stored when the import ends early. A dry run validates the whole body and reports what
+// would be stored, without quarantining anything.
+func (s *IoTSensorReadingService) IngestReadingStream(ctx context.Context, body io.Reader, opts ReadingStreamOptions) (*dto.ReadingStreamResponse, error) {
+	var decoder streamDecoder
+	switch opts.Format {
+	case ReadingStreamNDJSON:
+		decoder = newNDJSONStreamDecoder(body)
+	case ReadingStreamCSV:
+		csvDecoder, err := newCSVStreamDecoder(body, opts.Profile)
+		if err != nil {
+			return nil, err
+		}
+		decoder = csvDecoder
+	default:
+		return nil, common.NewValidationError(fmt.Sprintf("unsupported stream format %q, use ndjson or csv", opts.Format), nil)
+	}
+
+	effects := entity.ReadingEffects
+	if opts.Backfill {
+		effects = []entity.ReadingEffect{entity.ReadingEffectLastReading}
+	}
+
+	result := &dto.ReadingStreamResponse{Format: string(opts.Format), Backfill: opts.Backfill, DryRun: opts.DryRun}
+	sensors := make(map[uuid.UUID]*streamSensor)
+	chunk := make([]*entity.IoTSensorReadingFlexible, 0, readingStreamChunkSize)
+	chunkLines := 0
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if !opts.DryRun {
+			if err := s.iotSensorReadingRepo.CopyFlexibleBatch(ctx, chunk, effects); err != nil {
+				return fmt.Errorf("failed to store readings: %w", err)
+			}
+			if s.readingOutbox != nil {
+				s.readingOutbox.Notify()
+			} else if !opts.Backfill {
+				s.checkThresholdsForMultipleReadings(ctx, chunk)
+			}
+		}
+		result.LinesStored += chunkLines
+		result.ReadingsStored += len(chunk)
+		chunk = make([]*entity.IoTSensorReadingFlexible, 0, readingStreamChunkSize)
+		chunkLines = 0
+		return nil
+	}
+
+	reject := func(line int, err error) {
+		result.LinesRejected++
+		if len(result.Errors) < readingStreamMaxErrors {
+			result.Errors = append(result.Errors, dto.ReadingStreamLineError{Line: line, Error: err.Error()})
+		} else {
+			result.ErrorsTruncated = true
+		}
+	}
+
+	for {
+		line, err := decoder.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		result.LinesRead++
+
+		if line.err != nil {
+			reject(line.number, line.err)
+			continue
+		}
+
+		readings, lineResult, err := s.streamLineReadings(ctx, line, opts, sensors)
+		if lineResult != nil && len(lineResult.quarantined) > 0 {
+			result.LinesQuarantined++
+		}
+		if err != nil {
+			if common.IsValidationError(err) {
+				reject(line.number, err)
+				continue
+			}
+			return result, err
+		}
+		if len(lineResult.violations) > 0 {
+			if len(result.Warnings) < readingStreamMaxErrors {
+				result.Warnings = append(result.Warnings, dto.ReadingStreamLineError{Line: line.number, Error: strings.Join(lineResult.warnings(), "; ")})
+			} else {
+				result.WarningsTruncated = true
+			}
+		}
+
+		if opts.DryRun {
+			for _, reading := range readings {
+				if len(result.Preview) < readingStreamPreviewSize {
+					result.Preview = append(result.Preview, streamPreview(line.number, reading))
+				}
+			}
+		}
+
+		if len(readings) > 0 && readings[0].IsLate {
+			result.LinesLate++
+		}
+		if len(readings) > 0 && readings[0].TimeAnomaly != nil {
+			result.LinesTimeAnomaly++
+		}
+		chunk = append(chunk, readings...)
+		chunkLines++
+		if len(chunk) >= readingStreamChunkSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	log.Printf("Streamed %s import stored %d readings from %d lines, rejected %d lines",
+		opts.Format, result.ReadingsStored, result.LinesStored, result.LinesRejected)
+	return result, nil
+}
+
+// streamLineReadings validates a line against the measurement fields of its asset sensor
+// by its ingestion policy and returns one reading per accepted measurement, with the
+// outcome of the policy. Measurements the policy held back are quarantined, unless this
+// is a dry run; a line none of whose measurements were accepted is rejected.
+func (s *IoTSensorReadingService) streamLineReadings(
+	ctx context.Context,
+	line *streamLine,
+	opts ReadingStreamOptions,
+	sensors map[uuid.UUID]*streamSensor,
+) ([]*entity.IoTSensorReadingFlexible, *policyResult, error) {
+	req := line.request
+
+	if req.AssetSensorID == uuid.Nil {
+		req.AssetSensorID = opts.AssetSensorID
+	}
+	assetSensorID := req.AssetSensorID
+	if assetSensorID == uuid.Nil {
+		return nil, nil, common.NewValidationError("asset_sensor_id is required", nil)
+	}
+	if req.ReadingTime == nil && req.RawReadingTime == nil {
+		return nil, nil, common.NewValidationError("reading_time is required", nil)
+	}
+	if len(req.MeasurementData) == 0 {
+		return nil, nil, common.NewValidationError("no measurements", nil)
+	}
+
+	sensor, err := s.streamSensor(ctx, assetSensorID, sensors)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sensor.err != nil {
+		return nil, nil, sensor.err
+	}
+	if opts.Profile != nil && sensor.sensorTypeID != opts.Profile.SensorTypeID {
+		return nil, nil, common.NewValidationError(fmt.Sprintf("asset sensor %s is not of the sensor type of profile %s", assetSensorID, opts.Profile.Name), nil)
+	}
+	req.SensorTypeID = sensor.sensorTypeID
+	req.Source = entity.ReadingSourceImport
+
+	dataSource := "json"
+	if opts.Format == ReadingStreamCSV {
+		dataSource = "csv"
+	}
+
+	// Text values (CSV cells) are parsed by the data type of their field
+	measurements := req.MeasurementData
+	if line.textValues {
+		measurements = make(map[string]dto.MeasurementValue, len(req.MeasurementData))
+		for name, measurement := range req.MeasurementData {
+			if field, ok := sensor.fields[name]; ok {
+				measurement.Value = parseStreamText(field, measurement.Value)
+			}
+			measurements[name] = measurement
+		}
+	}
+
+	// Backfills are old by design, so only their clock and future times are checked
+	resolved, err := resolveReadingTime(sensor.timeSettings, req, time.Now(), !opts.Backfill)
+	if err != nil {
+		return nil, nil, err
+	}
+	isLate := sensor.lastReadingTime != nil && resolved.ReadingTime.Before(*sensor.lastReadingTime)
+
+	result := applyIngestionPolicy(sensor.policy, sensor.fields, measurements, func(name string) *entity.IoTSensorReadingFlexible {
+		fieldName := name
+		reading := &entity.IoTSensorReadingFlexible{
+			ID:                uuid.New(),
+			TenantID:          sensor.tenantID,
+			AssetSensorID:     assetSensorID,
+			SensorTypeID:      sensor.sensorTypeID,
+			MeasurementType:   name,
+			DataSource:        &dataSource,
+			OriginalFieldName: &fieldName,
+			IsLate:            isLate,
+		}
+		resolved.Apply(reading)
+		if req.MacAddress != "" {
+			macAddress := req.MacAddress
+			reading.MacAddress = &macAddress
+		}
+		return reading
+	}, true)
+
+	var quarantined *entity.QuarantinedReading
+	if !opts.DryRun {
+		quarantined, err = s.quarantine(ctx, req, sensor.tenantID, sensor.policy, result, resolved.ReadingTime)
+		if err != nil {
+			return nil, result, err
+		}
+	}
+
+	if len(result.readings) == 0 {
+		return nil, result, rejectionError(result, quarantined)
+	}
+	return result.readings, result, nil
+}
+
+// streamPreview describes a reading of a dry run
+func streamPreview(line int, reading *entity.IoTSensorReadingFlexible) dto.ReadingStreamPreview {
+	preview := dto.ReadingStreamPreview{
+		Line:            line,
+		AssetSensorID:   reading.AssetSensorID,
+		ReadingTime:     reading.ReadingTime,
+		MeasurementType: reading.MeasurementType,
+	}
+	switch {
+	case reading.NumericValue != nil:
+		preview.Value = *reading.NumericValue
+	case reading.BooleanValue != nil:
+		preview.Value = *reading.BooleanValue
+	case reading.TextValue != nil:
+		preview.Value = *reading.TextValue
+	}
+	if reading.MeasurementUnit != nil {
+		preview.Unit = *reading.MeasurementUnit
+	}
+	return preview
+}
+
+// streamSensor returns the asset sensor of a line, loading it once per import. Sensors
+// that can't receive readings are remembered with the reason.
+func (s *IoTSensorReadingService) streamSensor(ctx context.Context, assetSensorID uuid.UUID, sensors map[uuid.UUID]*streamSensor) (*streamSensor, error) {
+	if sensor, ok := sensors[assetSensorID]; ok {
+		return sensor, nil
+	}
+
+	assetSensor, err := s.assetSensorRepo.GetByID(ctx, assetSensorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset sensor %s: %w", assetSensorID, err)
+	}
+
+	sensor := &streamSensor{}
+	if assetSensor == nil {
+		sensor.err = common.NewValidationError(fmt.Sprintf("asset sensor %s not found", assetSensorID), nil)
+	} else {
+		sensor.tenantID = assetSensor.AssetSensor.TenantID
+		sensor.sensorTypeID = assetSensor.AssetSensor.SensorTypeID
+		sensor.lastReadingTime = assetSensor.AssetSensor.LastReadingTime
+		sensor.fields = measurementFields(assetSensor)
+		if sensor.policy, err = s.ingestionPolicy(sensor.sensorTypeID); err != nil {
+			return nil, err
+		}
+		if sensor.timeSettings, err = s.readingTimeSettings(ctx, assetSensorID); err != nil {
+			return nil, err
+		}
+		if len(sensor.fields) == 0 {
+			sensor.err = common.NewValidationError(fmt.Sprintf("asset sensor %s has no measurement fields", assetSensorID), nil)
+		}
+	}
+
+	sensors[assetSensorID] = sensor
+	return sensor, nil
+}
+
+// parseStreamText parses a text value by the data type of its field. Text that doesn't
+// parse is returned as is, so the policy reports it as the wrong type.
+func parseStreamText(field entity.SensorMeasurementField, value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch field.DataType {
+	case entity.MeasurementDataTypeNumber:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return number
+		}
+	case entity.MeasurementDataTypeBoolean:
+		if boolean, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+// ndjsonStreamDecoder reads one flexible reading request per line
+type ndjsonStreamDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONStreamDecoder(body io.Reader) *ndjsonStreamDecoder {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), readingStreamMaxLineBytes)
+	return &ndjsonStreamDecoder{scanner: scanner}
+}
+
+func (d *ndjsonStreamDecoder) next() (*streamLine, error) {
+	for d.scanner.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		line := &streamLine{number: d.line, request: &dto.FlexibleIoTSensorReadingRequest{}}
+		if err := json.Unmarshal(data, line.request); err != nil {
+			line.err = fmt.Errorf("invalid JSON: %v", err)
+		}
+		return line, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, common.NewValidationError(fmt.Sprintf("line %d is longer than %d bytes", d.line+1, readingStreamMaxLineBytes), nil)
+		}
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return nil, io.EOF
+}
+
+// csvColumnRole is what a CSV column holds
+type csvColumnRole int
+
+const (
+	csvColumnIgnored csvColumnRole = iota
+	csvColumnSensor
+	csvColumnTime
+	csvColumnMac
+	csvColumnMeasurement
+)
+
+// csvColumn describes a column of a CSV import
+type csvColumn struct {
+	role  csvColumnRole
+	name  string // Header of the column
+	field string // Measurement field of a measurement column
+	unit  string // Unit of a measurement column's values, empty for the field's unit
+}
+
+// csvStreamDecoder reads CSV rows, finding columns by their header. Without a mapping
+// profile, the columns asset_sensor_id, reading_time (RFC3339) and mac_address have a
+// fixed meaning and every other column is the measurement field of the same name. With a
+// profile, only the columns it maps are read. Empty cells are skipped.
+type csvStreamDecoder struct {
+	reader    *csv.Reader
+	columns   []csvColumn
+	parseTime func(value string) (time.Time, error) // Reads the timestamps of a profile
+}
+
+func newCSVStreamDecoder(body io.Reader, profile *entity.CSVMappingProfile) (*csvStreamDecoder, error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+	if profile != nil {
+		reader.Comma, _ = utf8.DecodeRuneInString(profile.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, common.NewValidationError("CSV body is empty", nil)
+	}
+	if err != nil {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid CSV header: %v", err), nil)
+	}
+
+	columns := make([]csvColumn, len(header))
+	seen := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Byte order mark of spreadsheet exports
+		}
+		if name == "" || seen[name] {
+			return nil, common.NewValidationError(fmt.Sprintf("CSV header column %d is empty or repeated", i+1), nil)
+		}
+		seen[name] = true
+		columns[i] = csvColumn{name: name}
+	}
+
+	decoder := &csvStreamDecoder{reader: reader, columns: columns}
+	if profile != nil {
+		err = decoder.mapProfile(profile)
+	} else {
+		err = decoder.mapHeader()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decoder, nil
+}
+
+// mapHeader assigns the columns by their header
+func (d *csvStreamDecoder) mapHeader() error {
+	hasTime, measurements := false, 0
+	for i := range d.columns {
+		column := &d.columns[i]
+		switch column.name {
+		case csvColumnAssetSensorID:
+			column.role = csvColumnSensor
+		case csvColumnReadingTime:
+			column.role = csvColumnTime
+			hasTime = true
+		case csvColumnMacAddress:
+			column.role = csvColumnMac
+		default:
+			column.role = csvColumnMeasurement
+			column.field = column.name
+			measurements++
+		}
+	}
+
+	if !hasTime {
+		return common.NewValidationError("CSV header must have a reading_time column", nil)
+	}
+	if measurements == 0 {
+		return common.NewValidationError("CSV header must have at least one measurement column", nil)
+	}
+	return nil
+}
+
+// mapProfile assigns the columns mapped by a profile. Every mapped column must be present.
+func (d *csvStreamDecoder) mapProfile(profile *entity.CSVMappingProfile) error {
+	byName := make(map[string]*csvColumn, len(d.columns))
+	for i := range d.columns {
+		byName[d.columns[i].name] = &d.columns[i]
+	}
+
+	var missing []string
+	assign := func(name string, role csvColumnRole, field, unit string) {
+		column, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			return
+		}
+		column.role, column.field, column.unit = role, field, unit
+	}
+
+	assign(profile.TimestampColumn, csvColumnTime, "", "")
+	if profile.AssetSensorColumn != nil {
+		assign(*profile.AssetSensorColumn, csvColumnSensor, "", "")
+	}
+	for _, mapping := range profile.Columns {
+		assign(mapping.Column, csvColumnMeasurement, mapping.Field, mapping.Unit)
+	}
+
+	if len(missing) > 0 {
+		return common.NewValidationError(fmt.Sprintf("CSV header is missing the columns of profile %s: %s",
+			profile.Name, strings.Join(missing, ", ")), nil)
+	}
+
+	d.parseTime = func(value string) (time.Time, error) {
+		t, err := profile.ParseTimestamp(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s: %v", profile.TimestampColumn, err)
+		}
+		return t, nil
+	}
+	return nil
+}
+
+func (d *csvStreamDecoder) next() (*streamLine, error) {
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &streamLine{number: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	number, _ := d.reader.FieldPos(0)
+	line := &streamLine{
+		number:     number,
+		request:    &dto.FlexibleIoTSensorReadingRequest{MeasurementData: make(map[string]dto.MeasurementValue)},
+		textValues: true,
+	}
+
+	for i, cell := range record {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+
+		column := d.columns[i]
+		switch column.role {
+		case csvColumnSensor:
+			id, err := uuid.Parse(cell)
+			if err != nil {
+				line.err = fmt.Errorf("%s must be a UUID", column.name)
+				return line, nil
+			}
+			line.request.AssetSensorID = id
+		case csvColumnTime:
+			if d.parseTime == nil {
+				// Without a profile, timestamps other than RFC3339 are parsed by the time
+				// settings of the line's asset sensor
+				if readingTime, err := time.Parse(time.RFC3339, cell); err == nil {
+					line.request.ReadingTime = &readingTime
+				} else {
+					line.request.RawReadingTime = cell
+				}
+				continue
+			}
+			readingTime, err := d.parseTime(cell)
+			if err != nil {
+				line.err = err
+				return line, nil
+			}
+			line.request.ReadingTime = &readingTime
+		case csvColumnMac:
+			line.request.MacAddress = cell
+		case csvColumnMeasurement:
+			line.request.MeasurementData[column.field] = dto.MeasurementValue{Unit: column.unit, Value: cell}
+		}
+	}
+
+	return line, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// readingTimeSettings returns the time settings of an asset sensor, or the defaults when
+// it has none
+func (s *IoTSensorReadingService) readingTimeSettings(ctx context.Context, assetSensorID uuid.UUID) (*entity.SensorTimeSettings, error) {
+	if s.timeSettingsRepo == nil {
+		return entity.NewSensorTimeSettings(assetSensorID), nil
+	}
+
+	settings, err := s.timeSettingsRepo.GetByAssetSensorID(ctx, assetSensorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time settings of asset sensor %s: %w", assetSensorID, err)
+	}
+	if settings == nil {
+		return entity.NewSensorTimeSettings(assetSensorID), nil
+	}
+	return settings, nil
+}
+
+// resolveReadingTime parses and corrects the reading time of a request by the time
+// settings of its asset sensor. Timestamps that can't be parsed and readings rejected for
+// their time are validation errors.
+func resolveReadingTime(settings *entity.SensorTimeSettings, req *dto.FlexibleIoTSensorReadingRequest, receivedAt time.Time, checkAge bool) (*entity.ResolvedReadingTime, error) {
+	resolved, err := settings.ResolveReadingTime(req.ReadingTime, req.RawReadingTime, receivedAt, checkAge)
+	if err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+
+	if resolved.Anomaly != "" {
+		log.Printf("Reading of asset sensor %s has a %s timestamp (%s, %s)",
+			req.AssetSensorID, resolved.Anomaly, resolved.Warning(), settings.SkewAction)
+	}
+	return resolved, nil
+}
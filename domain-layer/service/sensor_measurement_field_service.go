@@ -1,6 +1,7 @@
 package service
 
 import (
+	"be-lecsens/asset_management/data-layer/entity"
 	"be-lecsens/asset_management/data-layer/repository"
 	"be-lecsens/asset_management/helpers/dto"
 	"context"
@@ -108,7 +109,9 @@ func (s *SensorMeasurementFieldService) Create(ctx context.Context, req *dto.Cre
 		field.Description = repository.NullStringFromPtr(req.Description)
 	}
 	if req.Unit != nil {
-		field.Unit = repository.NullStringFromPtr(req.Unit)
+		// Readings are converted to the field's unit, so store its catalog symbol
+		unit := entity.NormalizeUnit(*req.Unit)
+		field.Unit = repository.NullStringFromPtr(&unit)
 	}
 	if req.Min != nil {
 		field.Min = repository.NullFloat64FromPtr(req.Min)
@@ -278,7 +281,9 @@ func (s *SensorMeasurementFieldService) Update(ctx context.Context, id uuid.UUID
 		field.Required = *req.Required
 	}
 	if req.Unit != nil {
-		field.Unit = repository.NullStringFromPtr(req.Unit)
+		// Readings are converted to the field's unit, so store its catalog symbol
+		unit := entity.NormalizeUnit(*req.Unit)
+		field.Unit = repository.NullStringFromPtr(&unit)
 	}
 	if req.Min != nil {
 		field.Min = repository.NullFloat64FromPtr(req.Min)
@@ -367,3 +372,8 @@ func (s *SensorMeasurementFieldService) GetRequiredFields(ctx context.Context, m
 
 	return dtos, nil
 }
+
+// GetUnits lists the units field values can be converted between
+func (s *SensorMeasurementFieldService) GetUnits() []entity.MeasurementUnit {
+	return entity.UnitCatalog()
+}
@@ -5,29 +5,52 @@ import (
 	"be-lecsens/asset_management/data-layer/repository"
 	"be-lecsens/asset_management/helpers/common"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// AlertNotifier is told about alerts that were opened or resolved
+type AlertNotifier interface {
+	NotifyAlert(ctx context.Context, alert *entity.AssetAlert, transition entity.ThresholdTransition) error
+}
+
+// MaintenanceChecker tells whether alerts of an asset are suppressed or tagged by maintenance
+type MaintenanceChecker interface {
+	CheckMaintenance(ctx context.Context, assetID uuid.UUID, at time.Time) (*MaintenanceStatus, error)
+}
+
 // SensorThresholdService handles business logic for sensor thresholds
 type SensorThresholdService struct {
 	sensorThresholdRepo repository.SensorThresholdRepository
 	assetSensorRepo     repository.AssetSensorRepository
 	assetAlertRepo      repository.AssetAlertRepository
+	readingRepo         repository.IoTSensorReadingRepository
+	alertNotifier       AlertNotifier
+	maintenanceChecker  MaintenanceChecker
 }
 
-// NewSensorThresholdService creates a new instance of SensorThresholdService
+// NewSensorThresholdService creates a new instance of SensorThresholdService.
+// alertNotifier and maintenanceChecker may be nil when not needed; readingRepo is
+// required to evaluate windowed rule kinds.
 func NewSensorThresholdService(
 	sensorThresholdRepo repository.SensorThresholdRepository,
 	assetSensorRepo repository.AssetSensorRepository,
 	assetAlertRepo repository.AssetAlertRepository,
+	readingRepo repository.IoTSensorReadingRepository,
+	alertNotifier AlertNotifier,
+	maintenanceChecker MaintenanceChecker,
 ) *SensorThresholdService {
 	return &SensorThresholdService{
 		sensorThresholdRepo: sensorThresholdRepo,
 		assetSensorRepo:     assetSensorRepo,
 		assetAlertRepo:      assetAlertRepo,
+		readingRepo:         readingRepo,
+		alertNotifier:       alertNotifier,
+		maintenanceChecker:  maintenanceChecker,
 	}
 }
 
@@ -44,16 +67,21 @@ func (s *SensorThresholdService) CreateSensorThreshold(ctx context.Context, thre
 		if assetSensor == nil {
 			return nil, common.NewValidationError("asset sensor not found", nil)
 		}
+		if err := validateRuleFieldType(assetSensor, threshold.MeasurementFieldName, threshold.RuleKind); err != nil {
+			return nil, err
+		}
 	}
 
-	// Validate threshold values
-	if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
-		return nil, common.NewValidationError("minimum threshold must be less than maximum threshold", nil)
+	if err := validateRuleKind(threshold); err != nil {
+		return nil, err
 	}
 
-	// Ensure at least one threshold value is set
-	if threshold.MinValue == nil && threshold.MaxValue == nil {
-		return nil, common.NewValidationError("at least one threshold value (min or max) must be set", nil)
+	if err := validateThresholdValues(threshold); err != nil {
+		return nil, err
+	}
+
+	if err := validateAlertRules(threshold); err != nil {
+		return nil, err
 	}
 
 	// Create the threshold
@@ -101,14 +129,16 @@ func (s *SensorThresholdService) ListSensorThresholds(ctx context.Context, tenan
 func (s *SensorThresholdService) UpdateSensorThreshold(ctx context.Context, threshold *entity.SensorThreshold) (*entity.SensorThreshold, error) {
 	log.Printf("Updating sensor threshold: %+v", threshold)
 
-	// Validate threshold values
-	if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
-		return nil, common.NewValidationError("minimum threshold must be less than maximum threshold", nil)
+	if err := validateRuleKind(threshold); err != nil {
+		return nil, err
 	}
 
-	// Ensure at least one threshold value is set
-	if threshold.MinValue == nil && threshold.MaxValue == nil {
-		return nil, common.NewValidationError("at least one threshold value (min or max) must be set", nil)
+	if err := validateThresholdValues(threshold); err != nil {
+		return nil, err
+	}
+
+	if err := validateAlertRules(threshold); err != nil {
+		return nil, err
 	}
 
 	// Check if threshold exists
@@ -120,6 +150,18 @@ func (s *SensorThresholdService) UpdateSensorThreshold(ctx context.Context, thre
 		return nil, common.NewNotFoundError("sensor threshold", threshold.ID.String())
 	}
 
+	if s.assetSensorRepo != nil && threshold.RuleKind != existing.RuleKind {
+		assetSensor, err := s.assetSensorRepo.GetByID(ctx, existing.AssetSensorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate asset sensor: %w", err)
+		}
+		if assetSensor != nil {
+			if err := validateRuleFieldType(assetSensor, existing.MeasurementFieldName, threshold.RuleKind); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Update the threshold
 	if err := s.sensorThresholdRepo.Update(ctx, threshold); err != nil {
 		log.Printf("Error updating sensor threshold: %v", err)
@@ -247,19 +289,35 @@ func (s *SensorThresholdService) ListAllSensorThresholds(ctx context.Context, li
 	return thresholds, totalCount, nil
 }
 
-// CheckThresholdsForValue checks if a value breaches any thresholds and creates alerts
+// CheckThresholdsForValue evaluates a reading value (float64, bool or string) against the
+// asset sensor's thresholds for the field. Numeric values are checked by the min/max rule
+// kinds and boolean and text values by the value match rule kinds; thresholds of another
+// kind are skipped. Each (asset sensor, threshold) pair keeps at most one open alert; the
+// threshold's alert rules decide when that alert is opened, updated and resolved. Windowed
+// rule kinds are evaluated against a value derived from the field's recent readings, which
+// include the reading itself since it is stored before thresholds are checked. No alert is
+// opened while the asset is under suppressing maintenance, though open alerts still update
+// and resolve; alerts opened during a tagging maintenance window are tagged with it and not
+// notified. A threshold that fails to
+// evaluate doesn't stop the others; the failures are returned together so the evaluation
+// can be retried.
 func (s *SensorThresholdService) CheckThresholdsForValue(
 	ctx context.Context,
 	reading *entity.IoTSensorReading,
 	fieldName string,
-	value float64,
+	value interface{},
 ) error {
-	// Get all thresholds for this measurement type
-	thresholds, err := s.sensorThresholdRepo.GetByMeasurementTypeID(ctx, reading.SensorTypeID)
+	// Get all thresholds configured for this asset sensor
+	thresholds, err := s.sensorThresholdRepo.GetByAssetSensorID(ctx, reading.AssetSensorID)
 	if err != nil {
 		return fmt.Errorf("failed to get thresholds: %w", err)
 	}
 
+	var assetID uuid.UUID
+	var maintenance *MaintenanceStatus
+	assetIDLoaded := false
+	var errs []error
+
 	// Check each threshold
 	for _, threshold := range thresholds {
 		// Skip if threshold is not active
@@ -272,23 +330,159 @@ func (s *SensorThresholdService) CheckThresholdsForValue(
 			continue
 		}
 
-		// Check if value breaches threshold
-		status := threshold.CheckValue(value)
-
-		if status != entity.ThresholdStatusNormal {
-			// Create or update alert
-			if err := s.assetAlertRepo.CreateAlertFromReading(ctx, reading, threshold, value); err != nil {
-				log.Printf("Error creating alert for threshold breach: %v", err)
+		// Skip thresholds that don't apply to values of this type
+		numeric, isNumeric := value.(float64)
+		var matched bool
+		if threshold.RuleKind.IsValueMatch() {
+			var ok bool
+			if matched, ok = threshold.MatchValue(value); !ok {
 				continue
 			}
+		} else if !isNumeric {
+			continue
+		}
+
+		// Alerts reference the asset, so resolve it once for all matching thresholds
+		if !assetIDLoaded {
+			assetSensor, err := s.assetSensorRepo.GetByID(ctx, reading.AssetSensorID)
+			if err != nil {
+				return fmt.Errorf("failed to get asset sensor: %w", err)
+			}
+			if assetSensor == nil {
+				return common.NewNotFoundError("asset sensor", reading.AssetSensorID.String())
+			}
+			assetID = assetSensor.AssetID
+			assetIDLoaded = true
+
+			if s.maintenanceChecker != nil {
+				// Evaluate normally if maintenance cannot be checked rather than lose alerts
+				maintenance, err = s.maintenanceChecker.CheckMaintenance(ctx, assetID, reading.ReadingTime)
+				if err != nil {
+					log.Printf("Error checking maintenance for asset %s: %v", assetID, err)
+					maintenance = nil
+				}
+			}
+		}
+
+		var alert *entity.AssetAlert
+		var transition entity.ThresholdTransition
+		if threshold.RuleKind.IsValueMatch() {
+			alert, transition, err = s.assetAlertRepo.ApplyThresholdMatch(ctx, reading, assetID, threshold, value, matched, maintenance.alertMaintenance())
 		} else {
-			// Resolve any active alerts for this threshold
-			if err := s.assetAlertRepo.ResolveAlertsForReading(ctx, reading, threshold, value); err != nil {
-				log.Printf("Error resolving alerts for normal value: %v", err)
+			evaluated, ok, valueErr := s.thresholdValue(ctx, reading, threshold, numeric)
+			if valueErr != nil {
+				log.Printf("Error computing value for threshold %s: %v", threshold.ID, valueErr)
+				errs = append(errs, fmt.Errorf("failed to compute value for threshold %s: %w", threshold.ID, valueErr))
+				continue
+			}
+			if !ok {
 				continue
 			}
+			alert, transition, err = s.assetAlertRepo.ApplyThresholdEvaluation(ctx, reading, assetID, threshold, evaluated, maintenance.alertMaintenance())
 		}
+		if err != nil {
+			log.Printf("Error evaluating threshold %s: %v", threshold.ID, err)
+			errs = append(errs, fmt.Errorf("failed to evaluate threshold %s: %w", threshold.ID, err))
+			continue
+		}
+
+		if alert != nil {
+			log.Printf("Threshold %s on asset sensor %s: %s (alert %s, occurrences %d)",
+				threshold.ID, reading.AssetSensorID, transition, alert.ID, alert.OccurrenceCount)
+
+			if s.alertNotifier != nil && alert.MaintenanceWindowID == nil {
+				if err := s.alertNotifier.NotifyAlert(ctx, alert, transition); err != nil {
+					log.Printf("Error queueing notifications for alert %s: %v", alert.ID, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// thresholdValue returns the value a threshold's range applies to. For windowed rule kinds
+// it is derived from the readings within the window; ok is false when there are too few
+// readings to evaluate the rule yet.
+func (s *SensorThresholdService) thresholdValue(
+	ctx context.Context,
+	reading *entity.IoTSensorReading,
+	threshold *entity.SensorThreshold,
+	value float64,
+) (float64, bool, error) {
+	if !threshold.RuleKind.IsWindowed() {
+		return value, true, nil
+	}
+	if s.readingRepo == nil {
+		return 0, false, fmt.Errorf("readings are not available for %s rules", threshold.RuleKind)
+	}
+
+	stats, err := s.readingRepo.GetWindowStats(ctx, reading.AssetSensorID, threshold.MeasurementFieldName,
+		reading.ReadingTime.Add(-threshold.Window()), reading.ReadingTime)
+	if err != nil {
+		return 0, false, err
+	}
+
+	evaluated, ok := threshold.DerivedValue(value, reading.ReadingTime, stats)
+	return evaluated, ok, nil
+}
+
+// validateRuleKind checks the rule kind and window of a threshold
+func validateRuleKind(threshold *entity.SensorThreshold) error {
+	if err := threshold.ValidateRuleKind(); err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+	return nil
+}
+
+// validateRuleFieldType checks that a rule kind applies to the data type of the measurement
+// field. Fields the sensor doesn't define are left to the existing checks.
+func validateRuleFieldType(assetSensor *repository.AssetSensorWithDetails, fieldName string, ruleKind entity.ThresholdRuleKind) error {
+	expected := entity.MeasurementDataTypeNumber
+	if ruleKind.IsBoolean() {
+		expected = entity.MeasurementDataTypeBoolean
+	} else if ruleKind.IsText() {
+		expected = entity.MeasurementDataTypeString
+	}
+
+	for _, measurementType := range assetSensor.MeasurementTypes {
+		for _, field := range measurementType.Fields {
+			if field.Name == fieldName && entity.MeasurementDataType(field.DataType) != expected {
+				return common.NewValidationError(fmt.Sprintf("%s rules need a %s field, but %s is a %s field",
+					ruleKind, expected, fieldName, field.DataType), nil)
+			}
+		}
+	}
+	return nil
+}
+
+// validateThresholdValues checks the min/max values of a threshold. Boolean and text
+// rules don't use them; ValidateRuleKind checks what those rules need instead.
+func validateThresholdValues(threshold *entity.SensorThreshold) error {
+	if threshold.RuleKind.IsValueMatch() {
+		return nil
+	}
+
+	// Validate threshold values
+	if threshold.MinValue != nil && threshold.MaxValue != nil && *threshold.MinValue >= *threshold.MaxValue {
+		return common.NewValidationError("minimum threshold must be less than maximum threshold", nil)
+	}
+
+	// Ensure at least one threshold value is set
+	if threshold.MinValue == nil && threshold.MaxValue == nil {
+		return common.NewValidationError("at least one threshold value (min or max) must be set", nil)
 	}
+	return nil
+}
 
+// validateAlertRules checks the alert rules of a threshold
+func validateAlertRules(threshold *entity.SensorThreshold) error {
+	if err := threshold.AlertRules.Validate(); err != nil {
+		return common.NewValidationError(err.Error(), nil)
+	}
+	if threshold.MinValue != nil && threshold.MaxValue != nil &&
+		2*threshold.AlertRules.Hysteresis >= *threshold.MaxValue-*threshold.MinValue {
+		return common.NewValidationError("hysteresis must be less than half of the threshold range", nil)
+	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SensorTimeSettingsService manages how the timestamps of each asset sensor's readings are
+// parsed and corrected
+type SensorTimeSettingsService struct {
+	settingsRepo    repository.SensorTimeSettingsRepository
+	assetSensorRepo repository.AssetSensorRepository
+}
+
+// NewSensorTimeSettingsService creates a new instance of SensorTimeSettingsService
+func NewSensorTimeSettingsService(
+	settingsRepo repository.SensorTimeSettingsRepository,
+	assetSensorRepo repository.AssetSensorRepository,
+) *SensorTimeSettingsService {
+	return &SensorTimeSettingsService{
+		settingsRepo:    settingsRepo,
+		assetSensorRepo: assetSensorRepo,
+	}
+}
+
+// GetSettings retrieves the time settings of an asset sensor of the tenant, or the
+// defaults when it has none
+func (s *SensorTimeSettingsService) GetSettings(ctx context.Context, tenantID, assetSensorID uuid.UUID) (*dto.SensorTimeSettingsResponse, error) {
+	assetSensor, err := s.getTenantAssetSensor(ctx, tenantID, assetSensorID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByAssetSensorID(ctx, assetSensorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sensor time settings: %w", err)
+	}
+	if settings == nil {
+		settings = entity.NewSensorTimeSettings(assetSensorID)
+		settings.TenantID = assetSensor.TenantID
+		return &dto.SensorTimeSettingsResponse{SensorTimeSettings: settings, IsDefault: true}, nil
+	}
+
+	return &dto.SensorTimeSettingsResponse{SensorTimeSettings: settings}, nil
+}
+
+// UpdateSettings updates the time settings of an asset sensor of the tenant
+func (s *SensorTimeSettingsService) UpdateSettings(ctx context.Context, tenantID, assetSensorID uuid.UUID, req dto.UpdateSensorTimeSettingsRequest) (*dto.SensorTimeSettingsResponse, error) {
+	current, err := s.GetSettings(ctx, tenantID, assetSensorID)
+	if err != nil {
+		return nil, err
+	}
+	settings := current.SensorTimeSettings
+
+	if req.TimestampFormat != nil {
+		settings.TimestampFormat = *req.TimestampFormat
+	}
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+	if req.MaxFutureSkewSeconds != nil {
+		settings.MaxFutureSkewSeconds = *req.MaxFutureSkewSeconds
+	}
+	if req.MaxAgeSeconds != nil {
+		settings.MaxAgeSeconds = *req.MaxAgeSeconds
+	}
+	if req.SkewAction != nil {
+		settings.SkewAction = entity.TimeSkewAction(*req.SkewAction)
+	}
+	switch {
+	case req.ClockOffsetMs != nil:
+		settings.ClockOffsetMs = *req.ClockOffsetMs
+	case req.DeviceClock != nil:
+		settings.ClockOffsetMs = time.Since(*req.DeviceClock).Milliseconds()
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, common.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		log.Printf("Error saving time settings of asset sensor %s: %v", assetSensorID, err)
+		return nil, fmt.Errorf("failed to save sensor time settings: %w", err)
+	}
+
+	log.Printf("Updated time settings of asset sensor %s (format %s, timezone %s, clock offset %dms)",
+		assetSensorID, settings.TimestampFormat, settings.Timezone, settings.ClockOffsetMs)
+	return &dto.SensorTimeSettingsResponse{SensorTimeSettings: settings}, nil
+}
+
+// ResetSettings returns an asset sensor of the tenant to the default time settings
+func (s *SensorTimeSettingsService) ResetSettings(ctx context.Context, tenantID, assetSensorID uuid.UUID) error {
+	if _, err := s.getTenantAssetSensor(ctx, tenantID, assetSensorID); err != nil {
+		return err
+	}
+
+	if err := s.settingsRepo.Delete(ctx, assetSensorID); err != nil {
+		log.Printf("Error resetting time settings of asset sensor %s: %v", assetSensorID, err)
+		return fmt.Errorf("failed to reset sensor time settings: %w", err)
+	}
+
+	log.Printf("Reset time settings of asset sensor %s", assetSensorID)
+	return nil
+}
+
+// getTenantAssetSensor retrieves an asset sensor belonging to the tenant
+func (s *SensorTimeSettingsService) getTenantAssetSensor(ctx context.Context, tenantID, assetSensorID uuid.UUID) (*entity.AssetSensor, error) {
+	assetSensor, err := s.assetSensorRepo.GetByID(ctx, assetSensorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset sensor: %w", err)
+	}
+	if assetSensor == nil || assetSensor.TenantID == nil || *assetSensor.TenantID != tenantID {
+		return nil, common.NewNotFoundError("asset sensor", assetSensorID.String())
+	}
+
+	return assetSensor.AssetSensor, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
+	"be-lecsens/asset_management/data-layer/entity"
 	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/helpers/common"
 	"be-lecsens/asset_management/helpers/dto"
 
 	"github.com/google/uuid"
@@ -26,37 +29,44 @@ func NewSensorTypeService(sensorTypeRepo *repository.SensorTypeRepository) *Sens
 
 // CreateSensorType creates a new sensor type
 func (s *SensorTypeService) CreateSensorType(ctx context.Context, req *dto.CreateSensorTypeRequest) (*dto.SensorTypeDTO, error) {
+	policy, err := parseIngestionPolicy(req.IngestionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create new sensor type
 	now := time.Now()
 	sensorType := &repository.SensorType{
-		ID:           uuid.New(),
-		Name:         req.Name,
-		Description:  req.Description,
-		Manufacturer: req.Manufacturer,
-		Model:        req.Model,
-		Version:      req.Version,
-		IsActive:     req.IsActive,
-		CreatedAt:    now,
-		UpdatedAt:    &now,
+		ID:              uuid.New(),
+		Name:            req.Name,
+		Description:     req.Description,
+		Manufacturer:    req.Manufacturer,
+		Model:           req.Model,
+		Version:         req.Version,
+		IsActive:        req.IsActive,
+		IngestionPolicy: policy,
+		CreatedAt:       now,
+		UpdatedAt:       &now,
 	}
 
 	// Save to repository
-	err := s.sensorTypeRepo.Create(sensorType)
+	err = s.sensorTypeRepo.Create(sensorType)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert to DTO
 	return &dto.SensorTypeDTO{
-		ID:           sensorType.ID,
-		Name:         sensorType.Name,
-		Description:  sensorType.Description,
-		Manufacturer: sensorType.Manufacturer,
-		Model:        sensorType.Model,
-		Version:      sensorType.Version,
-		IsActive:     sensorType.IsActive,
-		CreatedAt:    sensorType.CreatedAt,
-		UpdatedAt:    sensorType.UpdatedAt,
+		ID:              sensorType.ID,
+		Name:            sensorType.Name,
+		Description:     sensorType.Description,
+		Manufacturer:    sensorType.Manufacturer,
+		Model:           sensorType.Model,
+		Version:         sensorType.Version,
+		IsActive:        sensorType.IsActive,
+		IngestionPolicy: string(sensorType.IngestionPolicy),
+		CreatedAt:       sensorType.CreatedAt,
+		UpdatedAt:       sensorType.UpdatedAt,
 	}, nil
 }
 
@@ -71,15 +81,16 @@ func (s *SensorTypeService) GetSensorType(ctx context.Context, id uuid.UUID) (*d
 	}
 
 	return &dto.SensorTypeDTO{
-		ID:           sensorType.ID,
-		Name:         sensorType.Name,
-		Description:  sensorType.Description,
-		Manufacturer: sensorType.Manufacturer,
-		Model:        sensorType.Model,
-		Version:      sensorType.Version,
-		IsActive:     sensorType.IsActive,
-		CreatedAt:    sensorType.CreatedAt,
-		UpdatedAt:    sensorType.UpdatedAt,
+		ID:              sensorType.ID,
+		Name:            sensorType.Name,
+		Description:     sensorType.Description,
+		Manufacturer:    sensorType.Manufacturer,
+		Model:           sensorType.Model,
+		Version:         sensorType.Version,
+		IsActive:        sensorType.IsActive,
+		IngestionPolicy: string(sensorType.IngestionPolicy),
+		CreatedAt:       sensorType.CreatedAt,
+		UpdatedAt:       sensorType.UpdatedAt,
 	}, nil
 }
 
@@ -94,15 +105,16 @@ func (s *SensorTypeService) ListSensorTypes(ctx context.Context, page, pageSize
 	var dtos []*dto.SensorTypeDTO
 	for _, st := range sensorTypes {
 		dtos = append(dtos, &dto.SensorTypeDTO{
-			ID:           st.ID,
-			Name:         st.Name,
-			Description:  st.Description,
-			Manufacturer: st.Manufacturer,
-			Model:        st.Model,
-			Version:      st.Version,
-			IsActive:     st.IsActive,
-			CreatedAt:    st.CreatedAt,
-			UpdatedAt:    st.UpdatedAt,
+			ID:              st.ID,
+			Name:            st.Name,
+			Description:     st.Description,
+			Manufacturer:    st.Manufacturer,
+			Model:           st.Model,
+			Version:         st.Version,
+			IsActive:        st.IsActive,
+			IngestionPolicy: string(st.IngestionPolicy),
+			CreatedAt:       st.CreatedAt,
+			UpdatedAt:       st.UpdatedAt,
 		})
 	}
 
@@ -137,6 +149,13 @@ func (s *SensorTypeService) UpdateSensorType(ctx context.Context, id uuid.UUID,
 		existingSensorType.Version = req.Version
 	}
 	existingSensorType.IsActive = req.IsActive
+	if req.IngestionPolicy != "" {
+		policy, err := parseIngestionPolicy(req.IngestionPolicy)
+		if err != nil {
+			return nil, err
+		}
+		existingSensorType.IngestionPolicy = policy
+	}
 	now := time.Now()
 	existingSensorType.UpdatedAt = &now
 
@@ -148,15 +167,16 @@ func (s *SensorTypeService) UpdateSensorType(ctx context.Context, id uuid.UUID,
 
 	// Convert to DTO
 	return &dto.SensorTypeDTO{
-		ID:           existingSensorType.ID,
-		Name:         existingSensorType.Name,
-		Description:  existingSensorType.Description,
-		Manufacturer: existingSensorType.Manufacturer,
-		Model:        existingSensorType.Model,
-		Version:      existingSensorType.Version,
-		IsActive:     existingSensorType.IsActive,
-		CreatedAt:    existingSensorType.CreatedAt,
-		UpdatedAt:    existingSensorType.UpdatedAt,
+		ID:              existingSensorType.ID,
+		Name:            existingSensorType.Name,
+		Description:     existingSensorType.Description,
+		Manufacturer:    existingSensorType.Manufacturer,
+		Model:           existingSensorType.Model,
+		Version:         existingSensorType.Version,
+		IsActive:        existingSensorType.IsActive,
+		IngestionPolicy: string(existingSensorType.IngestionPolicy),
+		CreatedAt:       existingSensorType.CreatedAt,
+		UpdatedAt:       existingSensorType.UpdatedAt,
 	}, nil
 }
 
@@ -176,15 +196,16 @@ func (s *SensorTypeService) GetActiveSensorTypes(ctx context.Context) ([]*dto.Se
 	var dtos []*dto.SensorTypeDTO
 	for _, st := range sensorTypes {
 		dtos = append(dtos, &dto.SensorTypeDTO{
-			ID:           st.ID,
-			Name:         st.Name,
-			Description:  st.Description,
-			Manufacturer: st.Manufacturer,
-			Model:        st.Model,
-			Version:      st.Version,
-			IsActive:     st.IsActive,
-			CreatedAt:    st.CreatedAt,
-			UpdatedAt:    st.UpdatedAt,
+			ID:              st.ID,
+			Name:            st.Name,
+			Description:     st.Description,
+			Manufacturer:    st.Manufacturer,
+			Model:           st.Model,
+			Version:         st.Version,
+			IsActive:        st.IsActive,
+			IngestionPolicy: string(st.IngestionPolicy),
+			CreatedAt:       st.CreatedAt,
+			UpdatedAt:       st.UpdatedAt,
 		})
 	}
 
@@ -233,6 +254,14 @@ func (s *SensorTypeService) UpdateSensorTypePartial(ctx context.Context, id uuid
 	if isActive, exists := updateRequest["is_active"]; exists && isActive != nil {
 		existingSensorType.IsActive = isActive.(bool)
 	}
+	if value, exists := updateRequest["ingestion_policy"]; exists && value != nil {
+		text, _ := value.(string)
+		policy, err := parseIngestionPolicy(text)
+		if err != nil {
+			return nil, err
+		}
+		existingSensorType.IngestionPolicy = policy
+	}
 
 	// Update timestamp
 	now := time.Now()
@@ -246,14 +275,28 @@ func (s *SensorTypeService) UpdateSensorTypePartial(ctx context.Context, id uuid
 
 	// Convert to DTO
 	return &dto.SensorTypeDTO{
-		ID:           existingSensorType.ID,
-		Name:         existingSensorType.Name,
-		Description:  existingSensorType.Description,
-		Manufacturer: existingSensorType.Manufacturer,
-		Model:        existingSensorType.Model,
-		Version:      existingSensorType.Version,
-		IsActive:     existingSensorType.IsActive,
-		CreatedAt:    existingSensorType.CreatedAt,
-		UpdatedAt:    existingSensorType.UpdatedAt,
+		ID:              existingSensorType.ID,
+		Name:            existingSensorType.Name,
+		Description:     existingSensorType.Description,
+		Manufacturer:    existingSensorType.Manufacturer,
+		Model:           existingSensorType.Model,
+		Version:         existingSensorType.Version,
+		IsActive:        existingSensorType.IsActive,
+		IngestionPolicy: string(existingSensorType.IngestionPolicy),
+		CreatedAt:       existingSensorType.CreatedAt,
+		UpdatedAt:       existingSensorType.UpdatedAt,
 	}, nil
 }
+
+// parseIngestionPolicy parses the ingestion policy of a sensor type request, defaulting
+// to warn
+func parseIngestionPolicy(value string) (entity.IngestionPolicy, error) {
+	if value == "" {
+		return entity.DefaultIngestionPolicy, nil
+	}
+	policy := entity.IngestionPolicy(value)
+	if !policy.IsValid() {
+		return "", common.NewValidationError(fmt.Sprintf("invalid ingestion policy %s, must be strict, warn or permissive", value), nil)
+	}
+	return policy, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"fmt"
+	"strings"
+)
+
+// UnitTargets are the units requested for converting readings on read, keyed by the
+// quantity they measure. Values in units of another quantity are left as stored.
+type UnitTargets map[string]string
+
+// ParseUnitTargets parses the unit query parameter. Units may be repeated or comma
+// separated, e.g. unit=°F,psi. There can be one unit per quantity.
+func ParseUnitTargets(values []string) (UnitTargets, error) {
+	targets := make(UnitTargets)
+	for _, value := range values {
+		for _, symbol := range strings.Split(value, ",") {
+			symbol = strings.TrimSpace(symbol)
+			if symbol == "" {
+				continue
+			}
+			unit, ok := entity.LookupUnit(symbol)
+			if !ok {
+				return nil, common.NewValidationError(fmt.Sprintf("unknown unit %s", symbol), nil)
+			}
+			if existing, ok := targets[unit.Quantity]; ok && existing != unit.Symbol {
+				return nil, common.NewValidationError(fmt.Sprintf("units %s and %s both measure %s", existing, unit.Symbol, unit.Quantity), nil)
+			}
+			targets[unit.Quantity] = unit.Symbol
+		}
+	}
+	return targets, nil
+}
+
+// Target returns the requested unit for values stored in unit, or "" when none was
+// requested for its quantity
+func (t UnitTargets) Target(unit string) string {
+	found, ok := entity.LookupUnit(unit)
+	if !ok {
+		return ""
+	}
+	return t[found.Quantity]
+}
+
+// Apply converts the numeric measurements of a reading response to the requested units.
+// The stored value is reported as the original one, unless the reading already has an
+// original value from ingestion.
+func (t UnitTargets) Apply(reading *dto.IoTSensorReadingResponse) {
+	if len(t) == 0 || reading == nil {
+		return
+	}
+
+	for name, measurement := range reading.MeasurementData {
+		value, ok := measurement.Value.(float64)
+		if !ok {
+			continue
+		}
+		target := t.Target(measurement.Unit)
+		if target == "" || target == entity.NormalizeUnit(measurement.Unit) {
+			continue
+		}
+		converted, err := entity.ConvertUnit(value, measurement.Unit, target)
+		if err != nil {
+			continue
+		}
+
+		if measurement.OriginalValue == nil {
+			measurement.OriginalValue = &value
+			measurement.OriginalUnit = measurement.Unit
+		}
+		measurement.Value = converted
+		measurement.Unit = target
+		reading.MeasurementData[name] = measurement
+	}
+}
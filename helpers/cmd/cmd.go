@@ -1,9 +1,13 @@
 package main
 
 import (
+	"be-lecsens/asset_management/data-layer/archivestore"
 	"be-lecsens/asset_management/data-layer/config"
+	"be-lecsens/asset_management/data-layer/entity"
 	"be-lecsens/asset_management/data-layer/migration"
 	"be-lecsens/asset_management/data-layer/migration/seeder"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/domain-layer/service"
 	"context"
 	"database/sql"
 	"flag"
@@ -13,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
@@ -32,13 +37,17 @@ func main() {
 
 	// Parse command line flags
 	var (
-		action      = flag.String("action", "", "Action to perform: drop-table, truncate-table, drop-all, migrate, seed, seed-all")
+		action      = flag.String("action", "", "Action to perform: drop-table, truncate-table, drop-all, migrate, seed, seed-all, partition-readings, partitions-list, partitions-create, retention-list, retention-set, retention-delete, retention-apply, archive-run, archive-list, archive-restore")
 		tableName   = flag.String("table", "", "Table name (for drop-table, truncate-table)")
 		csvPath     = flag.String("csv", "", "Path to CSV file (for seed)")
 		seederType  = flag.String("seeder", "", "Seeder type: location, asset-type, sensor-type, measurement-type, asset, asset-sensor, measurement-field, threshold, reading, alert, sensor-status, sensor-logs, or all")
 		force       = flag.Bool("force", false, "Force action without confirmation")
 		days        = flag.Int("days", 7, "Number of days of historical data to generate (for reading seeder)")
 		forceReseed = flag.Bool("force-reseed", false, "Force re-seed even if data exists")
+		tenantID    = flag.String("tenant", "", "Tenant ID of the retention policy, empty for the default policy (for retention-set, retention-delete); tenant of the archives (for archive-list)")
+		keepDays    = flag.Int("retention-days", 0, "Days readings are kept (for retention-set)")
+		keepAction  = flag.String("retention-action", "delete", "What happens to expired readings: delete or archive (for retention-set)")
+		archiveID   = flag.String("archive", "", "Archive ID (for archive-restore)")
 	)
 	flag.Parse()
 
@@ -90,6 +99,26 @@ func main() {
 		runSpecificSeeder(db, validator, *seederType, *csvPath, *days, *forceReseed)
 	case "seed-all":
 		runAllSeeders(db, validator, *days, *forceReseed)
+	case "partition-readings":
+		partitionReadings(db, *force)
+	case "partitions-list":
+		listReadingPartitions(newReadingRetentionService(db, cfg))
+	case "partitions-create":
+		createReadingPartitions(newReadingRetentionService(db, cfg))
+	case "retention-list":
+		listRetentionPolicies(newReadingRetentionService(db, cfg))
+	case "retention-set":
+		setRetentionPolicy(newReadingRetentionService(db, cfg), *tenantID, *keepDays, *keepAction)
+	case "retention-delete":
+		deleteRetentionPolicy(newReadingRetentionService(db, cfg), *tenantID)
+	case "retention-apply":
+		applyRetention(newReadingRetentionService(db, cfg), *force)
+	case "archive-run":
+		runReadingArchive(newReadingArchiveService(db, cfg))
+	case "archive-list":
+		listReadingArchives(newReadingArchiveService(db, cfg), *tenantID)
+	case "archive-restore":
+		restoreReadingArchive(newReadingArchiveService(db, cfg), *archiveID, *force)
 	default:
 		log.Fatalf("Unknown action: %s", *action)
 	}
@@ -106,11 +135,25 @@ func printUsage() {
 	fmt.Println("  drop-all       Drop all tables")
 	fmt.Println("  migrate        Run all migrations")
 	fmt.Println("  seed           Run location seeder")
+	fmt.Println("  partition-readings  Convert an unpartitioned iot_sensor_readings table into monthly partitions")
+	fmt.Println("  partitions-list     List the iot_sensor_readings partitions")
+	fmt.Println("  partitions-create   Create the monthly partitions ahead of time")
+	fmt.Println("  retention-list      List the reading retention policies")
+	fmt.Println("  retention-set       Set the retention policy of a tenant, or the default policy")
+	fmt.Println("  retention-delete    Delete the retention policy of a tenant, or the default policy")
+	fmt.Println("  retention-apply     Create the partitions ahead and apply the retention policies now")
+	fmt.Println("  archive-run         Export the completed months of archived readings to archive files now")
+	fmt.Println("  archive-list        List the reading archive manifest")
+	fmt.Println("  archive-restore     Reload a reading archive back into iot_sensor_readings")
 	fmt.Println("")
 	fmt.Println("Options:")
 	fmt.Println("  -table=<name>  Table name (required for drop-table, truncate-table)")
 	fmt.Println("  -csv=<path>    CSV file path (for seed action)")
 	fmt.Println("  -force         Skip confirmation prompts")
+	fmt.Println("  -tenant=<id>   Tenant ID of the retention policy; omit for the default policy")
+	fmt.Println("  -retention-days=<n>      Days readings are kept (for retention-set)")
+	fmt.Println("  -retention-action=<a>    delete or archive expired readings (for retention-set)")
+	fmt.Println("  -archive=<id>  Archive ID (required for archive-restore)")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  go run helpers/cmd/cmd.go -action=drop-table -table=assets")
@@ -118,6 +161,8 @@ func printUsage() {
 	fmt.Println("  go run helpers/cmd/cmd.go -action=drop-all -force")
 	fmt.Println("  go run helpers/cmd/cmd.go -action=migrate")
 	fmt.Println("  go run helpers/cmd/cmd.go -action=seed -csv=data-layer/migration/seeder/kota_kab.csv")
+	fmt.Println("  go run helpers/cmd/cmd.go -action=retention-set -tenant=<id> -retention-days=90 -retention-action=archive")
+	fmt.Println("  go run helpers/cmd/cmd.go -action=archive-restore -archive=<id>")
 }
 
 func dropTable(db *sql.DB, tableName string, force bool) {
@@ -458,3 +503,178 @@ func runSensorLogsSeeder(db *sql.DB) error {
 	sensorLogsSeeder := seeder.NewSensorLogsSeeder(db)
 	return sensorLogsSeeder.Seed()
 }
+
+// newReadingRetentionService creates the reading retention service the retention actions use
+func newReadingRetentionService(db *sql.DB, cfg *config.Config) *service.ReadingRetentionService {
+	return service.NewReadingRetentionService(repository.NewReadingRetentionRepository(db),
+		cfg.Retention.PartitionsAhead, cfg.Retention.DeleteBatchSize, time.Duration(cfg.Retention.MessageTTLHours)*time.Hour)
+}
+
+// parseTenantFlag parses the -tenant flag; an empty flag selects the default policy
+func parseTenantFlag(tenantID string) *uuid.UUID {
+	if tenantID == "" {
+		return nil
+	}
+	id, err := uuid.Parse(tenantID)
+	if err != nil {
+		log.Fatalf("Invalid tenant ID %s: %v", tenantID, err)
+	}
+	return &id
+}
+
+func partitionReadings(db *sql.DB, force bool) {
+	if !force {
+		fmt.Print("Partitioning copies every reading into a new table and locks iot_sensor_readings until done. Stop ingestion first. Continue? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			log.Println("Operation cancelled")
+			return
+		}
+	}
+
+	if err := migration.PartitionIoTSensorReadingTable(db); err != nil {
+		log.Fatalf("Failed to partition iot_sensor_readings: %v", err)
+	}
+}
+
+func listReadingPartitions(retentionService *service.ReadingRetentionService) {
+	partitions, err := retentionService.ListPartitions(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to list reading partitions: %v", err)
+	}
+
+	for _, partition := range partitions {
+		if partition.IsDefault {
+			fmt.Printf("%-32s default\n", partition.Name)
+			continue
+		}
+		fmt.Printf("%-32s %s - %s\n", partition.Name, partition.From.Format("2006-01-02"), partition.To.Format("2006-01-02"))
+	}
+}
+
+func createReadingPartitions(retentionService *service.ReadingRetentionService) {
+	created, err := retentionService.EnsurePartitions(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to create reading partitions: %v", err)
+	}
+	log.Printf("%d reading partitions created", len(created))
+}
+
+func listRetentionPolicies(retentionService *service.ReadingRetentionService) {
+	policies, err := retentionService.ListPolicies(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to list retention policies: %v", err)
+	}
+	if len(policies) == 0 {
+		log.Println("No retention policies; readings are kept forever")
+		return
+	}
+
+	for _, policy := range policies {
+		tenant := "default"
+		if policy.TenantID != nil {
+			tenant = policy.TenantID.String()
+		}
+		fmt.Printf("%-36s %5d days  %s\n", tenant, policy.RetentionDays, policy.Action)
+	}
+}
+
+func setRetentionPolicy(retentionService *service.ReadingRetentionService, tenantID string, retentionDays int, action string) {
+	policy, err := retentionService.SetPolicy(context.Background(), parseTenantFlag(tenantID), retentionDays, entity.RetentionAction(action))
+	if err != nil {
+		log.Fatalf("Failed to set retention policy: %v", err)
+	}
+	log.Printf("Retention policy set: readings kept %d days, then %sd", policy.RetentionDays, policy.Action)
+}
+
+func deleteRetentionPolicy(retentionService *service.ReadingRetentionService, tenantID string) {
+	if err := retentionService.DeletePolicy(context.Background(), parseTenantFlag(tenantID)); err != nil {
+		log.Fatalf("Failed to delete retention policy: %v", err)
+	}
+	log.Println("Retention policy deleted")
+}
+
+func applyRetention(retentionService *service.ReadingRetentionService, force bool) {
+	if !force {
+		fmt.Print("Are you sure you want to delete or archive every expired reading now? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			log.Println("Operation cancelled")
+			return
+		}
+	}
+
+	result, err := retentionService.Run(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to apply retention policies: %v", err)
+	}
+	log.Printf("Retention applied: %d partitions created, %d partitions dropped, %d readings deleted, %d readings archived, %d message IDs deleted",
+		len(result.PartitionsCreated), len(result.PartitionsDropped), result.RowsDeleted, result.RowsArchived, result.MessagesDeleted)
+}
+
+// newReadingArchiveService creates the reading archive service the archive actions use
+func newReadingArchiveService(db *sql.DB, cfg *config.Config) *service.ReadingArchiveService {
+	storage, err := archivestore.New(cfg.Archive.Storage, cfg.Archive.LocalDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize archive storage: %v", err)
+	}
+	return service.NewReadingArchiveService(repository.NewReadingArchiveRepository(db),
+		repository.NewReadingRetentionRepository(db), storage)
+}
+
+func runReadingArchive(archiveService *service.ReadingArchiveService) {
+	archives, err := archiveService.Run(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to archive readings: %v", err)
+	}
+	log.Printf("%d reading archives written", len(archives))
+}
+
+func listReadingArchives(archiveService *service.ReadingArchiveService, tenantID string) {
+	archives, err := archiveService.ListArchives(context.Background(), parseTenantFlag(tenantID))
+	if err != nil {
+		log.Fatalf("Failed to list reading archives: %v", err)
+	}
+	if len(archives) == 0 {
+		log.Println("No reading archives")
+		return
+	}
+
+	for _, archive := range archives {
+		restored := ""
+		if archive.RestoredAt != nil {
+			restored = "restored " + archive.RestoredAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%s  %s  %8d readings  %10d bytes  %s:%s  %s\n", archive.ID, archive.Month.Format("2006-01"),
+			archive.ReadingCount, archive.SizeBytes, archive.Storage, archive.ObjectKey, restored)
+	}
+}
+
+func restoreReadingArchive(archiveService *service.ReadingArchiveService, archiveID string, force bool) {
+	if archiveID == "" {
+		log.Fatal("Archive ID is required for archive-restore action. Use -archive flag")
+	}
+	id, err := uuid.Parse(archiveID)
+	if err != nil {
+		log.Fatalf("Invalid archive ID %s: %v", archiveID, err)
+	}
+
+	if !force {
+		fmt.Println("Restored readings older than the tenant's retention policy keeps are expired again by the next retention run; extend the policy first to keep them.")
+		fmt.Printf("Are you sure you want to reload archive '%s' into iot_sensor_readings? (y/N): ", archiveID)
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			log.Println("Operation cancelled")
+			return
+		}
+	}
+
+	restored, skipped, err := archiveService.Restore(context.Background(), id)
+	if err != nil {
+		log.Fatalf("Failed to restore reading archive: %v", err)
+	}
+	log.Printf("Archive restored: %d readings restored, %d skipped", restored, skipped)
+}
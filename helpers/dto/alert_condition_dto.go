@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+
+	"github.com/google/uuid"
+)
+
+// AlertConditionRequest represents the request for creating or updating an alert
+// condition. Unqualified field names in the expression refer to asset_sensor_id; fields
+// of other sensors of the asset are written alias.field with the alias listed in sensors.
+type AlertConditionRequest struct {
+	AssetID              uuid.UUID                   `json:"asset_id" binding:"required"`
+	AssetSensorID        *uuid.UUID                  `json:"asset_sensor_id,omitempty"`
+	Sensors              map[string]uuid.UUID        `json:"sensors,omitempty"`
+	Name                 string                      `json:"name" binding:"required"`
+	Description          *string                     `json:"description,omitempty"`
+	Expression           string                      `json:"expression" binding:"required"` // e.g. "temperature > 30 AND humidity < 20"
+	Severity             entity.ThresholdSeverity    `json:"severity,omitempty"`            // warning (default) or critical
+	AlertRules           *entity.ThresholdAlertRules `json:"alert_rules,omitempty"`         // Hysteresis is not supported
+	MaxReadingAgeSeconds *int                        `json:"max_reading_age_seconds,omitempty"`
+	IsActive             *bool                       `json:"is_active,omitempty"`
+}
+
+// AlertConditionListResponse represents the paginated response for listing alert conditions
+type AlertConditionListResponse struct {
+	Data       []*entity.AlertCondition `json:"data"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
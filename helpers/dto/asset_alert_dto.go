@@ -9,23 +9,40 @@ import (
 
 // AssetAlertResponse represents the response structure for asset alerts
 type AssetAlertResponse struct {
-	ID                   uuid.UUID                `json:"id"`
-	TenantID             uuid.UUID                `json:"tenant_id"`
-	AssetID              uuid.UUID                `json:"asset_id"`
-	AssetSensorID        uuid.UUID                `json:"asset_sensor_id"`
-	ThresholdID          uuid.UUID                `json:"threshold_id"`
-	MeasurementFieldName string                   `json:"measurement_field_name"`
-	AlertTime            time.Time                `json:"alert_time"`
-	ResolvedTime         *time.Time               `json:"resolved_time,omitempty"`
-	Severity             entity.ThresholdSeverity `json:"severity"`
-	TriggerValue         float64                  `json:"trigger_value"`
-	ThresholdMinValue    *float64                 `json:"threshold_min_value,omitempty"`
-	ThresholdMaxValue    *float64                 `json:"threshold_max_value,omitempty"`
-	AlertMessage         string                   `json:"alert_message"`
-	AlertType            string                   `json:"alert_type"`
-	IsResolved           bool                     `json:"is_resolved"`
-	CreatedAt            time.Time                `json:"created_at"`
-	UpdatedAt            *time.Time               `json:"updated_at,omitempty"`
+	ID                   uuid.UUID                   `json:"id"`
+	TenantID             uuid.UUID                   `json:"tenant_id"`
+	AssetID              uuid.UUID                   `json:"asset_id"`
+	AssetSensorID        uuid.UUID                   `json:"asset_sensor_id"`
+	ThresholdID          uuid.UUID                   `json:"threshold_id"`
+	ConditionID          *uuid.UUID                  `json:"condition_id,omitempty"`
+	MeasurementFieldName string                      `json:"measurement_field_name"`
+	AlertTime            time.Time                   `json:"alert_time"`
+	ResolvedTime         *time.Time                  `json:"resolved_time,omitempty"`
+	Severity             entity.ThresholdSeverity    `json:"severity"`
+	TriggerValue         float64                     `json:"trigger_value"`
+	ThresholdMinValue    *float64                    `json:"threshold_min_value,omitempty"`
+	ThresholdMaxValue    *float64                    `json:"threshold_max_value,omitempty"`
+	AlertMessage         string                      `json:"alert_message"`
+	AlertType            string                      `json:"alert_type"`
+	IsResolved           bool                        `json:"is_resolved"`
+	LastTriggerValue     float64                     `json:"last_trigger_value"`
+	PeakTriggerValue     float64                     `json:"peak_trigger_value"`
+	OccurrenceCount      int                         `json:"occurrence_count"`
+	LastTriggeredAt      *time.Time                  `json:"last_triggered_at,omitempty"`
+	State                entity.AlertState           `json:"state"`
+	AcknowledgedAt       *time.Time                  `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy       *uuid.UUID                  `json:"acknowledged_by,omitempty"`
+	AssignedTo           *uuid.UUID                  `json:"assigned_to,omitempty"`
+	AssignedAt           *time.Time                  `json:"assigned_at,omitempty"`
+	ResolvedBy           *uuid.UUID                  `json:"resolved_by,omitempty"`
+	ResolutionCode       *entity.AlertResolutionCode `json:"resolution_code,omitempty"`
+	ResolutionNote       *string                     `json:"resolution_note,omitempty"`
+	MaintenanceWindowID  *uuid.UUID                  `json:"maintenance_window_id,omitempty"`
+	CreatedAt            time.Time                   `json:"created_at"`
+	UpdatedAt            *time.Time                  `json:"updated_at,omitempty"`
+
+	// Escalation history, only included in the alert detail
+	Escalations []*entity.AssetAlertEscalation `json:"escalations,omitempty"`
 }
 
 // AssetAlertListResponse represents the paginated response for listing asset alerts
@@ -54,11 +71,48 @@ type AssetAlertStatisticsResponse struct {
 	Alerts24h      int `json:"alerts_24h"`
 	Alerts7d       int `json:"alerts_7d"`
 	TotalTenants   int `json:"total_tenants,omitempty"` // Only for global statistics
+
+	AcknowledgedAlerts int      `json:"acknowledged_alerts"`    // Active alerts that were acknowledged
+	UnassignedAlerts   int      `json:"unassigned_alerts"`      // Active alerts without an assignee
+	MTTASeconds        *float64 `json:"mtta_seconds,omitempty"` // Mean time to acknowledge
+	MTTRSeconds        *float64 `json:"mttr_seconds,omitempty"` // Mean time to resolve
+}
+
+// ResolveAlertRequest represents the optional body for resolving an alert.
+// The resolution code defaults to "other".
+type ResolveAlertRequest struct {
+	ResolutionCode entity.AlertResolutionCode `json:"resolution_code,omitempty"`
+	Note           *string                    `json:"note,omitempty"`
+}
+
+// AcknowledgeAlertRequest represents the optional body for acknowledging an alert
+type AcknowledgeAlertRequest struct {
+	Comment *string `json:"comment,omitempty"`
+}
+
+// AssignAlertRequest represents the request for assigning an alert.
+// Omit assigned_to (or send null) to clear the assignment.
+type AssignAlertRequest struct {
+	AssignedTo *uuid.UUID `json:"assigned_to"`
+	Comment    *string    `json:"comment,omitempty"`
+}
+
+// AlertCommentRequest represents the request for commenting on an alert
+type AlertCommentRequest struct {
+	Comment string `json:"comment" binding:"required"`
+}
+
+// AssetAlertTimelineResponse represents the timeline of an alert, oldest first
+type AssetAlertTimelineResponse struct {
+	AlertID uuid.UUID                 `json:"alert_id"`
+	Events  []*entity.AssetAlertEvent `json:"events"`
 }
 
 // ResolveMultipleAlertsRequest represents the request for resolving multiple alerts
 type ResolveMultipleAlertsRequest struct {
-	AlertIDs []uuid.UUID `json:"alert_ids" binding:"required"`
+	AlertIDs       []uuid.UUID                `json:"alert_ids" binding:"required"`
+	ResolutionCode entity.AlertResolutionCode `json:"resolution_code,omitempty"`
+	Note           *string                    `json:"note,omitempty"`
 }
 
 // ResolveMultipleAlertsResponse represents the response for resolving multiple alerts
@@ -105,6 +105,7 @@ type MeasurementFieldInfo struct {
 
 // UpdateSensorReadingRequest represents the request to update sensor readings
 type UpdateSensorReadingRequest struct {
-	Value    float64                `json:"value" binding:"required"`
-	Readings map[string]interface{} `json:"readings,omitempty"`
+	Value       float64                `json:"value" binding:"required"`
+	Readings    map[string]interface{} `json:"readings,omitempty"`
+	ReadingTime *time.Time             `json:"reading_time,omitempty"` // Defaults to now; older readings don't replace the last reading
 }
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+
+	"github.com/google/uuid"
+)
+
+// CSVMappingProfileRequest represents the request for creating or updating a CSV mapping
+// profile. The delimiter defaults to a comma, timestamps to RFC3339 and the time zone to UTC.
+type CSVMappingProfileRequest struct {
+	SensorTypeID      uuid.UUID                 `json:"sensor_type_id" binding:"required"`
+	Name              string                    `json:"name" binding:"required"`
+	Description       *string                   `json:"description,omitempty"`
+	Delimiter         string                    `json:"delimiter,omitempty"`
+	TimestampColumn   string                    `json:"timestamp_column" binding:"required"`
+	TimestampFormat   string                    `json:"timestamp_format,omitempty"`
+	Timezone          string                    `json:"timezone,omitempty"`
+	AssetSensorColumn *string                   `json:"asset_sensor_column,omitempty"`
+	Columns           []entity.CSVColumnMapping `json:"columns" binding:"required"`
+}
+
+// CSVMappingProfileListResponse represents the paginated response for listing CSV mapping profiles
+type CSVMappingProfileListResponse struct {
+	Data       []*entity.CSVMappingProfile `json:"data"`
+	Pagination PaginationInfo              `json:"pagination"`
+}
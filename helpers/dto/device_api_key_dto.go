@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateDeviceAPIKeyRequest represents the request for issuing a device API key.
+// Leave asset_sensor_id empty to issue a gateway key that may write for any
+// sensor in the tenant.
+type CreateDeviceAPIKeyRequest struct {
+	Name          string     `json:"name" binding:"required"`
+	AssetSensorID *uuid.UUID `json:"asset_sensor_id,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+// DeviceAPIKeyResponse represents a device API key without its secret
+type DeviceAPIKeyResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	AssetSensorID *uuid.UUID `json:"asset_sensor_id,omitempty"`
+	Name          string     `json:"name"`
+	KeyPrefix     string     `json:"key_prefix"`
+	IsGatewayKey  bool       `json:"is_gateway_key"`
+	IsActive      bool       `json:"is_active"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	RotatedAt     *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// DeviceAPIKeySecretResponse is returned once when a key is issued or rotated
+type DeviceAPIKeySecretResponse struct {
+	DeviceAPIKeyResponse
+	APIKey string `json:"api_key"` // Plaintext key, not retrievable afterwards
+}
+
+// DeviceAPIKeyListResponse represents the paginated response for listing device API keys
+type DeviceAPIKeyListResponse struct {
+	Data       []DeviceAPIKeyResponse `json:"data"`
+	Pagination PaginationInfo         `json:"pagination"`
+}
+
+// DeviceAPIKeyFromEntity converts entity.DeviceAPIKey to DeviceAPIKeyResponse
+func DeviceAPIKeyFromEntity(key *entity.DeviceAPIKey) DeviceAPIKeyResponse {
+	return DeviceAPIKeyResponse{
+		ID:            key.ID,
+		TenantID:      key.TenantID,
+		AssetSensorID: key.AssetSensorID,
+		Name:          key.Name,
+		KeyPrefix:     key.KeyPrefix,
+		IsGatewayKey:  key.IsGatewayKey(),
+		IsActive:      key.IsActive,
+		ExpiresAt:     key.ExpiresAt,
+		LastUsedAt:    key.LastUsedAt,
+		RotatedAt:     key.RotatedAt,
+		RevokedAt:     key.RevokedAt,
+		CreatedAt:     key.CreatedAt,
+		UpdatedAt:     key.UpdatedAt,
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+)
+
+// EscalationPolicyRequest represents the request for creating or updating an escalation
+// policy. Empty severities default to critical alerts only.
+type EscalationPolicyRequest struct {
+	Name        string                     `json:"name" binding:"required"`
+	Description *string                    `json:"description,omitempty"`
+	Severities  []entity.ThresholdSeverity `json:"severities,omitempty"`
+	Steps       []entity.EscalationStep    `json:"steps" binding:"required"`
+	IsActive    *bool                      `json:"is_active,omitempty"`
+}
+
+// EscalationPolicyListResponse represents the paginated response for listing escalation policies
+type EscalationPolicyListResponse struct {
+	Data       []*entity.EscalationPolicy `json:"data"`
+	Pagination PaginationInfo             `json:"pagination"`
+}
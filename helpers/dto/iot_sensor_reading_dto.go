@@ -44,11 +44,18 @@ type IoTSensorReadingResponse struct {
 	LocationID      *uuid.UUID                  `json:"location_id,omitempty"`
 	Location        string                      `json:"location"`
 	ReadingTime     time.Time                   `json:"reading_time"`
+	IsLate          bool                        `json:"is_late,omitempty"`         // Arrived after a newer reading of the asset sensor
+	DeviceTime      *time.Time                  `json:"device_time,omitempty"`     // Timestamp as sent, when the reading is stored at another time
+	ClockOffsetMs   *int64                      `json:"clock_offset_ms,omitempty"` // Clock offset applied to the device time
+	TimeAnomaly     string                      `json:"time_anomaly,omitempty"`    // future or far_past against the receive time
 	CreatedAt       time.Time                   `json:"created_at"`
 	UpdatedAt       *time.Time                  `json:"updated_at,omitempty"`
 	MeasurementData map[string]MeasurementValue `json:"measurement_data,omitempty"`
 	Message         string                      `json:"message,omitempty"`
 	Warnings        []string                    `json:"warnings,omitempty"`
+	ReadingIDs      []uuid.UUID                 `json:"reading_ids,omitempty"`   // All readings stored for the request
+	Duplicate       bool                        `json:"duplicate,omitempty"`     // The message ID was already stored; nothing new was inserted
+	QuarantineID    *uuid.UUID                  `json:"quarantine_id,omitempty"` // Quarantined reading holding the measurements that weren't stored
 }
 
 // IoTSensorReadingWithDetailsResponse represents the response with detailed related information
@@ -123,12 +130,21 @@ type GetReadingsInTimeRangeRequest struct {
 
 // GetAggregatedDataRequest represents request for aggregated analytics data
 type GetAggregatedDataRequest struct {
+	TenantID      *uuid.UUID `json:"-"` // Tenant whose readings are aggregated, from the request context
 	AssetSensorID *uuid.UUID `json:"asset_sensor_id,omitempty"`
+	AssetID       *uuid.UUID `json:"asset_id,omitempty"`
+	AssetTypeID   *uuid.UUID `json:"asset_type_id,omitempty"`
+	LocationID    *uuid.UUID `json:"location_id,omitempty"`
 	SensorTypeID  *uuid.UUID `json:"sensor_type_id,omitempty"`
 	FromTime      time.Time  `json:"from_time" binding:"required" validate:"required"`
 	ToTime        time.Time  `json:"to_time" binding:"required" validate:"required"`
-	Interval      string     `json:"interval,omitempty"`     // hour, day, week, month - defaults to "hour"
+	Interval      string     `json:"interval,omitempty"`     // hour, day, week, month or a length such as 15m - defaults to "hour"
+	Timezone      string     `json:"timezone,omitempty"`     // IANA time zone buckets are aligned to - defaults to UTC
+	Fill          string     `json:"fill,omitempty"`         // none, null, previous or linear - defaults to "none"
 	AggregateBy   []string   `json:"aggregate_by,omitempty"` // Fields to aggregate from measurement_data
+	Units         []string   `json:"units,omitempty"`        // Units to convert to, at most one per quantity
+	GroupBy       string     `json:"group_by,omitempty"`     // asset_sensor, asset, asset_type, location, sensor_type or measurement_field
+	Percentiles   []float64  `json:"percentiles,omitempty"`  // Percentiles to compute, between 0 and 100
 }
 
 // AggregatedDataPoint represents a single aggregated data point
@@ -139,7 +155,22 @@ type AggregatedDataPoint struct {
 	Sums     map[string]float64     `json:"sums,omitempty"`
 	Mins     map[string]float64     `json:"mins,omitempty"`
 	Maxs     map[string]float64     `json:"maxs,omitempty"`
-	Data     map[string]interface{} `json:"data,omitempty"` // Additional aggregated data
+	Units    map[string]string      `json:"units,omitempty"` // Unit of each measurement's values
+	Data     map[string]interface{} `json:"data,omitempty"`  // Additional aggregated data
+
+	// Percentiles of each measurement, keyed by "p" and the percentile, e.g. "p95"
+	Percentiles map[string]map[string]float64 `json:"percentiles,omitempty"`
+
+	// Measurements whose values were filled in from other buckets rather than aggregated
+	Filled []string `json:"filled,omitempty"`
+}
+
+// AggregatedGroup represents the aggregated data points of one group of a grouped query
+type AggregatedGroup struct {
+	ID         string                `json:"id"` // Group's ID, or the field name when grouped by measurement field
+	Name       string                `json:"name,omitempty"`
+	TotalCount int64                 `json:"total_count"`
+	DataPoints []AggregatedDataPoint `json:"data_points"`
 }
 
 // GetAggregatedDataResponse represents response for aggregated analytics data
@@ -149,7 +180,12 @@ type GetAggregatedDataResponse struct {
 	FromTime    time.Time             `json:"from_time"`
 	ToTime      time.Time             `json:"to_time"`
 	Interval    string                `json:"interval"`
+	Timezone    string                `json:"timezone"`
+	Fill        string                `json:"fill"`
 	AggregateBy []string              `json:"aggregate_by"`
+	GroupBy     string                `json:"group_by,omitempty"`
+	Groups      []AggregatedGroup     `json:"groups,omitempty"` // Set instead of data_points when grouped
+	Percentiles []float64             `json:"percentiles,omitempty"`
 	RequestedAt time.Time             `json:"requested_at"`
 }
 
@@ -180,6 +216,9 @@ type FlexibleIoTSensorReadingRequest struct {
 	SensorTypeID    uuid.UUID                   `json:"sensor_type_id" binding:"required" validate:"required"`
 	MacAddress      string                      `json:"mac_address" binding:"required" validate:"required"`
 	ReadingTime     *time.Time                  `json:"reading_time,omitempty"` // Optional, defaults to current time
+	RawReadingTime  interface{}                 `json:"-"`                      // reading_time when it isn't RFC3339, parsed by the asset sensor's time settings
+	MessageID       string                      `json:"message_id,omitempty"`   // Optional client message ID; a message is stored once per asset sensor
+	Source          string                      `json:"-"`                      // Where the reading came from, recorded when it is quarantined
 	MeasurementData map[string]MeasurementValue `json:"-"`                      // Will be populated from other fields
 	RawJSON         map[string]interface{}      `json:"-"`                      // Store the raw JSON for processing
 }
@@ -189,6 +228,10 @@ type MeasurementValue struct {
 	Label string      `json:"label"` // e.g., "Temperature", "Raw Value"
 	Unit  string      `json:"unit"`  // e.g., "°C", "μg/m³"
 	Value interface{} `json:"value"` // The actual measurement value (can be float64, int, string, etc.)
+
+	// Value and unit as sent, when the value was converted to another unit
+	OriginalValue *float64 `json:"original_value,omitempty"`
+	OriginalUnit  string   `json:"original_unit,omitempty"`
 }
 
 // FlexibleBatchIoTSensorReadingRequest represents a flexible batch request
@@ -224,12 +267,21 @@ func (f *FlexibleIoTSensorReadingRequest) UnmarshalJSON(data []byte) error {
 		f.MacAddress = macAddress
 	}
 
-	if readingTimeStr, ok := raw["reading_time"].(string); ok {
-		if t, err := time.Parse(time.RFC3339, readingTimeStr); err == nil {
+	// Timestamps other than RFC3339 (epochs, local times, custom layouts) depend on the
+	// device, so they are kept for the service to parse
+	if readingTime, ok := raw["reading_time"]; ok && readingTime != nil {
+		readingTimeStr, isString := readingTime.(string)
+		if t, err := time.Parse(time.RFC3339, readingTimeStr); isString && err == nil {
 			f.ReadingTime = &t
+		} else {
+			f.RawReadingTime = readingTime
 		}
 	}
 
+	if messageID, ok := raw["message_id"].(string); ok {
+		f.MessageID = messageID
+	}
+
 	// Extract measurement data
 	f.MeasurementData = make(map[string]MeasurementValue)
 
@@ -262,6 +314,7 @@ func (f *FlexibleIoTSensorReadingRequest) UnmarshalJSON(data []byte) error {
 			"sensor_type_id":   true,
 			"mac_address":      true,
 			"reading_time":     true,
+			"message_id":       true,
 			"measurement_data": true,
 		}
 
@@ -344,3 +397,38 @@ type AssetSensorLocationInfo struct {
 	LocationID      uuid.UUID `json:"location_id"`
 	LocationName    string    `json:"location_name"`
 }
+
+// ReadingStreamLineError describes a rejected line of a streamed reading import
+type ReadingStreamLineError struct {
+	Line  int    `json:"line"` // 1-based line number in the request body
+	Error string `json:"error"`
+}
+
+// ReadingStreamResponse reports the outcome of a streamed reading import
+type ReadingStreamResponse struct {
+	Format            string                   `json:"format"`
+	Backfill          bool                     `json:"backfill"`
+	DryRun            bool                     `json:"dry_run"`    // Nothing was stored; the stored counts are what would be stored
+	LinesRead         int                      `json:"lines_read"` // Data lines, without blank lines and the CSV header
+	LinesStored       int                      `json:"lines_stored"`
+	LinesRejected     int                      `json:"lines_rejected"`
+	ReadingsStored    int                      `json:"readings_stored"`    // One reading per measurement of a stored line
+	LinesLate         int                      `json:"lines_late"`         // Stored lines older than the last reading of their asset sensor
+	LinesTimeAnomaly  int                      `json:"lines_time_anomaly"` // Stored lines in the future or, outside backfills, older than the maximum age
+	Errors            []ReadingStreamLineError `json:"errors,omitempty"`
+	ErrorsTruncated   bool                     `json:"errors_truncated,omitempty"`   // More lines were rejected than listed in errors
+	LinesQuarantined  int                      `json:"lines_quarantined"`            // Lines with measurements held back by the ingestion policy
+	Warnings          []ReadingStreamLineError `json:"warnings,omitempty"`           // Stored lines with measurements that don't match their fields
+	WarningsTruncated bool                     `json:"warnings_truncated,omitempty"` // More lines had warnings than listed in warnings
+	Preview           []ReadingStreamPreview   `json:"preview,omitempty"`            // First readings of a dry run
+}
+
+// ReadingStreamPreview is a reading a dry run would store
+type ReadingStreamPreview struct {
+	Line            int         `json:"line"`
+	AssetSensorID   uuid.UUID   `json:"asset_sensor_id"`
+	ReadingTime     time.Time   `json:"reading_time"`
+	MeasurementType string      `json:"measurement_type"`
+	Value           interface{} `json:"value"`
+	Unit            string      `json:"unit,omitempty"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceWindowRequest represents the request for creating or updating a maintenance
+// window. At least one of asset_id, location_id and asset_type_id is required.
+type MaintenanceWindowRequest struct {
+	Name            string                       `json:"name" binding:"required"`
+	Description     *string                      `json:"description,omitempty"`
+	AssetID         *uuid.UUID                   `json:"asset_id,omitempty"`
+	LocationID      *uuid.UUID                   `json:"location_id,omitempty"`
+	AssetTypeID     *uuid.UUID                   `json:"asset_type_id,omitempty"`
+	StartTime       time.Time                    `json:"start_time" binding:"required"`
+	EndTime         time.Time                    `json:"end_time" binding:"required"`
+	Recurrence      entity.MaintenanceRecurrence `json:"recurrence,omitempty"`       // none (default), daily, weekly or monthly
+	RecurrenceUntil *time.Time                   `json:"recurrence_until,omitempty"` // Open-ended when omitted
+	AlertMode       entity.MaintenanceAlertMode  `json:"alert_mode,omitempty"`       // suppress (default) or tag
+	SetAssetStatus  *bool                        `json:"set_asset_status,omitempty"`
+	IsActive        *bool                        `json:"is_active,omitempty"`
+}
+
+// MaintenanceWindowResponse represents a maintenance window and whether it is in progress
+type MaintenanceWindowResponse struct {
+	*entity.MaintenanceWindow
+	InProgress bool `json:"in_progress"`
+}
+
+// MaintenanceWindowListResponse represents the paginated response for listing maintenance windows
+type MaintenanceWindowListResponse struct {
+	Data       []MaintenanceWindowResponse `json:"data"`
+	Pagination PaginationInfo              `json:"pagination"`
+}
+
+// MaintenanceWindowFromEntity converts entity.MaintenanceWindow to MaintenanceWindowResponse
+func MaintenanceWindowFromEntity(window *entity.MaintenanceWindow, now time.Time) MaintenanceWindowResponse {
+	return MaintenanceWindowResponse{
+		MaintenanceWindow: window,
+		InProgress:        window.ActiveAt(now),
+	}
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RedactedSecret replaces stored secrets in responses
+const RedactedSecret = "********"
+
+// NotificationChannelRequest represents the request for creating or updating a
+// notification channel. On update, empty secret and smtp_password keep the stored values.
+type NotificationChannelRequest struct {
+	Name     string                           `json:"name" binding:"required"`
+	Type     entity.NotificationChannelType   `json:"type" binding:"required"`
+	Config   entity.NotificationChannelConfig `json:"config"`
+	IsActive *bool                            `json:"is_active,omitempty"`
+}
+
+// NotificationChannelResponse represents a notification channel with secrets redacted
+type NotificationChannelResponse struct {
+	ID        uuid.UUID                        `json:"id"`
+	TenantID  uuid.UUID                        `json:"tenant_id"`
+	Name      string                           `json:"name"`
+	Type      entity.NotificationChannelType   `json:"type"`
+	Config    entity.NotificationChannelConfig `json:"config"`
+	IsActive  bool                             `json:"is_active"`
+	CreatedAt time.Time                        `json:"created_at"`
+	UpdatedAt *time.Time                       `json:"updated_at,omitempty"`
+}
+
+// NotificationChannelListResponse represents the paginated response for listing notification channels
+type NotificationChannelListResponse struct {
+	Data       []NotificationChannelResponse `json:"data"`
+	Pagination PaginationInfo                `json:"pagination"`
+}
+
+// NotificationChannelFromEntity converts entity.NotificationChannel to NotificationChannelResponse
+func NotificationChannelFromEntity(channel *entity.NotificationChannel) NotificationChannelResponse {
+	config := channel.Config
+	if config.Secret != "" {
+		config.Secret = RedactedSecret
+	}
+	if config.SMTPPassword != "" {
+		config.SMTPPassword = RedactedSecret
+	}
+
+	return NotificationChannelResponse{
+		ID:        channel.ID,
+		TenantID:  channel.TenantID,
+		Name:      channel.Name,
+		Type:      channel.Type,
+		Config:    config,
+		IsActive:  channel.IsActive,
+		CreatedAt: channel.CreatedAt,
+		UpdatedAt: channel.UpdatedAt,
+	}
+}
+
+// NotificationRuleRequest represents the request for creating or updating a notification rule.
+// Empty severities and missing asset_id/asset_type_id match every alert.
+type NotificationRuleRequest struct {
+	ChannelID       uuid.UUID                  `json:"channel_id" binding:"required"`
+	Name            string                     `json:"name" binding:"required"`
+	Severities      []entity.ThresholdSeverity `json:"severities,omitempty"`
+	AssetID         *uuid.UUID                 `json:"asset_id,omitempty"`
+	AssetTypeID     *uuid.UUID                 `json:"asset_type_id,omitempty"`
+	NotifyOnResolve *bool                      `json:"notify_on_resolve,omitempty"`
+	IsActive        *bool                      `json:"is_active,omitempty"`
+}
+
+// NotificationRuleListResponse represents the paginated response for listing notification rules
+type NotificationRuleListResponse struct {
+	Data       []*entity.NotificationRule `json:"data"`
+	Pagination PaginationInfo             `json:"pagination"`
+}
+
+// NotificationDeliveryListResponse represents the paginated delivery log
+type NotificationDeliveryListResponse struct {
+	Data       []*entity.NotificationDelivery `json:"data"`
+	Pagination PaginationInfo                 `json:"pagination"`
+}
+
+// NotificationTestResponse is returned after sending a test notification
+type NotificationTestResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+
+	"github.com/google/uuid"
+)
+
+// PayloadDecoderRequest represents the request for creating or updating a payload decoder.
+// The format defaults to json and new decoders are active.
+type PayloadDecoderRequest struct {
+	SensorTypeID  uuid.UUID                 `json:"sensor_type_id" binding:"required"`
+	Name          string                    `json:"name" binding:"required"`
+	Description   *string                   `json:"description,omitempty"`
+	Format        entity.PayloadFormat      `json:"format,omitempty"`
+	FramePath     string                    `json:"frame_path,omitempty"`
+	TimestampPath string                    `json:"timestamp_path,omitempty"`
+	MessageIDPath string                    `json:"message_id_path,omitempty"`
+	Fields        []entity.PayloadFieldSpec `json:"fields" binding:"required"`
+	IsActive      *bool                     `json:"is_active,omitempty"`
+}
+
+// PayloadDecoderListResponse represents the paginated response for listing payload decoders
+type PayloadDecoderListResponse struct {
+	Data       []*entity.PayloadDecoder `json:"data"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+)
+
+// QuarantinedReadingListResponse represents the paginated response for listing quarantined readings
+type QuarantinedReadingListResponse struct {
+	Data       []*entity.QuarantinedReading `json:"data"`
+	Pagination PaginationInfo               `json:"pagination"`
+}
+
+// ReplayQuarantinedReadingRequest represents the request for replaying a quarantined
+// reading. The measurements are checked with the strict policy against the current fields
+// of the sensor type, unless force stores them as they are.
+type ReplayQuarantinedReadingRequest struct {
+	Force bool   `json:"force"`
+	Note  string `json:"note,omitempty"`
+}
+
+// DiscardQuarantinedReadingRequest represents the request for discarding a quarantined reading
+type DiscardQuarantinedReadingRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+// ReplayQuarantinedReadingResponse represents the response for replaying a quarantined reading
+type ReplayQuarantinedReadingResponse struct {
+	Quarantine *entity.QuarantinedReading `json:"quarantine"`
+	Reading    *IoTSensorReadingResponse  `json:"reading"`
+}
@@ -9,17 +9,23 @@ import (
 
 // SensorThresholdResponse represents the response structure for sensor thresholds
 type SensorThresholdResponse struct {
-	ID                   uuid.UUID                `json:"id"`
-	TenantID             uuid.UUID                `json:"tenant_id"`
-	AssetSensorID        uuid.UUID                `json:"asset_sensor_id"`
-	MeasurementTypeID    uuid.UUID                `json:"measurement_type_id"`
-	MeasurementFieldName string                   `json:"measurement_field_name"`
-	MinValue             *float64                 `json:"min_value,omitempty"`
-	MaxValue             *float64                 `json:"max_value,omitempty"`
-	Severity             entity.ThresholdSeverity `json:"severity"`
-	IsActive             bool                     `json:"is_active"`
-	CreatedAt            time.Time                `json:"created_at"`
-	UpdatedAt            *time.Time               `json:"updated_at,omitempty"`
+	ID                   uuid.UUID                  `json:"id"`
+	TenantID             uuid.UUID                  `json:"tenant_id"`
+	AssetSensorID        uuid.UUID                  `json:"asset_sensor_id"`
+	MeasurementTypeID    uuid.UUID                  `json:"measurement_type_id"`
+	MeasurementFieldName string                     `json:"measurement_field_name"`
+	MinValue             *float64                   `json:"min_value,omitempty"`
+	MaxValue             *float64                   `json:"max_value,omitempty"`
+	Severity             entity.ThresholdSeverity   `json:"severity"`
+	RuleKind             entity.ThresholdRuleKind   `json:"rule_kind"`
+	WindowSeconds        int                        `json:"window_seconds"`
+	ExpectedBoolean      *bool                      `json:"expected_boolean,omitempty"`
+	TextValues           []string                   `json:"text_values,omitempty"`
+	TextPattern          *string                    `json:"text_pattern,omitempty"`
+	AlertRules           entity.ThresholdAlertRules `json:"alert_rules"`
+	IsActive             bool                       `json:"is_active"`
+	CreatedAt            time.Time                  `json:"created_at"`
+	UpdatedAt            *time.Time                 `json:"updated_at,omitempty"`
 }
 
 // SensorThresholdListResponse represents the paginated response for listing sensor thresholds
@@ -30,22 +36,34 @@ type SensorThresholdListResponse struct {
 
 // CreateSensorThresholdRequest represents the request structure for creating a sensor threshold
 type CreateSensorThresholdRequest struct {
-	AssetSensorID        uuid.UUID                `json:"asset_sensor_id" binding:"required"`
-	MeasurementTypeID    uuid.UUID                `json:"measurement_type_id" binding:"required"`
-	MeasurementFieldName string                   `json:"measurement_field_name" binding:"required"`
-	MinValue             *float64                 `json:"min_value,omitempty"`
-	MaxValue             *float64                 `json:"max_value,omitempty"`
-	Severity             entity.ThresholdSeverity `json:"severity" binding:"required"`
-	IsActive             bool                     `json:"is_active"`
+	AssetSensorID        uuid.UUID                   `json:"asset_sensor_id" binding:"required"`
+	MeasurementTypeID    uuid.UUID                   `json:"measurement_type_id" binding:"required"`
+	MeasurementFieldName string                      `json:"measurement_field_name" binding:"required"`
+	MinValue             *float64                    `json:"min_value,omitempty"`
+	MaxValue             *float64                    `json:"max_value,omitempty"`
+	Severity             entity.ThresholdSeverity    `json:"severity" binding:"required"`
+	RuleKind             entity.ThresholdRuleKind    `json:"rule_kind,omitempty"`        // Optional, defaults to static
+	WindowSeconds        int                         `json:"window_seconds,omitempty"`   // Required for windowed and boolean_duration rule kinds
+	ExpectedBoolean      *bool                       `json:"expected_boolean,omitempty"` // Required for boolean rule kinds
+	TextValues           []string                    `json:"text_values,omitempty"`      // Required for text_equals and text_in_set
+	TextPattern          *string                     `json:"text_pattern,omitempty"`     // Required for text_regex
+	AlertRules           *entity.ThresholdAlertRules `json:"alert_rules,omitempty"`      // Optional, defaults to alerting on every breach
+	IsActive             bool                        `json:"is_active"`
 }
 
 // UpdateSensorThresholdRequest represents the request structure for updating a sensor threshold
 type UpdateSensorThresholdRequest struct {
-	MeasurementFieldName string                   `json:"measurement_field_name,omitempty"`
-	MinValue             *float64                 `json:"min_value,omitempty"`
-	MaxValue             *float64                 `json:"max_value,omitempty"`
-	Severity             entity.ThresholdSeverity `json:"severity,omitempty"`
-	IsActive             *bool                    `json:"is_active,omitempty"`
+	MeasurementFieldName string                      `json:"measurement_field_name,omitempty"`
+	MinValue             *float64                    `json:"min_value,omitempty"`
+	MaxValue             *float64                    `json:"max_value,omitempty"`
+	Severity             entity.ThresholdSeverity    `json:"severity,omitempty"`
+	RuleKind             entity.ThresholdRuleKind    `json:"rule_kind,omitempty"`
+	WindowSeconds        int                         `json:"window_seconds,omitempty"`
+	ExpectedBoolean      *bool                       `json:"expected_boolean,omitempty"`
+	TextValues           []string                    `json:"text_values,omitempty"`
+	TextPattern          *string                     `json:"text_pattern,omitempty"`
+	AlertRules           *entity.ThresholdAlertRules `json:"alert_rules,omitempty"`
+	IsActive             *bool                       `json:"is_active,omitempty"`
 }
 
 // SensorThresholdFilter represents filter parameters for listing thresholds
@@ -60,7 +78,7 @@ type SensorThresholdFilter struct {
 
 // ToEntity converts CreateSensorThresholdRequest to entity.SensorThreshold
 func (r *CreateSensorThresholdRequest) ToEntity(tenantID uuid.UUID) *entity.SensorThreshold {
-	return &entity.SensorThreshold{
+	threshold := &entity.SensorThreshold{
 		TenantID:             tenantID,
 		AssetSensorID:        r.AssetSensorID,
 		MeasurementTypeID:    r.MeasurementTypeID,
@@ -68,20 +86,38 @@ func (r *CreateSensorThresholdRequest) ToEntity(tenantID uuid.UUID) *entity.Sens
 		MinValue:             r.MinValue,
 		MaxValue:             r.MaxValue,
 		Severity:             r.Severity,
+		RuleKind:             r.RuleKind,
+		WindowSeconds:        r.WindowSeconds,
+		ExpectedBoolean:      r.ExpectedBoolean,
+		TextValues:           r.TextValues,
+		TextPattern:          r.TextPattern,
 		IsActive:             r.IsActive,
 	}
+	if r.AlertRules != nil {
+		threshold.AlertRules = *r.AlertRules
+	}
+	return threshold
 }
 
 // ToEntity converts UpdateSensorThresholdRequest to entity.SensorThreshold
 func (r *UpdateSensorThresholdRequest) ToEntity(id uuid.UUID) *entity.SensorThreshold {
-	return &entity.SensorThreshold{
+	threshold := &entity.SensorThreshold{
 		ID:                   id,
 		MeasurementFieldName: r.MeasurementFieldName,
 		MinValue:             r.MinValue,
 		MaxValue:             r.MaxValue,
 		Severity:             r.Severity,
+		RuleKind:             r.RuleKind,
+		WindowSeconds:        r.WindowSeconds,
+		ExpectedBoolean:      r.ExpectedBoolean,
+		TextValues:           r.TextValues,
+		TextPattern:          r.TextPattern,
 		IsActive:             r.IsActive != nil && *r.IsActive,
 	}
+	if r.AlertRules != nil {
+		threshold.AlertRules = *r.AlertRules
+	}
+	return threshold
 }
 
 // FromEntity converts entity.SensorThreshold to SensorThresholdResponse
@@ -98,6 +134,12 @@ func FromEntity(e *entity.SensorThreshold) *SensorThresholdResponse {
 		MinValue:             e.MinValue,
 		MaxValue:             e.MaxValue,
 		Severity:             e.Severity,
+		RuleKind:             e.RuleKind,
+		WindowSeconds:        e.WindowSeconds,
+		ExpectedBoolean:      e.ExpectedBoolean,
+		TextValues:           e.TextValues,
+		TextPattern:          e.TextPattern,
+		AlertRules:           e.AlertRules,
 		IsActive:             e.IsActive,
 		CreatedAt:            e.CreatedAt,
 		UpdatedAt:            e.UpdatedAt,
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"time"
+)
+
+// UpdateSensorTimeSettingsRequest represents the request for updating the time settings of
+// an asset sensor. Omitted fields keep their current value.
+type UpdateSensorTimeSettingsRequest struct {
+	TimestampFormat      *string `json:"timestamp_format,omitempty"` // auto, rfc3339, unix, unix_ms or a Go time layout
+	Timezone             *string `json:"timezone,omitempty"`         // IANA time zone of timestamps without an offset
+	ClockOffsetMs        *int64  `json:"clock_offset_ms,omitempty"`
+	MaxFutureSkewSeconds *int    `json:"max_future_skew_seconds,omitempty"`
+	MaxAgeSeconds        *int64  `json:"max_age_seconds,omitempty"`
+	SkewAction           *string `json:"skew_action,omitempty"` // flag, receive_time or reject
+
+	// DeviceClock is the current time on the device's clock. It sets clock_offset_ms to the
+	// difference from the server's time, unless clock_offset_ms is given.
+	DeviceClock *time.Time `json:"device_clock,omitempty"`
+}
+
+// SensorTimeSettingsResponse represents the time settings of an asset sensor
+type SensorTimeSettingsResponse struct {
+	*entity.SensorTimeSettings
+	IsDefault bool `json:"is_default"` // The sensor has no settings of its own
+}
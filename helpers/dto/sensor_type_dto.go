@@ -16,8 +16,10 @@ type SensorTypeDTO struct {
 	Model        string     `json:"model"`
 	Version      string     `json:"version"`
 	IsActive     bool       `json:"is_active"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	// IngestionPolicy is strict, warn or permissive
+	IngestionPolicy string     `json:"ingestion_policy"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
 }
 
 // CreateSensorTypeRequest represents the request for creating a new sensor type
@@ -28,6 +30,8 @@ type CreateSensorTypeRequest struct {
 	Model        string `json:"model" validate:"required"`
 	Version      string `json:"version" validate:"required"`
 	IsActive     bool   `json:"is_active"`
+	// IngestionPolicy is strict, warn or permissive; defaults to warn
+	IngestionPolicy string `json:"ingestion_policy,omitempty"`
 }
 
 // UpdateSensorTypeRequest represents the request for updating a sensor type
@@ -38,6 +42,8 @@ type UpdateSensorTypeRequest struct {
 	Model        string `json:"model,omitempty"`
 	Version      string `json:"version,omitempty"`
 	IsActive     bool   `json:"is_active,omitempty"`
+	// IngestionPolicy is strict, warn or permissive; unchanged when empty
+	IngestionPolicy string `json:"ingestion_policy,omitempty"`
 }
 
 // SensorTypeResponse represents the response for sensor type operations
@@ -1,17 +1,27 @@
 package main
 
 import (
+	"be-lecsens/asset_management/data-layer/archivestore"
 	"be-lecsens/asset_management/data-layer/cloudinary"
 	"be-lecsens/asset_management/data-layer/config"
+	"be-lecsens/asset_management/data-layer/entity"
 	"be-lecsens/asset_management/data-layer/migration"
+	"be-lecsens/asset_management/data-layer/mqtt"
+	"be-lecsens/asset_management/data-layer/notifier"
 	"be-lecsens/asset_management/data-layer/repository"
 	"be-lecsens/asset_management/domain-layer/middleware"
 	"be-lecsens/asset_management/domain-layer/service"
 	"be-lecsens/asset_management/presentation-layer/controller"
 	"be-lecsens/asset_management/presentation-layer/routes"
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -62,6 +72,12 @@ func main() {
 		log.Fatalf("Failed to initialize Cloudinary service: %v", err)
 	}
 
+	// Initialize reading archive storage
+	archiveStorage, err := archivestore.New(cfg.Archive.Storage, cfg.Archive.LocalDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize archive storage: %v", err)
+	}
+
 	// Initialize repositories
 	assetRepo := repository.NewAssetRepository(db)
 	assetTypeRepo := repository.NewAssetTypeRepository(db)
@@ -76,6 +92,22 @@ func main() {
 	assetAlertRepo := repository.NewAssetAlertRepository(db)
 	sensorStatusRepo := repository.NewSensorStatusRepository(db)
 	sensorLogsRepo := repository.NewSensorLogsRepository(db)
+	deviceAPIKeyRepo := repository.NewDeviceAPIKeyRepository(db)
+	notificationChannelRepo := repository.NewNotificationChannelRepository(db)
+	notificationRuleRepo := repository.NewNotificationRuleRepository(db)
+	notificationDeliveryRepo := repository.NewNotificationDeliveryRepository(db)
+	escalationPolicyRepo := repository.NewEscalationPolicyRepository(db)
+	assetAlertEscalationRepo := repository.NewAssetAlertEscalationRepository(db)
+	maintenanceWindowRepo := repository.NewMaintenanceWindowRepository(db)
+	alertConditionRepo := repository.NewAlertConditionRepository(db)
+	readingOutboxRepo := repository.NewReadingOutboxRepository(db)
+	csvMappingProfileRepo := repository.NewCSVMappingProfileRepository(db)
+	payloadDecoderRepo := repository.NewPayloadDecoderRepository(db)
+	quarantinedReadingRepo := repository.NewQuarantinedReadingRepository(db)
+	sensorTimeSettingsRepo := repository.NewSensorTimeSettingsRepository(db)
+	readingRollupRepo := repository.NewReadingRollupRepository(db)
+	readingRetentionRepo := repository.NewReadingRetentionRepository(db)
+	readingArchiveRepo := repository.NewReadingArchiveRepository(db)
 
 	// Initialize services
 	log.Println("Initializing services")
@@ -87,11 +119,104 @@ func main() {
 	sensorTypeService := service.NewSensorTypeService(sensorTypeRepo)
 	sensorMeasurementFieldService := service.NewSensorMeasurementFieldService(sensorMeasurementFieldRepo)
 	sensorMeasurementTypeService := service.NewSensorMeasurementTypeService(sensorMeasurementTypeRepo)
-	sensorThresholdService := service.NewSensorThresholdService(sensorThresholdRepo, assetSensorRepo, assetAlertRepo)
-	assetAlertService := service.NewAssetAlertService(assetAlertRepo, assetRepo, assetSensorRepo)
-	iotSensorReadingService := service.NewIoTSensorReadingService(iotSensorReadingRepo, assetSensorRepo, sensorTypeRepo, assetRepo, locationRepo, sensorThresholdService, sensorMeasurementTypeRepo)
+	notificationDispatcher := notifier.NewDispatcher(map[entity.NotificationChannelType]notifier.Sender{
+		entity.NotificationChannelWebhook: notifier.NewWebhookSender(time.Duration(cfg.Notifier.HTTPTimeout) * time.Second),
+		entity.NotificationChannelSlack:   notifier.NewSlackSender(time.Duration(cfg.Notifier.HTTPTimeout) * time.Second),
+		entity.NotificationChannelEmail: notifier.NewEmailSender(notifier.SMTPConfig{
+			Host:     cfg.Notifier.SMTPHost,
+			Port:     cfg.Notifier.SMTPPort,
+			Username: cfg.Notifier.SMTPUsername,
+			Password: cfg.Notifier.SMTPPassword,
+			From:     cfg.Notifier.SMTPFrom,
+			Timeout:  time.Duration(cfg.Notifier.HTTPTimeout) * time.Second,
+		}),
+	})
+	notificationService := service.NewNotificationService(notificationChannelRepo, notificationRuleRepo, notificationDeliveryRepo, assetRepo, notificationDispatcher, service.NotificationRetryPolicy{
+		MaxAttempts: cfg.Notifier.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.Notifier.RetryBaseDelay) * time.Second,
+		MaxDelay:    time.Duration(cfg.Notifier.RetryMaxDelay) * time.Second,
+	})
+	maintenanceService := service.NewMaintenanceService(maintenanceWindowRepo, assetRepo, locationRepo, assetTypeRepo)
+	sensorThresholdService := service.NewSensorThresholdService(sensorThresholdRepo, assetSensorRepo, assetAlertRepo, iotSensorReadingRepo, notificationService, maintenanceService)
+	alertConditionService := service.NewAlertConditionService(alertConditionRepo, assetRepo, assetSensorRepo, iotSensorReadingRepo, assetAlertRepo, notificationService, maintenanceService)
+	assetAlertService := service.NewAssetAlertService(assetAlertRepo, assetRepo, assetSensorRepo, assetAlertEscalationRepo)
+	escalationService := service.NewEscalationService(escalationPolicyRepo, assetAlertEscalationRepo, assetAlertRepo, notificationChannelRepo, notificationService)
+	alertEvaluationQueue := service.NewAlertEvaluationQueue(cfg.Alerting.Workers, cfg.Alerting.QueueSize, time.Duration(cfg.Alerting.EvaluationTimeout)*time.Second)
+	readingOutboxService := service.NewReadingOutboxService(readingOutboxRepo, iotSensorReadingRepo, alertEvaluationQueue, service.ReadingOutboxPolicy{
+		BatchSize:   cfg.Outbox.BatchSize,
+		Lease:       time.Duration(cfg.Outbox.Lease) * time.Second,
+		MaxAttempts: cfg.Outbox.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.Outbox.RetryBaseDelay) * time.Second,
+		MaxDelay:    time.Duration(cfg.Outbox.RetryMaxDelay) * time.Second,
+		Retention:   time.Duration(cfg.Outbox.RetentionHours) * time.Hour,
+	})
+	iotSensorReadingService := service.NewIoTSensorReadingService(iotSensorReadingRepo, assetSensorRepo, sensorTypeRepo, assetRepo, locationRepo, sensorThresholdService, alertConditionService, sensorMeasurementTypeRepo, alertEvaluationQueue, readingOutboxService, quarantinedReadingRepo, sensorTimeSettingsRepo, readingRollupRepo)
+	csvMappingProfileService := service.NewCSVMappingProfileService(csvMappingProfileRepo, sensorTypeRepo, sensorMeasurementTypeRepo, sensorMeasurementFieldRepo, iotSensorReadingService)
+	payloadDecoderService := service.NewPayloadDecoderService(payloadDecoderRepo, sensorTypeRepo, sensorMeasurementTypeRepo, sensorMeasurementFieldRepo)
+	readingQuarantineService := service.NewReadingQuarantineService(quarantinedReadingRepo, iotSensorReadingService)
 	sensorStatusService := service.NewSensorStatusService(sensorStatusRepo)
 	sensorLogsService := service.NewSensorLogsService(sensorLogsRepo)
+	deviceAPIKeyService := service.NewDeviceAPIKeyService(deviceAPIKeyRepo, assetSensorRepo)
+	sensorTimeSettingsService := service.NewSensorTimeSettingsService(sensorTimeSettingsRepo, assetSensorRepo)
+	readingRollupService := service.NewReadingRollupService(readingRollupRepo, time.Duration(cfg.Rollup.SafetyLag)*time.Second, time.Duration(cfg.Rollup.ChunkHours)*time.Hour)
+	readingRetentionService := service.NewReadingRetentionService(readingRetentionRepo, cfg.Retention.PartitionsAhead, cfg.Retention.DeleteBatchSize,
+		time.Duration(cfg.Retention.MessageTTLHours)*time.Hour)
+	readingArchiveService := service.NewReadingArchiveService(readingArchiveRepo, readingRetentionRepo, archiveStorage)
+
+	// Start notification delivery worker
+	notificationService.Start(time.Duration(cfg.Notifier.PollInterval) * time.Second)
+	defer notificationService.Stop()
+
+	// Start alert escalation scheduler
+	escalationService.Start(time.Duration(cfg.Notifier.EscalationInterval) * time.Second)
+	defer escalationService.Stop()
+
+	// Start maintenance window scheduler
+	maintenanceService.Start(time.Duration(cfg.Maintenance.PollInterval) * time.Second)
+	defer maintenanceService.Stop()
+
+	// Start reading rollup refresher
+	readingRollupService.Start(time.Duration(cfg.Rollup.PollInterval) * time.Second)
+	defer readingRollupService.Stop()
+
+	// Start reading partition and retention job
+	readingRetentionService.Start(time.Duration(cfg.Retention.PollInterval) * time.Second)
+	defer readingRetentionService.Stop()
+
+	// Start reading archive export job
+	readingArchiveService.Start(time.Duration(cfg.Archive.PollInterval) * time.Second)
+	defer readingArchiveService.Stop()
+
+	// Start alert evaluation workers; deferred before the ingestion sources so they are
+	// stopped first and the queue drains what they produced
+	iotSensorReadingService.StartAlertEvaluation()
+	defer alertEvaluationQueue.Stop(time.Duration(cfg.Alerting.DrainTimeout) * time.Second)
+
+	// Start the reading outbox dispatcher; it feeds the evaluation queue, so it is
+	// stopped before the queue drains
+	readingOutboxService.Start(time.Duration(cfg.Outbox.PollInterval) * time.Second)
+	defer readingOutboxService.Stop()
+
+	// Start MQTT ingestion bridge if enabled
+	if cfg.MQTT.Enabled {
+		mqttClient := mqtt.NewClient(&mqtt.MQTTConfig{
+			BrokerURL:    cfg.MQTT.BrokerURL,
+			ClientID:     cfg.MQTT.ClientID,
+			Username:     cfg.MQTT.Username,
+			Password:     cfg.MQTT.Password,
+			KeepAlive:    time.Duration(cfg.MQTT.KeepAlive) * time.Second,
+			CleanSession: cfg.MQTT.CleanSession,
+		})
+		if err := mqttClient.Connect(context.Background()); err != nil {
+			log.Fatalf("Failed to connect to MQTT broker: %v", err)
+		}
+
+		mqttIngestionService := service.NewMQTTIngestionService(mqttClient, iotSensorReadingService, assetSensorRepo, payloadDecoderService, cfg.MQTT.TopicPattern, byte(cfg.MQTT.QoS))
+		if err := mqttIngestionService.Start(); err != nil {
+			log.Fatalf("Failed to start MQTT ingestion bridge: %v", err)
+		}
+		defer mqttIngestionService.Stop()
+	}
 
 	// Initialize controllers
 	assetController := controller.NewAssetController(assetService, cfg)
@@ -107,6 +232,16 @@ func main() {
 	assetAlertController := controller.NewAssetAlertController(assetAlertService)
 	sensorStatusController := controller.NewSensorStatusController(sensorStatusService)
 	sensorLogsController := controller.NewSensorLogsController(sensorLogsService)
+	deviceAPIKeyController := controller.NewDeviceAPIKeyController(deviceAPIKeyService)
+	deviceIngestionController := controller.NewDeviceIngestionController(iotSensorReadingService, deviceAPIKeyService, payloadDecoderService)
+	notificationController := controller.NewNotificationController(notificationService)
+	escalationController := controller.NewEscalationController(escalationService)
+	maintenanceController := controller.NewMaintenanceController(maintenanceService)
+	alertConditionController := controller.NewAlertConditionController(alertConditionService)
+	csvMappingProfileController := controller.NewCSVMappingProfileController(csvMappingProfileService)
+	payloadDecoderController := controller.NewPayloadDecoderController(payloadDecoderService)
+	readingQuarantineController := controller.NewReadingQuarantineController(readingQuarantineService)
+	sensorTimeSettingsController := controller.NewSensorTimeSettingsController(sensorTimeSettingsService)
 
 	// Initialize JWT config
 	jwtConfig := middleware.JWTConfig{
@@ -136,10 +271,42 @@ func main() {
 		assetAlertController,
 		sensorLogsController,
 		sensorStatusController,
+		deviceAPIKeyController,
+		deviceIngestionController,
+		deviceAPIKeyService,
+		notificationController,
+		escalationController,
+		maintenanceController,
+		alertConditionController,
+		csvMappingProfileController,
+		payloadDecoderController,
+		readingQuarantineController,
+		sensorTimeSettingsController,
 		jwtConfig,
 	)
 
 	// Start the server
-	log.Printf("Server running on port %s", cfg.Server.Port)
-	router.Run(":" + cfg.Server.Port)
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Server running on port %s", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Wait for a shutdown signal, then let in-flight requests finish before the deferred
+	// workers are stopped
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
 }
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertConditionController handles HTTP requests for composite alert conditions
+type AlertConditionController struct {
+	alertConditionService *service.AlertConditionService
+}
+
+// NewAlertConditionController creates a new alert condition controller
+func NewAlertConditionController(alertConditionService *service.AlertConditionService) *AlertConditionController {
+	return &AlertConditionController{
+		alertConditionService: alertConditionService,
+	}
+}
+
+// CreateCondition creates an alert condition
+// @Summary Create alert condition
+// @Description Create an alert condition that raises one alert when an expression over several measurement fields of an asset holds, e.g. "temperature > 30 AND humidity < 20"
+// @Tags Alert Conditions
+// @Accept json
+// @Produce json
+// @Param request body dto.AlertConditionRequest true "Alert condition"
+// @Success 201 {object} entity.AlertCondition
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/alert-conditions [post]
+func (c *AlertConditionController) CreateCondition(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.AlertConditionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	condition, err := c.alertConditionService.CreateCondition(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create alert condition")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, condition)
+}
+
+// ListConditions lists alert conditions for the tenant
+// @Summary List alert conditions
+// @Description Get a paginated list of alert conditions for a tenant
+// @Tags Alert Conditions
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Param asset_id query string false "Only conditions of this asset"
+// @Success 200 {object} dto.AlertConditionListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/alert-conditions [get]
+func (c *AlertConditionController) ListConditions(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	assetID, ok := optionalUUIDQuery(ctx, "asset_id")
+	if !ok {
+		return
+	}
+
+	response, err := c.alertConditionService.ListConditions(ctx.Request.Context(), tenantUUID, assetID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list alert conditions")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetCondition retrieves an alert condition by ID
+// @Summary Get alert condition
+// @Description Get an alert condition by its ID
+// @Tags Alert Conditions
+// @Produce json
+// @Param id path string true "Alert condition ID"
+// @Success 200 {object} entity.AlertCondition
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/alert-conditions/{id} [get]
+func (c *AlertConditionController) GetCondition(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	condition, err := c.alertConditionService.GetCondition(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get alert condition")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, condition)
+}
+
+// UpdateCondition updates an alert condition
+// @Summary Update alert condition
+// @Description Replace the definition of an alert condition. The expression is validated against the sensors' measurement fields.
+// @Tags Alert Conditions
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert condition ID"
+// @Param request body dto.AlertConditionRequest true "Alert condition"
+// @Success 200 {object} entity.AlertCondition
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/alert-conditions/{id} [put]
+func (c *AlertConditionController) UpdateCondition(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.AlertConditionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	condition, err := c.alertConditionService.UpdateCondition(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update alert condition")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, condition)
+}
+
+// DeleteCondition deletes an alert condition
+// @Summary Delete alert condition
+// @Description Delete an alert condition. Alerts it raised are kept.
+// @Tags Alert Conditions
+// @Produce json
+// @Param id path string true "Alert condition ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/alert-conditions/{id} [delete]
+func (c *AlertConditionController) DeleteCondition(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.alertConditionService.DeleteCondition(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete alert condition")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -222,10 +222,12 @@ func (c *AssetAlertController) ListAssetAlerts(ctx *gin.Context) {
 
 // ResolveAssetAlert resolves an asset alert
 // @Summary Resolve asset alert
-// @Description Mark an asset alert as resolved
+// @Description Mark an asset alert as resolved, optionally with a resolution code and note
 // @Tags Asset Alerts
+// @Accept json
 // @Produce json
 // @Param id path string true "Asset alert ID"
+// @Param request body dto.ResolveAlertRequest false "Resolution details"
 // @Success 200 {object} dto.AssetAlertResponse
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 404 {object} common.ErrorResponse
@@ -242,7 +244,19 @@ func (c *AssetAlertController) ResolveAssetAlert(ctx *gin.Context) {
 		return
 	}
 
-	alert, err := c.assetAlertService.ResolveAssetAlert(ctx.Request.Context(), id)
+	// The body is optional so existing clients can keep resolving without one
+	var request dto.ResolveAlertRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	alert, err := c.assetAlertService.ResolveAssetAlert(ctx.Request.Context(), id, optionalUserIDFromContext(ctx), request)
 	if err != nil {
 		if notFoundErr, ok := err.(*common.NotFoundError); ok {
 			ctx.JSON(http.StatusNotFound, common.ErrorResponse{
@@ -251,10 +265,7 @@ func (c *AssetAlertController) ResolveAssetAlert(ctx *gin.Context) {
 			})
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse{
-			Error:   "Failed to resolve asset alert",
-			Message: err.Error(),
-		})
+		respondServiceError(ctx, err, "Failed to resolve asset alert")
 		return
 	}
 
@@ -298,16 +309,183 @@ func (c *AssetAlertController) ResolveMultipleAssetAlerts(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.assetAlertService.ResolveMultipleAssetAlerts(ctx.Request.Context(), request)
+	response, err := c.assetAlertService.ResolveMultipleAssetAlerts(ctx.Request.Context(), optionalUserIDFromContext(ctx), request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse{
-			Error:   "Failed to resolve alerts",
+		respondServiceError(ctx, err, "Failed to resolve alerts")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// AcknowledgeAssetAlert acknowledges an asset alert
+// @Summary Acknowledge asset alert
+// @Description Mark an open asset alert as acknowledged by the current user
+// @Tags Asset Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset alert ID"
+// @Param request body dto.AcknowledgeAlertRequest false "Optional comment"
+// @Success 200 {object} dto.AssetAlertResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-alerts/{id}/acknowledge [patch]
+func (c *AssetAlertController) AcknowledgeAssetAlert(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.AcknowledgeAlertRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	alert, err := c.assetAlertService.AcknowledgeAssetAlert(ctx.Request.Context(), tenantUUID, id, userID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to acknowledge asset alert")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alert)
+}
+
+// AssignAssetAlert assigns an asset alert to a user
+// @Summary Assign asset alert
+// @Description Assign an open asset alert to a user, or clear the assignment when assigned_to is omitted
+// @Tags Asset Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset alert ID"
+// @Param request body dto.AssignAlertRequest true "Assignee"
+// @Success 200 {object} dto.AssetAlertResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-alerts/{id}/assign [patch]
+func (c *AssetAlertController) AssignAssetAlert(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.AssignAlertRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	alert, err := c.assetAlertService.AssignAssetAlert(ctx.Request.Context(), tenantUUID, id, userID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to assign asset alert")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alert)
+}
+
+// AddAssetAlertComment adds a comment to an asset alert
+// @Summary Comment on asset alert
+// @Description Add a comment to the timeline of an asset alert
+// @Tags Asset Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset alert ID"
+// @Param request body dto.AlertCommentRequest true "Comment"
+// @Success 201 {object} entity.AssetAlertEvent
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-alerts/{id}/comments [post]
+func (c *AssetAlertController) AddAssetAlertComment(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.AlertCommentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	event, err := c.assetAlertService.AddAlertComment(ctx.Request.Context(), tenantUUID, id, userID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to add alert comment")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, event)
+}
+
+// GetAssetAlertTimeline retrieves the timeline of an asset alert
+// @Summary Get asset alert timeline
+// @Description Get the lifecycle events and comments of an asset alert in chronological order
+// @Tags Asset Alerts
+// @Produce json
+// @Param id path string true "Asset alert ID"
+// @Success 200 {object} dto.AssetAlertTimelineResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /asset-alerts/{id}/timeline [get]
+func (c *AssetAlertController) GetAssetAlertTimeline(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	timeline, err := c.assetAlertService.GetAlertTimeline(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get alert timeline")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, timeline)
 }
 
 // GetAlertStatistics retrieves alert statistics for a tenant
@@ -396,7 +396,7 @@ func (c *AssetSensorController) UpdateSensorReading(ctx *gin.Context) {
 		return
 	}
 
-	err = c.assetSensorService.UpdateSensorReading(ctx, id, &req)
+	updated, err := c.assetSensorService.UpdateSensorReading(ctx, id, &req)
 	if err != nil {
 		if common.IsNotFoundError(err) {
 			ctx.JSON(http.StatusNotFound, gin.H{
@@ -412,6 +412,14 @@ func (c *AssetSensorController) UpdateSensorReading(ctx *gin.Context) {
 		return
 	}
 
+	if !updated {
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "Sensor reading is older than the last reading and was not applied",
+			"late":    true,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Sensor reading updated successfully",
 	})
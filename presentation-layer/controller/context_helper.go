@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/helpers/common"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// tenantIDFromContext reads the tenant ID set by the auth middleware, which may be
+// stored as a uuid.UUID or a string. It writes a 400 response and returns false
+// when the tenant is missing or malformed.
+func tenantIDFromContext(ctx *gin.Context) (uuid.UUID, bool) {
+	tenantID, exists := ctx.Get("tenant_id")
+	if !exists {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Tenant ID not found",
+			Message: "Tenant ID is required",
+		})
+		return uuid.Nil, false
+	}
+
+	switch t := tenantID.(type) {
+	case uuid.UUID:
+		return t, true
+	case string:
+		if tenantUUID, err := uuid.Parse(t); err == nil {
+			return tenantUUID, true
+		}
+	}
+
+	ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+		Error:   "Invalid tenant ID format",
+		Message: "Tenant ID must be a valid UUID",
+	})
+	return uuid.Nil, false
+}
+
+// respondServiceError maps service errors to HTTP responses
+func respondServiceError(ctx *gin.Context, err error, fallback string) {
+	switch {
+	case common.IsNotFoundError(err):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+	case common.IsValidationError(err):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse{
+			Error:   fallback,
+			Message: err.Error(),
+		})
+	}
+}
+
+// idParam parses the :id path parameter, writing a 400 response when it is not a UUID
+func idParam(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid UUID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// optionalUUIDQuery parses an optional UUID query parameter, writing a 400 response
+// when it is present but malformed
+func optionalUUIDQuery(ctx *gin.Context, name string) (*uuid.UUID, bool) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid " + name + " format",
+			Message: name + " must be a valid UUID",
+		})
+		return nil, false
+	}
+	return &id, true
+}
+
+// optionalUserIDFromContext reads the user ID set by the auth middleware, returning
+// nil when it is missing or not a UUID
+func optionalUserIDFromContext(ctx *gin.Context) *uuid.UUID {
+	value, exists := ctx.Get("user_id")
+	if !exists {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case uuid.UUID:
+		return &v
+	case string:
+		if userUUID, err := uuid.Parse(v); err == nil {
+			return &userUUID
+		}
+	}
+	return nil
+}
+
+// userIDFromContext reads the user ID set by the auth middleware, writing a 401
+// response when it is missing or malformed
+func userIDFromContext(ctx *gin.Context) (uuid.UUID, bool) {
+	userID := optionalUserIDFromContext(ctx)
+	if userID == nil {
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID is required",
+		})
+		return uuid.Nil, false
+	}
+	return *userID, true
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CSVMappingProfileController handles HTTP requests for CSV mapping profiles and CSV imports
+type CSVMappingProfileController struct {
+	profileService *service.CSVMappingProfileService
+}
+
+// NewCSVMappingProfileController creates a new CSV mapping profile controller
+func NewCSVMappingProfileController(profileService *service.CSVMappingProfileService) *CSVMappingProfileController {
+	return &CSVMappingProfileController{
+		profileService: profileService,
+	}
+}
+
+// CreateProfile creates a CSV mapping profile
+// @Summary Create CSV mapping profile
+// @Description Create a profile mapping the columns of a sensor type's CSV exports to measurement fields
+// @Tags CSV Mapping Profiles
+// @Accept json
+// @Produce json
+// @Param request body dto.CSVMappingProfileRequest true "CSV mapping profile"
+// @Success 201 {object} entity.CSVMappingProfile
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles [post]
+func (c *CSVMappingProfileController) CreateProfile(ctx *gin.Context) {
+	var request dto.CSVMappingProfileRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	profile, err := c.profileService.CreateProfile(ctx.Request.Context(), request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create CSV mapping profile")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, profile)
+}
+
+// ListProfiles lists CSV mapping profiles
+// @Summary List CSV mapping profiles
+// @Description Get a paginated list of CSV mapping profiles, optionally of one sensor type
+// @Tags CSV Mapping Profiles
+// @Produce json
+// @Param sensor_type_id query string false "Sensor type ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.CSVMappingProfileListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles [get]
+func (c *CSVMappingProfileController) ListProfiles(ctx *gin.Context) {
+	sensorTypeID, ok := optionalUUIDQuery(ctx, "sensor_type_id")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	response, err := c.profileService.ListProfiles(ctx.Request.Context(), sensorTypeID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list CSV mapping profiles")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetProfile retrieves a CSV mapping profile by ID
+// @Summary Get CSV mapping profile
+// @Description Get a CSV mapping profile by its ID
+// @Tags CSV Mapping Profiles
+// @Produce json
+// @Param id path string true "CSV mapping profile ID"
+// @Success 200 {object} entity.CSVMappingProfile
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles/{id} [get]
+func (c *CSVMappingProfileController) GetProfile(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := c.profileService.GetProfile(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get CSV mapping profile")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile updates a CSV mapping profile
+// @Summary Update CSV mapping profile
+// @Description Update a CSV mapping profile. The sensor type of a profile can't change.
+// @Tags CSV Mapping Profiles
+// @Accept json
+// @Produce json
+// @Param id path string true "CSV mapping profile ID"
+// @Param request body dto.CSVMappingProfileRequest true "CSV mapping profile"
+// @Success 200 {object} entity.CSVMappingProfile
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles/{id} [put]
+func (c *CSVMappingProfileController) UpdateProfile(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CSVMappingProfileRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	profile, err := c.profileService.UpdateProfile(ctx.Request.Context(), id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update CSV mapping profile")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// DeleteProfile deletes a CSV mapping profile
+// @Summary Delete CSV mapping profile
+// @Description Delete a CSV mapping profile. Readings imported with it are kept.
+// @Tags CSV Mapping Profiles
+// @Produce json
+// @Param id path string true "CSV mapping profile ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles/{id} [delete]
+func (c *CSVMappingProfileController) DeleteProfile(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.profileService.DeleteProfile(ctx.Request.Context(), id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete CSV mapping profile")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Import imports a CSV file with a mapping profile
+// @Summary Import CSV readings
+// @Description Stream a CSV file into readings using the profile's column mapping. Rows are validated against the measurement fields and rejected rows are reported with their line number. With dry_run=true nothing is stored and the first readings are previewed.
+// @Tags CSV Mapping Profiles
+// @Accept text/csv
+// @Produce json
+// @Param id path string true "CSV mapping profile ID"
+// @Param asset_sensor_id query string false "Asset sensor of rows without an asset sensor column"
+// @Param dry_run query bool false "Only validate and preview the rows"
+// @Param backfill query bool false "Historical data: don't evaluate alerts"
+// @Success 200 {object} dto.ReadingStreamResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/csv-mapping-profiles/{id}/import [post]
+func (c *CSVMappingProfileController) Import(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+	opts, ok := readingStreamOptions(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.profileService.Import(ctx.Request.Context(), id, ctx.Request.Body, opts)
+	if err != nil {
+		if result != nil {
+			// Chunks stored before the error stay stored, so report them too
+			status := http.StatusInternalServerError
+			if common.IsValidationError(err) {
+				status = http.StatusBadRequest
+			}
+			ctx.JSON(status, gin.H{
+				"error":   "Import stopped",
+				"message": err.Error(),
+				"data":    result,
+			})
+			return
+		}
+		respondServiceError(ctx, err, "Failed to import CSV readings")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
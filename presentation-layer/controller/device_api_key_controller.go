@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceAPIKeyController handles HTTP requests for device API key management
+type DeviceAPIKeyController struct {
+	deviceAPIKeyService *service.DeviceAPIKeyService
+}
+
+// NewDeviceAPIKeyController creates a new device API key controller
+func NewDeviceAPIKeyController(deviceAPIKeyService *service.DeviceAPIKeyService) *DeviceAPIKeyController {
+	return &DeviceAPIKeyController{
+		deviceAPIKeyService: deviceAPIKeyService,
+	}
+}
+
+// IssueDeviceAPIKey issues a new device API key
+// @Summary Issue device API key
+// @Description Issue an API key for an asset sensor, or a gateway key when asset_sensor_id is omitted. The plaintext key is only returned once.
+// @Tags Device API Keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateDeviceAPIKeyRequest true "Device API key"
+// @Success 201 {object} dto.DeviceAPIKeySecretResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/device-api-keys [post]
+func (c *DeviceAPIKeyController) IssueDeviceAPIKey(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.CreateDeviceAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.deviceAPIKeyService.IssueKey(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to issue device API key")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListDeviceAPIKeys lists device API keys for the tenant
+// @Summary List device API keys
+// @Description Get a paginated list of device API keys for a tenant
+// @Tags Device API Keys
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Param asset_sensor_id query string false "Filter by asset sensor ID"
+// @Success 200 {object} dto.DeviceAPIKeyListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/device-api-keys [get]
+func (c *DeviceAPIKeyController) ListDeviceAPIKeys(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	var assetSensorID *uuid.UUID
+	if assetSensorIDStr := ctx.Query("asset_sensor_id"); assetSensorIDStr != "" {
+		id, err := uuid.Parse(assetSensorIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid asset sensor ID format",
+				Message: "Asset sensor ID must be a valid UUID",
+			})
+			return
+		}
+		assetSensorID = &id
+	}
+
+	response, err := c.deviceAPIKeyService.ListKeys(ctx.Request.Context(), tenantUUID, assetSensorID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list device API keys")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetDeviceAPIKey retrieves a device API key by ID
+// @Summary Get device API key
+// @Description Get a device API key by its ID (the secret is never returned)
+// @Tags Device API Keys
+// @Produce json
+// @Param id path string true "Device API key ID"
+// @Success 200 {object} dto.DeviceAPIKeyResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/device-api-keys/{id} [get]
+func (c *DeviceAPIKeyController) GetDeviceAPIKey(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid UUID",
+		})
+		return
+	}
+
+	response, err := c.deviceAPIKeyService.GetKey(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get device API key")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RotateDeviceAPIKey rotates the secret of a device API key
+// @Summary Rotate device API key
+// @Description Replace the secret of a device API key. The previous key stops working immediately.
+// @Tags Device API Keys
+// @Produce json
+// @Param id path string true "Device API key ID"
+// @Success 200 {object} dto.DeviceAPIKeySecretResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/device-api-keys/{id}/rotate [post]
+func (c *DeviceAPIKeyController) RotateDeviceAPIKey(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid UUID",
+		})
+		return
+	}
+
+	response, err := c.deviceAPIKeyService.RotateKey(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to rotate device API key")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RevokeDeviceAPIKey revokes a device API key
+// @Summary Revoke device API key
+// @Description Permanently revoke a device API key
+// @Tags Device API Keys
+// @Produce json
+// @Param id path string true "Device API key ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/device-api-keys/{id} [delete]
+func (c *DeviceAPIKeyController) RevokeDeviceAPIKey(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid ID format",
+			Message: "ID must be a valid UUID",
+		})
+		return
+	}
+
+	if err := c.deviceAPIKeyService.RevokeKey(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to revoke device API key")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceIngestionController handles sensor reading ingestion from devices
+// authenticated with a device API key
+type DeviceIngestionController struct {
+	iotSensorReadingService *service.IoTSensorReadingService
+	deviceAPIKeyService     *service.DeviceAPIKeyService
+	payloadDecoderService   *service.PayloadDecoderService
+}
+
+// NewDeviceIngestionController creates a new device ingestion controller
+func NewDeviceIngestionController(
+	iotSensorReadingService *service.IoTSensorReadingService,
+	deviceAPIKeyService *service.DeviceAPIKeyService,
+	payloadDecoderService *service.PayloadDecoderService,
+) *DeviceIngestionController {
+	return &DeviceIngestionController{
+		iotSensorReadingService: iotSensorReadingService,
+		deviceAPIKeyService:     deviceAPIKeyService,
+		payloadDecoderService:   payloadDecoderService,
+	}
+}
+
+// IngestReading stores a flexible reading pushed by a device
+// @Summary Ingest sensor reading
+// @Description Store a flexible sensor reading using a device API key. Sensor keys may omit asset_sensor_id; gateway keys must provide it.
+// @Tags Device Ingestion
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Device API key"
+// @Param Idempotency-Key header string false "Message ID, used when the body has no message_id"
+// @Param request body dto.FlexibleIoTSensorReadingRequest true "Reading"
+// @Success 201 {object} dto.IoTSensorReadingResponse
+// @Success 200 {object} dto.IoTSensorReadingResponse "Message was already stored"
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingest/readings [post]
+func (c *DeviceIngestionController) IngestReading(ctx *gin.Context) {
+	key, ok := deviceAPIKeyFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	// Decoded without binding validation: sensor keys don't need to send identifiers
+	var req dto.FlexibleIoTSensorReadingRequest
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := c.deviceAPIKeyService.ScopeReadingRequest(ctx.Request.Context(), key, &req); err != nil {
+		respondServiceError(ctx, err, "Failed to ingest reading")
+		return
+	}
+
+	applyIdempotencyKey(ctx, &req)
+	reading, err := c.iotSensorReadingService.CreateFlexibleIoTSensorReading(ctx.Request.Context(), &req)
+	if err != nil {
+		log.Printf("Error ingesting reading with device API key %s: %v", key.KeyPrefix, err)
+		respondServiceError(ctx, err, "Failed to ingest reading")
+		return
+	}
+
+	ctx.JSON(readingCreatedStatus(reading), reading)
+}
+
+// IngestRawReading decodes and stores a vendor specific payload pushed by a device
+// @Summary Ingest raw sensor payload
+// @Description Decode a raw payload (vendor JSON, base64 or hex frame) with the payload decoder of the sensor's type and store it as a flexible reading. Gateway keys must pass asset_sensor_id.
+// @Tags Device Ingestion
+// @Accept plain
+// @Produce json
+// @Param X-API-Key header string true "Device API key"
+// @Param Idempotency-Key header string false "Message ID, used when the payload has none"
+// @Param asset_sensor_id query string false "Asset sensor, required for gateway keys"
+// @Param payload body string true "Raw payload"
+// @Success 201 {object} dto.IoTSensorReadingResponse
+// @Success 200 {object} dto.IoTSensorReadingResponse "Message was already stored"
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingest/readings/raw [post]
+func (c *DeviceIngestionController) IngestRawReading(ctx *gin.Context) {
+	key, ok := deviceAPIKeyFromContext(ctx)
+	if !ok {
+		return
+	}
+	assetSensorID, ok := optionalUUIDQuery(ctx, "asset_sensor_id")
+	if !ok {
+		return
+	}
+	payload, ok := rawPayload(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.FlexibleIoTSensorReadingRequest
+	if assetSensorID != nil {
+		req.AssetSensorID = *assetSensorID
+	}
+	if err := c.deviceAPIKeyService.ScopeReadingRequest(ctx.Request.Context(), key, &req); err != nil {
+		respondServiceError(ctx, err, "Failed to ingest reading")
+		return
+	}
+
+	decoded, err := c.payloadDecoderService.DecodeReading(ctx.Request.Context(), &req, payload)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to decode payload")
+		return
+	}
+	if !decoded {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Validation failed",
+			Message: "the sensor type of this asset sensor has no active payload decoder",
+		})
+		return
+	}
+
+	applyIdempotencyKey(ctx, &req)
+	reading, err := c.iotSensorReadingService.CreateFlexibleIoTSensorReading(ctx.Request.Context(), &req)
+	if err != nil {
+		log.Printf("Error ingesting raw payload with device API key %s: %v", key.KeyPrefix, err)
+		respondServiceError(ctx, err, "Failed to ingest reading")
+		return
+	}
+
+	ctx.JSON(readingCreatedStatus(reading), reading)
+}
+
+// IngestBulkReadings stores several flexible readings pushed by a device or gateway
+// @Summary Ingest sensor readings in bulk
+// @Description Store multiple flexible sensor readings using a device API key
+// @Tags Device Ingestion
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Device API key"
+// @Param Idempotency-Key header string false "Batch key; readings without a message_id use the key and their position"
+// @Param request body dto.FlexibleBatchIoTSensorReadingRequest true "Readings"
+// @Success 201 {array} dto.IoTSensorReadingResponse
+// @Success 200 {array} dto.IoTSensorReadingResponse "All messages were already stored"
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /ingest/readings/bulk [post]
+func (c *DeviceIngestionController) IngestBulkReadings(ctx *gin.Context) {
+	key, ok := deviceAPIKeyFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.FlexibleBatchIoTSensorReadingRequest
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if len(req.Readings) == 0 || len(req.Readings) > 1000 {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: "readings must contain between 1 and 1000 items",
+		})
+		return
+	}
+
+	requests := make([]*dto.FlexibleIoTSensorReadingRequest, 0, len(req.Readings))
+	for i := range req.Readings {
+		if err := c.deviceAPIKeyService.ScopeReadingRequest(ctx.Request.Context(), key, &req.Readings[i]); err != nil {
+			respondServiceError(ctx, err, "Failed to ingest readings")
+			return
+		}
+		requests = append(requests, &req.Readings[i])
+	}
+
+	applyBatchIdempotencyKey(ctx, requests)
+	readings, err := c.iotSensorReadingService.CreateBulkFlexibleIoTSensorReadings(ctx.Request.Context(), requests)
+	if err != nil {
+		log.Printf("Error ingesting bulk readings with device API key %s: %v", key.KeyPrefix, err)
+		respondServiceError(ctx, err, "Failed to ingest readings")
+		return
+	}
+
+	ctx.JSON(readingCreatedStatus(readings...), readings)
+}
+
+// deviceAPIKeyFromContext returns the key set by DeviceAPIKeyMiddleware
+func deviceAPIKeyFromContext(ctx *gin.Context) (*entity.DeviceAPIKey, bool) {
+	value, exists := ctx.Get(middleware.DeviceAPIKeyContextKey)
+	key, ok := value.(*entity.DeviceAPIKey)
+	if !exists || !ok {
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Device API key is required",
+		})
+		return nil, false
+	}
+	return key, true
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EscalationController handles HTTP requests for alert escalation policies
+type EscalationController struct {
+	escalationService *service.EscalationService
+}
+
+// NewEscalationController creates a new escalation controller
+func NewEscalationController(escalationService *service.EscalationService) *EscalationController {
+	return &EscalationController{
+		escalationService: escalationService,
+	}
+}
+
+// CreatePolicy creates an escalation policy
+// @Summary Create escalation policy
+// @Description Create a policy escalating open, unacknowledged alerts to channels and users after the step delays
+// @Tags Escalation Policies
+// @Accept json
+// @Produce json
+// @Param request body dto.EscalationPolicyRequest true "Escalation policy"
+// @Success 201 {object} entity.EscalationPolicy
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/escalation-policies [post]
+func (c *EscalationController) CreatePolicy(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.EscalationPolicyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	policy, err := c.escalationService.CreatePolicy(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create escalation policy")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, policy)
+}
+
+// ListPolicies lists escalation policies for the tenant
+// @Summary List escalation policies
+// @Description Get a paginated list of escalation policies for a tenant
+// @Tags Escalation Policies
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.EscalationPolicyListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/escalation-policies [get]
+func (c *EscalationController) ListPolicies(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	response, err := c.escalationService.ListPolicies(ctx.Request.Context(), tenantUUID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list escalation policies")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetPolicy retrieves an escalation policy by ID
+// @Summary Get escalation policy
+// @Description Get an escalation policy by its ID
+// @Tags Escalation Policies
+// @Produce json
+// @Param id path string true "Escalation policy ID"
+// @Success 200 {object} entity.EscalationPolicy
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/escalation-policies/{id} [get]
+func (c *EscalationController) GetPolicy(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	policy, err := c.escalationService.GetPolicy(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get escalation policy")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy updates an escalation policy
+// @Summary Update escalation policy
+// @Description Update an escalation policy. Steps that already fired for open alerts are not repeated.
+// @Tags Escalation Policies
+// @Accept json
+// @Produce json
+// @Param id path string true "Escalation policy ID"
+// @Param request body dto.EscalationPolicyRequest true "Escalation policy"
+// @Success 200 {object} entity.EscalationPolicy
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/escalation-policies/{id} [put]
+func (c *EscalationController) UpdatePolicy(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.EscalationPolicyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	policy, err := c.escalationService.UpdatePolicy(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update escalation policy")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy deletes an escalation policy
+// @Summary Delete escalation policy
+// @Description Delete an escalation policy. The escalation history of alerts is kept.
+// @Tags Escalation Policies
+// @Produce json
+// @Param id path string true "Escalation policy ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/escalation-policies/{id} [delete]
+func (c *EscalationController) DeletePolicy(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.escalationService.DeletePolicy(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete escalation policy")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -123,6 +123,11 @@ func (c *IoTSensorReadingController) CreateBatchReading(ctx *gin.Context) {
 
 // GetReading handles GET /api/v1/iot-sensor-readings/:id
 func (c *IoTSensorReadingController) GetReading(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -149,6 +154,8 @@ func (c *IoTSensorReadingController) GetReading(ctx *gin.Context) {
 		return
 	}
 
+	units.Apply(reading.IoTSensorReadingResponse)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor reading retrieved successfully",
 		"data":    reading,
@@ -157,6 +164,11 @@ func (c *IoTSensorReadingController) GetReading(ctx *gin.Context) {
 
 // ListReadings handles GET /api/v1/iot-sensor-readings
 func (c *IoTSensorReadingController) ListReadings(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	// Parse pagination parameters
 	page := 1
 	pageSize := 10
@@ -187,6 +199,10 @@ func (c *IoTSensorReadingController) ListReadings(ctx *gin.Context) {
 		return
 	}
 
+	for i := range response.Readings {
+		units.Apply(response.Readings[i].IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor readings listed successfully",
 		"data":    response,
@@ -195,6 +211,11 @@ func (c *IoTSensorReadingController) ListReadings(ctx *gin.Context) {
 
 // ListAllReadings handles GET /api/v1/superadmin/iot-sensor-readings (SuperAdmin only)
 func (c *IoTSensorReadingController) ListAllReadings(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	// Parse pagination parameters
 	page := 1
 	pageSize := 10
@@ -225,6 +246,10 @@ func (c *IoTSensorReadingController) ListAllReadings(ctx *gin.Context) {
 		return
 	}
 
+	for i := range response.Readings {
+		units.Apply(response.Readings[i].IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "All IoT sensor readings listed successfully",
 		"data":    response,
@@ -233,6 +258,11 @@ func (c *IoTSensorReadingController) ListAllReadings(ctx *gin.Context) {
 
 // ListReadingsByAssetSensor handles GET /api/v1/iot-sensor-readings/by-asset-sensor/:asset_sensor_id
 func (c *IoTSensorReadingController) ListReadingsByAssetSensor(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	assetSensorIDParam := ctx.Param("asset_sensor_id")
 	assetSensorID, err := uuid.Parse(assetSensorIDParam)
 	if err != nil {
@@ -267,6 +297,10 @@ func (c *IoTSensorReadingController) ListReadingsByAssetSensor(ctx *gin.Context)
 		return
 	}
 
+	for _, reading := range response {
+		units.Apply(reading.IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor readings by asset sensor retrieved successfully",
 		"data":    response,
@@ -275,6 +309,11 @@ func (c *IoTSensorReadingController) ListReadingsByAssetSensor(ctx *gin.Context)
 
 // ListReadingsBySensorType handles GET /api/v1/iot-sensor-readings/by-sensor-type/:sensor_type_id
 func (c *IoTSensorReadingController) ListReadingsBySensorType(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	sensorTypeIDParam := ctx.Param("sensor_type_id")
 	sensorTypeID, err := uuid.Parse(sensorTypeIDParam)
 	if err != nil {
@@ -309,6 +348,10 @@ func (c *IoTSensorReadingController) ListReadingsBySensorType(ctx *gin.Context)
 		return
 	}
 
+	for _, reading := range response {
+		units.Apply(reading.IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor readings by sensor type retrieved successfully",
 		"data":    response,
@@ -317,6 +360,11 @@ func (c *IoTSensorReadingController) ListReadingsBySensorType(ctx *gin.Context)
 
 // ListReadingsByMacAddress handles GET /api/v1/iot-sensor-readings/by-mac-address/:mac_address
 func (c *IoTSensorReadingController) ListReadingsByMacAddress(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	macAddress := ctx.Param("mac_address")
 	if macAddress == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -350,6 +398,10 @@ func (c *IoTSensorReadingController) ListReadingsByMacAddress(ctx *gin.Context)
 		return
 	}
 
+	for _, reading := range response {
+		units.Apply(reading.IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor readings by MAC address retrieved successfully",
 		"data":    response,
@@ -358,6 +410,11 @@ func (c *IoTSensorReadingController) ListReadingsByMacAddress(ctx *gin.Context)
 
 // GetLatestByAssetSensor handles GET /api/v1/iot-sensor-readings/latest/by-asset-sensor/:asset_sensor_id
 func (c *IoTSensorReadingController) GetLatestByAssetSensor(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	assetSensorIDParam := ctx.Param("asset_sensor_id")
 	assetSensorID, err := uuid.Parse(assetSensorIDParam)
 	if err != nil {
@@ -384,6 +441,8 @@ func (c *IoTSensorReadingController) GetLatestByAssetSensor(ctx *gin.Context) {
 		return
 	}
 
+	units.Apply(reading.IoTSensorReadingResponse)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Latest IoT sensor reading by asset sensor retrieved successfully",
 		"data":    reading,
@@ -392,6 +451,11 @@ func (c *IoTSensorReadingController) GetLatestByAssetSensor(ctx *gin.Context) {
 
 // ListReadingsByTimeRange handles GET /api/v1/iot-sensor-readings/by-time-range
 func (c *IoTSensorReadingController) ListReadingsByTimeRange(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
 	assetSensorIDParam := ctx.Query("asset_sensor_id")
 	startTimeParam := ctx.Query("start_time")
@@ -471,6 +535,10 @@ func (c *IoTSensorReadingController) ListReadingsByTimeRange(ctx *gin.Context) {
 		return
 	}
 
+	for _, reading := range response {
+		units.Apply(reading.IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "IoT sensor readings by time range retrieved successfully",
 		"data":    response,
@@ -478,29 +546,60 @@ func (c *IoTSensorReadingController) ListReadingsByTimeRange(ctx *gin.Context) {
 }
 
 // GetAggregatedData handles GET /api/v1/iot-sensor-readings/aggregated
+// Without asset_sensor_id the tenant's readings are aggregated across sensors, optionally
+// filtered by asset, asset type, location or sensor type and grouped by group_by.
+// interval takes hour, day, week, month or a length such as 15m; buckets are aligned to
+// timezone and empty ones reported by fill.
 func (c *IoTSensorReadingController) GetAggregatedData(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
-	assetSensorIDParam := ctx.Query("asset_sensor_id")
 	startTimeParam := ctx.Query("start_time")
 	endTimeParam := ctx.Query("end_time")
 	intervalParam := ctx.Query("interval")
 
 	// Validate required parameters
-	if assetSensorIDParam == "" || startTimeParam == "" || endTimeParam == "" {
+	if startTimeParam == "" || endTimeParam == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
-			"message": "asset_sensor_id, start_time, and end_time are required",
+			"message": "start_time and end_time are required",
 		})
 		return
 	}
 
-	assetSensorID, err := uuid.Parse(assetSensorIDParam)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Bad Request",
-			"message": "Invalid asset sensor ID format",
-		})
-		return
+	filters := make(map[string]*uuid.UUID)
+	for _, name := range []string{"asset_sensor_id", "asset_id", "asset_type_id", "location_id", "sensor_type_id"} {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": fmt.Sprintf("Invalid %s format", name),
+			})
+			return
+		}
+		filters[name] = &id
+	}
+
+	var percentiles []float64
+	for _, param := range ctx.QueryArray("percentile") {
+		for _, value := range strings.Split(param, ",") {
+			percentile, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Bad Request",
+					"message": fmt.Sprintf("Invalid percentile %q", value),
+				})
+				return
+			}
+			percentiles = append(percentiles, percentile)
+		}
 	}
 
 	startTime, err := time.Parse(time.RFC3339, startTimeParam)
@@ -528,11 +627,21 @@ func (c *IoTSensorReadingController) GetAggregatedData(ctx *gin.Context) {
 	}
 
 	req := &dto.GetAggregatedDataRequest{
-		AssetSensorID: &assetSensorID,
+		TenantID:      &tenantUUID,
+		AssetSensorID: filters["asset_sensor_id"],
+		AssetID:       filters["asset_id"],
+		AssetTypeID:   filters["asset_type_id"],
+		LocationID:    filters["location_id"],
+		SensorTypeID:  filters["sensor_type_id"],
 		FromTime:      startTime,
 		ToTime:        endTime,
 		Interval:      intervalStr,
-		AggregateBy:   []string{}, // Could be populated from query params if needed
+		Timezone:      ctx.Query("timezone"),
+		Fill:          ctx.Query("fill"),
+		AggregateBy:   ctx.QueryArray("field"),
+		Units:         ctx.QueryArray("unit"),
+		GroupBy:       ctx.Query("group_by"),
+		Percentiles:   percentiles,
 	}
 
 	response, err := c.iotSensorReadingService.GetAggregatedData(ctx, req)
@@ -1007,6 +1116,7 @@ func (c *IoTSensorReadingController) CreateFlexibleReading(ctx *gin.Context) {
 
 	log.Printf("Creating flexible IoT sensor reading with request: %+v", req)
 
+	applyIdempotencyKey(ctx, &req)
 	reading, err := c.iotSensorReadingService.CreateFlexibleIoTSensorReading(ctx, &req)
 	if err != nil {
 		log.Printf("Error creating flexible IoT sensor reading: %v", err)
@@ -1024,7 +1134,7 @@ func (c *IoTSensorReadingController) CreateFlexibleReading(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	ctx.JSON(readingCreatedStatus(reading), gin.H{
 		"message": "Flexible IoT sensor reading created successfully",
 		"data":    reading,
 	})
@@ -1050,6 +1160,7 @@ func (c *IoTSensorReadingController) CreateBulkFlexibleReadings(ctx *gin.Context
 		requests = append(requests, &req.Readings[i])
 	}
 
+	applyBatchIdempotencyKey(ctx, requests)
 	readings, err := c.iotSensorReadingService.CreateBulkFlexibleIoTSensorReadings(ctx, requests)
 	if err != nil {
 		log.Printf("Error creating bulk flexible IoT sensor readings: %v", err)
@@ -1067,13 +1178,91 @@ func (c *IoTSensorReadingController) CreateBulkFlexibleReadings(ctx *gin.Context
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	ctx.JSON(readingCreatedStatus(readings...), gin.H{
 		"message": fmt.Sprintf("Successfully created %d flexible IoT sensor readings", len(readings)),
 		"data":    readings,
 		"count":   len(readings),
 	})
 }
 
+// StreamFlexibleReadings handles POST /api/v1/superadmin/iot-sensor-readings/flexible/stream
+// The body is NDJSON or CSV, chosen by the format query parameter or the Content-Type.
+// asset_sensor_id sets the asset sensor of lines that don't name one, backfill=true
+// imports historical data without evaluating alerts and dry_run=true only validates.
+func (c *IoTSensorReadingController) StreamFlexibleReadings(ctx *gin.Context) {
+	format := strings.ToLower(ctx.Query("format"))
+	if format == "" {
+		switch ctx.ContentType() {
+		case "text/csv":
+			format = string(service.ReadingStreamCSV)
+		case "application/x-ndjson", "application/jsonl", "application/json":
+			format = string(service.ReadingStreamNDJSON)
+		}
+	}
+	opts, ok := readingStreamOptions(ctx)
+	if !ok {
+		return
+	}
+	opts.Format = service.ReadingStreamFormat(format)
+
+	result, err := c.iotSensorReadingService.IngestReadingStream(ctx, ctx.Request.Body, opts)
+	if err != nil {
+		log.Printf("Error streaming flexible IoT sensor readings: %v", err)
+		// Chunks stored before the error stay stored, so report them too
+		if common.IsValidationError(err) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+				"data":    result,
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to stream flexible readings",
+			"data":    result,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Stored %d readings from %d lines, rejected %d lines", result.ReadingsStored, result.LinesStored, result.LinesRejected),
+		"data":    result,
+	})
+}
+
+// readingStreamOptions parses the asset_sensor_id, backfill and dry_run query parameters
+// of a streamed reading import, writing a 400 response when one is malformed
+func readingStreamOptions(ctx *gin.Context) (service.ReadingStreamOptions, bool) {
+	var opts service.ReadingStreamOptions
+
+	assetSensorID, ok := optionalUUIDQuery(ctx, "asset_sensor_id")
+	if !ok {
+		return opts, false
+	}
+	if assetSensorID != nil {
+		opts.AssetSensorID = *assetSensorID
+	}
+
+	for name, target := range map[string]*bool{"backfill": &opts.Backfill, "dry_run": &opts.DryRun} {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid " + name + " value",
+				Message: name + " must be true or false",
+			})
+			return opts, false
+		}
+		*target = parsed
+	}
+
+	return opts, true
+}
+
 // ParseTextToFlexible handles POST /api/v1/superadmin/iot-sensor-readings/parse-text
 func (c *IoTSensorReadingController) ParseTextToFlexible(ctx *gin.Context) {
 	var req dto.TextToJSONRequest
@@ -1113,6 +1302,11 @@ func (c *IoTSensorReadingController) ParseTextToFlexible(ctx *gin.Context) {
 
 // GetFlexibleReading handles GET /api/v1/superadmin/iot-sensor-readings/flexible/:id
 func (c *IoTSensorReadingController) GetFlexibleReading(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	idParam := ctx.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -1140,6 +1334,8 @@ func (c *IoTSensorReadingController) GetFlexibleReading(ctx *gin.Context) {
 		return
 	}
 
+	units.Apply(reading.IoTSensorReadingResponse)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Flexible IoT sensor reading retrieved successfully",
 		"data":    reading,
@@ -1172,6 +1368,7 @@ func (c *IoTSensorReadingController) CreateFromRawJSON(ctx *gin.Context) {
 		return
 	}
 
+	applyIdempotencyKey(ctx, &flexibleReq)
 	reading, err := c.iotSensorReadingService.CreateFlexibleIoTSensorReading(ctx, &flexibleReq)
 	if err != nil {
 		log.Printf("Error creating IoT sensor reading from raw JSON: %v", err)
@@ -1189,7 +1386,7 @@ func (c *IoTSensorReadingController) CreateFromRawJSON(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	ctx.JSON(readingCreatedStatus(reading), gin.H{
 		"message": "IoT sensor reading created from raw JSON successfully",
 		"data":    reading,
 	})
@@ -1249,6 +1446,7 @@ func (c *IoTSensorReadingController) CreateFromArrayJSON(ctx *gin.Context) {
 		return
 	}
 
+	applyBatchIdempotencyKey(ctx, requests)
 	readings, err := c.iotSensorReadingService.CreateBulkFlexibleIoTSensorReadings(ctx, requests)
 	if err != nil {
 		log.Printf("Error creating IoT sensor readings from JSON array: %v", err)
@@ -1266,7 +1464,7 @@ func (c *IoTSensorReadingController) CreateFromArrayJSON(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
+	ctx.JSON(readingCreatedStatus(readings...), gin.H{
 		"message": fmt.Sprintf("Successfully created %d IoT sensor readings from JSON array", len(readings)),
 		"data":    readings,
 		"count":   len(readings),
@@ -1275,6 +1473,11 @@ func (c *IoTSensorReadingController) CreateFromArrayJSON(ctx *gin.Context) {
 
 // ListFlexibleReadings handles GET /api/v1/superadmin/iot-sensor-readings/flexible
 func (c *IoTSensorReadingController) ListFlexibleReadings(ctx *gin.Context) {
+	units, ok := unitTargetsQuery(ctx)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
 	var req dto.IoTSensorReadingListRequest
 
@@ -1348,6 +1551,10 @@ func (c *IoTSensorReadingController) ListFlexibleReadings(ctx *gin.Context) {
 		return
 	}
 
+	for i := range readings.Readings {
+		units.Apply(readings.Readings[i].IoTSensorReadingResponse)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Flexible IoT sensor readings retrieved successfully",
 		"data":    readings,
@@ -1446,3 +1653,82 @@ func (c *IoTSensorReadingController) GetAutoPopulationOptions(ctx *gin.Context)
 		"data":    options,
 	})
 }
+
+// GetAlertEvaluationStats handles GET /api/v1/superadmin/iot-sensor-readings/alert-evaluation/stats
+func (c *IoTSensorReadingController) GetAlertEvaluationStats(ctx *gin.Context) {
+	stats := c.iotSensorReadingService.GetAlertEvaluationStats()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Alert evaluation statistics retrieved successfully",
+		"data":    stats,
+	})
+}
+
+// GetReadingOutboxStats handles GET /api/v1/superadmin/iot-sensor-readings/outbox/stats
+func (c *IoTSensorReadingController) GetReadingOutboxStats(ctx *gin.Context) {
+	stats, err := c.iotSensorReadingService.GetReadingOutboxStats(ctx)
+	if err != nil {
+		log.Printf("Error getting reading outbox stats: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to get reading outbox statistics",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Reading outbox statistics retrieved successfully",
+		"data":    stats,
+	})
+}
+
+// idempotencyKeyHeader lets clients send a reading's message ID as a header
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// applyIdempotencyKey uses the Idempotency-Key header as the message ID of a reading
+// that doesn't carry one in its body
+func applyIdempotencyKey(ctx *gin.Context, req *dto.FlexibleIoTSensorReadingRequest) {
+	if key := ctx.GetHeader(idempotencyKeyHeader); key != "" && req.MessageID == "" {
+		req.MessageID = key
+	}
+}
+
+// applyBatchIdempotencyKey derives a message ID for every reading of a batch that doesn't
+// carry one from the Idempotency-Key header and the reading's position, so retrying the
+// same batch stores each reading once
+func applyBatchIdempotencyKey(ctx *gin.Context, requests []*dto.FlexibleIoTSensorReadingRequest) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		return
+	}
+	for i, req := range requests {
+		if req.MessageID == "" {
+			req.MessageID = fmt.Sprintf("%s:%d", key, i)
+		}
+	}
+}
+
+// readingCreatedStatus is 201 when a request stored new readings and 200 when all of its
+// messages were already stored
+func readingCreatedStatus(readings ...*dto.IoTSensorReadingResponse) int {
+	for _, reading := range readings {
+		if !reading.Duplicate {
+			return http.StatusCreated
+		}
+	}
+	return http.StatusOK
+}
+
+// unitTargetsQuery parses the unit query parameters readings are converted to, writing
+// a 400 response when a unit is unknown
+func unitTargetsQuery(ctx *gin.Context) (service.UnitTargets, bool) {
+	targets, err := service.ParseUnitTargets(ctx.QueryArray("unit"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid unit",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return targets, true
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceController handles HTTP requests for maintenance windows
+type MaintenanceController struct {
+	maintenanceService *service.MaintenanceService
+}
+
+// NewMaintenanceController creates a new maintenance controller
+func NewMaintenanceController(maintenanceService *service.MaintenanceService) *MaintenanceController {
+	return &MaintenanceController{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// CreateWindow creates a maintenance window
+// @Summary Create maintenance window
+// @Description Schedule a one-off or recurring maintenance window for an asset, location or asset type that suppresses or tags alerts
+// @Tags Maintenance Windows
+// @Accept json
+// @Produce json
+// @Param request body dto.MaintenanceWindowRequest true "Maintenance window"
+// @Success 201 {object} dto.MaintenanceWindowResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/maintenance-windows [post]
+func (c *MaintenanceController) CreateWindow(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.MaintenanceWindowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.maintenanceService.CreateWindow(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create maintenance window")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListWindows lists maintenance windows for the tenant
+// @Summary List maintenance windows
+// @Description Get a paginated list of maintenance windows for a tenant
+// @Tags Maintenance Windows
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Param asset_id query string false "Only windows scoped to this asset"
+// @Success 200 {object} dto.MaintenanceWindowListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/maintenance-windows [get]
+func (c *MaintenanceController) ListWindows(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	assetID, ok := optionalUUIDQuery(ctx, "asset_id")
+	if !ok {
+		return
+	}
+
+	response, err := c.maintenanceService.ListWindows(ctx.Request.Context(), tenantUUID, assetID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list maintenance windows")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetWindow retrieves a maintenance window by ID
+// @Summary Get maintenance window
+// @Description Get a maintenance window by its ID
+// @Tags Maintenance Windows
+// @Produce json
+// @Param id path string true "Maintenance window ID"
+// @Success 200 {object} dto.MaintenanceWindowResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/maintenance-windows/{id} [get]
+func (c *MaintenanceController) GetWindow(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.maintenanceService.GetWindow(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get maintenance window")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateWindow updates a maintenance window
+// @Summary Update maintenance window
+// @Description Update a maintenance window. Asset statuses are re-synchronised immediately.
+// @Tags Maintenance Windows
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance window ID"
+// @Param request body dto.MaintenanceWindowRequest true "Maintenance window"
+// @Success 200 {object} dto.MaintenanceWindowResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/maintenance-windows/{id} [put]
+func (c *MaintenanceController) UpdateWindow(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.MaintenanceWindowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.maintenanceService.UpdateWindow(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update maintenance window")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteWindow deletes a maintenance window
+// @Summary Delete maintenance window
+// @Description Delete a maintenance window and restore the asset statuses it changed
+// @Tags Maintenance Windows
+// @Produce json
+// @Param id path string true "Maintenance window ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/maintenance-windows/{id} [delete]
+func (c *MaintenanceController) DeleteWindow(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.maintenanceService.DeleteWindow(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete maintenance window")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
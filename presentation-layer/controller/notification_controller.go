@@ -0,0 +1,441 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController handles HTTP requests for alert notification channels,
+// routing rules and the delivery log
+type NotificationController struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notificationService *service.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// CreateChannel creates a notification channel
+// @Summary Create notification channel
+// @Description Create a webhook, email or Slack-compatible notification channel
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationChannelRequest true "Notification channel"
+// @Success 201 {object} dto.NotificationChannelResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels [post]
+func (c *NotificationController) CreateChannel(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.NotificationChannelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.notificationService.CreateChannel(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create notification channel")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListChannels lists notification channels for the tenant
+// @Summary List notification channels
+// @Description Get a paginated list of notification channels for a tenant
+// @Tags Notifications
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.NotificationChannelListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels [get]
+func (c *NotificationController) ListChannels(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	response, err := c.notificationService.ListChannels(ctx.Request.Context(), tenantUUID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list notification channels")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetChannel retrieves a notification channel by ID
+// @Summary Get notification channel
+// @Description Get a notification channel by its ID (secrets are redacted)
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification channel ID"
+// @Success 200 {object} dto.NotificationChannelResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels/{id} [get]
+func (c *NotificationController) GetChannel(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.notificationService.GetChannel(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get notification channel")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateChannel updates a notification channel
+// @Summary Update notification channel
+// @Description Replace the settings of a notification channel. Empty secrets keep the stored values.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification channel ID"
+// @Param request body dto.NotificationChannelRequest true "Notification channel"
+// @Success 200 {object} dto.NotificationChannelResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels/{id} [put]
+func (c *NotificationController) UpdateChannel(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.NotificationChannelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.notificationService.UpdateChannel(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update notification channel")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteChannel deletes a notification channel
+// @Summary Delete notification channel
+// @Description Delete a notification channel together with its rules and delivery log
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification channel ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels/{id} [delete]
+func (c *NotificationController) DeleteChannel(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.notificationService.DeleteChannel(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete notification channel")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// TestChannel sends a test notification through a channel
+// @Summary Test notification channel
+// @Description Send a test notification synchronously and report whether it was delivered
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification channel ID"
+// @Success 200 {object} dto.NotificationTestResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-channels/{id}/test [post]
+func (c *NotificationController) TestChannel(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.notificationService.TestChannel(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to test notification channel")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateRule creates a notification rule
+// @Summary Create notification rule
+// @Description Route alerts matching severity, asset and asset type filters to a channel
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationRuleRequest true "Notification rule"
+// @Success 201 {object} entity.NotificationRule
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-rules [post]
+func (c *NotificationController) CreateRule(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.NotificationRuleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := c.notificationService.CreateRule(ctx.Request.Context(), tenantUUID, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create notification rule")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+// ListRules lists notification rules for the tenant
+// @Summary List notification rules
+// @Description Get a paginated list of notification rules for a tenant
+// @Tags Notifications
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Param channel_id query string false "Filter by channel ID"
+// @Success 200 {object} dto.NotificationRuleListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-rules [get]
+func (c *NotificationController) ListRules(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	channelID, ok := optionalUUIDQuery(ctx, "channel_id")
+	if !ok {
+		return
+	}
+
+	response, err := c.notificationService.ListRules(ctx.Request.Context(), tenantUUID, channelID, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list notification rules")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetRule retrieves a notification rule by ID
+// @Summary Get notification rule
+// @Description Get a notification rule by its ID
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification rule ID"
+// @Success 200 {object} entity.NotificationRule
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-rules/{id} [get]
+func (c *NotificationController) GetRule(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	rule, err := c.notificationService.GetRule(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get notification rule")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule updates a notification rule
+// @Summary Update notification rule
+// @Description Replace the settings of a notification rule
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification rule ID"
+// @Param request body dto.NotificationRuleRequest true "Notification rule"
+// @Success 200 {object} entity.NotificationRule
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-rules/{id} [put]
+func (c *NotificationController) UpdateRule(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.NotificationRuleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := c.notificationService.UpdateRule(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update notification rule")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule deletes a notification rule
+// @Summary Delete notification rule
+// @Description Delete a notification rule
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification rule ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-rules/{id} [delete]
+func (c *NotificationController) DeleteRule(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.notificationService.DeleteRule(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete notification rule")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries lists the notification delivery log for the tenant
+// @Summary List notification deliveries
+// @Description Get the paginated notification delivery log, newest first
+// @Tags Notifications
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Param channel_id query string false "Filter by channel ID"
+// @Param alert_id query string false "Filter by alert ID"
+// @Param status query string false "Filter by status (pending, sending, sent, failed)"
+// @Success 200 {object} dto.NotificationDeliveryListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/notification-deliveries [get]
+func (c *NotificationController) ListDeliveries(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	var filter repository.NotificationDeliveryFilter
+	if filter.ChannelID, ok = optionalUUIDQuery(ctx, "channel_id"); !ok {
+		return
+	}
+	if filter.AlertID, ok = optionalUUIDQuery(ctx, "alert_id"); !ok {
+		return
+	}
+	if statusStr := ctx.Query("status"); statusStr != "" {
+		status := entity.NotificationDeliveryStatus(statusStr)
+		filter.Status = &status
+	}
+
+	response, err := c.notificationService.ListDeliveries(ctx.Request.Context(), tenantUUID, filter, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list notification deliveries")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PayloadDecoderController handles HTTP requests for the payload decoders of sensor types
+type PayloadDecoderController struct {
+	decoderService *service.PayloadDecoderService
+}
+
+// NewPayloadDecoderController creates a new payload decoder controller
+func NewPayloadDecoderController(decoderService *service.PayloadDecoderService) *PayloadDecoderController {
+	return &PayloadDecoderController{
+		decoderService: decoderService,
+	}
+}
+
+// CreateDecoder creates the payload decoder of a sensor type
+// @Summary Create payload decoder
+// @Description Create the decoder converting a sensor type's raw payloads (JSON paths or base64/hex frames with byte offsets) into measurements. A sensor type has at most one decoder.
+// @Tags Payload Decoders
+// @Accept json
+// @Produce json
+// @Param request body dto.PayloadDecoderRequest true "Payload decoder"
+// @Success 201 {object} entity.PayloadDecoder
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders [post]
+func (c *PayloadDecoderController) CreateDecoder(ctx *gin.Context) {
+	var request dto.PayloadDecoderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	decoder, err := c.decoderService.CreateDecoder(ctx.Request.Context(), request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to create payload decoder")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, decoder)
+}
+
+// ListDecoders lists payload decoders
+// @Summary List payload decoders
+// @Description Get a paginated list of payload decoders
+// @Tags Payload Decoders
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.PayloadDecoderListResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders [get]
+func (c *PayloadDecoderController) ListDecoders(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	response, err := c.decoderService.ListDecoders(ctx.Request.Context(), page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list payload decoders")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetDecoder retrieves a payload decoder by ID
+// @Summary Get payload decoder
+// @Description Get a payload decoder by its ID
+// @Tags Payload Decoders
+// @Produce json
+// @Param id path string true "Payload decoder ID"
+// @Success 200 {object} entity.PayloadDecoder
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders/{id} [get]
+func (c *PayloadDecoderController) GetDecoder(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	decoder, err := c.decoderService.GetDecoder(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get payload decoder")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, decoder)
+}
+
+// UpdateDecoder updates a payload decoder
+// @Summary Update payload decoder
+// @Description Update a payload decoder. The sensor type of a decoder can't change.
+// @Tags Payload Decoders
+// @Accept json
+// @Produce json
+// @Param id path string true "Payload decoder ID"
+// @Param request body dto.PayloadDecoderRequest true "Payload decoder"
+// @Success 200 {object} entity.PayloadDecoder
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders/{id} [put]
+func (c *PayloadDecoderController) UpdateDecoder(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.PayloadDecoderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	decoder, err := c.decoderService.UpdateDecoder(ctx.Request.Context(), id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update payload decoder")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, decoder)
+}
+
+// DeleteDecoder deletes a payload decoder
+// @Summary Delete payload decoder
+// @Description Delete a payload decoder. Its sensor type goes back to the flexible JSON format.
+// @Tags Payload Decoders
+// @Produce json
+// @Param id path string true "Payload decoder ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders/{id} [delete]
+func (c *PayloadDecoderController) DeleteDecoder(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.decoderService.DeleteDecoder(ctx.Request.Context(), id); err != nil {
+		respondServiceError(ctx, err, "Failed to delete payload decoder")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// TestDecoder decodes a sample payload
+// @Summary Test payload decoder
+// @Description Decode a sample raw payload with the decoder without storing a reading
+// @Tags Payload Decoders
+// @Accept plain
+// @Produce json
+// @Param id path string true "Payload decoder ID"
+// @Param payload body string true "Raw payload"
+// @Success 200 {object} entity.DecodedPayload
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/payload-decoders/{id}/test [post]
+func (c *PayloadDecoderController) TestDecoder(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+	payload, ok := rawPayload(ctx)
+	if !ok {
+		return
+	}
+
+	decoded, err := c.decoderService.TestDecoder(ctx.Request.Context(), id, payload)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to decode payload")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, decoded)
+}
+
+// maxRawPayloadSize is the largest raw device payload accepted for decoding
+const maxRawPayloadSize = 64 << 10
+
+// rawPayload reads a raw device payload from the request body. It writes a 400 response
+// and returns false when the body is empty or too large.
+func rawPayload(ctx *gin.Context) ([]byte, bool) {
+	payload, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxRawPayloadSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	if len(payload) == 0 || len(payload) > maxRawPayloadSize {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: fmt.Sprintf("payload must be between 1 and %d bytes", maxRawPayloadSize),
+		})
+		return nil, false
+	}
+	return payload, true
+}
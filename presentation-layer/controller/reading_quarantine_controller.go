@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/data-layer/entity"
+	"be-lecsens/asset_management/data-layer/repository"
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingQuarantineController handles HTTP requests for reviewing quarantined readings
+type ReadingQuarantineController struct {
+	quarantineService *service.ReadingQuarantineService
+}
+
+// NewReadingQuarantineController creates a new reading quarantine controller
+func NewReadingQuarantineController(quarantineService *service.ReadingQuarantineService) *ReadingQuarantineController {
+	return &ReadingQuarantineController{
+		quarantineService: quarantineService,
+	}
+}
+
+// ListQuarantinedReadings lists quarantined readings
+// @Summary List quarantined readings
+// @Description Get a paginated list of the readings held back by the ingestion policies of their sensor types, newest first
+// @Tags Reading Quarantine
+// @Produce json
+// @Param tenant_id query string false "Filter by tenant ID"
+// @Param asset_sensor_id query string false "Filter by asset sensor ID"
+// @Param sensor_type_id query string false "Filter by sensor type ID"
+// @Param status query string false "Filter by status (pending, replayed, discarded)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of items per page (default: 20, max: 100)"
+// @Success 200 {object} dto.QuarantinedReadingListResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/reading-quarantine [get]
+func (c *ReadingQuarantineController) ListQuarantinedReadings(ctx *gin.Context) {
+	var filter repository.QuarantinedReadingFilter
+	var ok bool
+	if filter.TenantID, ok = optionalUUIDQuery(ctx, "tenant_id"); !ok {
+		return
+	}
+	if filter.AssetSensorID, ok = optionalUUIDQuery(ctx, "asset_sensor_id"); !ok {
+		return
+	}
+	if filter.SensorTypeID, ok = optionalUUIDQuery(ctx, "sensor_type_id"); !ok {
+		return
+	}
+	if status := ctx.Query("status"); status != "" {
+		quarantineStatus := entity.QuarantineStatus(status)
+		filter.Status = &quarantineStatus
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+
+	response, err := c.quarantineService.ListQuarantinedReadings(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to list quarantined readings")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetQuarantinedReading retrieves a quarantined reading by ID
+// @Summary Get quarantined reading
+// @Description Get a quarantined reading with its measurements and violations
+// @Tags Reading Quarantine
+// @Produce json
+// @Param id path string true "Quarantined reading ID"
+// @Success 200 {object} entity.QuarantinedReading
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/reading-quarantine/{id} [get]
+func (c *ReadingQuarantineController) GetQuarantinedReading(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	quarantined, err := c.quarantineService.GetQuarantinedReading(ctx.Request.Context(), id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get quarantined reading")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quarantined)
+}
+
+// ReplayQuarantinedReading stores the measurements of a quarantined reading
+// @Summary Replay quarantined reading
+// @Description Store the measurements of a pending quarantined reading. They are checked with the strict policy against the current measurement fields of the sensor type, unless force stores them as they are.
+// @Tags Reading Quarantine
+// @Accept json
+// @Produce json
+// @Param id path string true "Quarantined reading ID"
+// @Param request body dto.ReplayQuarantinedReadingRequest false "Replay options"
+// @Success 200 {object} dto.ReplayQuarantinedReadingResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/reading-quarantine/{id}/replay [post]
+func (c *ReadingQuarantineController) ReplayQuarantinedReading(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.ReplayQuarantinedReadingRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	response, err := c.quarantineService.ReplayQuarantinedReading(ctx.Request.Context(), id, request, optionalUserIDFromContext(ctx))
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to replay quarantined reading")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DiscardQuarantinedReading drops a quarantined reading
+// @Summary Discard quarantined reading
+// @Description Mark a pending quarantined reading as discarded without storing it
+// @Tags Reading Quarantine
+// @Accept json
+// @Produce json
+// @Param id path string true "Quarantined reading ID"
+// @Param request body dto.DiscardQuarantinedReadingRequest false "Review note"
+// @Success 200 {object} entity.QuarantinedReading
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /superadmin/reading-quarantine/{id}/discard [post]
+func (c *ReadingQuarantineController) DiscardQuarantinedReading(ctx *gin.Context) {
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.DiscardQuarantinedReadingRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	quarantined, err := c.quarantineService.DiscardQuarantinedReading(ctx.Request.Context(), id, request, optionalUserIDFromContext(ctx))
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to discard quarantined reading")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quarantined)
+}
@@ -317,3 +317,15 @@ func (c *SensorMeasurementFieldController) GetRequiredFields(ctx *gin.Context) {
 		Message: "Required sensor measurement fields retrieved successfully",
 	})
 }
+
+// GetUnits handles listing the unit catalog. Readings are converted to the unit of their
+// field at ingestion and to these units on read.
+func (c *SensorMeasurementFieldController) GetUnits(ctx *gin.Context) {
+	units := c.service.GetUnits()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":    units,
+		"total":   len(units),
+		"message": "Units retrieved successfully",
+	})
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/helpers/common"
+	"be-lecsens/asset_management/helpers/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SensorTimeSettingsController handles HTTP requests for the time settings of asset sensors
+type SensorTimeSettingsController struct {
+	sensorTimeSettingsService *service.SensorTimeSettingsService
+}
+
+// NewSensorTimeSettingsController creates a new sensor time settings controller
+func NewSensorTimeSettingsController(sensorTimeSettingsService *service.SensorTimeSettingsService) *SensorTimeSettingsController {
+	return &SensorTimeSettingsController{
+		sensorTimeSettingsService: sensorTimeSettingsService,
+	}
+}
+
+// GetSensorTimeSettings retrieves the time settings of an asset sensor
+// @Summary Get sensor time settings
+// @Description Get how the timestamps of an asset sensor's readings are parsed and corrected. Sensors without settings report the defaults.
+// @Tags Sensor Time Settings
+// @Produce json
+// @Param id path string true "Asset sensor ID"
+// @Success 200 {object} dto.SensorTimeSettingsResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/sensor-time-settings/{id} [get]
+func (c *SensorTimeSettingsController) GetSensorTimeSettings(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.sensorTimeSettingsService.GetSettings(ctx.Request.Context(), tenantUUID, id)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to get sensor time settings")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateSensorTimeSettings updates the time settings of an asset sensor
+// @Summary Update sensor time settings
+// @Description Set the timestamp format, time zone, clock offset and skew checks of an asset sensor. device_clock measures the clock offset from the device's current time.
+// @Tags Sensor Time Settings
+// @Accept json
+// @Produce json
+// @Param id path string true "Asset sensor ID"
+// @Param request body dto.UpdateSensorTimeSettingsRequest true "Time settings"
+// @Success 200 {object} dto.SensorTimeSettingsResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/sensor-time-settings/{id} [put]
+func (c *SensorTimeSettingsController) UpdateSensorTimeSettings(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var request dto.UpdateSensorTimeSettingsRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := c.sensorTimeSettingsService.UpdateSettings(ctx.Request.Context(), tenantUUID, id, request)
+	if err != nil {
+		respondServiceError(ctx, err, "Failed to update sensor time settings")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ResetSensorTimeSettings returns an asset sensor to the default time settings
+// @Summary Reset sensor time settings
+// @Description Remove the time settings of an asset sensor, so its readings use the defaults
+// @Tags Sensor Time Settings
+// @Produce json
+// @Param id path string true "Asset sensor ID"
+// @Success 204
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /admin/sensor-time-settings/{id} [delete]
+func (c *SensorTimeSettingsController) ResetSensorTimeSettings(ctx *gin.Context) {
+	tenantUUID, ok := tenantIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := idParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.sensorTimeSettingsService.ResetSettings(ctx.Request.Context(), tenantUUID, id); err != nil {
+		respondServiceError(ctx, err, "Failed to reset sensor time settings")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
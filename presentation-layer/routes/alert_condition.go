@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAlertConditionRoutes configures composite alert condition routes
+func SetupAlertConditionRoutes(router *gin.Engine, alertConditionController *controller.AlertConditionController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	conditionGroup := router.Group("/api/v1/admin/alert-conditions")
+	conditionGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Create condition
+		conditionGroup.POST("", alertConditionController.CreateCondition)
+		// List conditions
+		conditionGroup.GET("", alertConditionController.ListConditions)
+		// Get condition by ID
+		conditionGroup.GET("/:id", alertConditionController.GetCondition)
+		// Update condition
+		conditionGroup.PUT("/:id", alertConditionController.UpdateCondition)
+		// Delete condition
+		conditionGroup.DELETE("/:id", alertConditionController.DeleteCondition)
+	}
+}
@@ -21,12 +21,20 @@ func SetupAssetAlertRoutes(router *gin.Engine, assetAlertController *controller.
 			assetAlertGroup.GET("/:id", assetAlertController.GetAssetAlert)
 			// Get alert statistics dashboard
 			assetAlertGroup.GET("/statistics", assetAlertController.GetAlertStatistics)
+			// Get alert timeline (lifecycle events and comments)
+			assetAlertGroup.GET("/:id/timeline", assetAlertController.GetAssetAlertTimeline)
+			// Comment on alert
+			assetAlertGroup.POST("/:id/comments", assetAlertController.AddAssetAlertComment)
 		}
 
 		// Admin routes - use TenantAdmin middleware for role validation
 		adminGroup := assetAlertGroup.Group("")
 		adminGroup.Use(middleware.TenantAdminMiddleware())
 		{
+			// Acknowledge alert
+			adminGroup.PATCH("/:id/acknowledge", assetAlertController.AcknowledgeAssetAlert)
+			// Assign alert to a user
+			adminGroup.PATCH("/:id/assign", assetAlertController.AssignAssetAlert)
 			// Resolve single alert
 			adminGroup.PATCH("/:id/resolve", assetAlertController.ResolveAssetAlert)
 			// Resolve multiple alerts
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCSVMappingProfileRoutes configures CSV mapping profile and CSV import routes
+func SetupCSVMappingProfileRoutes(router *gin.Engine, csvMappingProfileController *controller.CSVMappingProfileController) {
+	// SuperAdmin only routes - use SuperAdmin middleware for role validation
+	profileGroup := router.Group("/api/v1/superadmin/csv-mapping-profiles")
+	profileGroup.Use(middleware.SuperAdminPassthroughMiddleware())
+	{
+		// Create profile
+		profileGroup.POST("", csvMappingProfileController.CreateProfile)
+		// List profiles
+		profileGroup.GET("", csvMappingProfileController.ListProfiles)
+		// Get profile by ID
+		profileGroup.GET("/:id", csvMappingProfileController.GetProfile)
+		// Update profile
+		profileGroup.PUT("/:id", csvMappingProfileController.UpdateProfile)
+		// Delete profile
+		profileGroup.DELETE("/:id", csvMappingProfileController.DeleteProfile)
+		// Import a CSV file with the profile, or preview it with dry_run=true
+		profileGroup.POST("/:id/import", csvMappingProfileController.Import)
+	}
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDeviceAPIKeyRoutes configures device API key management routes
+func SetupDeviceAPIKeyRoutes(router *gin.Engine, deviceAPIKeyController *controller.DeviceAPIKeyController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	adminGroup := router.Group("/api/v1/admin/device-api-keys")
+	adminGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Issue a new key (plaintext key is returned once)
+		adminGroup.POST("", deviceAPIKeyController.IssueDeviceAPIKey)
+		// List keys
+		adminGroup.GET("", deviceAPIKeyController.ListDeviceAPIKeys)
+		// Get key by ID
+		adminGroup.GET("/:id", deviceAPIKeyController.GetDeviceAPIKey)
+		// Rotate key secret
+		adminGroup.POST("/:id/rotate", deviceAPIKeyController.RotateDeviceAPIKey)
+		// Revoke key
+		adminGroup.DELETE("/:id", deviceAPIKeyController.RevokeDeviceAPIKey)
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/domain-layer/service"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDeviceIngestionRoutes configures ingestion routes for devices and gateways
+// authenticated with a device API key instead of a user JWT
+func SetupDeviceIngestionRoutes(router *gin.Engine, deviceIngestionController *controller.DeviceIngestionController, deviceAPIKeyService *service.DeviceAPIKeyService) {
+	ingestGroup := router.Group("/api/v1/ingest")
+	ingestGroup.Use(middleware.DeviceAPIKeyMiddleware(deviceAPIKeyService))
+	{
+		// Ingest a single reading
+		ingestGroup.POST("/readings", deviceIngestionController.IngestReading)
+		// Ingest multiple readings
+		ingestGroup.POST("/readings/bulk", deviceIngestionController.IngestBulkReadings)
+		// Ingest a vendor specific payload using the sensor type's payload decoder
+		ingestGroup.POST("/readings/raw", deviceIngestionController.IngestRawReading)
+	}
+}
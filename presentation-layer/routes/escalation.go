@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupEscalationRoutes configures alert escalation policy routes
+func SetupEscalationRoutes(router *gin.Engine, escalationController *controller.EscalationController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	policyGroup := router.Group("/api/v1/admin/escalation-policies")
+	policyGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Create policy
+		policyGroup.POST("", escalationController.CreatePolicy)
+		// List policies
+		policyGroup.GET("", escalationController.ListPolicies)
+		// Get policy by ID
+		policyGroup.GET("/:id", escalationController.GetPolicy)
+		// Update policy
+		policyGroup.PUT("/:id", escalationController.UpdatePolicy)
+		// Delete policy
+		policyGroup.DELETE("/:id", escalationController.DeletePolicy)
+	}
+}
@@ -41,6 +41,10 @@ func SetupIoTSensorReadingRoutes(router *gin.Engine, iotSensorReadingController
 		{
 			// List all readings (for SuperAdmin - across all tenants)
 			superAdminGroup.GET("", iotSensorReadingController.ListAllReadings)
+			// Get alert evaluation queue statistics
+			superAdminGroup.GET("/alert-evaluation/stats", iotSensorReadingController.GetAlertEvaluationStats)
+			// Get reading outbox statistics
+			superAdminGroup.GET("/outbox/stats", iotSensorReadingController.GetReadingOutboxStats)
 			// Create new reading
 			superAdminGroup.POST("", iotSensorReadingController.CreateReading)
 			// Create batch readings
@@ -75,6 +79,8 @@ func SetupIoTSensorReadingRoutes(router *gin.Engine, iotSensorReadingController
 			superAdminGroup.POST("/flexible", iotSensorReadingController.CreateFlexibleReading)
 			// Create bulk flexible readings with dynamic JSON structure
 			superAdminGroup.POST("/flexible/bulk", iotSensorReadingController.CreateBulkFlexibleReadings)
+			// Stream large NDJSON or CSV imports straight into the database
+			superAdminGroup.POST("/flexible/stream", iotSensorReadingController.StreamFlexibleReadings)
 			// Parse text input to flexible JSON structure
 			superAdminGroup.POST("/parse-text", iotSensorReadingController.ParseTextToFlexible)
 			// List flexible readings with pagination and filtering
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMaintenanceRoutes configures maintenance window routes
+func SetupMaintenanceRoutes(router *gin.Engine, maintenanceController *controller.MaintenanceController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	windowGroup := router.Group("/api/v1/admin/maintenance-windows")
+	windowGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Create window
+		windowGroup.POST("", maintenanceController.CreateWindow)
+		// List windows
+		windowGroup.GET("", maintenanceController.ListWindows)
+		// Get window by ID
+		windowGroup.GET("/:id", maintenanceController.GetWindow)
+		// Update window
+		windowGroup.PUT("/:id", maintenanceController.UpdateWindow)
+		// Delete window
+		windowGroup.DELETE("/:id", maintenanceController.DeleteWindow)
+	}
+}
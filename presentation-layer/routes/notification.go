@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupNotificationRoutes configures alert notification channel, rule and delivery log routes
+func SetupNotificationRoutes(router *gin.Engine, notificationController *controller.NotificationController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	channelGroup := router.Group("/api/v1/admin/notification-channels")
+	channelGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Create channel
+		channelGroup.POST("", notificationController.CreateChannel)
+		// List channels
+		channelGroup.GET("", notificationController.ListChannels)
+		// Get channel by ID
+		channelGroup.GET("/:id", notificationController.GetChannel)
+		// Update channel
+		channelGroup.PUT("/:id", notificationController.UpdateChannel)
+		// Delete channel
+		channelGroup.DELETE("/:id", notificationController.DeleteChannel)
+		// Send a test notification
+		channelGroup.POST("/:id/test", notificationController.TestChannel)
+	}
+
+	ruleGroup := router.Group("/api/v1/admin/notification-rules")
+	ruleGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Create rule
+		ruleGroup.POST("", notificationController.CreateRule)
+		// List rules
+		ruleGroup.GET("", notificationController.ListRules)
+		// Get rule by ID
+		ruleGroup.GET("/:id", notificationController.GetRule)
+		// Update rule
+		ruleGroup.PUT("/:id", notificationController.UpdateRule)
+		// Delete rule
+		ruleGroup.DELETE("/:id", notificationController.DeleteRule)
+	}
+
+	deliveryGroup := router.Group("/api/v1/admin/notification-deliveries")
+	deliveryGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Delivery log
+		deliveryGroup.GET("", notificationController.ListDeliveries)
+	}
+}
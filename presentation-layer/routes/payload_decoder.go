@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupPayloadDecoderRoutes configures payload decoder routes
+func SetupPayloadDecoderRoutes(router *gin.Engine, payloadDecoderController *controller.PayloadDecoderController) {
+	// SuperAdmin only routes - use SuperAdmin middleware for role validation
+	decoderGroup := router.Group("/api/v1/superadmin/payload-decoders")
+	decoderGroup.Use(middleware.SuperAdminPassthroughMiddleware())
+	{
+		// Create decoder
+		decoderGroup.POST("", payloadDecoderController.CreateDecoder)
+		// List decoders
+		decoderGroup.GET("", payloadDecoderController.ListDecoders)
+		// Get decoder by ID
+		decoderGroup.GET("/:id", payloadDecoderController.GetDecoder)
+		// Update decoder
+		decoderGroup.PUT("/:id", payloadDecoderController.UpdateDecoder)
+		// Delete decoder
+		decoderGroup.DELETE("/:id", payloadDecoderController.DeleteDecoder)
+		// Decode a sample payload without storing it
+		decoderGroup.POST("/:id/test", payloadDecoderController.TestDecoder)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupReadingQuarantineRoutes configures reading quarantine routes
+func SetupReadingQuarantineRoutes(router *gin.Engine, readingQuarantineController *controller.ReadingQuarantineController) {
+	// SuperAdmin only routes - use SuperAdmin middleware for role validation
+	quarantineGroup := router.Group("/api/v1/superadmin/reading-quarantine")
+	quarantineGroup.Use(middleware.SuperAdminPassthroughMiddleware())
+	{
+		// List quarantined readings
+		quarantineGroup.GET("", readingQuarantineController.ListQuarantinedReadings)
+		// Get quarantined reading by ID
+		quarantineGroup.GET("/:id", readingQuarantineController.GetQuarantinedReading)
+		// Store the measurements of a quarantined reading
+		quarantineGroup.POST("/:id/replay", readingQuarantineController.ReplayQuarantinedReading)
+		// Drop a quarantined reading
+		quarantineGroup.POST("/:id/discard", readingQuarantineController.DiscardQuarantinedReading)
+	}
+}
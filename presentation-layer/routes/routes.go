@@ -28,6 +28,17 @@ func SetupRoutes(
 	assetAlertController *controller.AssetAlertController,
 	sensorLogsController *controller.SensorLogsController,
 	sensorStatusController *controller.SensorStatusController,
+	deviceAPIKeyController *controller.DeviceAPIKeyController,
+	deviceIngestionController *controller.DeviceIngestionController,
+	deviceAPIKeyService *service.DeviceAPIKeyService,
+	notificationController *controller.NotificationController,
+	escalationController *controller.EscalationController,
+	maintenanceController *controller.MaintenanceController,
+	alertConditionController *controller.AlertConditionController,
+	csvMappingProfileController *controller.CSVMappingProfileController,
+	payloadDecoderController *controller.PayloadDecoderController,
+	readingQuarantineController *controller.ReadingQuarantineController,
+	sensorTimeSettingsController *controller.SensorTimeSettingsController,
 	jwtConfig middleware.JWTConfig,
 ) {
 
@@ -196,4 +207,34 @@ func SetupRoutes(
 
 	// Setup Sensor Status routes
 	SetupSensorStatusRoutes(router, sensorStatusController)
+
+	// Setup Device API Key routes
+	SetupDeviceAPIKeyRoutes(router, deviceAPIKeyController)
+
+	// Setup Device Ingestion routes
+	SetupDeviceIngestionRoutes(router, deviceIngestionController, deviceAPIKeyService)
+
+	// Setup Notification routes
+	SetupNotificationRoutes(router, notificationController)
+
+	// Setup Escalation routes
+	SetupEscalationRoutes(router, escalationController)
+
+	// Setup Maintenance routes
+	SetupMaintenanceRoutes(router, maintenanceController)
+
+	// Setup Alert Condition routes
+	SetupAlertConditionRoutes(router, alertConditionController)
+
+	// Setup CSV Mapping Profile routes
+	SetupCSVMappingProfileRoutes(router, csvMappingProfileController)
+
+	// Setup Payload Decoder routes
+	SetupPayloadDecoderRoutes(router, payloadDecoderController)
+
+	// Setup Reading Quarantine routes
+	SetupReadingQuarantineRoutes(router, readingQuarantineController)
+
+	// Setup Sensor Time Settings routes
+	SetupSensorTimeSettingsRoutes(router, sensorTimeSettingsController)
 }
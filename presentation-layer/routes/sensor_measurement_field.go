@@ -24,6 +24,9 @@ func SetupSensorMeasurementFieldRoutes(router *gin.Engine, controller *controlle
 				// Get all fields
 				sensorMeasurementFields.GET("", controller.GetAll)
 
+				// Get the unit catalog
+				sensorMeasurementFields.GET("/units", controller.GetUnits)
+
 				// Get field by ID
 				sensorMeasurementFields.GET("/:id", controller.GetByID)
 
@@ -55,6 +58,9 @@ func SetupSensorMeasurementFieldRoutes(router *gin.Engine, controller *controlle
 			// Get all fields
 			userApi.GET("", controller.GetAll)
 
+			// Get the unit catalog
+			userApi.GET("/units", controller.GetUnits)
+
 			// Get field by ID
 			userApi.GET("/:id", controller.GetByID)
 		}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"be-lecsens/asset_management/domain-layer/middleware"
+	"be-lecsens/asset_management/presentation-layer/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSensorTimeSettingsRoutes configures the routes of the time settings of asset sensors
+func SetupSensorTimeSettingsRoutes(router *gin.Engine, sensorTimeSettingsController *controller.SensorTimeSettingsController) {
+	// Admin routes - use TenantAdmin middleware for role validation
+	adminGroup := router.Group("/api/v1/admin/sensor-time-settings")
+	adminGroup.Use(middleware.TenantAdminMiddleware())
+	{
+		// Get the time settings of an asset sensor
+		adminGroup.GET("/:id", sensorTimeSettingsController.GetSensorTimeSettings)
+		// Update the time settings of an asset sensor
+		adminGroup.PUT("/:id", sensorTimeSettingsController.UpdateSensorTimeSettings)
+		// Return an asset sensor to the default time settings
+		adminGroup.DELETE("/:id", sensorTimeSettingsController.ResetSensorTimeSettings)
+	}
+}